@@ -23,8 +23,11 @@ function filter() {
     if ((typeof ah === 'string') && ah.startsWith("Bearer ")) {
         jwtToken = ah.substr(7)
         console.log("we have a jwt " + jwtToken)
-        var verified = jwt.verifyJwt(jwtToken)
-        console.log("verified: " + verified)
+        var verified = JSON.parse(jwt.verifyJwt(jwtToken))
+        if (verified.valid)
+            console.log("verified: " + JSON.stringify(verified.claims))
+        else
+            console.log("invalid jwt: " + verified.error + " (" + verified.message + ")")
     }
     else {
         console.log("no authentication")
@@ -6,6 +6,8 @@ function mapReturnType(tag) {
     switch (tag) {
         case "int":
             return "i"
+        case "i64":
+            return "i" // the result is written at the pointer given as last argument, wasmer cannot return i64 from host functions
         case "bytes":
             return "i" // returns the result buffer length if passed buffer is NULL and written size if not
         case "string":
@@ -24,6 +26,9 @@ function mapArgumentType(tag) {
         case "int":
             return "i"
 
+        case "i64":
+            return "I"
+
         case "bytes":
             return "ii"
 
@@ -98,6 +103,11 @@ function getGoParamExtractionCode(args) {
                 currentWasmParamIndex++
                 break
 
+            case "i64":
+                code += `cs.GetParamINT64(${currentWasmParamIndex})`
+                currentWasmParamIndex++
+                break
+
             case "bytes":
                 code += `cs.GetParamByteBuffer(${currentWasmParamIndex}, ${currentWasmParamIndex + 1})`
                 currentWasmParamIndex += 2
@@ -141,6 +151,10 @@ function getJsParamExtractionCode(args) {
                 code += `int(c.GetNumber(${stackPosition}))`
                 break
 
+            case "i64":
+                code += `int64(c.GetNumber(${stackPosition}))`
+                break
+
             case "bytes":
                 code += `c.SafeToBytes(${stackPosition})`
                 break
@@ -186,6 +200,9 @@ function getJsReturnCode(type) {
         case "int":
             return `c.PushInt(res)`
 
+        case "i64":
+            return `c.PushNumber(float64(res))`
+
         case "buffer":
             return `if res == nil {
                     return 0
@@ -220,6 +237,9 @@ function getGoPreCallCode(fct, goParamExtraction) {
         case "int":
             return ""
 
+        case "i64":
+            return ""
+
         case "buffer":
             return ""
 
@@ -236,22 +256,25 @@ function getGoPreCallCode(fct, goParamExtraction) {
     throw `unknown js retrun type for precall code`
 }
 
-function getGoReturnCode(fct) {
+function getGoReturnCode(fct, goParamExtraction) {
     switch (fct.returnType) {
         case "int":
             return `return uint32(res), err`
 
+        case "i64":
+            return `return 0, cs.SetResultINT64(${goParamExtraction.currentWasmParamIndex}, res)`
+
         case "buffer":
             return `
-                    resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                    resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                     resultBuffer.Write(res)
                     return uint32(resultBufferID), nil`
 
         case "string":
             return `
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil`
 
@@ -285,8 +308,8 @@ function getGoReturnCode(fct) {
                     b.Write([]byte(v))
                 }
 
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write(b.Bytes())
 
                 return uint32(resultBufferID), nil`
@@ -299,6 +322,8 @@ function getWasmAdditionalPrototype(type) {
     switch (type) {
         case "bytes":
             return `ii`
+        case "i64":
+            return `i`
     }
     return ""
 }
@@ -311,23 +336,24 @@ function generateWasmBindings(apiDescription, out) {
     out(`package api${apiDescription.moduleName}
 
     import (
+        "github.com/ltearno/my-own-cluster/common"
         "github.com/ltearno/my-own-cluster/enginewasm"
 
         ${needBytesPackage ? '"bytes"' : ''}
         ${needBytesPackage ? '"encoding/binary"' : ''}
     )
     \n\n`)
-    out(`func ${apiDescription.bindFunctionName}WASM(wctx enginewasm.WasmProcessContext, cookie interface{}) {`)
+    out(`func ${apiDescription.bindFunctionName}WASM(host enginewasm.WasmHost, cookie interface{}) {`)
     for (let fctName in apiDescription.functions) {
         let fct = apiDescription.functions[fctName]
         let wasmName = fctName
         let goName = mapGoName(fctName)
 
         let goParamExtraction = getGoParamExtractionCode(fct.args)
-        let goCallParams = ["wctx.Fctx", "cookie"]
+        let goCallParams = ["fctx", "cookie"]
         goCallParams = goCallParams.concat(...goParamExtraction.argNames)
         out(`
-	wctx.BindAPIFunction("${apiDescription.moduleName}", "${wasmName}", "${mapReturnType(fct.returnType)}(${fct.args.map(arg => mapArgumentType(arg.type)).join('')}${getWasmAdditionalPrototype(fct.returnType)})", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("${apiDescription.moduleName}", "${wasmName}", "${mapReturnType(fct.returnType)}(${fct.args.map(arg => mapArgumentType(arg.type)).join('')}${getWasmAdditionalPrototype(fct.returnType)})", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         ${goParamExtraction.code}
 
         ${getGoPreCallCode(fct, goParamExtraction)}
@@ -337,7 +363,7 @@ function generateWasmBindings(apiDescription, out) {
             return uint32(0xffff), err
         }
         
-        ${getGoReturnCode(fct)}
+        ${getGoReturnCode(fct, goParamExtraction)}
     })
     `)
     }
@@ -363,18 +389,17 @@ function generateJsBindings(apiDescription, out) {
         let jsCallParams = ["ctx.Fctx", "cookie"]
         jsCallParams = jsCallParams.concat(...jsParamExtraction.argNames)
         out(`
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("${jsName}", func(c *duktape.Context) int {
             ${jsParamExtraction.code}
             res, err := ${goName}(${jsCallParams.join(', ')})
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             ${getJsReturnCode(fct.returnType)}
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "${jsName}")
         `)
     }
     out(`}`)
@@ -389,6 +414,9 @@ function mapTypescriptType(tag) {
         case "int":
             return "number"
 
+        case "i64":
+            return "number"
+
         case "bytes":
             return "Uint8Array"
 
@@ -440,6 +468,8 @@ function getCReturnType(type) {
     switch (type) {
         case "int":
             return "uint32_t"
+        case "i64":
+            return "uint32_t" // the result is written at the result pointer
         case "bytes":
             return "uint32_t" // returns the result buffer length if passed buffer is NULL and written size if not
         case "string":
@@ -458,6 +488,9 @@ function getCArgument(arg) {
         case "int":
             return `int ${arg.name}`
 
+        case "i64":
+            return `int64_t ${arg.name}`
+
         case "bytes":
             return `const void *${arg.name}_bytes, int ${arg.name}_length`
 
@@ -495,6 +528,8 @@ function generateGuestCBindings(apiDescription, out) {
         let args = fct.args.map(getCArgument)
         if (fct.returnType == "bytes")
             args.push(`void *result_bytes, int result_length`)
+        if (fct.returnType == "i64")
+            args.push(`int64_t *result`)
         if (fct.comment)
             out(`// ${fct.comment}\n`)
         out(`WASM_IMPORT("${apiDescription.moduleName}", "${fctName}") ${getCReturnType(fct.returnType)} ${fctName}(${args.join(', ')});\n`)
@@ -516,6 +551,9 @@ function getRustArgument(arg) {
         case "int":
             return `${arg.name}:u32`
 
+        case "i64":
+            return `${arg.name}: i64`
+
         case "bytes":
             return `${arg.name}_bytes: *const u8, ${arg.name}_length: u32`
 
@@ -540,6 +578,9 @@ function getRustGentleArgument(arg) {
         case "int":
             return `${arg.name}:u32`
 
+        case "i64":
+            return `${arg.name}: i64`
+
         case "bytes":
             return `${arg.name}: &[u8]`
 
@@ -561,6 +602,9 @@ function getRustGentleArgumentTransform(arg) {
         case "int":
             return `${arg.name}`
 
+        case "i64":
+            return `${arg.name}`
+
         case "bytes":
             return `${arg.name}.as_ptr(), ${arg.name}.len() as u32`
 
@@ -583,6 +627,9 @@ function getRustGentleReturnType(type) {
         case "int":
             return `u32`
 
+        case "i64":
+            return `i64`
+
         case "bytes":
             return `Result<Vec<u8>, u32>`
 
@@ -690,6 +737,11 @@ function getRustGentleCall(fctName, fct) {
 
         case "int":
             return `unsafe { raw::${fctName}(${fct.args.map(getRustGentleArgumentTransform).join(', ')}) }`
+
+        case "i64":
+            return `let mut result: i64 = 0;
+    unsafe { raw::${fctName}(${fct.args.map(getRustGentleArgumentTransform).concat(['&mut result']).join(', ')}) };
+    result`
     }
 
     throw `unknown gentle call '${fct.returnType}'`
@@ -732,6 +784,8 @@ pub mod raw {
         let args = fct.args.map(getRustArgument)
         if (fct.returnType == "bytes")
             args.push(`result_bytes: *mut u8, result_length: u32`)
+        if (fct.returnType == "i64")
+            args.push(`result: *mut i64`)
         if (fct.comment)
             out(`        // ${fct.comment}\n`)
         out(`        pub fn ${fctName}(${args.join(', ')}) -> u32;\n`)
@@ -7,97 +7,103 @@ package apicore
     )
 
 func BindMyOwnClusterFunctionsJs(ctx enginejs.JSProcessContext, cookie interface{}) {
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getInputBufferId", func(c *duktape.Context) int {
             
             res, err := GetInputBufferID(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getInputBufferId")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getOutputBufferId", func(c *duktape.Context) int {
             
             res, err := GetOutputBufferID(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getOutputBufferId")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("createExchangeBuffer", func(c *duktape.Context) int {
             
             res, err := CreateExchangeBuffer(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "createExchangeBuffer")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("createStreamingExchangeBuffer", func(c *duktape.Context) int {
+            
+            res, err := CreateStreamingExchangeBuffer(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("writeExchangeBuffer", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-2))
 content := c.SafeToBytes(-1)
 
             res, err := WriteExchangeBuffer(ctx.Fctx, cookie, bufferId, content)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "writeExchangeBuffer")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("writeExchangeBufferHeader", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-3))
 name := c.SafeToString(-2)
 value := c.SafeToString(-1)
 
             res, err := WriteExchangeBufferHeader(ctx.Fctx, cookie, bufferId, name, value)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "writeExchangeBufferHeader")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("writeExchangeBufferStatusCode", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-2))
 statusCode := int(c.GetNumber(-1))
 
             res, err := WriteExchangeBufferStatusCode(ctx.Fctx, cookie, bufferId, statusCode)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "writeExchangeBufferStatusCode")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("readExchangeBuffer", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-1))
 
             res, err := ReadExchangeBuffer(ctx.Fctx, cookie, bufferId)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             if res == nil {
@@ -108,14 +114,13 @@ statusCode := int(c.GetNumber(-1))
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "readExchangeBuffer")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("readExchangeBufferHeaders", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-1))
 
             res, err := ReadExchangeBufferHeaders(ctx.Fctx, cookie, bufferId)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             
@@ -127,14 +132,83 @@ statusCode := int(c.GetNumber(-1))
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "readExchangeBufferHeaders")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("readExchangeBufferStatusCode", func(c *duktape.Context) int {
+            bufferId := int(c.GetNumber(-1))
+
+            res, err := ReadExchangeBufferStatusCode(ctx.Fctx, cookie, bufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("readExchangeBufferPart", func(c *duktape.Context) int {
+            bufferId := int(c.GetNumber(-1))
+
+            res, err := ReadExchangeBufferPart(ctx.Fctx, cookie, bufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            if res == nil {
+                    return 0
+                }
+                dest := (*[1 << 30]byte)(c.PushBuffer(len(res), false))[:len(res):len(res)]
+                copy(dest, res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("isExchangeBufferWriteFinished", func(c *duktape.Context) int {
+            bufferId := int(c.GetNumber(-1))
+
+            res, err := IsExchangeBufferWriteFinished(ctx.Fctx, cookie, bufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("closeExchangeBuffer", func(c *duktape.Context) int {
+            bufferId := int(c.GetNumber(-1))
+
+            res, err := CloseExchangeBuffer(ctx.Fctx, cookie, bufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("forwardExchangeBuffer", func(c *duktape.Context) int {
+            sourceBufferId := int(c.GetNumber(-2))
+targetBufferId := int(c.GetNumber(-1))
+
+            res, err := ForwardExchangeBuffer(ctx.Fctx, cookie, sourceBufferId, targetBufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("base64Decode", func(c *duktape.Context) int {
             encoded := c.SafeToString(-1)
 
             res, err := Base64Decode(ctx.Fctx, cookie, encoded)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             if res == nil {
@@ -145,82 +219,76 @@ statusCode := int(c.GetNumber(-1))
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "base64Decode")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("base64Encode", func(c *duktape.Context) int {
             input := c.SafeToBytes(-1)
 
             res, err := Base64Encode(ctx.Fctx, cookie, input)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "base64Encode")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("registerBlobWithName", func(c *duktape.Context) int {
             name := c.SafeToString(-3)
 contentType := c.SafeToString(-2)
 content := c.SafeToBytes(-1)
 
             res, err := RegisterBlobWithName(ctx.Fctx, cookie, name, contentType, content)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "registerBlobWithName")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("registerBlob", func(c *duktape.Context) int {
             contentType := c.SafeToString(-2)
 content := c.SafeToBytes(-1)
 
             res, err := RegisterBlob(ctx.Fctx, cookie, contentType, content)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "registerBlob")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getBlobTechIdFromName", func(c *duktape.Context) int {
             name := c.SafeToString(-1)
 
             res, err := GetBlobTechIdFromName(ctx.Fctx, cookie, name)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getBlobTechIdFromName")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getBlobBytesAsString", func(c *duktape.Context) int {
             name := c.SafeToString(-1)
 
             res, err := GetBlobBytesAsString(ctx.Fctx, cookie, name)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getBlobBytesAsString")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("plugFunction", func(c *duktape.Context) int {
             method := c.SafeToString(-6)
 path := c.SafeToString(-5)
 name := c.SafeToString(-4)
@@ -230,16 +298,15 @@ tagsJson := c.SafeToString(-1)
 
             res, err := PlugFunction(ctx.Fctx, cookie, method, path, name, startFunction, data, tagsJson)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "plugFunction")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("plugFile", func(c *duktape.Context) int {
             method := c.SafeToString(-4)
 path := c.SafeToString(-3)
 name := c.SafeToString(-2)
@@ -247,64 +314,152 @@ tagsJson := c.SafeToString(-1)
 
             res, err := PlugFile(ctx.Fctx, cookie, method, path, name, tagsJson)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "plugFile")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("unplugPath", func(c *duktape.Context) int {
             method := c.SafeToString(-2)
 path := c.SafeToString(-1)
 
             res, err := UnplugPath(ctx.Fctx, cookie, method, path)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "unplugPath")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getStatus", func(c *duktape.Context) int {
             
             res, err := GetStatus(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getStatus")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("persistenceSet", func(c *duktape.Context) int {
             key := c.SafeToBytes(-2)
 value := c.SafeToBytes(-1)
 
             res, err := PersistenceSet(ctx.Fctx, cookie, key, value)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceSetWithTtl", func(c *duktape.Context) int {
+            key := c.SafeToBytes(-3)
+value := c.SafeToBytes(-2)
+ttlSeconds := int(c.GetNumber(-1))
+
+            res, err := PersistenceSetWithTtl(ctx.Fctx, cookie, key, value, ttlSeconds)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceDelete", func(c *duktape.Context) int {
+            key := c.SafeToBytes(-1)
+
+            res, err := PersistenceDelete(ctx.Fctx, cookie, key)
+            if err != nil {
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "persistenceSet")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("persistenceGetVersion", func(c *duktape.Context) int {
+            key := c.SafeToBytes(-1)
+
+            res, err := PersistenceGetVersion(ctx.Fctx, cookie, key)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushNumber(float64(res))
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceCompareAndSet", func(c *duktape.Context) int {
+            key := c.SafeToBytes(-3)
+expectedVersion := int64(c.GetNumber(-2))
+value := c.SafeToBytes(-1)
+
+            res, err := PersistenceCompareAndSet(ctx.Fctx, cookie, key, expectedVersion, value)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushNumber(float64(res))
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceBeginTransaction", func(c *duktape.Context) int {
+            
+            res, err := PersistenceBeginTransaction(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceCommitTransaction", func(c *duktape.Context) int {
+            
+            res, err := PersistenceCommitTransaction(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushNumber(float64(res))
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceAbortTransaction", func(c *duktape.Context) int {
+            
+            res, err := PersistenceAbortTransaction(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getUrl", func(c *duktape.Context) int {
             url := c.SafeToString(-1)
 
             res, err := GetUrl(ctx.Fctx, cookie, url)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             if res == nil {
@@ -315,14 +470,27 @@ value := c.SafeToBytes(-1)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getUrl")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("fetch", func(c *duktape.Context) int {
+            requestJson := c.SafeToString(-2)
+bodyBufferId := int(c.GetNumber(-1))
+
+            res, err := Fetch(ctx.Fctx, cookie, requestJson, bodyBufferId)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceGet", func(c *duktape.Context) int {
             key := c.SafeToBytes(-1)
 
             res, err := PersistenceGet(ctx.Fctx, cookie, key)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             if res == nil {
@@ -333,14 +501,13 @@ value := c.SafeToBytes(-1)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "persistenceGet")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("persistenceGetSubset", func(c *duktape.Context) int {
             prefix := c.SafeToString(-1)
 
             res, err := PersistenceGetSubset(ctx.Fctx, cookie, prefix)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             
@@ -352,51 +519,290 @@ value := c.SafeToBytes(-1)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "persistenceGetSubset")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("persistenceScan", func(c *duktape.Context) int {
+            queryJson := c.SafeToString(-1)
+
+            res, err := PersistenceScan(ctx.Fctx, cookie, queryJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceGetChanges", func(c *duktape.Context) int {
+            queryJson := c.SafeToString(-1)
+
+            res, err := PersistenceGetChanges(ctx.Fctx, cookie, queryJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("persistenceUseNamespace", func(c *duktape.Context) int {
+            namespace := c.SafeToString(-1)
+
+            res, err := PersistenceUseNamespace(ctx.Fctx, cookie, namespace)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("grantNamespace", func(c *duktape.Context) int {
+            namespace := c.SafeToString(-3)
+grantee := c.SafeToString(-2)
+access := c.SafeToString(-1)
+
+            res, err := GrantNamespace(ctx.Fctx, cookie, namespace, grantee, access)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("revokeNamespaceGrant", func(c *duktape.Context) int {
+            namespace := c.SafeToString(-2)
+grantee := c.SafeToString(-1)
+
+            res, err := RevokeNamespaceGrant(ctx.Fctx, cookie, namespace, grantee)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getNamespaces", func(c *duktape.Context) int {
+            
+            res, err := GetNamespaces(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("scanNamespace", func(c *duktape.Context) int {
+            namespace := c.SafeToString(-2)
+queryJson := c.SafeToString(-1)
+
+            res, err := ScanNamespace(ctx.Fctx, cookie, namespace, queryJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getNamespaceChanges", func(c *duktape.Context) int {
+            namespace := c.SafeToString(-2)
+queryJson := c.SafeToString(-1)
+
+            res, err := GetNamespaceChanges(ctx.Fctx, cookie, namespace, queryJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getDomainWriteVersion", func(c *duktape.Context) int {
+            domain := c.SafeToString(-1)
+
+            res, err := GetDomainWriteVersion(ctx.Fctx, cookie, domain)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("isDomainBackwardCompatible", func(c *duktape.Context) int {
+            domain := c.SafeToString(-1)
+
+            res, err := IsDomainBackwardCompatible(ctx.Fctx, cookie, domain)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("holdDomain", func(c *duktape.Context) int {
+            domain := c.SafeToString(-1)
+
+            res, err := HoldDomain(ctx.Fctx, cookie, domain)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("unholdDomain", func(c *duktape.Context) int {
+            domain := c.SafeToString(-1)
+
+            res, err := UnholdDomain(ctx.Fctx, cookie, domain)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("registerDomainHandler", func(c *duktape.Context) int {
+            domain := c.SafeToString(-2)
+functionName := c.SafeToString(-1)
+
+            res, err := RegisterDomainHandler(ctx.Fctx, cookie, domain, functionName)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getDomains", func(c *duktape.Context) int {
+            
+            res, err := GetDomains(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getSecret", func(c *duktape.Context) int {
+            name := c.SafeToString(-1)
+
+            res, err := GetSecret(ctx.Fctx, cookie, name)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            if res == nil {
+                    return 0
+                }
+                dest := (*[1 << 30]byte)(c.PushBuffer(len(res), false))[:len(res):len(res)]
+                copy(dest, res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("setSecret", func(c *duktape.Context) int {
+            name := c.SafeToString(-3)
+value := c.SafeToBytes(-2)
+functions := c.SafeToString(-1)
+
+            res, err := SetSecret(ctx.Fctx, cookie, name, value, functions)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("deleteSecret", func(c *duktape.Context) int {
+            name := c.SafeToString(-1)
+
+            res, err := DeleteSecret(ctx.Fctx, cookie, name)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getSecrets", func(c *duktape.Context) int {
+            
+            res, err := GetSecrets(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("printDebug", func(c *duktape.Context) int {
             text := c.SafeToString(-1)
 
             res, err := PrintDebug(ctx.Fctx, cookie, text)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "printDebug")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("getTime", func(c *duktape.Context) int {
             dest := c.SafeToBytes(-1)
 
             res, err := GetTime(ctx.Fctx, cookie, dest)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "getTime")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("freeBuffer", func(c *duktape.Context) int {
             bufferId := int(c.GetNumber(-1))
 
             res, err := FreeBuffer(ctx.Fctx, cookie, bufferId)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "freeBuffer")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("callFunction", func(c *duktape.Context) int {
             name := c.SafeToString(-8)
 startFunction := c.SafeToString(-7)
 arguments := []int{}
@@ -424,20 +830,94 @@ posixArguments := []string{}
 
             res, err := CallFunction(ctx.Fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "callFunction")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("callFunctionWithMounts", func(c *duktape.Context) int {
+            name := c.SafeToString(-9)
+startFunction := c.SafeToString(-8)
+arguments := []int{}
+                // #define DUK_ENUM_ARRAY_INDICES_ONLY       (1U << 5)    // only enumerate array indices
+                c.Enum(-7, (1 << 5))
+                for c.Next(-1, true) {
+                    arguments = append(arguments, c.GetInt(-1))
+                    c.Pop()
+                    c.Pop()
+                }
+                c.Pop()
+mode := c.SafeToString(-6)
+inputExchangeBufferId := int(c.GetNumber(-5))
+outputExchangeBufferId := int(c.GetNumber(-4))
+posixFileName := c.SafeToString(-3)
+posixArguments := []string{}
+                // #define DUK_ENUM_ARRAY_INDICES_ONLY       (1U << 5)    // only enumerate array indices
+                c.Enum(-2, (1 << 5))
+                for c.Next(-1, true) {
+                    posixArguments = append(posixArguments, c.SafeToString(-1))
+                    c.Pop()
+                    c.Pop()
+                }
+                c.Pop()
+mounts := c.SafeToString(-1)
+
+            res, err := CallFunctionWithMounts(ctx.Fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments, mounts)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("callFunctionWithEngine", func(c *duktape.Context) int {
+            name := c.SafeToString(-10)
+startFunction := c.SafeToString(-9)
+arguments := []int{}
+                // #define DUK_ENUM_ARRAY_INDICES_ONLY       (1U << 5)    // only enumerate array indices
+                c.Enum(-8, (1 << 5))
+                for c.Next(-1, true) {
+                    arguments = append(arguments, c.GetInt(-1))
+                    c.Pop()
+                    c.Pop()
+                }
+                c.Pop()
+mode := c.SafeToString(-7)
+inputExchangeBufferId := int(c.GetNumber(-6))
+outputExchangeBufferId := int(c.GetNumber(-5))
+posixFileName := c.SafeToString(-4)
+posixArguments := []string{}
+                // #define DUK_ENUM_ARRAY_INDICES_ONLY       (1U << 5)    // only enumerate array indices
+                c.Enum(-3, (1 << 5))
+                for c.Next(-1, true) {
+                    posixArguments = append(posixArguments, c.SafeToString(-1))
+                    c.Pop()
+                    c.Pop()
+                }
+                c.Pop()
+mounts := c.SafeToString(-2)
+engine := c.SafeToString(-1)
+
+            res, err := CallFunctionWithEngine(ctx.Fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments, mounts, engine)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("exportDatabase", func(c *duktape.Context) int {
             
             res, err := ExportDatabase(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             if res == nil {
@@ -448,62 +928,96 @@ posixArguments := []string{}
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "exportDatabase")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("betaWebProxy", func(c *duktape.Context) int {
             proxySpecJson := c.SafeToString(-1)
 
             res, err := BetaWebProxy(ctx.Fctx, cookie, proxySpecJson)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "betaWebProxy")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("isTrace", func(c *duktape.Context) int {
             
             res, err := IsTrace(ctx.Fctx, cookie)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "isTrace")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("plugFilter", func(c *duktape.Context) int {
             name := c.SafeToString(-3)
 startFunction := c.SafeToString(-2)
 data := c.SafeToString(-1)
 
             res, err := PlugFilter(ctx.Fctx, cookie, name, startFunction, data)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "plugFilter")
         
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("unplugFilter", func(c *duktape.Context) int {
             id := c.SafeToString(-1)
 
             res, err := UnplugFilter(ctx.Fctx, cookie, id)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
             }
             
             c.PushInt(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "unplugFilter")
+        
+        ctx.BindAPIFunction("createApiToken", func(c *duktape.Context) int {
+            name := c.SafeToString(-2)
+role := c.SafeToString(-1)
+
+            res, err := CreateApiToken(ctx.Fctx, cookie, name, role)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("revokeApiToken", func(c *duktape.Context) int {
+            name := c.SafeToString(-1)
+
+            res, err := RevokeApiToken(ctx.Fctx, cookie, name)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getApiTokens", func(c *duktape.Context) int {
+            
+            res, err := GetApiTokens(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
         }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package apicore
 
     import (
+        "github.com/ltearno/my-own-cluster/common"
         "github.com/ltearno/my-own-cluster/enginewasm"
 
         "bytes"
@@ -8,13 +9,13 @@ package apicore
     )
     
 
-func BindMyOwnClusterFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie interface{}) {
-	wctx.BindAPIFunction("core", "get_input_buffer_id", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+func BindMyOwnClusterFunctionsWASM(host enginewasm.WasmHost, cookie interface{}) {
+	host.BindAPIFunction("core", "get_input_buffer_id", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := GetInputBufferID(wctx.Fctx, cookie)
+        res, err := GetInputBufferID(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -22,12 +23,12 @@ func BindMyOwnClusterFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie in
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "get_output_buffer_id", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "get_output_buffer_id", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := GetOutputBufferID(wctx.Fctx, cookie)
+        res, err := GetOutputBufferID(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -35,12 +36,12 @@ func BindMyOwnClusterFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie in
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "create_exchange_buffer", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "create_exchange_buffer", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := CreateExchangeBuffer(wctx.Fctx, cookie)
+        res, err := CreateExchangeBuffer(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -48,14 +49,27 @@ func BindMyOwnClusterFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie in
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "write_exchange_buffer", "i(iii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "create_streaming_exchange_buffer", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := CreateStreamingExchangeBuffer(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "write_exchange_buffer", "i(iii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 content := cs.GetParamByteBuffer(1, 2)
 
 
         
 
-        res, err := WriteExchangeBuffer(wctx.Fctx, cookie, bufferId, content)
+        res, err := WriteExchangeBuffer(fctx, cookie, bufferId, content)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -63,7 +77,7 @@ content := cs.GetParamByteBuffer(1, 2)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "write_exchange_buffer_header", "i(iiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "write_exchange_buffer_header", "i(iiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 name := cs.GetParamString(1, 2)
 value := cs.GetParamString(3, 4)
@@ -71,7 +85,7 @@ value := cs.GetParamString(3, 4)
 
         
 
-        res, err := WriteExchangeBufferHeader(wctx.Fctx, cookie, bufferId, name, value)
+        res, err := WriteExchangeBufferHeader(fctx, cookie, bufferId, name, value)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -79,14 +93,14 @@ value := cs.GetParamString(3, 4)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "write_exchange_buffer_status_code", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "write_exchange_buffer_status_code", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 statusCode := cs.GetParamInt(1)
 
 
         
 
-        res, err := WriteExchangeBufferStatusCode(wctx.Fctx, cookie, bufferId, statusCode)
+        res, err := WriteExchangeBufferStatusCode(fctx, cookie, bufferId, statusCode)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -94,13 +108,13 @@ statusCode := cs.GetParamInt(1)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "read_exchange_buffer", "i(iii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "read_exchange_buffer", "i(iii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 
 
         resultBuffer := cs.GetParamByteBuffer(1, 2)
 
-        res, err := ReadExchangeBuffer(wctx.Fctx, cookie, bufferId)
+        res, err := ReadExchangeBuffer(fctx, cookie, bufferId)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -114,13 +128,13 @@ statusCode := cs.GetParamInt(1)
                 }
     })
     
-	wctx.BindAPIFunction("core", "read_exchange_buffer_headers", "i(i)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "read_exchange_buffer_headers", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 
 
         
 
-        res, err := ReadExchangeBufferHeaders(wctx.Fctx, cookie, bufferId)
+        res, err := ReadExchangeBufferHeaders(fctx, cookie, bufferId)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -145,50 +159,125 @@ statusCode := cs.GetParamInt(1)
                     b.Write([]byte(v))
                 }
 
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write(b.Bytes())
 
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "base64_decode", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "read_exchange_buffer_status_code", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        bufferId := cs.GetParamInt(0)
+
+
+        
+
+        res, err := ReadExchangeBufferStatusCode(fctx, cookie, bufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "read_exchange_buffer_part", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        bufferId := cs.GetParamInt(0)
+
+
+        
+
+        res, err := ReadExchangeBufferPart(fctx, cookie, bufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                    resultBuffer.Write(res)
+                    return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "is_exchange_buffer_write_finished", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        bufferId := cs.GetParamInt(0)
+
+
+        
+
+        res, err := IsExchangeBufferWriteFinished(fctx, cookie, bufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "close_exchange_buffer", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        bufferId := cs.GetParamInt(0)
+
+
+        
+
+        res, err := CloseExchangeBuffer(fctx, cookie, bufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "forward_exchange_buffer", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        sourceBufferId := cs.GetParamInt(0)
+targetBufferId := cs.GetParamInt(1)
+
+
+        
+
+        res, err := ForwardExchangeBuffer(fctx, cookie, sourceBufferId, targetBufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "base64_decode", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         encoded := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := Base64Decode(wctx.Fctx, cookie, encoded)
+        res, err := Base64Decode(fctx, cookie, encoded)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                    resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                    resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                     resultBuffer.Write(res)
                     return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "base64_encode", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "base64_encode", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         input := cs.GetParamByteBuffer(0, 1)
 
 
         
 
-        res, err := Base64Encode(wctx.Fctx, cookie, input)
+        res, err := Base64Encode(fctx, cookie, input)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "register_blob_with_name", "i(iiiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "register_blob_with_name", "i(iiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         name := cs.GetParamString(0, 1)
 contentType := cs.GetParamString(2, 3)
 content := cs.GetParamByteBuffer(4, 5)
@@ -196,74 +285,74 @@ content := cs.GetParamByteBuffer(4, 5)
 
         
 
-        res, err := RegisterBlobWithName(wctx.Fctx, cookie, name, contentType, content)
+        res, err := RegisterBlobWithName(fctx, cookie, name, contentType, content)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "register_blob", "i(iiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "register_blob", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         contentType := cs.GetParamString(0, 1)
 content := cs.GetParamByteBuffer(2, 3)
 
 
         
 
-        res, err := RegisterBlob(wctx.Fctx, cookie, contentType, content)
+        res, err := RegisterBlob(fctx, cookie, contentType, content)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "get_blob_tech_id_from_name", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "get_blob_tech_id_from_name", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         name := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := GetBlobTechIdFromName(wctx.Fctx, cookie, name)
+        res, err := GetBlobTechIdFromName(fctx, cookie, name)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "get_blob_bytes_as_string", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "get_blob_bytes_as_string", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         name := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := GetBlobBytesAsString(wctx.Fctx, cookie, name)
+        res, err := GetBlobBytesAsString(fctx, cookie, name)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "plug_function", "i(iiiiiiiiiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "plug_function", "i(iiiiiiiiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         method := cs.GetParamString(0, 1)
 path := cs.GetParamString(2, 3)
 name := cs.GetParamString(4, 5)
@@ -274,7 +363,7 @@ tagsJson := cs.GetParamString(10, 11)
 
         
 
-        res, err := PlugFunction(wctx.Fctx, cookie, method, path, name, startFunction, data, tagsJson)
+        res, err := PlugFunction(fctx, cookie, method, path, name, startFunction, data, tagsJson)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -282,7 +371,7 @@ tagsJson := cs.GetParamString(10, 11)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "plug_file", "i(iiiiiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "plug_file", "i(iiiiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         method := cs.GetParamString(0, 1)
 path := cs.GetParamString(2, 3)
 name := cs.GetParamString(4, 5)
@@ -291,7 +380,7 @@ tagsJson := cs.GetParamString(6, 7)
 
         
 
-        res, err := PlugFile(wctx.Fctx, cookie, method, path, name, tagsJson)
+        res, err := PlugFile(fctx, cookie, method, path, name, tagsJson)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -299,14 +388,14 @@ tagsJson := cs.GetParamString(6, 7)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "unplug_path", "i(iiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "unplug_path", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         method := cs.GetParamString(0, 1)
 path := cs.GetParamString(2, 3)
 
 
         
 
-        res, err := UnplugPath(wctx.Fctx, cookie, method, path)
+        res, err := UnplugPath(fctx, cookie, method, path)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -314,31 +403,31 @@ path := cs.GetParamString(2, 3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "get_status", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "get_status", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := GetStatus(wctx.Fctx, cookie)
+        res, err := GetStatus(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "persistence_set", "i(iiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "persistence_set", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         key := cs.GetParamByteBuffer(0, 1)
 value := cs.GetParamByteBuffer(2, 3)
 
 
         
 
-        res, err := PersistenceSet(wctx.Fctx, cookie, key, value)
+        res, err := PersistenceSet(fctx, cookie, key, value)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -346,49 +435,163 @@ value := cs.GetParamByteBuffer(2, 3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "get_url", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "persistence_set_with_ttl", "i(iiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        key := cs.GetParamByteBuffer(0, 1)
+value := cs.GetParamByteBuffer(2, 3)
+ttlSeconds := cs.GetParamInt(4)
+
+
+        
+
+        res, err := PersistenceSetWithTtl(fctx, cookie, key, value, ttlSeconds)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "persistence_delete", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        key := cs.GetParamByteBuffer(0, 1)
+
+
+        
+
+        res, err := PersistenceDelete(fctx, cookie, key)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "persistence_get_version", "i(iii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        key := cs.GetParamByteBuffer(0, 1)
+
+
+        
+
+        res, err := PersistenceGetVersion(fctx, cookie, key)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return 0, cs.SetResultINT64(2, res)
+    })
+    
+	host.BindAPIFunction("core", "persistence_compare_and_set", "i(iiIiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        key := cs.GetParamByteBuffer(0, 1)
+expectedVersion := cs.GetParamINT64(2)
+value := cs.GetParamByteBuffer(3, 4)
+
+
+        
+
+        res, err := PersistenceCompareAndSet(fctx, cookie, key, expectedVersion, value)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return 0, cs.SetResultINT64(5, res)
+    })
+    
+	host.BindAPIFunction("core", "persistence_begin_transaction", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := PersistenceBeginTransaction(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "persistence_commit_transaction", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := PersistenceCommitTransaction(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return 0, cs.SetResultINT64(0, res)
+    })
+    
+	host.BindAPIFunction("core", "persistence_abort_transaction", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := PersistenceAbortTransaction(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "get_url", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         url := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := GetUrl(wctx.Fctx, cookie, url)
+        res, err := GetUrl(fctx, cookie, url)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                    resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                    resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                     resultBuffer.Write(res)
                     return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "persistence_get", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "fetch", "i(iii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        requestJson := cs.GetParamString(0, 1)
+bodyBufferId := cs.GetParamInt(2)
+
+
+        
+
+        res, err := Fetch(fctx, cookie, requestJson, bodyBufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "persistence_get", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         key := cs.GetParamByteBuffer(0, 1)
 
 
         
 
-        res, err := PersistenceGet(wctx.Fctx, cookie, key)
+        res, err := PersistenceGet(fctx, cookie, key)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                    resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                    resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                     resultBuffer.Write(res)
                     return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "persistence_get_subset", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "persistence_get_subset", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         prefix := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := PersistenceGetSubset(wctx.Fctx, cookie, prefix)
+        res, err := PersistenceGetSubset(fctx, cookie, prefix)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -413,20 +616,309 @@ value := cs.GetParamByteBuffer(2, 3)
                     b.Write([]byte(v))
                 }
 
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write(b.Bytes())
 
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "print_debug", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "persistence_scan", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        queryJson := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := PersistenceScan(fctx, cookie, queryJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "persistence_get_changes", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        queryJson := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := PersistenceGetChanges(fctx, cookie, queryJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "persistence_use_namespace", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        namespace := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := PersistenceUseNamespace(fctx, cookie, namespace)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "grant_namespace", "i(iiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        namespace := cs.GetParamString(0, 1)
+grantee := cs.GetParamString(2, 3)
+access := cs.GetParamString(4, 5)
+
+
+        
+
+        res, err := GrantNamespace(fctx, cookie, namespace, grantee, access)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "revoke_namespace_grant", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        namespace := cs.GetParamString(0, 1)
+grantee := cs.GetParamString(2, 3)
+
+
+        
+
+        res, err := RevokeNamespaceGrant(fctx, cookie, namespace, grantee)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "get_namespaces", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetNamespaces(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "scan_namespace", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        namespace := cs.GetParamString(0, 1)
+queryJson := cs.GetParamString(2, 3)
+
+
+        
+
+        res, err := ScanNamespace(fctx, cookie, namespace, queryJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "get_namespace_changes", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        namespace := cs.GetParamString(0, 1)
+queryJson := cs.GetParamString(2, 3)
+
+
+        
+
+        res, err := GetNamespaceChanges(fctx, cookie, namespace, queryJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "get_domain_write_version", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        domain := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := GetDomainWriteVersion(fctx, cookie, domain)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "is_domain_backward_compatible", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        domain := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := IsDomainBackwardCompatible(fctx, cookie, domain)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "hold_domain", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        domain := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := HoldDomain(fctx, cookie, domain)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "unhold_domain", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        domain := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := UnholdDomain(fctx, cookie, domain)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "register_domain_handler", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        domain := cs.GetParamString(0, 1)
+functionName := cs.GetParamString(2, 3)
+
+
+        
+
+        res, err := RegisterDomainHandler(fctx, cookie, domain, functionName)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "get_domains", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetDomains(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "get_secret", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := GetSecret(fctx, cookie, name)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                    resultBuffer.Write(res)
+                    return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "set_secret", "i(iiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+value := cs.GetParamByteBuffer(2, 3)
+functions := cs.GetParamString(4, 5)
+
+
+        
+
+        res, err := SetSecret(fctx, cookie, name, value, functions)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "delete_secret", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := DeleteSecret(fctx, cookie, name)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "get_secrets", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetSecrets(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "print_debug", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         text := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := PrintDebug(wctx.Fctx, cookie, text)
+        res, err := PrintDebug(fctx, cookie, text)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -434,13 +926,13 @@ value := cs.GetParamByteBuffer(2, 3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "get_time", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "get_time", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         dest := cs.GetParamByteBuffer(0, 1)
 
 
         
 
-        res, err := GetTime(wctx.Fctx, cookie, dest)
+        res, err := GetTime(fctx, cookie, dest)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -448,13 +940,56 @@ value := cs.GetParamByteBuffer(2, 3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "free_buffer", "i(i)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "free_buffer", "i(i)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         bufferId := cs.GetParamInt(0)
 
 
         
 
-        res, err := FreeBuffer(wctx.Fctx, cookie, bufferId)
+        res, err := FreeBuffer(fctx, cookie, bufferId)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "call_function", "i(iiiiiiiiiiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+startFunction := cs.GetParamString(2, 3)
+arguments := []int{} // TODO : To be implemented !!!
+mode := cs.GetParamString(6, 7)
+inputExchangeBufferId := cs.GetParamInt(8)
+outputExchangeBufferId := cs.GetParamInt(9)
+posixFileName := cs.GetParamString(10, 11)
+posixArguments := []string{} // TODO : To be implemented !!!
+
+
+        
+
+        res, err := CallFunction(fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "call_function_with_mounts", "i(iiiiiiiiiiiiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+startFunction := cs.GetParamString(2, 3)
+arguments := []int{} // TODO : To be implemented !!!
+mode := cs.GetParamString(6, 7)
+inputExchangeBufferId := cs.GetParamInt(8)
+outputExchangeBufferId := cs.GetParamInt(9)
+posixFileName := cs.GetParamString(10, 11)
+posixArguments := []string{} // TODO : To be implemented !!!
+mounts := cs.GetParamString(14, 15)
+
+
+        
+
+        res, err := CallFunctionWithMounts(fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments, mounts)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -462,7 +997,7 @@ value := cs.GetParamByteBuffer(2, 3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "call_function", "i(iiiiiiiiiiiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "call_function_with_engine", "i(iiiiiiiiiiiiiiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         name := cs.GetParamString(0, 1)
 startFunction := cs.GetParamString(2, 3)
 arguments := []int{} // TODO : To be implemented !!!
@@ -471,11 +1006,13 @@ inputExchangeBufferId := cs.GetParamInt(8)
 outputExchangeBufferId := cs.GetParamInt(9)
 posixFileName := cs.GetParamString(10, 11)
 posixArguments := []string{} // TODO : To be implemented !!!
+mounts := cs.GetParamString(14, 15)
+engine := cs.GetParamString(16, 17)
 
 
         
 
-        res, err := CallFunction(wctx.Fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments)
+        res, err := CallFunctionWithEngine(fctx, cookie, name, startFunction, arguments, mode, inputExchangeBufferId, outputExchangeBufferId, posixFileName, posixArguments, mounts, engine)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -483,30 +1020,30 @@ posixArguments := []string{} // TODO : To be implemented !!!
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "export_database", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "export_database", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := ExportDatabase(wctx.Fctx, cookie)
+        res, err := ExportDatabase(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                    resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                    resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                    resultBufferID := fctx.CreateExchangeBuffer()
+                    resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                     resultBuffer.Write(res)
                     return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "beta_web_proxy", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "beta_web_proxy", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         proxySpecJson := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := BetaWebProxy(wctx.Fctx, cookie, proxySpecJson)
+        res, err := BetaWebProxy(fctx, cookie, proxySpecJson)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -514,12 +1051,12 @@ posixArguments := []string{} // TODO : To be implemented !!!
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "is_trace", "i()", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "is_trace", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         
 
         
 
-        res, err := IsTrace(wctx.Fctx, cookie)
+        res, err := IsTrace(fctx, cookie)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -527,7 +1064,7 @@ posixArguments := []string{} // TODO : To be implemented !!!
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("core", "plug_filter", "i(iiiiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "plug_filter", "i(iiiiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         name := cs.GetParamString(0, 1)
 startFunction := cs.GetParamString(2, 3)
 data := cs.GetParamString(4, 5)
@@ -535,29 +1072,79 @@ data := cs.GetParamString(4, 5)
 
         
 
-        res, err := PlugFilter(wctx.Fctx, cookie, name, startFunction, data)
+        res, err := PlugFilter(fctx, cookie, name, startFunction, data)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
     
-	wctx.BindAPIFunction("core", "unplug_filter", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("core", "unplug_filter", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         id := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := UnplugFilter(wctx.Fctx, cookie, id)
+        res, err := UnplugFilter(fctx, cookie, id)
         if err != nil {
             return uint32(0xffff), err
         }
         
         return uint32(res), err
     })
+    
+	host.BindAPIFunction("core", "create_api_token", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+role := cs.GetParamString(2, 3)
+
+
+        
+
+        res, err := CreateApiToken(fctx, cookie, name, role)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("core", "revoke_api_token", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        name := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := RevokeApiToken(fctx, cookie, name)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("core", "get_api_tokens", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetApiTokens(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
     }
\ No newline at end of file
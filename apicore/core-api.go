@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -24,10 +24,9 @@ func NewCoreAPIProvider() (common.APIProvider, error) {
 }
 
 func (p *CoreAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngineContextBounding) {
-	wctx, ok := ctx.(*enginewasm.WasmProcessContext)
+	host, ok := ctx.(enginewasm.WasmHost)
 	if ok {
-		wctx := *wctx
-		BindMyOwnClusterFunctionsWASM(wctx, p)
+		BindMyOwnClusterFunctionsWASM(host, p)
 	}
 
 	jsctx, ok := ctx.(*enginejs.JSProcessContext)
@@ -41,29 +40,61 @@ func (p *CoreAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngin
  */
 
 func GetInputBufferID(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
-	return ctx.InputExchangeBufferID, nil
+	return ctx.InputExchangeBufferHandle, nil
 }
 
 func GetOutputBufferID(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
-	return ctx.OutputExchangeBufferID, nil
+	return ctx.OutputExchangeBufferHandle, nil
 }
 
 func FreeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) (int, error) {
-	ctx.Orchestrator.ReleaseExchangeBuffer(int(bufferID))
+	if !ctx.ReleaseExchangeBuffer(bufferID) {
+		return -1, fmt.Errorf("%w (handle %d in function '%s')", common.ErrExchangeBufferAccessDenied, bufferID, ctx.Name)
+	}
+
 	return 0, nil
 }
 
 func PlugFunction(ctx *common.FunctionExecutionContext, cookie interface{}, method string, path string, name string, startFunction string, data string, tagsJSON string) (int, error) {
-	ctx.Orchestrator.PlugFunction(method, path, name, startFunction, data, tagsJSON)
+	tags := make(map[string]string)
+	err := json.Unmarshal([]byte(tagsJSON), &tags)
+	if err != nil {
+		return -1, err
+	}
+
+	err = ctx.CheckPlug(path, tags)
+	if err != nil {
+		return -1, err
+	}
+
+	err = ctx.Orchestrator.PlugFunction(method, path, name, startFunction, data, tagsJSON)
+	if err != nil {
+		return -1, err
+	}
+
 	return 0, nil
 }
 
 func PlugFile(ctx *common.FunctionExecutionContext, cookie interface{}, method string, path string, name string, tagsJSON string) (int, error) {
-	ctx.Orchestrator.PlugFile(method, path, name, tagsJSON)
+	err := ctx.CheckPlug(path, nil)
+	if err != nil {
+		return -1, err
+	}
+
+	err = ctx.Orchestrator.PlugFile(method, path, name, tagsJSON)
+	if err != nil {
+		return -1, err
+	}
+
 	return 0, nil
 }
 
 func UnplugPath(ctx *common.FunctionExecutionContext, cookie interface{}, method string, path string) (int, error) {
+	err := ctx.CheckPlug(path, nil)
+	if err != nil {
+		return -1, err
+	}
+
 	ctx.Orchestrator.UnplugPath(method, path)
 	return 0, nil
 }
@@ -78,8 +109,30 @@ func UnplugFilter(ctx *common.FunctionExecutionContext, cookie interface{}, id s
 	return 0, nil
 }
 
+func CreateApiToken(ctx *common.FunctionExecutionContext, cookie interface{}, name string, role string) (string, error) {
+	return ctx.Orchestrator.CreateAPIToken(name, role)
+}
+
+func RevokeApiToken(ctx *common.FunctionExecutionContext, cookie interface{}, name string) (int, error) {
+	err := ctx.Orchestrator.RevokeAPIToken(name)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func GetApiTokens(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	b, err := json.Marshal(ctx.Orchestrator.GetAPITokens())
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func RegisterBlobWithName(ctx *common.FunctionExecutionContext, cookie interface{}, name string, contentType string, contentBytes []byte) (string, error) {
-	techID, err := ctx.Orchestrator.RegisterBlobWithName(name, contentType, contentBytes)
+	techID, err := ctx.RegisterBlobWithName(name, contentType, contentBytes)
 	if err != nil {
 		return "", err
 	}
@@ -110,22 +163,24 @@ func Base64Encode(ctx *common.FunctionExecutionContext, cookie interface{}, b []
 }
 
 func WriteExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int, content []byte) (int, error) {
-	exchangeBuffer := ctx.Orchestrator.GetExchangeBuffer(bufferID)
-	if exchangeBuffer == nil {
-		fmt.Printf("GET EXCHANGE BUFFER FOR UNKNOWN BUFFER %d\n", bufferID)
-		return 0, nil
+	exchangeBuffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
 	}
 
-	exchangeBuffer.Write(content)
+	// the output of a request fails when the client is gone, streaming functions then stop
+	_, err = exchangeBuffer.Write(content)
+	if err != nil {
+		return -1, nil
+	}
 
 	return len(content), nil
 }
 
 func WriteExchangeBufferHeader(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int, name string, value string) (int, error) {
-	exchangeBuffer := ctx.Orchestrator.GetExchangeBuffer(int(bufferID))
-	if exchangeBuffer == nil {
-		fmt.Printf("GET EXCHANGE BUFFER FOR UNKNOWN BUFFER %d\n", bufferID)
-		return 0, nil
+	exchangeBuffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
 	}
 
 	exchangeBuffer.SetHeader(string(name), string(value))
@@ -134,10 +189,9 @@ func WriteExchangeBufferHeader(ctx *common.FunctionExecutionContext, cookie inte
 }
 
 func WriteExchangeBufferStatusCode(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int, statusCode int) (int, error) {
-	exchangeBuffer := ctx.Orchestrator.GetExchangeBuffer(bufferID)
-	if exchangeBuffer == nil {
-		fmt.Printf("GET EXCHANGE BUFFER FOR UNKNOWN BUFFER %d\n", bufferID)
-		return 0, nil
+	exchangeBuffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
 	}
 
 	exchangeBuffer.WriteStatusCode(statusCode)
@@ -146,10 +200,9 @@ func WriteExchangeBufferStatusCode(ctx *common.FunctionExecutionContext, cookie
 }
 
 func ReadExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) ([]byte, error) {
-	buffer := ctx.Orchestrator.GetExchangeBuffer(bufferID)
-	if buffer == nil {
-		fmt.Printf("buffer %d not found for reading\n", bufferID)
-		return nil, nil
+	buffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return nil, err
 	}
 
 	bufferBytes := buffer.GetBuffer()
@@ -157,25 +210,137 @@ func ReadExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}
 	return bufferBytes, nil
 }
 
+func ReadExchangeBufferStatusCode(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) (int, error) {
+	buffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
+	}
+
+	return buffer.GetStatusCode(), nil
+}
+
+func ReadExchangeBufferPart(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) ([]byte, error) {
+	buffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := buffer.ReadPart()
+	if err == io.EOF {
+		return nil, nil
+	}
+
+	return part, err
+}
+
+func IsExchangeBufferWriteFinished(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) (int, error) {
+	buffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
+	}
+
+	if buffer.IsWriteFinished() {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+func CloseExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) (int, error) {
+	buffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return 0, err
+	}
+
+	return buffer.Close(), nil
+}
+
+// ForwardExchangeBuffer hands the copy of a buffer to another one off to the host, see common.ForwardExchangeBuffer
+func ForwardExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}, sourceBufferID int, targetBufferID int) (int, error) {
+	err := ctx.ForwardExchangeBuffer(sourceBufferID, targetBufferID)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
 func PersistenceSet(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte, value []byte) (int, error) {
-	ok := ctx.Orchestrator.PersistenceSet(key, value)
-	if !ok {
-		return 0, errors.New("cannot persist key !")
+	err := ctx.PersistenceSet(key, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// PersistenceSetWithTtl writes a key which expires after ttlSeconds
+func PersistenceSetWithTtl(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte, value []byte, ttlSeconds int) (int, error) {
+	err := ctx.PersistenceSetWithTTL(key, value, time.Duration(ttlSeconds)*time.Second)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func PersistenceDelete(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte) (int, error) {
+	err := ctx.PersistenceDelete(key)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+// PersistenceGetVersion returns the version of a key, 0 when it does not exist
+func PersistenceGetVersion(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte) (int64, error) {
+	return ctx.PersistenceGetVersion(key), nil
+}
+
+// PersistenceCompareAndSet writes a key having the expected version, it returns the new version or 0
+// when the key has another version
+func PersistenceCompareAndSet(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte, expectedVersion int64, value []byte) (int64, error) {
+	return ctx.PersistenceCompareAndSet(key, expectedVersion, value)
+}
+
+func PersistenceBeginTransaction(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
+	err := ctx.BeginPersistenceTransaction()
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+// PersistenceCommitTransaction returns the version of the commit, 0 when a key read by the transaction changed
+func PersistenceCommitTransaction(ctx *common.FunctionExecutionContext, cookie interface{}) (int64, error) {
+	return ctx.CommitPersistenceTransaction()
+}
+
+func PersistenceAbortTransaction(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
+	err := ctx.AbortPersistenceTransaction()
+	if err != nil {
+		return -1, err
 	}
 
 	return 0, nil
 }
 
 func CreateExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
-	return ctx.Orchestrator.CreateExchangeBuffer(), nil
+	return ctx.CreateExchangeBuffer(), nil
+}
+
+func CreateStreamingExchangeBuffer(ctx *common.FunctionExecutionContext, cookie interface{}) (int, error) {
+	return ctx.CreateStreamingExchangeBuffer(), nil
 }
 
 func ReadExchangeBufferHeaders(ctx *common.FunctionExecutionContext, cookie interface{}, bufferID int) (map[string]string, error) {
 	res := make(map[string]string)
 
-	exchangeBuffer := ctx.Orchestrator.GetExchangeBuffer(int(bufferID))
-	if exchangeBuffer == nil {
-		return nil, nil
+	exchangeBuffer, err := ctx.GetExchangeBuffer(bufferID)
+	if err != nil {
+		return nil, err
 	}
 
 	exchangeBuffer.GetHeaders(func(name string, value string) {
@@ -185,7 +350,15 @@ func ReadExchangeBufferHeaders(ctx *common.FunctionExecutionContext, cookie inte
 }
 
 func GetUrl(ctx *common.FunctionExecutionContext, cookie interface{}, url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	err := ctx.CheckEgress(url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ctx.EgressHTTPClient().Get(url)
+	if errors.Is(err, common.ErrEgressDenied) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, nil
 	}
@@ -196,8 +369,89 @@ func GetUrl(ctx *common.FunctionExecutionContext, cookie interface{}, url string
 	return bytes, nil
 }
 
+type FetchSpec struct {
+	Method  string            `json:"method"`
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Timeout string            `json:"timeout"` // like "500ms", for the whole exchange, body included
+}
+
+const defaultFetchTimeout = 30 * time.Second
+
+/*
+	Fetch sends an http request and returns the handle of the response buffer, with the status code,
+	the headers and the body of the response, streamed while it is read. The request body is read
+	from the body buffer, none when bodyBufferID is -1.
+
+	The timeout cannot go beyond the deadline of the function. When the request fails before a
+	response is received, the response buffer has a 0 status code and the error in its
+	common.HttpClientErrorHeader header. Requests denied by the egress policy fail with
+	common.ErrEgressDenied.
+*/
+func Fetch(ctx *common.FunctionExecutionContext, cookie interface{}, requestJSON string, bodyBufferID int) (int, error) {
+	spec := &FetchSpec{}
+	err := json.Unmarshal([]byte(requestJSON), spec)
+	if err != nil {
+		return -1, fmt.Errorf("invalid fetch request (%v)", err)
+	}
+
+	if spec.Method == "" {
+		spec.Method = "GET"
+	}
+
+	timeout := defaultFetchTimeout
+	if spec.Timeout != "" {
+		timeout, err = time.ParseDuration(spec.Timeout)
+		if err != nil || timeout <= 0 {
+			return -1, fmt.Errorf("invalid fetch timeout '%s'", spec.Timeout)
+		}
+	}
+	if !ctx.Deadline.IsZero() && time.Until(ctx.Deadline) < timeout {
+		timeout = time.Until(ctx.Deadline)
+	}
+
+	var body common.ExchangeBuffer = common.NewMemoryExchangeBuffer()
+	body.Close()
+	if bodyBufferID >= 0 {
+		body, err = ctx.GetExchangeBuffer(bodyBufferID)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	err = ctx.CheckEgress(spec.Url)
+	if err != nil {
+		return -1, err
+	}
+
+	client := ctx.EgressHTTPClient()
+	client.Timeout = timeout
+
+	reqID, respID, err := ctx.Orchestrator.CreateExchangeBuffersFromHttpClientRequest(client, spec.Method, spec.Url, spec.Headers, body)
+	if errors.Is(err, common.ErrEgressDenied) {
+		return -1, err
+	}
+	if err != nil {
+		responseID := ctx.CreateExchangeBuffer()
+		response, _ := ctx.GetExchangeBuffer(responseID)
+		response.WriteStatusCode(0)
+		response.SetHeader(common.HttpClientErrorHeader, err.Error())
+		response.Close()
+
+		return responseID, nil
+	}
+
+	// the body comes from the body buffer, the request one is not used
+	ctx.Orchestrator.ReleaseExchangeBuffer(reqID)
+
+	return ctx.AdoptExchangeBuffer(respID), nil
+}
+
 func PersistenceGet(ctx *common.FunctionExecutionContext, cookie interface{}, key []byte) ([]byte, error) {
-	value, present := ctx.Orchestrator.PersistenceGet(key)
+	value, present, err := ctx.PersistenceGet(key)
+	if err != nil {
+		return nil, err
+	}
 	if !present {
 		return nil, nil
 	}
@@ -234,6 +488,49 @@ func GetStatus(ctx *common.FunctionExecutionContext, cookie interface{}) (string
 }
 
 func CallFunction(ctx *common.FunctionExecutionContext, cookie interface{}, name string, startFunction string, arguments []int, mode string, inputExchangeBufferID int, outputExchangeBufferID int, posixFileName string, posixArguments []string) (int, error) {
+	return callFunction(ctx, name, startFunction, arguments, mode, inputExchangeBufferID, outputExchangeBufferID, posixFileName, posixArguments, nil, "")
+}
+
+// CallFunctionWithMounts calls a function with directories mounted in its file system, see common.ParseMounts
+func CallFunctionWithMounts(ctx *common.FunctionExecutionContext, cookie interface{}, name string, startFunction string, arguments []int, mode string, inputExchangeBufferID int, outputExchangeBufferID int, posixFileName string, posixArguments []string, mountsSpec string) (int, error) {
+	mounts, err := common.ParseMounts(mountsSpec)
+	if err != nil {
+		return -1, err
+	}
+
+	return callFunction(ctx, name, startFunction, arguments, mode, inputExchangeBufferID, outputExchangeBufferID, posixFileName, posixArguments, mounts, "")
+}
+
+// CallFunctionWithEngine calls a function with mounts, see CallFunctionWithMounts, and with the
+// engines given like in the "engine" tag of a plugged function, empty for the default ones
+func CallFunctionWithEngine(ctx *common.FunctionExecutionContext, cookie interface{}, name string, startFunction string, arguments []int, mode string, inputExchangeBufferID int, outputExchangeBufferID int, posixFileName string, posixArguments []string, mountsSpec string, engine string) (int, error) {
+	mounts, err := common.ParseMounts(mountsSpec)
+	if err != nil {
+		return -1, err
+	}
+
+	return callFunction(ctx, name, startFunction, arguments, mode, inputExchangeBufferID, outputExchangeBufferID, posixFileName, posixArguments, mounts, engine)
+}
+
+// callFunction runs a function with the buffers of the caller's input and output handles, which get
+// their own handles in the called function. Without requested engines, it runs with the ones of its
+// blob or with the caller's engine, see common.CalledFunctionEngine
+func callFunction(ctx *common.FunctionExecutionContext, name string, startFunction string, arguments []int, mode string, inputExchangeBufferHandle int, outputExchangeBufferHandle int, posixFileName string, posixArguments []string, mounts []common.Mount, engine string) (int, error) {
+	inputExchangeBufferID, err := ctx.ExchangeBufferID(inputExchangeBufferHandle)
+	if err != nil {
+		return -1, err
+	}
+
+	outputExchangeBufferID, err := ctx.ExchangeBufferID(outputExchangeBufferHandle)
+	if err != nil {
+		return -1, err
+	}
+
+	limits, err := ctx.CalledFunctionLimits()
+	if err != nil {
+		return -1, err
+	}
+
 	newCtx := ctx.Orchestrator.NewFunctionExecutionContext(
 		name,
 		startFunction,
@@ -245,18 +542,32 @@ func CallFunction(ctx *common.FunctionExecutionContext, cookie interface{}, name
 		inputExchangeBufferID,
 		outputExchangeBufferID,
 	)
+	newCtx.Mounts = mounts
+	newCtx.Engine = engine
+	if engine == "" {
+		newCtx.Engine = ctx.CalledFunctionEngine(name)
+	}
+
+	// the called function runs with the caller's limits, the fuel it has left, and within its
+	// deadline : it cannot do more than its caller, which is charged for the fuel it consumed
+	newCtx.SetLimits(limits)
+	newCtx.Deadline = ctx.Deadline
+	newCtx.Capabilities = ctx.Capabilities.ForCalledFunction()
+	newCtx.Egress = ctx.Egress.ForCalledFunction()
+	newCtx.InheritDomains(ctx)
 
-	err := newCtx.Run()
+	err = newCtx.Run()
+	ctx.ChargeCalledFunction(newCtx)
 	if err != nil {
 		fmt.Printf("[ERROR] callFunction failed (%v)\n", err)
-		return -1, err
+		return -1, fmt.Errorf("called function failed: %w", err)
 	}
 
 	return newCtx.Result, nil
 }
 
 func PersistenceGetSubset(ctx *common.FunctionExecutionContext, cookie interface{}, prefix string) (map[string]string, error) {
-	subset, err := ctx.Orchestrator.PersistenceGetSubset([]byte(prefix))
+	subset, err := ctx.PersistenceGetSubset([]byte(prefix))
 	if err != nil {
 		return nil, err
 	}
@@ -272,6 +583,208 @@ func PersistenceGetSubset(ctx *common.FunctionExecutionContext, cookie interface
 	return res, nil
 }
 
+// PersistenceScan reads a page of the keys of the namespace used by the function, see common.PersistenceScanQuery
+func PersistenceScan(ctx *common.FunctionExecutionContext, cookie interface{}, queryJSON string) (string, error) {
+	return scanNamespace(ctx, ctx.PersistenceNamespace(), queryJSON)
+}
+
+// ScanNamespace reads a page of the keys of any namespace
+func ScanNamespace(ctx *common.FunctionExecutionContext, cookie interface{}, namespace string, queryJSON string) (string, error) {
+	return scanNamespace(ctx, namespace, queryJSON)
+}
+
+func scanNamespace(ctx *common.FunctionExecutionContext, namespace string, queryJSON string) (string, error) {
+	query := &common.PersistenceScanQuery{}
+	err := json.Unmarshal([]byte(queryJSON), query)
+	if err != nil {
+		return "", fmt.Errorf("invalid scan query (%v)", err)
+	}
+
+	result, err := ctx.Orchestrator.PersistenceScan(namespace, query)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// PersistenceGetChanges reads the changes of the namespace used by the function, see common.PersistenceChangesQuery
+func PersistenceGetChanges(ctx *common.FunctionExecutionContext, cookie interface{}, queryJSON string) (string, error) {
+	return getNamespaceChanges(ctx, ctx.PersistenceNamespace(), queryJSON)
+}
+
+// GetNamespaceChanges reads the changes of any namespace
+func GetNamespaceChanges(ctx *common.FunctionExecutionContext, cookie interface{}, namespace string, queryJSON string) (string, error) {
+	return getNamespaceChanges(ctx, namespace, queryJSON)
+}
+
+func getNamespaceChanges(ctx *common.FunctionExecutionContext, namespace string, queryJSON string) (string, error) {
+	query := &common.PersistenceChangesQuery{}
+	err := json.Unmarshal([]byte(queryJSON), query)
+	if err != nil {
+		return "", fmt.Errorf("invalid changes query (%v)", err)
+	}
+
+	result, err := ctx.Orchestrator.PersistenceGetChanges(namespace, query, ctx.Deadline)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// PersistenceUseNamespace selects the namespace of the next persistence calls, the function's one when empty
+func PersistenceUseNamespace(ctx *common.FunctionExecutionContext, cookie interface{}, namespace string) (int, error) {
+	err := ctx.UseNamespace(namespace)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func GrantNamespace(ctx *common.FunctionExecutionContext, cookie interface{}, namespace string, grantee string, access string) (int, error) {
+	err := ctx.Orchestrator.GrantNamespace(namespace, grantee, access)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func RevokeNamespaceGrant(ctx *common.FunctionExecutionContext, cookie interface{}, namespace string, grantee string) (int, error) {
+	err := ctx.Orchestrator.RevokeNamespaceGrant(namespace, grantee)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+// GetNamespaces returns the persistence namespaces as json, with their size and grants
+func GetNamespaces(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	namespaces, err := ctx.Orchestrator.GetNamespaces()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(namespaces)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// GetDomainWriteVersion returns the schema version of the data written in a domain, see common/versionning.go
+func GetDomainWriteVersion(ctx *common.FunctionExecutionContext, cookie interface{}, domain string) (int, error) {
+	version, err := ctx.GetDomainWriteVersion(domain)
+	if err != nil {
+		return -1, err
+	}
+
+	return version, nil
+}
+
+// IsDomainBackwardCompatible returns 1 when the reads of a domain should also merge the data of the previous version
+func IsDomainBackwardCompatible(ctx *common.FunctionExecutionContext, cookie interface{}, domain string) (int, error) {
+	backwardCompatible, err := ctx.IsDomainBackwardCompatible(domain)
+	if err != nil {
+		return -1, err
+	}
+
+	if backwardCompatible {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+func HoldDomain(ctx *common.FunctionExecutionContext, cookie interface{}, domain string) (int, error) {
+	err := ctx.HoldDomain(domain)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func UnholdDomain(ctx *common.FunctionExecutionContext, cookie interface{}, domain string) (int, error) {
+	err := ctx.UnholdDomain(domain)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func RegisterDomainHandler(ctx *common.FunctionExecutionContext, cookie interface{}, domain string, functionName string) (int, error) {
+	err := ctx.Orchestrator.RegisterGuestDomainHandler(domain, functionName)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func GetDomains(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	domains, err := ctx.Orchestrator.GetDomains()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(domains)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// GetSecret returns the value of a secret the function may read, nothing when it does not exist
+func GetSecret(ctx *common.FunctionExecutionContext, cookie interface{}, name string) ([]byte, error) {
+	return ctx.GetSecret(name)
+}
+
+// SetSecret creates or replaces a secret, readable by the comma separated functions
+func SetSecret(ctx *common.FunctionExecutionContext, cookie interface{}, name string, value []byte, functions string) (int, error) {
+	err := ctx.Orchestrator.SetSecret(name, value, strings.Split(functions, ","))
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+func DeleteSecret(ctx *common.FunctionExecutionContext, cookie interface{}, name string) (int, error) {
+	err := ctx.Orchestrator.DeleteSecret(name)
+	if err != nil {
+		return -1, err
+	}
+
+	return 0, nil
+}
+
+// GetSecrets returns the secrets as json, without their values
+func GetSecrets(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	b, err := json.Marshal(ctx.Orchestrator.GetSecrets())
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func ExportDatabase(ctx *common.FunctionExecutionContext, cookie interface{}) ([]byte, error) {
 	res, err := ctx.Orchestrator.GetDatabaseExport("")
 	if err != nil {
@@ -300,16 +813,31 @@ func BetaWebProxy(ctx *common.FunctionExecutionContext, cookie interface{}, prox
 		fmt.Printf("BETA PROXY to %s\n", spec.Url)
 	}
 
+	input, err := ctx.GetExchangeBuffer(spec.InputExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+
+	output, err := ctx.GetExchangeBuffer(spec.OutputExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+
+	err = ctx.CheckEgress(spec.Url)
+	if err != nil {
+		return -1, err
+	}
+
 	var reqID, respID int
 
 	if strings.HasPrefix(spec.Url, "http") {
-		reqID, respID, err = ctx.Orchestrator.CreateExchangeBuffersFromHttpClientRequest(spec.Method, spec.Url, spec.Headers)
+		reqID, respID, err = ctx.Orchestrator.CreateExchangeBuffersFromHttpClientRequest(ctx.EgressHTTPClient(), spec.Method, spec.Url, spec.Headers, nil)
 		if err != nil {
 			fmt.Printf("ERROR 555 %v\n", err)
 			return -1, err
 		}
 	} else if strings.HasPrefix(spec.Url, "ws") {
-		reqID, respID, err = ctx.Orchestrator.CreateExchangeBuffersFromWebSocketClient(spec.Method, spec.Url, spec.Headers)
+		reqID, respID, err = ctx.Orchestrator.CreateExchangeBuffersFromWebSocketClient(ctx.EgressWebSocketDialer(), spec.Method, spec.Url, spec.Headers)
 		if err != nil {
 			fmt.Printf("ERROR 444 %v\n", err)
 			return -1, err
@@ -319,10 +847,12 @@ func BetaWebProxy(ctx *common.FunctionExecutionContext, cookie interface{}, prox
 		return -1, fmt.Errorf("unknown url '%s'", spec.Url)
 	}
 
+	// the client buffers are owned by the function and released when it finishes
+	ctx.AdoptExchangeBuffer(reqID)
+	ctx.AdoptExchangeBuffer(respID)
+
 	req := ctx.Orchestrator.GetExchangeBuffer(reqID)
 	resp := ctx.Orchestrator.GetExchangeBuffer(respID)
-	input := ctx.Orchestrator.GetExchangeBuffer(spec.InputExchangeBufferID)
-	output := ctx.Orchestrator.GetExchangeBuffer(spec.OutputExchangeBufferID)
 
 	var wg sync.WaitGroup
 	wg.Add(2)
@@ -334,10 +864,10 @@ func BetaWebProxy(ctx *common.FunctionExecutionContext, cookie interface{}, prox
 	go func() {
 		defer wg.Done()
 		for {
-			i := input.GetBuffer()
-			if i == nil || len(i) == 0 {
+			i, err := input.ReadPart()
+			if err != nil {
 				if ctx.Trace {
-					fmt.Printf("INPUT FINISHED\n")
+					fmt.Printf("INPUT FINISHED (%v)\n", err)
 				}
 				req.Close()
 				return
@@ -363,10 +893,10 @@ func BetaWebProxy(ctx *common.FunctionExecutionContext, cookie interface{}, prox
 		output.WriteStatusCode(resp.GetStatusCode())
 
 		for {
-			o := resp.GetBuffer()
-			if o == nil {
+			o, err := resp.ReadPart()
+			if err != nil {
 				if ctx.Trace {
-					fmt.Printf("RESPONSE FINISHED\n")
+					fmt.Printf("RESPONSE FINISHED (%v)\n", err)
 				}
 				output.Close()
 				return
@@ -33,9 +33,9 @@ func NewGPUAPIProvider() (common.APIProvider, error) {
 }
 
 func (p *GPUAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngineContextBounding) {
-	wctx, ok := ctx.(*enginewasm.WasmProcessContext)
+	host, ok := ctx.(enginewasm.WasmHost)
 	if ok {
-		BindOpenGLFunctionsWASM(*wctx, p)
+		BindOpenGLFunctionsWASM(host, p)
 	}
 
 	jsctx, ok := ctx.(*enginejs.JSProcessContext)
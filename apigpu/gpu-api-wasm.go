@@ -1,6 +1,7 @@
 package apigpu
 
     import (
+        "github.com/ltearno/my-own-cluster/common"
         "github.com/ltearno/my-own-cluster/enginewasm"
 
         
@@ -8,14 +9,14 @@ package apigpu
     )
     
 
-func BindOpenGLFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie interface{}) {
-	wctx.BindAPIFunction("gpu", "compute_shader", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+func BindOpenGLFunctionsWASM(host enginewasm.WasmHost, cookie interface{}) {
+	host.BindAPIFunction("gpu", "compute_shader", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         specification := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := ComputeShader(wctx.Fctx, cookie, specification)
+        res, err := ComputeShader(fctx, cookie, specification)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -23,7 +24,7 @@ func BindOpenGLFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie interfac
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("gpu", "create_image_from_rgba_float_pixels", "i(iiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("gpu", "create_image_from_rgba_float_pixels", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         width := cs.GetParamInt(0)
 height := cs.GetParamInt(1)
 pixelsExchangeBufferId := cs.GetParamInt(2)
@@ -32,7 +33,7 @@ pngExchangeBufferId := cs.GetParamInt(3)
 
         
 
-        res, err := CreateImageFromRgbaFloatPixels(wctx.Fctx, cookie, width, height, pixelsExchangeBufferId, pngExchangeBufferId)
+        res, err := CreateImageFromRgbaFloatPixels(fctx, cookie, width, height, pixelsExchangeBufferId, pngExchangeBufferId)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -40,7 +41,7 @@ pngExchangeBufferId := cs.GetParamInt(3)
         return uint32(res), err
     })
     
-	wctx.BindAPIFunction("gpu", "create_image_from_r_float_pixels", "i(iiii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+	host.BindAPIFunction("gpu", "create_image_from_r_float_pixels", "i(iiii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         width := cs.GetParamInt(0)
 height := cs.GetParamInt(1)
 pixelsExchangeBufferId := cs.GetParamInt(2)
@@ -49,7 +50,7 @@ pngExchangeBufferId := cs.GetParamInt(3)
 
         
 
-        res, err := CreateImageFromRFloatPixels(wctx.Fctx, cookie, width, height, pixelsExchangeBufferId, pngExchangeBufferId)
+        res, err := CreateImageFromRFloatPixels(fctx, cookie, width, height, pixelsExchangeBufferId, pngExchangeBufferId)
         if err != nil {
             return uint32(0xffff), err
         }
@@ -37,6 +37,19 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 		return -1, nil
 	}
 
+	// bindings give handles of exchange buffers held by the function
+	bindingBuffers := make(map[int]common.ExchangeBuffer)
+	for binding, bindingSpec := range spec.Bindings {
+		if bindingSpec.Target != "STORAGE" && !bindingSpec.IsOUT {
+			continue
+		}
+
+		bindingBuffers[binding], err = ctx.GetExchangeBuffer(bindingSpec.ExchangeBufferID)
+		if err != nil {
+			return -1, err
+		}
+	}
+
 	openglCtx, err := InitOpenGLContext(ctx)
 	if err != nil {
 		fmt.Printf("cannot init opengl %v\n", err)
@@ -70,7 +83,7 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 	for binding, bindingSpec := range spec.Bindings {
 		switch bindingSpec.Target {
 		case "STORAGE":
-			buffer := ctx.Orchestrator.GetExchangeBuffer(bindingSpec.ExchangeBufferID).GetBuffer()
+			buffer := bindingBuffers[binding].GetBuffer()
 			bufferIndex, err := openglCtx.BindStorageBuffer(binding, buffer)
 			if err != nil {
 				fmt.Printf("cannot bind buffer\n")
@@ -114,7 +127,7 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 
 		switch bindingSpec.Target {
 		case "STORAGE":
-			buffer := ctx.Orchestrator.GetExchangeBuffer(bindingSpec.ExchangeBufferID).GetBuffer()
+			buffer := bindingBuffers[binding].GetBuffer()
 			bufferIndex := storageIndices[binding]
 
 			err := openglCtx.GetStorageBuffer(bufferIndex, buffer)
@@ -125,7 +138,7 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 			break
 
 		case "TEXTURE_2D_RGBA_FLOAT":
-			buffer := ctx.Orchestrator.GetExchangeBuffer(bindingSpec.ExchangeBufferID).GetBuffer()
+			buffer := bindingBuffers[binding].GetBuffer()
 			textureIndex := textureIndices[binding]
 
 			err := openglCtx.GetTexture2DRGBAFloatBuffer(textureIndex, buffer)
@@ -137,7 +150,7 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 			break
 
 		case "TEXTURE_2D_R_FLOAT":
-			buffer := ctx.Orchestrator.GetExchangeBuffer(bindingSpec.ExchangeBufferID).GetBuffer()
+			buffer := bindingBuffers[binding].GetBuffer()
 			textureIndex := textureIndices[binding]
 
 			err := openglCtx.GetTexture2DRFloatBuffer(textureIndex, buffer)
@@ -170,7 +183,15 @@ func ComputeShader(ctx *common.FunctionExecutionContext, cookie interface{}, spe
 }
 
 func CreateImageFromRgbaFloatPixels(ctx *common.FunctionExecutionContext, cookie interface{}, width int, height int, pixelsExchangeBufferID int, pngExchangeBufferID int) (int, error) {
-	pixelsBuffer := ctx.Orchestrator.GetExchangeBuffer(pixelsExchangeBufferID)
+	pixelsBuffer, err := ctx.GetExchangeBuffer(pixelsExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+	pngBuffer, err := ctx.GetExchangeBuffer(pngExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+
 	pixels := pixelsBuffer.GetBuffer()
 
 	upLeft := image.Point{0, 0}
@@ -190,13 +211,21 @@ func CreateImageFromRgbaFloatPixels(ctx *common.FunctionExecutionContext, cookie
 		}
 	}
 
-	png.Encode(ctx.Orchestrator.GetExchangeBuffer(pngExchangeBufferID), img)
+	png.Encode(pngBuffer, img)
 
 	return 0, nil
 }
 
 func CreateImageFromRFloatPixels(ctx *common.FunctionExecutionContext, cookie interface{}, width int, height int, pixelsExchangeBufferID int, pngExchangeBufferID int) (int, error) {
-	pixelsBuffer := ctx.Orchestrator.GetExchangeBuffer(pixelsExchangeBufferID)
+	pixelsBuffer, err := ctx.GetExchangeBuffer(pixelsExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+	pngBuffer, err := ctx.GetExchangeBuffer(pngExchangeBufferID)
+	if err != nil {
+		return -1, err
+	}
+
 	pixels := pixelsBuffer.GetBuffer()
 
 	upLeft := image.Point{0, 0}
@@ -217,7 +246,7 @@ func CreateImageFromRFloatPixels(ctx *common.FunctionExecutionContext, cookie in
 		}
 	}
 
-	png.Encode(ctx.Orchestrator.GetExchangeBuffer(pngExchangeBufferID), img)
+	png.Encode(pngBuffer, img)
 
 	return 0, nil
 }
@@ -7,17 +7,92 @@ package apijwt
     )
 
 func BindJwtFunctionsJs(ctx enginejs.JSProcessContext, cookie interface{}) {
-        ctx.Context.PushGoFunction(func(c *duktape.Context) int {
+        ctx.BindAPIFunction("verifyJwt", func(c *duktape.Context) int {
             jwt := c.SafeToString(-1)
 
             res, err := VerifyJwt(ctx.Fctx, cookie, jwt)
             if err != nil {
-                return 0
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("addTrustProvider", func(c *duktape.Context) int {
+            configurationJson := c.SafeToString(-1)
+
+            res, err := AddTrustProvider(ctx.Fctx, cookie, configurationJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("removeTrustProvider", func(c *duktape.Context) int {
+            iss := c.SafeToString(-1)
+
+            res, err := RemoveTrustProvider(ctx.Fctx, cookie, iss)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushInt(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getTrustProviders", func(c *duktape.Context) int {
+            
+            res, err := GetTrustProviders(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("signJwt", func(c *duktape.Context) int {
+            claimsJson := c.SafeToString(-1)
+
+            res, err := SignJwt(ctx.Fctx, cookie, claimsJson)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("rotateSigningKey", func(c *duktape.Context) int {
+            algorithm := c.SafeToString(-1)
+
+            res, err := RotateSigningKey(ctx.Fctx, cookie, algorithm)
+            if err != nil {
+                return ctx.APIError(err)
+            }
+            
+            c.PushString(res)
+    
+            return 1
+        })
+        
+        ctx.BindAPIFunction("getJwks", func(c *duktape.Context) int {
+            
+            res, err := GetJwks(ctx.Fctx, cookie)
+            if err != nil {
+                return ctx.APIError(err)
             }
             
             c.PushString(res)
     
             return 1
         })
-        ctx.Context.PutPropString(-2, "verifyJwt")
         }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package apijwt
 
     import (
+        "github.com/ltearno/my-own-cluster/common"
         "github.com/ltearno/my-own-cluster/enginewasm"
 
         
@@ -8,21 +9,119 @@ package apijwt
     )
     
 
-func BindJwtFunctionsWASM(wctx enginewasm.WasmProcessContext, cookie interface{}) {
-	wctx.BindAPIFunction("jwt", "verify_jwt", "i(ii)", func(wctx *enginewasm.WasmProcessContext, cs *enginewasm.CallSite) (uint32, error) {
+func BindJwtFunctionsWASM(host enginewasm.WasmHost, cookie interface{}) {
+	host.BindAPIFunction("jwt", "verify_jwt", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
         jwt := cs.GetParamString(0, 1)
 
 
         
 
-        res, err := VerifyJwt(wctx.Fctx, cookie, jwt)
+        res, err := VerifyJwt(fctx, cookie, jwt)
         if err != nil {
             return uint32(0xffff), err
         }
         
         
-                resultBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-                resultBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(resultBufferID)
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("jwt", "add_trust_provider", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        configurationJson := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := AddTrustProvider(fctx, cookie, configurationJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("jwt", "remove_trust_provider", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        iss := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := RemoveTrustProvider(fctx, cookie, iss)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        return uint32(res), err
+    })
+    
+	host.BindAPIFunction("jwt", "get_trust_providers", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetTrustProviders(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("jwt", "sign_jwt", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        claimsJson := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := SignJwt(fctx, cookie, claimsJson)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("jwt", "rotate_signing_key", "i(ii)", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        algorithm := cs.GetParamString(0, 1)
+
+
+        
+
+        res, err := RotateSigningKey(fctx, cookie, algorithm)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
+                resultBuffer.Write([]byte(res))
+                return uint32(resultBufferID), nil
+    })
+    
+	host.BindAPIFunction("jwt", "get_jwks", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+        
+
+        
+
+        res, err := GetJwks(fctx, cookie)
+        if err != nil {
+            return uint32(0xffff), err
+        }
+        
+        
+                resultBufferID := fctx.CreateExchangeBuffer()
+                resultBuffer, _ := fctx.GetExchangeBuffer(resultBufferID)
                 resultBuffer.Write([]byte(res))
                 return uint32(resultBufferID), nil
     })
@@ -1,14 +1,11 @@
 package apijwt
 
 import (
-	"bytes"
-	"context"
-	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/ltearno/my-own-cluster/common"
@@ -16,85 +13,51 @@ import (
 	"github.com/ltearno/my-own-cluster/enginewasm"
 
 	"github.com/lestrrat-go/jwx/jwa"
-	"github.com/lestrrat-go/jwx/jwk"
 	"github.com/lestrrat-go/jwx/jws"
 )
 
 type JWTAPIProvider struct {
-	client        *http.Client
-	trustProvider *TrustProviderConfiguration
-	// issuer => kid => public key
-	trustedKeys map[string]map[string]interface{}
-}
+	orchestrator *common.Orchestrator
+	client       *http.Client
+
+	lock sync.RWMutex
+	// issuer => trust provider and its keys
+	trustProviders map[string]*trustProvider
 
-type TrustProviderConfiguration struct {
-	iss          string // what we match in the token's payload's iss
-	certEndpoint string // endpoint url to fetch public keys
+	// issuer of the JWTs signed by the cluster, with its keys, the current one first
+	issuer      string
+	signingKeys []*signingKey
 }
 
-func NewJWTAPIProvider() (common.APIProvider, error) {
+func NewJWTAPIProvider(orchestrator *common.Orchestrator) (common.APIProvider, error) {
 	p := &JWTAPIProvider{
-		trustProvider: &TrustProviderConfiguration{
-			iss:          "https://home.lteconsulting.fr",
-			certEndpoint: "https://home.lteconsulting.fr/certs",
-		},
-		client: &http.Client{Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		}},
-		trustedKeys: make(map[string]map[string]interface{}),
-	}
-
-	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		for {
-			set, err := jwk.Fetch(context.Background(), p.trustProvider.certEndpoint, jwk.WithHTTPClient(p.client))
-			if err != nil {
-				log.Printf("failed to parse JWK: %s %v", err, set)
-			} else {
-				trustedKeys := make(map[string]interface{})
-
-				for it := set.Iterate(context.Background()); it.Next(context.Background()); {
-					pair := it.Pair()
-					key := pair.Value.(jwk.Key)
-
-					var rawkey interface{} // This is the raw key, like *rsa.PrivateKey or *ecdsa.PrivateKey
-					if err := key.Raw(&rawkey); err != nil {
-						log.Printf("failed to create public key: %s", err)
-					} else {
-						trustedKeys[key.KeyID()] = rawkey
-
-						exists := false
-						existingKeys, ok := p.trustedKeys[p.trustProvider.iss]
-						if ok {
-							_, ok := existingKeys[key.KeyID()]
-							exists = ok
-						}
-						if !exists {
-							log.Printf("registered key:%s for trust:%s\n", key.KeyID(), p.trustProvider.certEndpoint)
-						}
-					}
-
-				}
-
-				p.trustedKeys[p.trustProvider.iss] = trustedKeys
-
-				// TODO should clean up keys from this OIDC provider that are not published anymore
-			}
+		orchestrator:   orchestrator,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		trustProviders: make(map[string]*trustProvider),
+		issuer:         DefaultIssuer,
+		signingKeys:    make([]*signingKey, 0),
+	}
 
-			<-ticker.C
-		}
-	}()
+	err := p.loadTrustProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.loadSigningKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	go p.refreshTrustProviders()
+	go p.maintainSigningKeys()
 
 	return p, nil
 }
 
 func (p *JWTAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngineContextBounding) {
-	wctx, ok := ctx.(*enginewasm.WasmProcessContext)
+	host, ok := ctx.(enginewasm.WasmHost)
 	if ok {
-		wctx := *wctx
-		BindJwtFunctionsWASM(wctx, p)
+		BindJwtFunctionsWASM(host, p)
 	}
 
 	jsctx, ok := ctx.(*enginejs.JSProcessContext)
@@ -103,115 +66,229 @@ func (p *JWTAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngine
 	}
 }
 
-func VerifyJwt(ctx *common.FunctionExecutionContext, cookie interface{}, jwt string) (string, error) {
-	r, e := vVerifyJwt(ctx, cookie, jwt)
-	if e != nil {
-		fmt.Printf("ERROR JWT %v\n", e)
+/*
+	JWT verification
+
+	A JWT is valid when it is signed, with an accepted algorithm, by a key of the trust provider of its
+	issuer, and when its exp, nbf, iat and aud claims are valid. The verify_jwt guest function returns
+	a VerificationResult, telling why the JWT is not valid otherwise.
+*/
+
+const (
+	JWT_MALFORMED             = "malformed"
+	JWT_UNKNOWN_ISSUER        = "unknown_issuer"
+	JWT_UNSUPPORTED_ALGORITHM = "unsupported_algorithm"
+	JWT_UNKNOWN_KEY           = "unknown_key"
+	JWT_INVALID_SIGNATURE     = "invalid_signature"
+	JWT_INVALID_CLAIMS        = "invalid_claims"
+	JWT_EXPIRED               = "expired"
+	JWT_NOT_YET_VALID         = "not_yet_valid"
+	JWT_INVALID_AUDIENCE      = "invalid_audience"
+)
+
+type VerificationResult struct {
+	Valid   bool                   `json:"valid"`
+	Claims  map[string]interface{} `json:"claims,omitempty"`
+	Error   string                 `json:"error,omitempty"` // one of the JWT_ constants
+	Message string                 `json:"message,omitempty"`
+}
+
+type VerificationError struct {
+	Code    string
+	Message string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func verificationError(code string, format string, a ...interface{}) *VerificationError {
+	return &VerificationError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// VerifyCredential verifies a JWT used as an admin credential, see common.CredentialVerifier
+func (p *JWTAPIProvider) VerifyCredential(jwt string) (map[string]interface{}, error) {
+	claims, verifyErr := p.verify(jwt, time.Now())
+	if verifyErr != nil {
+		return nil, verifyErr
 	}
-	return r, e
+
+	return claims, nil
 }
 
-func vVerifyJwt(ctx *common.FunctionExecutionContext, cookie interface{}, jwt string) (string, error) {
+func VerifyJwt(ctx *common.FunctionExecutionContext, cookie interface{}, jwt string) (string, error) {
 	p := cookie.(*JWTAPIProvider)
 
-	// sample payload
-	/*
-		{
-			"alg": "RS256",
-			"kid": "N0IabvUCXr4UyWeNlphOFVl8U9edF7fKt6oiVWilVfRl8B7PLIsF0uUgWbH4w-_pC6gV_i78yunsNKz82_tnDg",
-			"typ": "JWT"
-		}
-		{
-			"iss": "https://home.lteconsulting.fr",
-			"sub": "LTE Consulting",
-			"aud": "IDP",
-			"exp": 1607628782,
-			"iat": 1607614382,
-			"jti": "78db8360-6be9-4bcc-a14b-0721528f96bd",
-			"uuid": "ltearno",
-			"role": "{}",
-			"roles": "{}"
+	result := &VerificationResult{}
+
+	claims, verifyErr := p.verify(jwt, time.Now())
+	if verifyErr != nil {
+		if ctx.Trace {
+			fmt.Printf("JWT verification failed: %v\n", verifyErr)
 		}
-	*/
+		result.Error = verifyErr.Code
+		result.Message = verifyErr.Message
+	} else {
+		result.Valid = true
+		result.Claims = claims
+	}
 
-	buf := []byte(jwt)
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
 
-	// check jwt's length
+	return string(b), nil
+}
 
+func (p *JWTAPIProvider) verify(jwt string, now time.Time) (map[string]interface{}, *VerificationError) {
 	// split jwt's three parts => 3 slices of the buffer
-	protected, payload, signature, err := jws.SplitCompact(buf)
+	protected, payload, _, err := jws.SplitCompact([]byte(jwt))
 	if err != nil {
-		return "", fmt.Errorf("bad formatted jwt")
+		return nil, verificationError(JWT_MALFORMED, "bad formatted jwt")
 	}
 
-	// decode payload
-	decodedPayload := make([]byte, base64.RawURLEncoding.DecodedLen(len(payload)))
-	if _, err := base64.RawURLEncoding.Decode(decodedPayload, payload); err != nil {
-		return "", fmt.Errorf(`failed to decode payload (%v)`, err)
+	header := make(map[string]interface{})
+	err = decodeJSONPart(protected, &header)
+	if err != nil {
+		return nil, verificationError(JWT_MALFORMED, "undecodable header (%v)", err)
 	}
 
-	// check we have issuer
 	claims := make(map[string]interface{})
-	err = json.Unmarshal(decodedPayload, &claims)
+	err = decodeJSONPart(payload, &claims)
 	if err != nil {
-		return "", fmt.Errorf("undecodable payload")
+		return nil, verificationError(JWT_MALFORMED, "undecodable payload (%v)", err)
 	}
-	issuerAny, ok := claims["iss"]
+
+	issuer, ok := claims["iss"].(string)
 	if !ok {
-		return "", fmt.Errorf("no issuer in claims")
+		return nil, verificationError(JWT_INVALID_CLAIMS, "no issuer in claims")
 	}
-	issuer, ok := issuerAny.(string)
-	if !ok {
-		return "", fmt.Errorf("issuer not a string in claims (%v)", issuerAny)
+
+	p.lock.RLock()
+	provider, ok := p.trustProviders[issuer]
+	if !ok && issuer == p.issuer {
+		provider, ok = p.ownTrustProvider(), true
+	}
+	var configuration TrustProviderConfiguration
+	var keys []*trustedKey
+	if ok {
+		configuration = provider.configuration
+		keys = provider.keys
 	}
-	keys, ok := p.trustedKeys[issuer]
+	p.lock.RUnlock()
 	if !ok {
-		return "", fmt.Errorf("no keys registered for issuer %s", issuer)
+		return nil, verificationError(JWT_UNKNOWN_ISSUER, "no trust provider for issuer %s", issuer)
 	}
 
-	// decode header
-	decodedHeader := make([]byte, base64.RawURLEncoding.DecodedLen(len(protected)))
-	if _, err := base64.RawURLEncoding.Decode(decodedHeader, protected); err != nil {
-		return "", fmt.Errorf(`failed to decode header (%v)`, err)
+	alg, _ := header["alg"].(string)
+	if !configuration.acceptsAlgorithm(alg) {
+		return nil, verificationError(JWT_UNSUPPORTED_ALGORITHM, "algorithm '%s' is not accepted for issuer %s", alg, issuer)
 	}
 
-	// check we have kid for issuer
-	header := make(map[string]interface{})
-	err = json.Unmarshal(decodedHeader, &header)
-	if err != nil {
-		return "", fmt.Errorf("undecodable header")
+	// the key given by kid, or any key when there is no kid
+	kid, hasKid := header["kid"].(string)
+	candidates := 0
+	for _, key := range keys {
+		if (hasKid && key.kid != kid) || (key.alg != "" && key.alg != alg) {
+			continue
+		}
+		candidates++
+
+		if _, err := jws.Verify([]byte(jwt), jwa.SignatureAlgorithm(alg), key.raw); err == nil {
+			return claims, validateClaims(claims, &configuration, now)
+		}
 	}
-	kidAny, ok := header["kid"]
-	if !ok {
-		return "", fmt.Errorf("no kid in header")
+
+	if candidates == 0 {
+		return nil, verificationError(JWT_UNKNOWN_KEY, "no %s key '%s' for issuer %s", alg, kid, issuer)
 	}
-	kid, ok := kidAny.(string)
-	if !ok {
-		return "", fmt.Errorf("kid is not a string in header (%v)", kidAny)
+
+	return nil, verificationError(JWT_INVALID_SIGNATURE, "failed to verify the signature")
+}
+
+func decodeJSONPart(part []byte, value interface{}) error {
+	decoded := make([]byte, base64.RawURLEncoding.DecodedLen(len(part)))
+	if _, err := base64.RawURLEncoding.Decode(decoded, part); err != nil {
+		return err
 	}
-	key, ok := keys[kid]
-	if !ok {
-		return "", fmt.Errorf("kid %s invalid for issuer %s", kid, issuer)
+
+	return json.Unmarshal(decoded, value)
+}
+
+// validateClaims checks the exp, nbf, iat and aud claims, with the clock skew of the trust provider
+func validateClaims(claims map[string]interface{}, configuration *TrustProviderConfiguration, now time.Time) *VerificationError {
+	skew := configuration.clockSkew()
+
+	for _, name := range []string{"exp", "nbf", "iat"} {
+		value, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		date, ok := value.(float64)
+		if !ok {
+			return verificationError(JWT_INVALID_CLAIMS, "%s is not a number (%v)", name, value)
+		}
+
+		switch {
+		case name == "exp" && now.Unix() > int64(date)+skew:
+			return verificationError(JWT_EXPIRED, "expired at %s", time.Unix(int64(date), 0).UTC().Format(time.RFC3339))
+		case name != "exp" && now.Unix()+skew < int64(date):
+			return verificationError(JWT_NOT_YET_VALID, "%s is %s", name, time.Unix(int64(date), 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	if len(configuration.Audiences) == 0 {
+		return nil
 	}
 
-	// check signature with info from the trust provider
-	alg := jwa.RS256
-	verifier, err := jws.NewVerifier(alg) // verify.New(alg)
+	audiences := []interface{}{claims["aud"]}
+	if list, ok := claims["aud"].([]interface{}); ok {
+		audiences = list
+	}
+	for _, audience := range audiences {
+		for _, accepted := range configuration.Audiences {
+			if audience == accepted {
+				return nil
+			}
+		}
+	}
+
+	return verificationError(JWT_INVALID_AUDIENCE, "audience %v is not accepted", claims["aud"])
+}
+
+func AddTrustProvider(ctx *common.FunctionExecutionContext, cookie interface{}, configurationJSON string) (int, error) {
+	p := cookie.(*JWTAPIProvider)
+
+	configuration := TrustProviderConfiguration{}
+	err := json.Unmarshal([]byte(configurationJSON), &configuration)
+	if err != nil {
+		return -1, err
+	}
+
+	err = p.AddTrustProvider(configuration)
 	if err != nil {
-		return "", fmt.Errorf(`failed to instantiate signature verifier %v`, err)
+		return -1, err
 	}
-	verifyBuf := &bytes.Buffer{}
-	verifyBuf.Write(protected)
-	verifyBuf.WriteByte('.')
-	verifyBuf.Write(payload)
-	decodedSignature := make([]byte, base64.RawURLEncoding.DecodedLen(len(signature)))
-	if _, err := base64.RawURLEncoding.Decode(decodedSignature, signature); err != nil {
-		return "", fmt.Errorf(`failed to decode signature %v`, err)
+
+	return 0, nil
+}
+
+func RemoveTrustProvider(ctx *common.FunctionExecutionContext, cookie interface{}, issuer string) (int, error) {
+	err := cookie.(*JWTAPIProvider).RemoveTrustProvider(issuer)
+	if err != nil {
+		return -1, err
 	}
-	if err := verifier.Verify(verifyBuf.Bytes(), decodedSignature, key); err != nil {
-		return "", fmt.Errorf(`failed to verify message (%v)`, err)
+
+	return 0, nil
+}
+
+func GetTrustProviders(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	b, err := json.Marshal(cookie.(*JWTAPIProvider).GetTrustProviders())
+	if err != nil {
+		return "", err
 	}
 
-	// return the token's body as a map
-	return string(decodedPayload), nil
+	return string(b), nil
 }
@@ -0,0 +1,368 @@
+package apijwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+)
+
+/*
+	Signing keys
+
+	The cluster signs JWTs with its own keys, kept in the database. The newest key signs, the keys
+	it replaced stay published in the JWKS for retiredSigningKeyRetention so that the JWTs they
+	signed can still be verified, then they are deleted. A key is replaced by a new one of the same
+	algorithm after signingKeyRotationInterval, or when rotate_signing_key is called.
+
+	The JWTs signed by the cluster have its issuer, they are verified with its keys unless a trust
+	provider is configured for this issuer. They may not carry a "moc_role" claim : signing JWTs
+	does not give admin credentials.
+*/
+
+const (
+	DefaultIssuer           = "my-own-cluster"
+	DefaultSigningAlgorithm = "ES256"
+
+	signingKeyRotationInterval = 30 * 24 * time.Hour
+	retiredSigningKeyRetention = 7 * 24 * time.Hour
+
+	defaultSignedTokenLifetime = time.Hour
+	maxSignedTokenLifetime     = retiredSigningKeyRetention
+)
+
+const signingKeysStoragePrefix = "signing-keys/"
+
+var signingAlgorithms = []string{"ES256", "RS256", "PS256", "EdDSA"}
+
+type storedSigningKey struct {
+	Kid       string          `json:"kid"`
+	Algorithm string          `json:"alg"`
+	Key       json.RawMessage `json:"key"` // private JWK
+	CreatedAt int64           `json:"created_at"`
+	RetiredAt int64           `json:"retired_at,omitempty"` // when a newer key replaced it
+}
+
+type signingKey struct {
+	stored  storedSigningKey
+	private interface{}
+	public  jwk.Key
+}
+
+// SetIssuer sets the issuer of the JWTs signed by the cluster
+func (p *JWTAPIProvider) SetIssuer(issuer string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.issuer = issuer
+}
+
+// loadSigningKeys reads the signing keys stored in the database, creating one when there is none
+func (p *JWTAPIProvider) loadSigningKeys() error {
+	stored, err := p.orchestrator.APIStorageGetSubset("jwt", signingKeysStoragePrefix)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	for _, storedJSON := range stored {
+		storedKey := storedSigningKey{}
+		err = json.Unmarshal(storedJSON, &storedKey)
+		if err != nil {
+			log.Printf("ignoring unreadable signing key (%v)", err)
+			continue
+		}
+
+		key, err := newSigningKey(storedKey)
+		if err != nil {
+			log.Printf("ignoring unreadable signing key '%s' (%v)", storedKey.Kid, err)
+			continue
+		}
+
+		p.signingKeys = append(p.signingKeys, key)
+	}
+	p.sortSigningKeys()
+	hasKeys := len(p.signingKeys) > 0
+	p.lock.Unlock()
+
+	if !hasKeys {
+		_, err = p.RotateSigningKey(DefaultSigningAlgorithm)
+		return err
+	}
+
+	return nil
+}
+
+func newSigningKey(stored storedSigningKey) (*signingKey, error) {
+	privateKey, err := jwk.ParseKey(stored.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{stored: stored}
+
+	err = privateKey.Raw(&key.private)
+	if err != nil {
+		return nil, err
+	}
+
+	key.public, err = jwk.PublicKeyOf(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key.public.Set(jwk.KeyIDKey, stored.Kid)
+	key.public.Set(jwk.AlgorithmKey, stored.Algorithm)
+	key.public.Set(jwk.KeyUsageKey, "sig")
+
+	return key, nil
+}
+
+// sortSigningKeys puts the newest key first, it is the one which signs
+func (p *JWTAPIProvider) sortSigningKeys() {
+	sort.Slice(p.signingKeys, func(i, j int) bool {
+		return p.signingKeys[i].stored.CreatedAt > p.signingKeys[j].stored.CreatedAt
+	})
+}
+
+func generatePrivateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case "ES256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "RS256", "PS256":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "EdDSA":
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	}
+
+	return nil, fmt.Errorf("unsupported signing algorithm '%s', should be one of %s", algorithm, strings.Join(signingAlgorithms, ", "))
+}
+
+// RotateSigningKey creates a new signing key, which replaces the current one, and returns its kid
+func (p *JWTAPIProvider) RotateSigningKey(algorithm string) (string, error) {
+	privateKey, err := generatePrivateKey(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	jwkKey, err := jwk.New(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	kidBytes := make([]byte, 12)
+	_, err = rand.Read(kidBytes)
+	if err != nil {
+		return "", err
+	}
+	kid := base64.RawURLEncoding.EncodeToString(kidBytes)
+
+	keyJSON, err := json.Marshal(jwkKey)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := newSigningKey(storedSigningKey{
+		Kid:       kid,
+		Algorithm: algorithm,
+		Key:       keyJSON,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	err = p.storeSigningKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	for _, previous := range p.signingKeys {
+		if previous.stored.RetiredAt == 0 {
+			previous.stored.RetiredAt = key.stored.CreatedAt
+			err = p.storeSigningKey(previous)
+			if err != nil {
+				log.Printf("cannot retire signing key '%s' (%v)", previous.stored.Kid, err)
+			}
+		}
+	}
+
+	p.signingKeys = append(p.signingKeys, key)
+	p.sortSigningKeys()
+
+	log.Printf("created %s signing key:%s\n", algorithm, kid)
+
+	return kid, nil
+}
+
+func (p *JWTAPIProvider) storeSigningKey(key *signingKey) error {
+	storedJSON, err := json.Marshal(key.stored)
+	if err != nil {
+		return err
+	}
+
+	return p.orchestrator.APIStorageSet("jwt", signingKeysStoragePrefix+key.stored.Kid, storedJSON)
+}
+
+// maintainSigningKeys rotates the signing key when it is too old and deletes the retired keys, forever
+func (p *JWTAPIProvider) maintainSigningKeys() {
+	ticker := time.NewTicker(time.Minute)
+	for {
+		<-ticker.C
+
+		p.lock.Lock()
+		kept := make([]*signingKey, 0)
+		for _, key := range p.signingKeys {
+			if key.stored.RetiredAt != 0 && time.Since(time.Unix(key.stored.RetiredAt, 0)) > retiredSigningKeyRetention {
+				log.Printf("deleting retired signing key:%s\n", key.stored.Kid)
+				p.orchestrator.APIStorageDelete("jwt", signingKeysStoragePrefix+key.stored.Kid)
+				continue
+			}
+			kept = append(kept, key)
+		}
+		p.signingKeys = kept
+
+		var current *signingKey
+		if len(p.signingKeys) > 0 {
+			current = p.signingKeys[0]
+		}
+		p.lock.Unlock()
+
+		if current != nil && time.Since(time.Unix(current.stored.CreatedAt, 0)) > signingKeyRotationInterval {
+			_, err := p.RotateSigningKey(current.stored.Algorithm)
+			if err != nil {
+				log.Printf("cannot rotate the signing key (%v)", err)
+			}
+		}
+	}
+}
+
+// SignJWT signs claims with the current signing key. The issuer and the issue date are set, the
+// expiration date defaults to one hour and cannot be more than a week away.
+func (p *JWTAPIProvider) SignJWT(claims map[string]interface{}) (string, error) {
+	p.lock.RLock()
+	issuer := p.issuer
+	var key *signingKey
+	if len(p.signingKeys) > 0 {
+		key = p.signingKeys[0]
+	}
+	p.lock.RUnlock()
+
+	if key == nil {
+		return "", fmt.Errorf("no signing key")
+	}
+
+	if _, ok := claims["moc_role"]; ok {
+		return "", fmt.Errorf("signed JWTs cannot have a 'moc_role' claim")
+	}
+
+	if iss, ok := claims["iss"]; ok && iss != issuer {
+		return "", fmt.Errorf("the issuer of signed JWTs is '%s', not '%v'", issuer, iss)
+	}
+
+	now := time.Now()
+	claims["iss"] = issuer
+	claims["iat"] = now.Unix()
+
+	if _, ok := claims["exp"]; !ok {
+		claims["exp"] = float64(now.Add(defaultSignedTokenLifetime).Unix())
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return "", fmt.Errorf("exp is not a number (%v)", claims["exp"])
+	}
+	if time.Unix(int64(exp), 0).After(now.Add(maxSignedTokenLifetime)) {
+		return "", fmt.Errorf("exp cannot be more than %v away", maxSignedTokenLifetime)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	headers := jws.NewHeaders()
+	headers.Set(jws.KeyIDKey, key.stored.Kid)
+	headers.Set(jws.TypeKey, "JWT")
+
+	signed, err := jws.Sign(payload, jwa.SignatureAlgorithm(key.stored.Algorithm), key.private, jws.WithHeaders(headers))
+	if err != nil {
+		return "", err
+	}
+
+	return string(signed), nil
+}
+
+// GetJWKS returns the public signing keys
+func (p *JWTAPIProvider) GetJWKS() ([]byte, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	set := jwk.NewSet()
+	for _, key := range p.signingKeys {
+		set.Add(key.public)
+	}
+
+	return json.Marshal(set)
+}
+
+// ownTrustProvider returns the trust provider of the JWTs signed by the cluster, p.lock must be held
+func (p *JWTAPIProvider) ownTrustProvider() *trustProvider {
+	provider := &trustProvider{
+		configuration: TrustProviderConfiguration{Issuer: p.issuer},
+		keys:          make([]*trustedKey, 0),
+	}
+
+	for _, key := range p.signingKeys {
+		var rawkey interface{}
+		if err := key.public.Raw(&rawkey); err == nil {
+			provider.keys = append(provider.keys, &trustedKey{kid: key.stored.Kid, alg: key.stored.Algorithm, raw: rawkey})
+		}
+	}
+
+	return provider
+}
+
+func SignJwt(ctx *common.FunctionExecutionContext, cookie interface{}, claimsJSON string) (string, error) {
+	claims := make(map[string]interface{})
+	err := json.Unmarshal([]byte(claimsJSON), &claims)
+	if err != nil {
+		return "", fmt.Errorf("claims should be a json object (%v)", err)
+	}
+
+	return cookie.(*JWTAPIProvider).SignJWT(claims)
+}
+
+func RotateSigningKey(ctx *common.FunctionExecutionContext, cookie interface{}, algorithm string) (string, error) {
+	if algorithm == "" {
+		algorithm = DefaultSigningAlgorithm
+	}
+
+	return cookie.(*JWTAPIProvider).RotateSigningKey(algorithm)
+}
+
+func GetJwks(ctx *common.FunctionExecutionContext, cookie interface{}) (string, error) {
+	jwks, err := cookie.(*JWTAPIProvider).GetJWKS()
+	if err != nil {
+		return "", err
+	}
+
+	return string(jwks), nil
+}
@@ -0,0 +1,394 @@
+package apijwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+/*
+	Trust providers
+
+	A trust provider is an issuer whose JWTs are accepted, with the JWKS publishing its public keys,
+	fetched from an url or read from a file of the server. Trust providers are added at runtime and
+	stored in the database.
+
+	Keys are refreshed every "refresh_interval" seconds, or when the cache headers of the JWKS
+	endpoint tell so (bounded between 30 seconds and a day). Keys which are not published anymore
+	are removed, the previous keys are kept while the JWKS cannot be fetched.
+*/
+
+const (
+	defaultRefreshInterval = 300
+	defaultClockSkew       = 60
+
+	minRefreshInterval = 30 * time.Second
+	maxRefreshInterval = 24 * time.Hour
+)
+
+const trustProvidersStoragePrefix = "trust-providers/"
+
+// asymmetric signature algorithms accepted by default
+var supportedAlgorithms = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+type TrustProviderConfiguration struct {
+	Issuer          string   `json:"iss"`                        // what we match in the token's payload's iss
+	JWKSURL         string   `json:"jwks_url,omitempty"`         // endpoint url to fetch public keys
+	JWKSFile        string   `json:"jwks_file,omitempty"`        // or file of the server holding them
+	Audiences       []string `json:"audiences,omitempty"`        // one of them must be in the aud claim, when given
+	Algorithms      []string `json:"algorithms,omitempty"`       // accepted algorithms, supportedAlgorithms by default
+	RefreshInterval int      `json:"refresh_interval,omitempty"` // in seconds
+	ClockSkew       int      `json:"clock_skew,omitempty"`       // tolerated on exp, nbf and iat, in seconds
+}
+
+type TrustProviderStatus struct {
+	TrustProviderConfiguration
+	Keys        []string `json:"keys"`
+	LastRefresh int64    `json:"last_refresh,omitempty"`
+	LastError   string   `json:"last_error,omitempty"`
+}
+
+type trustProvider struct {
+	configuration TrustProviderConfiguration
+	keys          []*trustedKey
+	nextRefresh   time.Time
+	lastRefresh   time.Time
+	lastError     string
+}
+
+type trustedKey struct {
+	kid string
+	alg string      // empty when the key may be used with any accepted algorithm
+	raw interface{} // public key, like *rsa.PublicKey
+}
+
+func (c *TrustProviderConfiguration) validate() error {
+	if c.Issuer == "" {
+		return fmt.Errorf("a trust provider needs an issuer ('iss')")
+	}
+
+	if (c.JWKSURL == "") == (c.JWKSFile == "") {
+		return fmt.Errorf("a trust provider needs either a 'jwks_url' or a 'jwks_file'")
+	}
+
+	for _, algorithm := range c.Algorithms {
+		if !isSupportedAlgorithm(algorithm) {
+			return fmt.Errorf("unsupported algorithm '%s', should be one of %s", algorithm, strings.Join(supportedAlgorithms, ", "))
+		}
+	}
+
+	if c.RefreshInterval < 0 || c.ClockSkew < 0 {
+		return fmt.Errorf("refresh_interval and clock_skew cannot be negative")
+	}
+
+	return nil
+}
+
+func isSupportedAlgorithm(algorithm string) bool {
+	for _, supported := range supportedAlgorithms {
+		if algorithm == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *TrustProviderConfiguration) acceptsAlgorithm(algorithm string) bool {
+	if len(c.Algorithms) == 0 {
+		return isSupportedAlgorithm(algorithm)
+	}
+
+	for _, accepted := range c.Algorithms {
+		if algorithm == accepted {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *TrustProviderConfiguration) refreshInterval() time.Duration {
+	if c.RefreshInterval == 0 {
+		return defaultRefreshInterval * time.Second
+	}
+
+	return time.Duration(c.RefreshInterval) * time.Second
+}
+
+func (c *TrustProviderConfiguration) clockSkew() int64 {
+	if c.ClockSkew == 0 {
+		return defaultClockSkew
+	}
+
+	return int64(c.ClockSkew)
+}
+
+// loadTrustProviders reads the trust providers stored in the database
+func (p *JWTAPIProvider) loadTrustProviders() error {
+	stored, err := p.orchestrator.APIStorageGetSubset("jwt", trustProvidersStoragePrefix)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, configurationJSON := range stored {
+		configuration := TrustProviderConfiguration{}
+		err = json.Unmarshal(configurationJSON, &configuration)
+		if err != nil {
+			log.Printf("ignoring unreadable trust provider configuration (%v)", err)
+			continue
+		}
+
+		p.trustProviders[configuration.Issuer] = &trustProvider{configuration: configuration}
+	}
+
+	return nil
+}
+
+// AddTrustProvider adds or replaces the trust provider of an issuer and fetches its keys
+func (p *JWTAPIProvider) AddTrustProvider(configuration TrustProviderConfiguration) error {
+	err := configuration.validate()
+	if err != nil {
+		return err
+	}
+
+	configurationJSON, err := json.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+
+	err = p.orchestrator.APIStorageSet("jwt", trustProvidersStoragePrefix+configuration.Issuer, configurationJSON)
+	if err != nil {
+		return err
+	}
+
+	provider := &trustProvider{configuration: configuration}
+
+	p.lock.Lock()
+	p.trustProviders[configuration.Issuer] = provider
+	p.lock.Unlock()
+
+	p.refreshTrustProvider(provider)
+
+	return nil
+}
+
+func (p *JWTAPIProvider) RemoveTrustProvider(issuer string) error {
+	p.lock.Lock()
+	_, ok := p.trustProviders[issuer]
+	delete(p.trustProviders, issuer)
+	p.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no trust provider for issuer '%s'", issuer)
+	}
+
+	return p.orchestrator.APIStorageDelete("jwt", trustProvidersStoragePrefix+issuer)
+}
+
+func (p *JWTAPIProvider) GetTrustProviders() []TrustProviderStatus {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	r := make([]TrustProviderStatus, 0)
+	for _, provider := range p.trustProviders {
+		status := TrustProviderStatus{
+			TrustProviderConfiguration: provider.configuration,
+			Keys:                       make([]string, 0),
+			LastError:                  provider.lastError,
+		}
+		if !provider.lastRefresh.IsZero() {
+			status.LastRefresh = provider.lastRefresh.Unix()
+		}
+		for _, key := range provider.keys {
+			status.Keys = append(status.Keys, key.kid)
+		}
+
+		r = append(r, status)
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Issuer < r[j].Issuer })
+
+	return r
+}
+
+// refreshTrustProviders refreshes the keys of the trust providers when they are due, forever
+func (p *JWTAPIProvider) refreshTrustProviders() {
+	ticker := time.NewTicker(time.Second)
+	for {
+		now := time.Now()
+		due := make([]*trustProvider, 0)
+
+		p.lock.RLock()
+		for _, provider := range p.trustProviders {
+			if !now.Before(provider.nextRefresh) {
+				due = append(due, provider)
+			}
+		}
+		p.lock.RUnlock()
+
+		for _, provider := range due {
+			p.refreshTrustProvider(provider)
+		}
+
+		<-ticker.C
+	}
+}
+
+func (p *JWTAPIProvider) refreshTrustProvider(provider *trustProvider) {
+	keys, maxAge, err := p.fetchKeys(&provider.configuration)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err != nil {
+		log.Printf("cannot refresh the keys of trust provider '%s' (%v)", provider.configuration.Issuer, err)
+
+		provider.lastError = err.Error()
+		provider.nextRefresh = time.Now().Add(minDuration(minRefreshInterval, provider.configuration.refreshInterval()))
+		return
+	}
+
+	for _, key := range keys {
+		if !provider.hasKey(key.kid) {
+			log.Printf("registered key:%s for trust:%s\n", key.kid, provider.configuration.Issuer)
+		}
+	}
+
+	provider.keys = keys
+	provider.lastError = ""
+	provider.lastRefresh = time.Now()
+	provider.nextRefresh = provider.lastRefresh.Add(maxAge)
+}
+
+func (provider *trustProvider) hasKey(kid string) bool {
+	for _, key := range provider.keys {
+		if key.kid == kid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetchKeys reads the JWKS of a trust provider, with the duration for which it may be kept
+func (p *JWTAPIProvider) fetchKeys(configuration *TrustProviderConfiguration) ([]*trustedKey, time.Duration, error) {
+	var jwksBytes []byte
+	maxAge := configuration.refreshInterval()
+
+	if configuration.JWKSFile != "" {
+		var err error
+		jwksBytes, err = ioutil.ReadFile(configuration.JWKSFile)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		resp, err := p.client.Get(configuration.JWKSURL)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return nil, 0, fmt.Errorf("%s status when fetching '%s'", resp.Status, configuration.JWKSURL)
+		}
+
+		jwksBytes, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if cacheMaxAge, ok := cacheMaxAge(resp.Header); ok {
+			maxAge = minDuration(maxDuration(cacheMaxAge, minRefreshInterval), maxRefreshInterval)
+		}
+	}
+
+	set, err := jwk.Parse(jwksBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys := make([]*trustedKey, 0)
+	for it := set.Iterate(context.Background()); it.Next(context.Background()); {
+		key := it.Pair().Value.(jwk.Key)
+
+		// secret keys are never trusted, encryption keys are not for signatures
+		if key.KeyType() == jwa.OctetSeq || key.KeyUsage() == "enc" {
+			continue
+		}
+
+		publicKey, err := jwk.PublicKeyOf(key)
+		if err != nil {
+			log.Printf("failed to create public key: %s", err)
+			continue
+		}
+
+		var rawkey interface{}
+		if err := publicKey.Raw(&rawkey); err != nil {
+			log.Printf("failed to create public key: %s", err)
+			continue
+		}
+
+		keys = append(keys, &trustedKey{kid: key.KeyID(), alg: key.Algorithm(), raw: rawkey})
+	}
+
+	return keys, maxAge, nil
+}
+
+// cacheMaxAge reads how long a response may be cached from its Cache-Control or Expires headers
+func cacheMaxAge(header http.Header) (time.Duration, bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		if directive == "no-cache" || directive == "no-store" {
+			return 0, true
+		}
+
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, err := strconv.Atoi(directive[len("max-age="):])
+			if err == nil {
+				return time.Duration(seconds) * time.Second, true
+			}
+		}
+	}
+
+	if expires, err := http.ParseTime(header.Get("Expires")); err == nil {
+		return time.Until(expires), true
+	}
+
+	return 0, false
+}
+
+func minDuration(a time.Duration, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxDuration(a time.Duration, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,211 @@
+package apijwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+const testIssuer = "https://issuer.example.com"
+
+type testKey struct {
+	kid     string
+	private *ecdsa.PrivateKey
+}
+
+func newTestKey(t *testing.T, kid string) *testKey {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testKey{kid: kid, private: private}
+}
+
+// writeJWKS publishes the public keys in a JWKS file
+func writeJWKS(t *testing.T, file string, keys ...*testKey) {
+	set := jwk.NewSet()
+	for _, key := range keys {
+		public, err := jwk.New(&key.private.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		public.Set(jwk.KeyIDKey, key.kid)
+		public.Set(jwk.AlgorithmKey, "ES256")
+		set.Add(public)
+	}
+
+	jwksBytes, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(file, jwksBytes, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (key *testKey) sign(t *testing.T, claims map[string]interface{}) string {
+	claims["iss"] = testIssuer
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := jws.NewHeaders()
+	headers.Set(jws.KeyIDKey, key.kid)
+
+	signed, err := jws.Sign(payload, jwa.ES256, key.private, jws.WithHeaders(headers))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(signed)
+}
+
+func newTestJWTAPIProvider(t *testing.T) *JWTAPIProvider {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &JWTAPIProvider{
+		orchestrator:   orchestrator,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		trustProviders: make(map[string]*trustProvider),
+		issuer:         DefaultIssuer,
+	}
+}
+
+func expectVerification(t *testing.T, p *JWTAPIProvider, jwt string, now time.Time, code string) {
+	t.Helper()
+
+	_, verifyErr := p.verify(jwt, now)
+	switch {
+	case code == "" && verifyErr != nil:
+		t.Fatalf("the JWT is not valid (%v)", verifyErr)
+	case code != "" && verifyErr == nil:
+		t.Fatalf("the JWT is valid, expected %s", code)
+	case code != "" && verifyErr.Code != code:
+		t.Fatalf("the JWT is not valid with %v, expected %s", verifyErr, code)
+	}
+}
+
+func TestTrustProviderKeyRotation(t *testing.T) {
+	p := newTestJWTAPIProvider(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+
+	oldKey, newKey := newTestKey(t, "old"), newTestKey(t, "new")
+	writeJWKS(t, jwksFile, oldKey)
+
+	err := p.AddTrustProvider(TrustProviderConfiguration{Issuer: testIssuer, JWKSFile: jwksFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	oldJWT := oldKey.sign(t, map[string]interface{}{"sub": "alice"})
+	newJWT := newKey.sign(t, map[string]interface{}{"sub": "alice"})
+
+	expectVerification(t, p, oldJWT, now, "")
+	expectVerification(t, p, newJWT, now, JWT_UNKNOWN_KEY)
+
+	// both keys are published during the rotation
+	writeJWKS(t, jwksFile, oldKey, newKey)
+	p.refreshTrustProvider(p.trustProviders[testIssuer])
+
+	expectVerification(t, p, oldJWT, now, "")
+	expectVerification(t, p, newJWT, now, "")
+
+	// then the old key is removed
+	writeJWKS(t, jwksFile, newKey)
+	p.refreshTrustProvider(p.trustProviders[testIssuer])
+
+	expectVerification(t, p, oldJWT, now, JWT_UNKNOWN_KEY)
+	expectVerification(t, p, newJWT, now, "")
+
+	// the keys are kept while the JWKS cannot be read
+	err = ioutil.WriteFile(jwksFile, []byte("not a jwks"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.refreshTrustProvider(p.trustProviders[testIssuer])
+
+	expectVerification(t, p, newJWT, now, "")
+	if status := p.GetTrustProviders(); len(status) != 1 || status[0].LastError == "" || len(status[0].Keys) != 1 {
+		t.Fatalf("unexpected trust provider status %+v", status)
+	}
+
+	// a JWT signed by the right kid with an other key
+	forged := newTestKey(t, "new").sign(t, map[string]interface{}{"sub": "mallory"})
+	expectVerification(t, p, forged, now, JWT_INVALID_SIGNATURE)
+}
+
+func TestTrustProviderClaimsValidation(t *testing.T) {
+	p := newTestJWTAPIProvider(t)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+
+	key := newTestKey(t, "key")
+	writeJWKS(t, jwksFile, key)
+
+	err := p.AddTrustProvider(TrustProviderConfiguration{Issuer: testIssuer, JWKSFile: jwksFile, Audiences: []string{"api"}, ClockSkew: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	at := func(offset int) float64 {
+		return float64(now.Unix() + int64(offset))
+	}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		code   string
+	}{
+		{"valid", map[string]interface{}{"aud": "api", "exp": at(60)}, ""},
+		// within the clock skew
+		{"exp within skew", map[string]interface{}{"aud": "api", "exp": at(-20)}, ""},
+		{"nbf within skew", map[string]interface{}{"aud": "api", "nbf": at(20)}, ""},
+		{"iat within skew", map[string]interface{}{"aud": "api", "iat": at(20)}, ""},
+		// past the clock skew
+		{"exp past skew", map[string]interface{}{"aud": "api", "exp": at(-40)}, JWT_EXPIRED},
+		{"nbf past skew", map[string]interface{}{"aud": "api", "nbf": at(40)}, JWT_NOT_YET_VALID},
+		{"iat past skew", map[string]interface{}{"aud": "api", "iat": at(40)}, JWT_NOT_YET_VALID},
+		{"exp not a number", map[string]interface{}{"aud": "api", "exp": "tomorrow"}, JWT_INVALID_CLAIMS},
+		// audiences
+		{"accepted audience in list", map[string]interface{}{"aud": []interface{}{"other", "api"}}, ""},
+		{"other audience", map[string]interface{}{"aud": "other"}, JWT_INVALID_AUDIENCE},
+		{"other audiences", map[string]interface{}{"aud": []interface{}{"other"}}, JWT_INVALID_AUDIENCE},
+		{"no audience", map[string]interface{}{}, JWT_INVALID_AUDIENCE},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			expectVerification(t, p, key.sign(t, test.claims), now, test.code)
+		})
+	}
+}
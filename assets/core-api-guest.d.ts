@@ -11,6 +11,8 @@ declare function requireApi(name: "core") : {
     getInputBufferId() : number
     getOutputBufferId() : number
     createExchangeBuffer() : number
+    // creates a buffer whose reader waits for the writer to write or close it, and whose writer waits when too much data is not read yet
+    createStreamingExchangeBuffer() : number
     writeExchangeBuffer(bufferId: number, content: Uint8Array) : number
     writeExchangeBufferHeader(bufferId: number, name: string, value: string) : number
     writeExchangeBufferStatusCode(bufferId: number, statusCode: number) : number
@@ -18,6 +20,15 @@ declare function requireApi(name: "core") : {
     readExchangeBuffer(bufferId: number) : Uint8Array
     // returns the buffer headers in JSON format
     readExchangeBufferHeaders(bufferId: number) : { [key: string]: string }
+    readExchangeBufferStatusCode(bufferId: number) : number
+    // returns an exchange buffer containing the next part written in the buffer, waiting for it if needed. The returned buffer is empty once the writer has finished
+    readExchangeBufferPart(bufferId: number) : Uint8Array
+    // returns 1 if the writer has closed the buffer, 0 otherwise
+    isExchangeBufferWriteFinished(bufferId: number) : number
+    // signals that nothing more will be written in the buffer
+    closeExchangeBuffer(bufferId: number) : number
+    // the host copies the source buffer to the target one in the background and closes the target when done, the function can exit meanwhile
+    forwardExchangeBuffer(sourceBufferId: number, targetBufferId: number) : number
     base64Decode(encoded: string) : Uint8Array
     base64Encode(input: Uint8Array) : string
     registerBlobWithName(name: string, contentType: string, content: Uint8Array) : string
@@ -29,16 +40,51 @@ declare function requireApi(name: "core") : {
     unplugPath(method: string, path: string) : number
     getStatus() : string
     persistenceSet(key: Uint8Array, value: Uint8Array) : number
+    persistenceSetWithTtl(key: Uint8Array, value: Uint8Array, ttlSeconds: number) : number
+    persistenceDelete(key: Uint8Array) : number
+    // gives the version of the key, 0 when it does not exist
+    persistenceGetVersion(key: Uint8Array) : number
+    // gives the new version of the key, 0 when it does not have the expected version
+    persistenceCompareAndSet(key: Uint8Array, expectedVersion: number, value: Uint8Array) : number
+    // the writes following are kept until the commit, transactions are the batch api : several writes are applied at once
+    persistenceBeginTransaction() : number
+    // writes the transaction in one batch, gives the version of the commit, 0 when a key read changed
+    persistenceCommitTransaction() : number
+    persistenceAbortTransaction() : number
     getUrl(url: string) : Uint8Array
+    fetch(requestJson: string, bodyBufferId: number) : number
     persistenceGet(key: Uint8Array) : Uint8Array
     persistenceGetSubset(prefix: string) : { [key: string]: string }
+    persistenceScan(queryJson: string) : string
+    persistenceGetChanges(queryJson: string) : string
+    persistenceUseNamespace(namespace: string) : number
+    grantNamespace(namespace: string, grantee: string, access: string) : number
+    revokeNamespaceGrant(namespace: string, grantee: string) : number
+    getNamespaces() : string
+    scanNamespace(namespace: string, queryJson: string) : string
+    getNamespaceChanges(namespace: string, queryJson: string) : string
+    getDomainWriteVersion(domain: string) : number
+    isDomainBackwardCompatible(domain: string) : number
+    holdDomain(domain: string) : number
+    unholdDomain(domain: string) : number
+    registerDomainHandler(domain: string, functionName: string) : number
+    getDomains() : string
+    getSecret(name: string) : Uint8Array
+    setSecret(name: string, value: Uint8Array, functions: string) : number
+    deleteSecret(name: string) : number
+    getSecrets() : string
     printDebug(text: string) : number
     getTime(dest: Uint8Array) : number
     freeBuffer(bufferId: number) : number
     callFunction(name: string, startFunction: string, arguments: int[], mode: string, inputExchangeBufferId: number, outputExchangeBufferId: number, posixFileName: string, posixArguments: string[]) : number
+    callFunctionWithMounts(name: string, startFunction: string, arguments: int[], mode: string, inputExchangeBufferId: number, outputExchangeBufferId: number, posixFileName: string, posixArguments: string[], mounts: string) : number
+    callFunctionWithEngine(name: string, startFunction: string, arguments: int[], mode: string, inputExchangeBufferId: number, outputExchangeBufferId: number, posixFileName: string, posixArguments: string[], mounts: string, engine: string) : number
     exportDatabase() : Uint8Array
     betaWebProxy(proxySpecJson: string) : number
     isTrace() : number
     plugFilter(name: string, startFunction: string, data: string) : string
     unplugFilter(id: string) : number
+    createApiToken(name: string, role: string) : string
+    revokeApiToken(name: string) : number
+    getApiTokens() : string
 }
\ No newline at end of file
@@ -12,6 +12,8 @@
 WASM_IMPORT("core", "get_input_buffer_id") uint32_t get_input_buffer_id();
 WASM_IMPORT("core", "get_output_buffer_id") uint32_t get_output_buffer_id();
 WASM_IMPORT("core", "create_exchange_buffer") uint32_t create_exchange_buffer();
+// creates a buffer whose reader waits for the writer to write or close it, and whose writer waits when too much data is not read yet
+WASM_IMPORT("core", "create_streaming_exchange_buffer") uint32_t create_streaming_exchange_buffer();
 WASM_IMPORT("core", "write_exchange_buffer") uint32_t write_exchange_buffer(int buffer_id, const void *content_bytes, int content_length);
 WASM_IMPORT("core", "write_exchange_buffer_header") uint32_t write_exchange_buffer_header(int buffer_id, const char *name_string, int name_length, const char *value_string, int value_length);
 WASM_IMPORT("core", "write_exchange_buffer_status_code") uint32_t write_exchange_buffer_status_code(int buffer_id, int status_code);
@@ -19,6 +21,15 @@ WASM_IMPORT("core", "write_exchange_buffer_status_code") uint32_t write_exchange
 WASM_IMPORT("core", "read_exchange_buffer") uint32_t read_exchange_buffer(int buffer_id, void *result_bytes, int result_length);
 // returns the buffer headers in JSON format
 WASM_IMPORT("core", "read_exchange_buffer_headers") uint32_t read_exchange_buffer_headers(int buffer_id);
+WASM_IMPORT("core", "read_exchange_buffer_status_code") uint32_t read_exchange_buffer_status_code(int buffer_id);
+// returns an exchange buffer containing the next part written in the buffer, waiting for it if needed. The returned buffer is empty once the writer has finished
+WASM_IMPORT("core", "read_exchange_buffer_part") uint32_t read_exchange_buffer_part(int buffer_id);
+// returns 1 if the writer has closed the buffer, 0 otherwise
+WASM_IMPORT("core", "is_exchange_buffer_write_finished") uint32_t is_exchange_buffer_write_finished(int buffer_id);
+// signals that nothing more will be written in the buffer
+WASM_IMPORT("core", "close_exchange_buffer") uint32_t close_exchange_buffer(int buffer_id);
+// the host copies the source buffer to the target one in the background and closes the target when done, the function can exit meanwhile
+WASM_IMPORT("core", "forward_exchange_buffer") uint32_t forward_exchange_buffer(int source_buffer_id, int target_buffer_id);
 WASM_IMPORT("core", "base64_decode") uint32_t base64_decode(const char *encoded_string, int encoded_length);
 WASM_IMPORT("core", "base64_encode") uint32_t base64_encode(const void *input_bytes, int input_length);
 WASM_IMPORT("core", "register_blob_with_name") uint32_t register_blob_with_name(const char *name_string, int name_length, const char *content_type_string, int content_type_length, const void *content_bytes, int content_length);
@@ -30,18 +41,53 @@ WASM_IMPORT("core", "plug_file") uint32_t plug_file(const char *method_string, i
 WASM_IMPORT("core", "unplug_path") uint32_t unplug_path(const char *method_string, int method_length, const char *path_string, int path_length);
 WASM_IMPORT("core", "get_status") uint32_t get_status();
 WASM_IMPORT("core", "persistence_set") uint32_t persistence_set(const void *key_bytes, int key_length, const void *value_bytes, int value_length);
+WASM_IMPORT("core", "persistence_set_with_ttl") uint32_t persistence_set_with_ttl(const void *key_bytes, int key_length, const void *value_bytes, int value_length, int ttl_seconds);
+WASM_IMPORT("core", "persistence_delete") uint32_t persistence_delete(const void *key_bytes, int key_length);
+// gives the version of the key, 0 when it does not exist
+WASM_IMPORT("core", "persistence_get_version") uint32_t persistence_get_version(const void *key_bytes, int key_length, int64_t *result);
+// gives the new version of the key, 0 when it does not have the expected version
+WASM_IMPORT("core", "persistence_compare_and_set") uint32_t persistence_compare_and_set(const void *key_bytes, int key_length, int64_t expected_version, const void *value_bytes, int value_length, int64_t *result);
+// the writes following are kept until the commit, transactions are the batch api : several writes are applied at once
+WASM_IMPORT("core", "persistence_begin_transaction") uint32_t persistence_begin_transaction();
+// writes the transaction in one batch, gives the version of the commit, 0 when a key read changed
+WASM_IMPORT("core", "persistence_commit_transaction") uint32_t persistence_commit_transaction(int64_t *result);
+WASM_IMPORT("core", "persistence_abort_transaction") uint32_t persistence_abort_transaction();
 WASM_IMPORT("core", "get_url") uint32_t get_url(const char *url_string, int url_length);
+WASM_IMPORT("core", "fetch") uint32_t fetch(const char *request_json_string, int request_json_length, int body_buffer_id);
 WASM_IMPORT("core", "persistence_get") uint32_t persistence_get(const void *key_bytes, int key_length);
 WASM_IMPORT("core", "persistence_get_subset") uint32_t persistence_get_subset(const char *prefix_string, int prefix_length);
+WASM_IMPORT("core", "persistence_scan") uint32_t persistence_scan(const char *query_json_string, int query_json_length);
+WASM_IMPORT("core", "persistence_get_changes") uint32_t persistence_get_changes(const char *query_json_string, int query_json_length);
+WASM_IMPORT("core", "persistence_use_namespace") uint32_t persistence_use_namespace(const char *namespace_string, int namespace_length);
+WASM_IMPORT("core", "grant_namespace") uint32_t grant_namespace(const char *namespace_string, int namespace_length, const char *grantee_string, int grantee_length, const char *access_string, int access_length);
+WASM_IMPORT("core", "revoke_namespace_grant") uint32_t revoke_namespace_grant(const char *namespace_string, int namespace_length, const char *grantee_string, int grantee_length);
+WASM_IMPORT("core", "get_namespaces") uint32_t get_namespaces();
+WASM_IMPORT("core", "scan_namespace") uint32_t scan_namespace(const char *namespace_string, int namespace_length, const char *query_json_string, int query_json_length);
+WASM_IMPORT("core", "get_namespace_changes") uint32_t get_namespace_changes(const char *namespace_string, int namespace_length, const char *query_json_string, int query_json_length);
+WASM_IMPORT("core", "get_domain_write_version") uint32_t get_domain_write_version(const char *domain_string, int domain_length);
+WASM_IMPORT("core", "is_domain_backward_compatible") uint32_t is_domain_backward_compatible(const char *domain_string, int domain_length);
+WASM_IMPORT("core", "hold_domain") uint32_t hold_domain(const char *domain_string, int domain_length);
+WASM_IMPORT("core", "unhold_domain") uint32_t unhold_domain(const char *domain_string, int domain_length);
+WASM_IMPORT("core", "register_domain_handler") uint32_t register_domain_handler(const char *domain_string, int domain_length, const char *function_name_string, int function_name_length);
+WASM_IMPORT("core", "get_domains") uint32_t get_domains();
+WASM_IMPORT("core", "get_secret") uint32_t get_secret(const char *name_string, int name_length);
+WASM_IMPORT("core", "set_secret") uint32_t set_secret(const char *name_string, int name_length, const void *value_bytes, int value_length, const char *functions_string, int functions_length);
+WASM_IMPORT("core", "delete_secret") uint32_t delete_secret(const char *name_string, int name_length);
+WASM_IMPORT("core", "get_secrets") uint32_t get_secrets();
 WASM_IMPORT("core", "print_debug") uint32_t print_debug(const char *text_string, int text_length);
 WASM_IMPORT("core", "get_time") uint32_t get_time(const void *dest_bytes, int dest_length);
 WASM_IMPORT("core", "free_buffer") uint32_t free_buffer(int bufferId);
 WASM_IMPORT("core", "call_function") uint32_t call_function(const char *name_string, int name_length, const char *start_function_string, int start_function_length, const void *arguments_int_array, int arguments_length, const char *mode_string, int mode_length, int input_exchange_buffer_id, int output_exchange_buffer_id, const char *posix_file_name_string, int posix_file_name_length, const void *posix_arguments_string_array, int posix_arguments_length);
+WASM_IMPORT("core", "call_function_with_mounts") uint32_t call_function_with_mounts(const char *name_string, int name_length, const char *start_function_string, int start_function_length, const void *arguments_int_array, int arguments_length, const char *mode_string, int mode_length, int input_exchange_buffer_id, int output_exchange_buffer_id, const char *posix_file_name_string, int posix_file_name_length, const void *posix_arguments_string_array, int posix_arguments_length, const char *mounts_string, int mounts_length);
+WASM_IMPORT("core", "call_function_with_engine") uint32_t call_function_with_engine(const char *name_string, int name_length, const char *start_function_string, int start_function_length, const void *arguments_int_array, int arguments_length, const char *mode_string, int mode_length, int input_exchange_buffer_id, int output_exchange_buffer_id, const char *posix_file_name_string, int posix_file_name_length, const void *posix_arguments_string_array, int posix_arguments_length, const char *mounts_string, int mounts_length, const char *engine_string, int engine_length);
 WASM_IMPORT("core", "export_database") uint32_t export_database();
 WASM_IMPORT("core", "beta_web_proxy") uint32_t beta_web_proxy(const char *proxy_spec_json_string, int proxy_spec_json_length);
 WASM_IMPORT("core", "is_trace") uint32_t is_trace();
 WASM_IMPORT("core", "plug_filter") uint32_t plug_filter(const char *name_string, int name_length, const char *start_function_string, int start_function_length, const char *data_string, int data_length);
 WASM_IMPORT("core", "unplug_filter") uint32_t unplug_filter(const char *id_string, int id_length);
+WASM_IMPORT("core", "create_api_token") uint32_t create_api_token(const char *name_string, int name_length, const char *role_string, int role_length);
+WASM_IMPORT("core", "revoke_api_token") uint32_t revoke_api_token(const char *name_string, int name_length);
+WASM_IMPORT("core", "get_api_tokens") uint32_t get_api_tokens();
 
 #endif
     
\ No newline at end of file
@@ -21,6 +21,8 @@ pub mod raw {
         pub fn get_input_buffer_id() -> u32;
         pub fn get_output_buffer_id() -> u32;
         pub fn create_exchange_buffer() -> u32;
+        // creates a buffer whose reader waits for the writer to write or close it, and whose writer waits when too much data is not read yet
+        pub fn create_streaming_exchange_buffer() -> u32;
         pub fn write_exchange_buffer(buffer_id:u32, content_bytes: *const u8, content_length: u32) -> u32;
         pub fn write_exchange_buffer_header(buffer_id:u32, name_string: *const u8, name_length: u32, value_string: *const u8, value_length: u32) -> u32;
         pub fn write_exchange_buffer_status_code(buffer_id:u32, status_code:u32) -> u32;
@@ -28,6 +30,15 @@ pub mod raw {
         pub fn read_exchange_buffer(buffer_id:u32, result_bytes: *mut u8, result_length: u32) -> u32;
         // returns the buffer headers in JSON format
         pub fn read_exchange_buffer_headers(buffer_id:u32) -> u32;
+        pub fn read_exchange_buffer_status_code(buffer_id:u32) -> u32;
+        // returns an exchange buffer containing the next part written in the buffer, waiting for it if needed. The returned buffer is empty once the writer has finished
+        pub fn read_exchange_buffer_part(buffer_id:u32) -> u32;
+        // returns 1 if the writer has closed the buffer, 0 otherwise
+        pub fn is_exchange_buffer_write_finished(buffer_id:u32) -> u32;
+        // signals that nothing more will be written in the buffer
+        pub fn close_exchange_buffer(buffer_id:u32) -> u32;
+        // the host copies the source buffer to the target one in the background and closes the target when done, the function can exit meanwhile
+        pub fn forward_exchange_buffer(source_buffer_id:u32, target_buffer_id:u32) -> u32;
         pub fn base64_decode(encoded_string: *const u8, encoded_length: u32) -> u32;
         pub fn base64_encode(input_bytes: *const u8, input_length: u32) -> u32;
         pub fn register_blob_with_name(name_string: *const u8, name_length: u32, content_type_string: *const u8, content_type_length: u32, content_bytes: *const u8, content_length: u32) -> u32;
@@ -39,18 +50,53 @@ pub mod raw {
         pub fn unplug_path(method_string: *const u8, method_length: u32, path_string: *const u8, path_length: u32) -> u32;
         pub fn get_status() -> u32;
         pub fn persistence_set(key_bytes: *const u8, key_length: u32, value_bytes: *const u8, value_length: u32) -> u32;
+        pub fn persistence_set_with_ttl(key_bytes: *const u8, key_length: u32, value_bytes: *const u8, value_length: u32, ttl_seconds:u32) -> u32;
+        pub fn persistence_delete(key_bytes: *const u8, key_length: u32) -> u32;
+        // gives the version of the key, 0 when it does not exist
+        pub fn persistence_get_version(key_bytes: *const u8, key_length: u32, result: *mut i64) -> u32;
+        // gives the new version of the key, 0 when it does not have the expected version
+        pub fn persistence_compare_and_set(key_bytes: *const u8, key_length: u32, expected_version: i64, value_bytes: *const u8, value_length: u32, result: *mut i64) -> u32;
+        // the writes following are kept until the commit, transactions are the batch api : several writes are applied at once
+        pub fn persistence_begin_transaction() -> u32;
+        // writes the transaction in one batch, gives the version of the commit, 0 when a key read changed
+        pub fn persistence_commit_transaction(result: *mut i64) -> u32;
+        pub fn persistence_abort_transaction() -> u32;
         pub fn get_url(url_string: *const u8, url_length: u32) -> u32;
+        pub fn fetch(request_json_string: *const u8, request_json_length: u32, body_buffer_id:u32) -> u32;
         pub fn persistence_get(key_bytes: *const u8, key_length: u32) -> u32;
         pub fn persistence_get_subset(prefix_string: *const u8, prefix_length: u32) -> u32;
+        pub fn persistence_scan(query_json_string: *const u8, query_json_length: u32) -> u32;
+        pub fn persistence_get_changes(query_json_string: *const u8, query_json_length: u32) -> u32;
+        pub fn persistence_use_namespace(namespace_string: *const u8, namespace_length: u32) -> u32;
+        pub fn grant_namespace(namespace_string: *const u8, namespace_length: u32, grantee_string: *const u8, grantee_length: u32, access_string: *const u8, access_length: u32) -> u32;
+        pub fn revoke_namespace_grant(namespace_string: *const u8, namespace_length: u32, grantee_string: *const u8, grantee_length: u32) -> u32;
+        pub fn get_namespaces() -> u32;
+        pub fn scan_namespace(namespace_string: *const u8, namespace_length: u32, query_json_string: *const u8, query_json_length: u32) -> u32;
+        pub fn get_namespace_changes(namespace_string: *const u8, namespace_length: u32, query_json_string: *const u8, query_json_length: u32) -> u32;
+        pub fn get_domain_write_version(domain_string: *const u8, domain_length: u32) -> u32;
+        pub fn is_domain_backward_compatible(domain_string: *const u8, domain_length: u32) -> u32;
+        pub fn hold_domain(domain_string: *const u8, domain_length: u32) -> u32;
+        pub fn unhold_domain(domain_string: *const u8, domain_length: u32) -> u32;
+        pub fn register_domain_handler(domain_string: *const u8, domain_length: u32, function_name_string: *const u8, function_name_length: u32) -> u32;
+        pub fn get_domains() -> u32;
+        pub fn get_secret(name_string: *const u8, name_length: u32) -> u32;
+        pub fn set_secret(name_string: *const u8, name_length: u32, value_bytes: *const u8, value_length: u32, functions_string: *const u8, functions_length: u32) -> u32;
+        pub fn delete_secret(name_string: *const u8, name_length: u32) -> u32;
+        pub fn get_secrets() -> u32;
         pub fn print_debug(text_string: *const u8, text_length: u32) -> u32;
         pub fn get_time(dest_bytes: *const u8, dest_length: u32) -> u32;
         pub fn free_buffer(bufferId:u32) -> u32;
         pub fn call_function(name_string: *const u8, name_length: u32, start_function_string: *const u8, start_function_length: u32, arguments_int_array: *const u32, arguments_length: u32, mode_string: *const u8, mode_length: u32, input_exchange_buffer_id:u32, output_exchange_buffer_id:u32, posix_file_name_string: *const u8, posix_file_name_length: u32, posix_arguments_string_array: *const u8, posix_arguments_length: u32) -> u32;
+        pub fn call_function_with_mounts(name_string: *const u8, name_length: u32, start_function_string: *const u8, start_function_length: u32, arguments_int_array: *const u32, arguments_length: u32, mode_string: *const u8, mode_length: u32, input_exchange_buffer_id:u32, output_exchange_buffer_id:u32, posix_file_name_string: *const u8, posix_file_name_length: u32, posix_arguments_string_array: *const u8, posix_arguments_length: u32, mounts_string: *const u8, mounts_length: u32) -> u32;
+        pub fn call_function_with_engine(name_string: *const u8, name_length: u32, start_function_string: *const u8, start_function_length: u32, arguments_int_array: *const u32, arguments_length: u32, mode_string: *const u8, mode_length: u32, input_exchange_buffer_id:u32, output_exchange_buffer_id:u32, posix_file_name_string: *const u8, posix_file_name_length: u32, posix_arguments_string_array: *const u8, posix_arguments_length: u32, mounts_string: *const u8, mounts_length: u32, engine_string: *const u8, engine_length: u32) -> u32;
         pub fn export_database() -> u32;
         pub fn beta_web_proxy(proxy_spec_json_string: *const u8, proxy_spec_json_length: u32) -> u32;
         pub fn is_trace() -> u32;
         pub fn plug_filter(name_string: *const u8, name_length: u32, start_function_string: *const u8, start_function_length: u32, data_string: *const u8, data_length: u32) -> u32;
         pub fn unplug_filter(id_string: *const u8, id_length: u32) -> u32;
+        pub fn create_api_token(name_string: *const u8, name_length: u32, role_string: *const u8, role_length: u32) -> u32;
+        pub fn revoke_api_token(name_string: *const u8, name_length: u32) -> u32;
+        pub fn get_api_tokens() -> u32;
 
     }
 }
@@ -67,6 +113,10 @@ pub fn create_exchange_buffer() -> u32 {
     unsafe { raw::create_exchange_buffer() }
 }
 
+pub fn create_streaming_exchange_buffer() -> u32 {
+    unsafe { raw::create_streaming_exchange_buffer() }
+}
+
 pub fn write_exchange_buffer(buffer_id:u32, content: &[u8]) -> u32 {
     unsafe { raw::write_exchange_buffer(buffer_id, content.as_ptr(), content.len() as u32) }
 }
@@ -131,6 +181,41 @@ pub fn read_exchange_buffer_headers(buffer_id:u32) -> Result<HashMap<String,Stri
     }
 }
 
+pub fn read_exchange_buffer_status_code(buffer_id:u32) -> u32 {
+    unsafe { raw::read_exchange_buffer_status_code(buffer_id) }
+}
+
+pub fn read_exchange_buffer_part(buffer_id:u32) -> Result<Vec<u8>, u32> {
+    let result_buffer_id = unsafe { raw::read_exchange_buffer_part(buffer_id) };
+    if result_buffer_id == 0xffff {
+        Err(1)
+    }
+    else {
+        let result = read_exchange_buffer(result_buffer_id);
+        match result {
+            Ok(result) => {
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(2)
+            },
+        }
+    }
+}
+
+pub fn is_exchange_buffer_write_finished(buffer_id:u32) -> u32 {
+    unsafe { raw::is_exchange_buffer_write_finished(buffer_id) }
+}
+
+pub fn close_exchange_buffer(buffer_id:u32) -> u32 {
+    unsafe { raw::close_exchange_buffer(buffer_id) }
+}
+
+pub fn forward_exchange_buffer(source_buffer_id:u32, target_buffer_id:u32) -> u32 {
+    unsafe { raw::forward_exchange_buffer(source_buffer_id, target_buffer_id) }
+}
+
 pub fn base64_decode(encoded: &str) -> Result<Vec<u8>, u32> {
     let result_buffer_id = unsafe { raw::base64_decode(encoded.as_bytes().as_ptr(), encoded.as_bytes().len() as u32) };
     if result_buffer_id == 0xffff {
@@ -286,6 +371,40 @@ pub fn persistence_set(key: &[u8], value: &[u8]) -> u32 {
     unsafe { raw::persistence_set(key.as_ptr(), key.len() as u32, value.as_ptr(), value.len() as u32) }
 }
 
+pub fn persistence_set_with_ttl(key: &[u8], value: &[u8], ttl_seconds:u32) -> u32 {
+    unsafe { raw::persistence_set_with_ttl(key.as_ptr(), key.len() as u32, value.as_ptr(), value.len() as u32, ttl_seconds) }
+}
+
+pub fn persistence_delete(key: &[u8]) -> u32 {
+    unsafe { raw::persistence_delete(key.as_ptr(), key.len() as u32) }
+}
+
+pub fn persistence_get_version(key: &[u8]) -> i64 {
+    let mut result: i64 = 0;
+    unsafe { raw::persistence_get_version(key.as_ptr(), key.len() as u32, &mut result) };
+    result
+}
+
+pub fn persistence_compare_and_set(key: &[u8], expected_version: i64, value: &[u8]) -> i64 {
+    let mut result: i64 = 0;
+    unsafe { raw::persistence_compare_and_set(key.as_ptr(), key.len() as u32, expected_version, value.as_ptr(), value.len() as u32, &mut result) };
+    result
+}
+
+pub fn persistence_begin_transaction() -> u32 {
+    unsafe { raw::persistence_begin_transaction() }
+}
+
+pub fn persistence_commit_transaction() -> i64 {
+    let mut result: i64 = 0;
+    unsafe { raw::persistence_commit_transaction(&mut result) };
+    result
+}
+
+pub fn persistence_abort_transaction() -> u32 {
+    unsafe { raw::persistence_abort_transaction() }
+}
+
 pub fn get_url(url: &str) -> Result<Vec<u8>, u32> {
     let result_buffer_id = unsafe { raw::get_url(url.as_bytes().as_ptr(), url.as_bytes().len() as u32) };
     if result_buffer_id == 0xffff {
@@ -305,6 +424,10 @@ pub fn get_url(url: &str) -> Result<Vec<u8>, u32> {
     }
 }
 
+pub fn fetch(request_json: &str, body_buffer_id:u32) -> u32 {
+    unsafe { raw::fetch(request_json.as_bytes().as_ptr(), request_json.as_bytes().len() as u32, body_buffer_id) }
+}
+
 pub fn persistence_get(key: &[u8]) -> Result<Vec<u8>, u32> {
     let result_buffer_id = unsafe { raw::persistence_get(key.as_ptr(), key.len() as u32) };
     if result_buffer_id == 0xffff {
@@ -368,6 +491,205 @@ pub fn persistence_get_subset(prefix: &str) -> Result<HashMap<String,String>, u3
     }
 }
 
+pub fn persistence_scan(query_json: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::persistence_scan(query_json.as_bytes().as_ptr(), query_json.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn persistence_get_changes(query_json: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::persistence_get_changes(query_json.as_bytes().as_ptr(), query_json.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn persistence_use_namespace(namespace: &str) -> u32 {
+    unsafe { raw::persistence_use_namespace(namespace.as_bytes().as_ptr(), namespace.as_bytes().len() as u32) }
+}
+
+pub fn grant_namespace(namespace: &str, grantee: &str, access: &str) -> u32 {
+    unsafe { raw::grant_namespace(namespace.as_bytes().as_ptr(), namespace.as_bytes().len() as u32, grantee.as_bytes().as_ptr(), grantee.as_bytes().len() as u32, access.as_bytes().as_ptr(), access.as_bytes().len() as u32) }
+}
+
+pub fn revoke_namespace_grant(namespace: &str, grantee: &str) -> u32 {
+    unsafe { raw::revoke_namespace_grant(namespace.as_bytes().as_ptr(), namespace.as_bytes().len() as u32, grantee.as_bytes().as_ptr(), grantee.as_bytes().len() as u32) }
+}
+
+pub fn get_namespaces() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_namespaces() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn scan_namespace(namespace: &str, query_json: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::scan_namespace(namespace.as_bytes().as_ptr(), namespace.as_bytes().len() as u32, query_json.as_bytes().as_ptr(), query_json.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn get_namespace_changes(namespace: &str, query_json: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_namespace_changes(namespace.as_bytes().as_ptr(), namespace.as_bytes().len() as u32, query_json.as_bytes().as_ptr(), query_json.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn get_domain_write_version(domain: &str) -> u32 {
+    unsafe { raw::get_domain_write_version(domain.as_bytes().as_ptr(), domain.as_bytes().len() as u32) }
+}
+
+pub fn is_domain_backward_compatible(domain: &str) -> u32 {
+    unsafe { raw::is_domain_backward_compatible(domain.as_bytes().as_ptr(), domain.as_bytes().len() as u32) }
+}
+
+pub fn hold_domain(domain: &str) -> u32 {
+    unsafe { raw::hold_domain(domain.as_bytes().as_ptr(), domain.as_bytes().len() as u32) }
+}
+
+pub fn unhold_domain(domain: &str) -> u32 {
+    unsafe { raw::unhold_domain(domain.as_bytes().as_ptr(), domain.as_bytes().len() as u32) }
+}
+
+pub fn register_domain_handler(domain: &str, function_name: &str) -> u32 {
+    unsafe { raw::register_domain_handler(domain.as_bytes().as_ptr(), domain.as_bytes().len() as u32, function_name.as_bytes().as_ptr(), function_name.as_bytes().len() as u32) }
+}
+
+pub fn get_domains() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_domains() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn get_secret(name: &str) -> Result<Vec<u8>, u32> {
+    let result_buffer_id = unsafe { raw::get_secret(name.as_bytes().as_ptr(), name.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(1)
+    }
+    else {
+        let result = read_exchange_buffer(result_buffer_id);
+        match result {
+            Ok(result) => {
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(2)
+            },
+        }
+    }
+}
+
+pub fn set_secret(name: &str, value: &[u8], functions: &str) -> u32 {
+    unsafe { raw::set_secret(name.as_bytes().as_ptr(), name.as_bytes().len() as u32, value.as_ptr(), value.len() as u32, functions.as_bytes().as_ptr(), functions.as_bytes().len() as u32) }
+}
+
+pub fn delete_secret(name: &str) -> u32 {
+    unsafe { raw::delete_secret(name.as_bytes().as_ptr(), name.as_bytes().len() as u32) }
+}
+
+pub fn get_secrets() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_secrets() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
 pub fn print_debug(text: &str) -> u32 {
     unsafe { raw::print_debug(text.as_bytes().as_ptr(), text.as_bytes().len() as u32) }
 }
@@ -384,6 +706,14 @@ pub fn call_function(name: &str, start_function: &str, arguments: &[u32], mode:
     unsafe { raw::call_function(name.as_bytes().as_ptr(), name.as_bytes().len() as u32, start_function.as_bytes().as_ptr(), start_function.as_bytes().len() as u32, arguments.as_ptr(), arguments.len() as u32, mode.as_bytes().as_ptr(), mode.as_bytes().len() as u32, input_exchange_buffer_id, output_exchange_buffer_id, posix_file_name.as_bytes().as_ptr(), posix_file_name.as_bytes().len() as u32, std::ptr::null(), 0) }
 }
 
+pub fn call_function_with_mounts(name: &str, start_function: &str, arguments: &[u32], mode: &str, input_exchange_buffer_id:u32, output_exchange_buffer_id:u32, posix_file_name: &str, posix_arguments: &[&str], mounts: &str) -> u32 {
+    unsafe { raw::call_function_with_mounts(name.as_bytes().as_ptr(), name.as_bytes().len() as u32, start_function.as_bytes().as_ptr(), start_function.as_bytes().len() as u32, arguments.as_ptr(), arguments.len() as u32, mode.as_bytes().as_ptr(), mode.as_bytes().len() as u32, input_exchange_buffer_id, output_exchange_buffer_id, posix_file_name.as_bytes().as_ptr(), posix_file_name.as_bytes().len() as u32, std::ptr::null(), 0, mounts.as_bytes().as_ptr(), mounts.as_bytes().len() as u32) }
+}
+
+pub fn call_function_with_engine(name: &str, start_function: &str, arguments: &[u32], mode: &str, input_exchange_buffer_id:u32, output_exchange_buffer_id:u32, posix_file_name: &str, posix_arguments: &[&str], mounts: &str, engine: &str) -> u32 {
+    unsafe { raw::call_function_with_engine(name.as_bytes().as_ptr(), name.as_bytes().len() as u32, start_function.as_bytes().as_ptr(), start_function.as_bytes().len() as u32, arguments.as_ptr(), arguments.len() as u32, mode.as_bytes().as_ptr(), mode.as_bytes().len() as u32, input_exchange_buffer_id, output_exchange_buffer_id, posix_file_name.as_bytes().as_ptr(), posix_file_name.as_bytes().len() as u32, std::ptr::null(), 0, mounts.as_bytes().as_ptr(), mounts.as_bytes().len() as u32, engine.as_bytes().as_ptr(), engine.as_bytes().len() as u32) }
+}
+
 pub fn export_database() -> Result<Vec<u8>, u32> {
     let result_buffer_id = unsafe { raw::export_database() };
     if result_buffer_id == 0xffff {
@@ -435,3 +765,47 @@ pub fn unplug_filter(id: &str) -> u32 {
     unsafe { raw::unplug_filter(id.as_bytes().as_ptr(), id.as_bytes().len() as u32) }
 }
 
+pub fn create_api_token(name: &str, role: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::create_api_token(name.as_bytes().as_ptr(), name.as_bytes().len() as u32, role.as_bytes().as_ptr(), role.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn revoke_api_token(name: &str) -> u32 {
+    unsafe { raw::revoke_api_token(name.as_bytes().as_ptr(), name.as_bytes().len() as u32) }
+}
+
+pub fn get_api_tokens() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_api_tokens() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
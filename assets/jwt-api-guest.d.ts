@@ -9,4 +9,10 @@
 //
 declare function requireApi(name: "jwt") : {
     verifyJwt(jwt: string) : string
+    addTrustProvider(configurationJson: string) : number
+    removeTrustProvider(iss: string) : number
+    getTrustProviders() : string
+    signJwt(claimsJson: string) : string
+    rotateSigningKey(algorithm: string) : string
+    getJwks() : string
 }
\ No newline at end of file
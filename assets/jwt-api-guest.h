@@ -10,6 +10,12 @@
 #define WASM_CONSTRUCTOR              __attribute__((constructor))
 
 WASM_IMPORT("jwt", "verify_jwt") uint32_t verify_jwt(const char *jwt_string, int jwt_length);
+WASM_IMPORT("jwt", "add_trust_provider") uint32_t add_trust_provider(const char *configuration_json_string, int configuration_json_length);
+WASM_IMPORT("jwt", "remove_trust_provider") uint32_t remove_trust_provider(const char *iss_string, int iss_length);
+WASM_IMPORT("jwt", "get_trust_providers") uint32_t get_trust_providers();
+WASM_IMPORT("jwt", "sign_jwt") uint32_t sign_jwt(const char *claims_json_string, int claims_json_length);
+WASM_IMPORT("jwt", "rotate_signing_key") uint32_t rotate_signing_key(const char *algorithm_string, int algorithm_length);
+WASM_IMPORT("jwt", "get_jwks") uint32_t get_jwks();
 
 #endif
     
\ No newline at end of file
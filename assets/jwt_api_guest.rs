@@ -19,6 +19,12 @@ pub mod raw {
     #[link(wasm_import_module = "jwt")]
     extern {
         pub fn verify_jwt(jwt_string: *const u8, jwt_length: u32) -> u32;
+        pub fn add_trust_provider(configuration_json_string: *const u8, configuration_json_length: u32) -> u32;
+        pub fn remove_trust_provider(iss_string: *const u8, iss_length: u32) -> u32;
+        pub fn get_trust_providers() -> u32;
+        pub fn sign_jwt(claims_json_string: *const u8, claims_json_length: u32) -> u32;
+        pub fn rotate_signing_key(algorithm_string: *const u8, algorithm_length: u32) -> u32;
+        pub fn get_jwks() -> u32;
 
     }
 }
@@ -43,3 +49,91 @@ pub fn verify_jwt(jwt: &str) -> Result<String, u32> {
     }
 }
 
+pub fn add_trust_provider(configuration_json: &str) -> u32 {
+    unsafe { raw::add_trust_provider(configuration_json.as_bytes().as_ptr(), configuration_json.as_bytes().len() as u32) }
+}
+
+pub fn remove_trust_provider(iss: &str) -> u32 {
+    unsafe { raw::remove_trust_provider(iss.as_bytes().as_ptr(), iss.as_bytes().len() as u32) }
+}
+
+pub fn get_trust_providers() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_trust_providers() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn sign_jwt(claims_json: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::sign_jwt(claims_json.as_bytes().as_ptr(), claims_json.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn rotate_signing_key(algorithm: &str) -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::rotate_signing_key(algorithm.as_bytes().as_ptr(), algorithm.as_bytes().len() as u32) };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
+pub fn get_jwks() -> Result<String, u32> {
+    let result_buffer_id = unsafe { raw::get_jwks() };
+    if result_buffer_id == 0xffff {
+        Err(3)
+    }
+    else {
+        let result_buffer = read_exchange_buffer(result_buffer_id);
+        match result_buffer {
+            Ok(result_buffer) => {
+                let result = String::from_utf8(result_buffer).unwrap();
+                //free_buffer(result_buffer_id);
+                Ok(result)
+            },
+            Err(err) => {
+                Err(4)
+            },
+        }
+    }
+}
+
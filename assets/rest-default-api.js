@@ -7,6 +7,8 @@
  * - plus a blob as a function
  * - plug a blob as a file
  * - call a function
+ * - manage the api tokens, the secrets, the persistence namespaces, the JWT trust providers and signing keys
+ * - publish the JWKS of the cluster
  * 
  * It is mainly used by the CLI program.
  * It is normally bound to the cluster web endpoint during startup (in main.go)
@@ -28,7 +30,7 @@ function getInputRequest() {
 function plugFunction() {
     var req = getInputRequest()
 
-    moc.plugFunction(
+    var result = moc.plugFunction(
         req.method,
         req.path,
         req.name,
@@ -38,7 +40,7 @@ function plugFunction() {
     )
 
     moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
-        status: true,
+        status: result === 0,
     }))
 
     return 200
@@ -47,7 +49,7 @@ function plugFunction() {
 function plugFile() {
     var req = getInputRequest()
 
-    moc.plugFile(
+    var result = moc.plugFile(
         req.method,
         req.path,
         req.name,
@@ -55,7 +57,7 @@ function plugFile() {
     )
 
     moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
-        status: true,
+        status: result === 0,
     }))
 
     return 200
@@ -140,16 +142,44 @@ function callFunction() {
 
     var outputExchangeBufferId = moc.createExchangeBuffer()
 
-    var result = moc.callFunction(
-        req.name,
-        req.start_function || "_start",
-        req.arguments || [],
-        req.mode || "direct",
-        inputExchangeBufferId,
-        outputExchangeBufferId,
-        req.posix_file_name || "",
-        req.posix_arguments || [""]
-    )
+    var result
+    if (req.engine) {
+        result = moc.callFunctionWithEngine(
+            req.name,
+            req.start_function || "_start",
+            req.arguments || [],
+            req.mode || "direct",
+            inputExchangeBufferId,
+            outputExchangeBufferId,
+            req.posix_file_name || "",
+            req.posix_arguments || [""],
+            req.mounts || "",
+            req.engine
+        )
+    } else if (req.mounts) {
+        result = moc.callFunctionWithMounts(
+            req.name,
+            req.start_function || "_start",
+            req.arguments || [],
+            req.mode || "direct",
+            inputExchangeBufferId,
+            outputExchangeBufferId,
+            req.posix_file_name || "",
+            req.posix_arguments || [""],
+            req.mounts
+        )
+    } else {
+        result = moc.callFunction(
+            req.name,
+            req.start_function || "_start",
+            req.arguments || [],
+            req.mode || "direct",
+            inputExchangeBufferId,
+            outputExchangeBufferId,
+            req.posix_file_name || "",
+            req.posix_arguments || [""]
+        )
+    }
 
     var response = {
         status: true,
@@ -169,4 +199,308 @@ function exportDatabase() {
 
     moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
     moc.writeExchangeBuffer(moc.getOutputBufferId(), exp)
-}
\ No newline at end of file
+}
+
+function getStatus() {
+    moc.writeExchangeBufferHeader(moc.getOutputBufferId(), "content-type", "application/json")
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), moc.getStatus())
+}
+
+function createApiToken() {
+    var req = getInputRequest()
+
+    var token = moc.createApiToken(req.name, req.role)
+    if (!token) {
+        moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 400)
+        moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+            status: false
+        }))
+        return
+    }
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        token: token
+    }))
+}
+
+function revokeApiToken() {
+    var headers = moc.readExchangeBufferHeaders(moc.getInputBufferId())
+    var name = headers["x-moc-path-param-name"]
+
+    var result = moc.revokeApiToken(name)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 404)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function getApiTokens() {
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        tokens: JSON.parse(moc.getApiTokens())
+    }))
+}
+
+function getSecrets() {
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        secrets: JSON.parse(moc.getSecrets())
+    }))
+}
+
+function setSecret() {
+    var req = getInputRequest()
+
+    var functions = Array.isArray(req.functions) ? req.functions.join(",") : (req.functions || "")
+    var result = moc.setSecret(req.name, req.value || "", functions)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 400)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function deleteSecret() {
+    var headers = moc.readExchangeBufferHeaders(moc.getInputBufferId())
+    var name = headers["x-moc-path-param-name"]
+
+    var result = moc.deleteSecret(name)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 404)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function getNamespaces() {
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        namespaces: JSON.parse(moc.getNamespaces())
+    }))
+}
+
+function getQueryParameters() {
+    var headers = moc.readExchangeBufferHeaders(moc.getInputBufferId())
+    var query = headers["x-moc-url-query"] || ""
+
+    var parameters = {}
+    query.split("&").forEach(function (part) {
+        if (!part)
+            return
+        var equal = part.indexOf("=")
+        var name = equal < 0 ? part : part.substring(0, equal)
+        var value = equal < 0 ? "" : part.substring(equal + 1)
+        parameters[decodeURIComponent(name.replace(/\+/g, " "))] = decodeURIComponent(value.replace(/\+/g, " "))
+    })
+
+    return parameters
+}
+
+function scanNamespace() {
+    var parameters = getQueryParameters()
+
+    var result = moc.scanNamespace(parameters.namespace || "", JSON.stringify({
+        prefix: parameters.prefix,
+        start: parameters.start,
+        end: parameters.end,
+        limit: parameters.limit ? parseInt(parameters.limit) : 0,
+        reverse: parameters.reverse === "true",
+        keys_only: parameters.keys_only === "true",
+        encoding: parameters.encoding,
+        cursor: parameters.cursor
+    }))
+    if (!result) {
+        moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 400)
+        moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+            status: false
+        }))
+        return
+    }
+
+    var page = JSON.parse(result)
+    page.status = true
+
+    moc.writeExchangeBufferHeader(moc.getOutputBufferId(), "content-type", "application/json")
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify(page))
+}
+
+/*
+    Changes of a namespace, after the "since" sequence (the current one by default) and under a
+    prefix. Served as server-sent events when accepted by the client, as messages on a web socket,
+    or as a long poll waiting at most "timeout" seconds for changes.
+*/
+function watchNamespace() {
+    var parameters = getQueryParameters()
+    var headers = moc.readExchangeBufferHeaders(moc.getInputBufferId())
+    var outputId = moc.getOutputBufferId()
+
+    var namespace = parameters.namespace || ""
+    var query = {
+        since: parameters.since ? parseInt(parameters.since) : -1,
+        prefix: parameters.prefix,
+        limit: parameters.limit ? parseInt(parameters.limit) : 0,
+        encoding: parameters.encoding,
+        timeout: parameters.timeout ? parseInt(parameters.timeout) : 30
+    }
+
+    var eventStream = (headers["accept"] || "").indexOf("text/event-stream") >= 0
+    var webSocket = (headers["upgrade"] || "").toLowerCase() === "websocket"
+
+    if (!eventStream && !webSocket) {
+        var result = moc.getNamespaceChanges(namespace, JSON.stringify(query))
+        if (!result) {
+            moc.writeExchangeBufferStatusCode(outputId, 400)
+            moc.writeExchangeBuffer(outputId, JSON.stringify({
+                status: false
+            }))
+            return
+        }
+
+        var page = JSON.parse(result)
+        page.status = true
+
+        moc.writeExchangeBufferHeader(outputId, "content-type", "application/json")
+        moc.writeExchangeBufferStatusCode(outputId, 200)
+        moc.writeExchangeBuffer(outputId, JSON.stringify(page))
+        return
+    }
+
+    if (eventStream) {
+        // a reconnecting client resumes after the last event it received
+        if (headers["last-event-id"])
+            query.since = parseInt(headers["last-event-id"])
+
+        moc.writeExchangeBufferHeader(outputId, "content-type", "text/event-stream")
+        moc.writeExchangeBufferHeader(outputId, "cache-control", "no-cache")
+        moc.writeExchangeBufferStatusCode(outputId, 200)
+    }
+
+    // runs until the client is gone, empty pages keep the connection alive
+    while (true) {
+        var result = moc.getNamespaceChanges(namespace, JSON.stringify(query))
+        if (!result)
+            return
+
+        var page = JSON.parse(result)
+        var message = JSON.stringify(page)
+        if (eventStream)
+            message = page.changes.length || page.truncated ? "id: " + page.sequence + "\ndata: " + message + "\n\n" : ": keep-alive\n\n"
+
+        if (moc.writeExchangeBuffer(outputId, message) < 0)
+            return
+
+        query.since = page.sequence
+    }
+}
+
+function grantNamespace() {
+    var req = getInputRequest()
+
+    var result = moc.grantNamespace(req.namespace, req.grantee, req.access || "read")
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 400)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function revokeNamespaceGrant() {
+    var req = getInputRequest()
+
+    var result = moc.revokeNamespaceGrant(req.namespace, req.grantee)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 404)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function getDomains() {
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        domains: JSON.parse(moc.getDomains())
+    }))
+}
+
+function registerDomainHandler() {
+    var req = getInputRequest()
+
+    var result = moc.registerDomainHandler(req.domain, req.function)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 400)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function getTrustProviders() {
+    var jwt = requireApi('jwt')
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        trust_providers: JSON.parse(jwt.getTrustProviders())
+    }))
+}
+
+function addTrustProvider() {
+    var jwt = requireApi('jwt')
+    var req = getInputRequest()
+
+    var result = jwt.addTrustProvider(JSON.stringify(req))
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 400)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function removeTrustProvider() {
+    var jwt = requireApi('jwt')
+    var req = getInputRequest()
+
+    var result = jwt.removeTrustProvider(req.iss)
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), result === 0 ? 200 : 404)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: result === 0
+    }))
+}
+
+function rotateSigningKey() {
+    var jwt = requireApi('jwt')
+    var req = getInputRequest()
+
+    var kid = jwt.rotateSigningKey(req.algorithm || "")
+    if (!kid) {
+        moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 400)
+        moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+            status: false
+        }))
+        return
+    }
+
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), JSON.stringify({
+        status: true,
+        kid: kid
+    }))
+}
+
+function getJwks() {
+    var jwt = requireApi('jwt')
+
+    moc.writeExchangeBufferHeader(moc.getOutputBufferId(), "content-type", "application/json")
+    moc.writeExchangeBufferHeader(moc.getOutputBufferId(), "cache-control", "public, max-age=300")
+    moc.writeExchangeBufferStatusCode(moc.getOutputBufferId(), 200)
+    moc.writeExchangeBuffer(moc.getOutputBufferId(), jwt.getJwks())
+}
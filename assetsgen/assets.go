@@ -81,7 +81,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _assetsCoreApiGuestDTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x55\x4d\x6f\xe3\x36\x10\xbd\xe7\x57\x0c\x72\x59\x15\xc8\xc6\x97\xa2\x28\x0c\xec\xc1\x69\x76\xdb\x2c\x16\xd9\x00\x4e\xb0\x87\x20\x28\x28\xf1\x49\x62\x2b\x91\xea\x70\xd8\x58\x5d\xe4\xbf\x17\x94\xe4\x58\x91\x1d\x39\xed\x25\xb1\xe6\xe3\xbd\xe1\xf0\xcd\xf0\x64\xb1\x20\x69\x1b\x90\x46\x6e\xac\x11\xe3\xac\xa7\xdc\x31\xd5\x4e\x87\x0a\xf4\x2e\x73\x8c\x77\x27\x8b\x45\x0c\xa4\xd6\x05\xca\x94\xa5\xe0\x41\x52\xa2\xa6\xb4\x25\xa5\xb5\xb1\x05\x49\x69\x3c\x29\x89\x66\x4a\x51\x18\x6b\xa3\xd5\xe5\x31\x87\xe9\x0f\x4f\xb9\xa9\x40\xcb\x0e\x66\xb1\x58\x10\x23\x07\xc3\x66\xa0\x46\x49\xf9\xe1\xf4\x7c\x11\x99\xde\xab\xc6\xbc\x2f\x02\xbc\x9c\xeb\x73\xf1\xa7\x5b\x62\x29\x61\xcf\xc8\x9b\xba\xa9\x5a\x32\x75\xe3\xb8\x67\x1a\xaa\x94\x92\x5d\x28\xca\xce\xc4\xc1\x8a\xa9\x41\xab\x9b\xab\x2e\x35\x73\xd6\x0b\x45\x70\xfa\x40\x8c\xbf\x82\x61\xac\x1a\x93\x9c\x46\xd3\xe9\x0f\x91\x41\x23\xab\x14\x83\xf2\x60\xb3\xd8\x81\x71\x98\x55\x35\x96\x34\x04\xd3\x92\xbe\x9f\x10\x11\x15\x90\x2b\xdb\x04\xb9\x08\x79\x0e\xbe\xd2\x49\x74\xd9\x50\xa7\xe0\xad\xff\x6b\x90\x99\x80\x8c\xa1\x04\x1f\x37\x59\xa9\x6c\x81\x3e\x6a\x1a\xf3\xc8\x66\x2f\x24\x1d\xf0\xb6\x81\x67\xf1\x7c\x02\x2b\x4b\xba\x33\x56\x7e\x5e\x31\xab\xf6\x38\xce\x6f\x50\xfa\x20\x5a\x7f\x5c\x2f\x6c\x6c\x71\x46\x7f\xab\x2a\x3c\x7f\x1e\x47\x5d\x8b\x92\xe0\x7f\x71\x1a\x07\x90\xfd\xb3\x73\x6b\x9b\x00\x76\xa2\x90\xc0\xd6\xf7\xf7\x08\xa5\x35\x2c\x79\xf3\x0f\xc8\xe4\xc4\xf0\xa1\x92\xdf\xd3\x56\xe0\xe9\x51\x79\xb2\x4e\xe8\xfa\xee\xcb\x17\x52\x56\x77\x19\x18\x8a\xa1\x9e\xbc\xcf\x74\x52\x82\x1f\x8d\x47\xc7\x11\x41\x8f\x74\x34\x56\xb5\x6b\xe5\xa1\xca\x06\xf8\xb2\xeb\xa1\x27\x63\xe9\xf3\xfa\xeb\x75\x9c\x9a\x5a\xc9\x2b\x34\x7d\xc3\xfd\x41\xb6\xef\x74\xff\x27\xda\x6d\x9b\x1f\xb6\x3f\xe8\xa9\xc3\x4a\x95\xc7\x4f\x3f\x5e\x22\x8b\x6d\x85\x8d\xff\xf4\xf8\x4a\x26\xb5\xf6\xe1\x1f\xbb\xb8\xc4\x44\x8d\x4e\x95\xd1\xa7\x0e\x75\x16\xc6\x0b\xf8\xa2\x72\xe9\x37\x23\xe5\xb5\xaa\x91\xbc\x94\xc0\x20\xaf\xdb\xb6\xd9\x37\xbe\x11\x39\xf9\x9f\x18\x05\x24\xa6\xdf\x22\x2b\xaf\xf4\x27\x76\xf5\x5e\x79\x87\x13\x2e\xa2\x44\x56\x7e\xdd\x39\xe6\xe2\x9b\x2a\x14\x9f\x86\x99\x4f\x6a\x48\xe9\xf4\xae\xbe\xb8\x96\x76\x5f\x2f\x9b\xe2\x45\xb1\x6c\x33\x77\x66\xad\x44\xed\xbe\x44\x15\xfe\xb3\xdf\xf9\x27\x72\xef\xc8\x4d\x85\xff\x44\x7c\x04\x33\xd8\x88\x7a\xa3\xa4\x9c\x45\xdd\x5f\x56\xfd\xe4\x26\xd3\xfe\x80\x7d\xbc\x45\x9b\x61\x0d\x49\x3a\x91\xee\x6e\xeb\x79\x3d\xbc\xba\x78\x0a\xc8\x1d\x57\x49\xe0\x6a\x46\xb0\x23\x92\x5f\xf7\x49\x8e\x26\xac\x43\xea\x21\x49\xc3\xc8\xcd\x66\x4c\x33\x3f\x55\x0d\x1b\x2b\x97\x48\x43\x91\x08\x36\xf2\x7a\x67\x6e\x4d\x8d\x44\xc3\xcf\x2d\xd8\x9c\x31\xb7\x4d\xc6\x6b\x5f\x55\xd5\xb3\xe2\xde\xa4\x29\xc5\x45\xa8\x61\xc5\x2f\xc9\x58\xb9\x7f\x38\x8b\x6f\xde\x28\xad\x1b\xf1\x97\xbb\x66\xbc\x75\x5d\xf7\x0a\xbd\xee\x6f\x9c\x37\x9b\x28\xc3\xeb\x17\xc5\x74\xe6\xd5\x8e\xba\xb7\xdf\x3f\x4c\x8e\x83\x4d\x7c\x89\x2f\x95\xa8\xb8\x76\x92\x43\xeb\x08\xa2\xbe\x21\xbd\x61\xb7\x69\x93\x26\xfe\x5d\x37\xc8\x66\x24\x6c\xfc\x2d\xab\x0c\xd3\xb7\x70\x98\x16\x01\xbf\xad\x6d\xe3\x51\x9c\xa8\xba\x1f\x92\x01\xcc\xe8\x03\x75\x3c\xfd\x1b\x00\x00\xff\xff\xbd\xce\x22\x3c\x16\x09\x00\x00")
+var _assetsCoreApiGuestDTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd5\x58\xdd\x6f\xdb\x36\x10\x7f\xef\x5f\x41\xe4\xa5\x1e\xe0\x26\x1b\x30\x0c\x43\x80\x3e\x38\x4d\x3f\x52\x74\x69\x00\x27\xeb\x43\x51\x0c\x94\x78\xb6\xb8\x48\xa4\x46\x9e\xe2\xb8\x45\xff\xf7\xdd\x91\x92\x2d\xcb\x92\xec\xf4\xad\x45\xe1\x58\xd4\x7d\xf1\xee\xf8\xbb\x1f\xfd\xec\xec\x4c\xe0\xba\x04\xa1\x60\xa1\x8d\x46\x6d\x8d\x17\x0b\xeb\x44\x61\x55\x95\x83\x78\x9e\x5a\x07\xcf\x9f\x9d\x9d\xd1\x7f\x21\xd6\xb6\x12\xa9\x34\xa2\xf2\x20\x30\x83\x42\x24\x6b\x21\x95\xd2\x66\x49\x8f\xda\x0b\x89\xbc\x2c\x12\x58\x6a\x63\x78\xd5\x2e\x58\xc7\x89\x7f\xc9\xa8\x26\x73\xe7\xc1\xcc\x19\x7d\x38\x58\x80\x03\x93\x82\x28\x25\x66\x2f\x4f\x4e\xcf\xd8\xd3\x0b\x59\xea\x17\xcb\x0a\x3c\x9e\xaa\x53\xf4\x27\x8d\x63\xb2\x6a\xa6\xc2\xeb\xa2\xcc\xd7\x82\x3e\xad\x8b\x9e\xea\x28\x31\x73\xb6\x5a\x66\x61\xc9\x55\x06\x75\x01\x62\x76\x73\x15\x54\x53\xda\x11\x0a\x36\x2e\x5e\x92\xd7\xff\x2a\xed\x60\x56\xea\xc9\x09\x2f\x9d\xfc\xc2\x1e\x14\xa4\xb9\xa4\xf7\x8b\xca\xa4\x9c\x81\xb6\x98\x91\x05\x9c\x8b\x5a\x58\x9c\x8b\x6f\xcf\x04\xfd\x5b\x02\x5e\x99\xb2\xc2\x8b\x6a\x41\xdb\xb8\x52\x13\x7e\x65\xaa\x22\x01\xd7\xbc\xff\x58\xe1\x88\x40\xea\x40\x22\xbc\x7e\x4c\x33\x69\x96\x10\xa5\xba\x32\x14\x7d\x14\xa3\xc4\x8a\x24\x88\x88\x55\x66\x29\xf7\xb4\xaa\xf8\x41\x6a\x8c\xd5\xe2\x8d\xaf\x9c\x46\x5a\x44\x1b\xbf\x09\x5a\x4e\x73\x96\xd6\x38\x15\xd2\xa8\x5a\xb7\x16\x8b\xba\x2b\xca\x2b\x69\x58\x51\x54\x69\x26\x94\x44\x29\xa8\x8c\xc6\x62\x70\x21\xd6\x80\xad\x68\xe7\x48\x7f\x0a\x2a\xeb\x78\xd8\xc1\x41\x47\x24\xa9\xd3\xd0\x08\x4e\xb9\x2c\x08\x06\xcf\xc5\x9d\x36\xf8\xe7\xcc\x39\xb9\x3e\x6c\xe7\x5d\xd8\x77\x8f\xb5\x58\x25\x8f\x8e\xa2\x9b\x8a\x07\x99\x57\x9b\xc7\xc3\x56\xe7\x28\xb1\xf2\xaf\xac\x82\x1e\xcb\x7e\xf3\xb2\x59\xdb\xaf\x92\x03\xac\x1c\x9d\x9b\xd0\x7e\x14\xa2\xa2\xa4\x7a\xfd\x95\x32\xbf\xa0\x67\x5f\xe5\xf8\x4f\xb2\xe6\x32\xae\x64\x4c\xee\xf5\xdd\x87\x0f\xa1\x24\xac\x01\x75\x30\x4d\x89\x83\xa6\xa5\x37\x6e\xa5\x3d\x04\x1f\x6c\xf4\x40\x46\x39\xaa\x6d\x2a\xfb\x22\xab\xcd\x67\x21\x87\x5e\x68\x23\xde\xcf\x3f\x5e\x73\xfb\x14\x12\x07\xdc\xc4\x84\xfb\x5e\x6f\xdf\xc4\xe7\x7b\x58\x37\x69\xfe\xd2\x7c\x11\xdf\x07\x6c\x8d\xa4\x79\x38\xa5\x04\x36\xdd\xfc\x70\xe7\x48\x6d\x22\xe8\x80\x30\xf0\x88\x84\x21\x04\x08\x5c\x5a\xea\x29\xde\xd9\x76\xbf\xd3\xd0\xe9\x2c\xcc\xe7\x44\x23\xd7\xc4\x00\x28\x50\xa7\xe2\x36\xd4\x8b\x1d\x81\x6a\xac\x53\xfb\x43\x51\xe2\x5a\x58\xc6\xa6\xd6\xb9\xca\x24\x63\x98\xd1\x3e\x03\x35\xb0\xc3\x1b\x8a\xe2\x89\x85\xf9\x8d\xe3\xe9\x78\x09\xa7\x56\xed\xec\xe1\xd7\x4e\x43\x68\xbf\xeb\xf9\x13\x6b\xbf\xa9\xa3\x3b\x22\xbd\x5e\x2f\x8d\xcc\xb9\x2f\x08\xb2\xa9\x23\x33\x4e\x50\xc1\x18\xb9\xd2\x79\x4e\x00\xde\x9f\xcc\x08\x06\x1c\xde\x11\xdd\xb8\xeb\x91\x8d\x10\xfe\x30\x12\x97\x1a\x62\x47\x7a\x1a\x0d\xe9\xa6\xae\x04\x5c\xbc\x88\xd2\x11\x7a\x52\xfa\x61\xe3\x5b\xa6\xf7\x4b\x02\x78\x3a\x2f\x7c\x66\x82\x7f\xdf\x96\x0d\x28\xa6\x48\x63\x1a\x56\x37\x30\x9e\x86\xee\xa1\x9a\x17\x20\xcd\x2a\xa3\x01\x14\xa2\xa1\x46\x58\x49\xd7\x3d\x50\x31\x98\x8b\x3d\x00\x88\x3e\xba\xeb\x9d\x0d\x26\xd2\xc3\x1f\xbf\x5f\x42\xca\xdd\x4d\x53\x8d\xfe\xa8\x36\x00\x75\x1a\x20\x8a\xbf\x0e\x72\x13\xcd\x83\xa4\x8b\x83\x51\xb5\xee\xb3\xa5\xf6\xd4\x1b\x17\xb9\x4d\x3e\x69\xcc\xae\x09\xeb\x26\xbb\x80\x57\x83\xe9\x2d\x0d\xf2\xbd\xc5\x23\x2d\x4f\x7e\xd0\x06\xe7\x86\xd4\x6f\x21\xcd\xae\xd4\x1b\x67\x8b\xbd\xf0\xfa\x15\x2e\x18\x10\x67\x7e\x1e\x5e\x8c\xc9\x97\x79\xb5\x7c\x53\x57\x74\x52\x00\x66\x56\x6d\xe3\x63\xee\xb0\x7d\xda\x4d\x0a\x21\xb7\xc3\x46\x73\xbb\xcc\x43\x6e\xfb\x84\x72\xe9\xdf\xfb\xed\xfb\x4e\x5d\x83\x73\xea\x9b\x27\x39\x3e\x60\xb3\x32\x6c\xf5\x86\x0c\x8c\x5a\xdd\x67\x14\x11\x40\x27\xdd\xfc\x10\x3e\x73\x15\x09\xae\xe6\x80\x93\x00\xc9\xdb\x6a\x6d\x86\xe1\xe0\x98\xdd\xd5\xe7\x06\xbb\xc5\xfc\x08\x33\xb4\x4f\xcc\xe7\xd4\xf1\x46\xf9\x81\x53\xd1\x32\x7d\x09\x39\x20\x74\xcd\xee\xc3\xc4\x52\x3f\xd4\x67\xfb\x81\x95\xe9\x10\xdb\x88\x8f\xa4\xc9\x30\x18\x8e\x3a\x9d\x68\x65\x21\x8e\x52\x3a\xdf\x1e\xbb\xde\xde\x02\xfe\x1d\xd5\x9f\xe2\xd1\xc0\xea\x58\xaf\x99\x7c\x80\x7a\x78\x97\x90\x22\xa1\x75\xad\xd8\x8d\xe4\x95\x2d\x68\x36\xc1\xcc\xa8\xde\xe2\x34\xea\x75\xb4\x5b\xd4\x39\x54\xb5\x1a\x51\xc3\xd4\x60\x0a\x98\xe7\x76\xc5\x18\xce\x34\xf6\x1e\x4a\x14\xcc\x82\xf3\x20\x93\xda\xa2\x60\x0a\x88\x4e\x1a\x2f\xd3\x48\xf1\x59\x2e\x62\x2b\x12\xf1\x23\xd6\xcd\x4d\x05\xb4\x09\x99\x37\x46\x59\x44\x96\x65\xae\x69\x77\x12\xc3\x3c\xec\xee\xee\x82\x89\xfe\xed\xd6\x6e\x0f\x83\xad\x8d\x05\xb4\xde\x0a\x32\xb4\x33\xc2\x07\xf7\xd3\xe1\xaa\x37\xb1\xd7\x25\x90\x5c\x90\x48\x4d\x23\x76\xab\x9e\x7c\x93\xc2\x48\x48\x2d\xd9\x59\x42\xd7\x88\x11\x51\x3a\x72\x77\x2e\x9f\x54\x2e\x1f\x81\xf2\x05\xd0\x0e\x26\x7c\x61\xa0\x4b\x4b\xfb\xd4\x4f\x45\x62\xd5\xfa\xc0\xdc\xd8\xed\xd9\xbe\x66\xed\xb8\xdb\x55\x98\x57\x89\x27\xb5\x92\x2e\x53\xfa\xb1\x1d\xe4\x38\x37\x6b\x1f\x79\x9a\x91\x13\x8a\xdd\xad\xbb\x88\xd5\x0f\x31\xe4\xf4\x55\xc8\xbc\x7f\x8a\xd6\x9d\x07\x1e\x0b\xbe\x94\x69\x9c\x0d\xe1\xdb\x10\xd2\x51\x45\x70\x44\x7c\x1a\x25\xa0\xb5\x20\xd3\x14\xbc\x1f\xb0\xe7\xe0\xc1\xde\x6f\xfd\xbf\x65\xe5\x23\xac\xee\x37\xc3\xc6\xc4\x1e\x06\x7b\xca\xe2\x68\xc4\x87\x72\xd5\xb6\xde\xe4\xf7\xc7\xcc\x5c\xda\x82\xc8\x71\xe0\x83\x0d\x04\xaa\xb0\x34\xb0\x2f\xed\xa3\xc6\x05\x91\x2c\x66\x45\x01\xb0\x50\x27\x34\xf3\x46\xf5\x32\x9b\xab\xa8\x39\x2e\x57\x99\x63\x25\x1b\x36\x12\x65\xdf\x11\xd5\xcb\x89\x96\xed\xaa\x4c\x37\xe4\xee\xba\xc3\x18\x76\x4b\x15\x6d\xec\xd5\x89\x87\x28\xd0\x65\x16\xf7\x08\x47\xe7\x98\xf9\x5e\xc1\xde\x29\xd8\x04\x34\xd4\x7e\x2a\x4c\xbe\x01\xb7\x9d\x01\x1f\x84\xf6\x27\x3c\x7d\xc3\x4b\x48\xaa\xe5\x04\xe9\xb2\x33\xac\x7f\xab\x89\x7a\x29\xc2\xa1\x91\xc1\xb1\x70\x70\x24\x69\x4f\x65\x9e\x6f\x88\xd7\x51\xd4\x8a\x98\x72\x55\x10\x5d\xa4\x54\x90\xfb\xcf\x5f\xa6\xfc\xfb\x4c\x4b\x2d\x30\xdd\x5d\xda\xdd\x66\xda\x36\xfc\x62\x32\xfc\xbe\xb4\x5e\x3f\x32\x1b\xbb\xde\x09\x26\x2c\xcf\xb6\xae\xe3\xfa\xe7\x2f\x23\xdb\x61\x96\xf3\x17\x5d\x28\xd0\xff\x8c\x1b\x63\xef\x55\x6b\xe5\xc0\x46\x5f\x1b\x1a\xd2\xdd\x1b\xc3\x4f\xb9\x51\xe2\x4b\x61\x2f\x03\x1b\x27\x32\x45\x03\xfd\x92\xf8\x3d\xdf\xaf\x26\x7d\xf7\x2e\x40\xf9\x09\x92\x1b\x67\x1f\xd7\x34\x35\xe9\x73\x4e\xfc\x6b\x84\xab\x6b\x4f\xfc\x20\x85\x3d\x12\x11\xaf\x05\x04\x56\xc7\xa5\xb5\x7d\xe7\xe8\x1c\xee\x78\x1b\xa8\x8d\x69\x35\x54\xd3\xf0\x03\xdc\xac\xd4\xb7\x34\xca\xba\xa7\xd1\xd9\x7c\xe8\xf2\x14\x67\x5f\xaf\xde\x3e\x7c\x34\x62\x3b\x00\xf4\xfd\x7f\x69\x35\x4f\xc0\x22\x16\x00\x00")
 
 func assetsCoreApiGuestDTsBytes() ([]byte, error) {
 	return bindataRead(
@@ -96,12 +96,12 @@ func assetsCoreApiGuestDTs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/core-api-guest.d.ts", size: 2326, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/core-api-guest.d.ts", size: 5666, mode: os.FileMode(436), modTime: time.Unix(1792311904, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsCoreApiGuestH = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x58\x4b\x6f\xe3\x36\x10\xbe\xfb\x57\x10\xce\x45\x5a\x04\x5d\xa0\x2d\x7a\xd9\xa2\x40\xb0\xcd\x61\x8b\x3c\x8a\x3c\xd0\xde\x08\x4a\x1c\x59\x6c\x65\x52\x20\x87\xb1\xdd\x5f\x5f\xe8\xe1\x98\x43\x53\xb2\x9b\xa0\xed\xe6\xa6\x99\x6f\x1e\xdf\x70\x38\x1c\x67\x71\xa1\x2a\x2d\xa1\x62\xa5\xb1\xc0\x45\xab\x78\xbd\xb8\x90\x50\x29\x0d\xa1\x68\x71\xa1\x74\xd9\x78\x09\xec\x47\x87\x52\x69\xfc\xa6\xfe\x69\xf1\x0a\xfc\xed\xea\xf1\x96\x5f\xff\xfe\xeb\xfd\xc3\x13\x3b\xfe\x83\x2d\x82\xd5\x8c\x73\x81\x68\x55\xe1\x11\x38\xcf\x32\xef\x40\xe6\x79\x2c\x7d\x51\x4e\x15\xaa\x51\xb8\x63\xd9\x52\x42\x25\x7c\x83\xcb\x3c\xcf\x53\xa1\xf8\xd5\x63\x76\x77\x75\x7b\x9d\x1f\x42\x85\x89\x44\x9e\x61\xdb\x1a\x8b\x5c\x8b\x35\x0c\x56\xb1\xd3\x2f\xb7\x9d\x59\x76\x7b\xff\xf3\xf3\xcd\xf5\x65\xe0\x38\x72\xa4\xd6\xbd\xa3\xb5\x91\xbe\x81\x11\x9e\x1f\x33\x19\x61\xd3\xf1\x3e\xdf\xdf\x3d\x3e\x3d\x3c\x7f\x7e\xba\x7f\xa0\xf5\x8a\x1c\x95\x46\x3b\xb4\xbe\x44\x63\xf3\x7c\xb1\x08\x73\x5d\x76\x27\xb4\xbc\x64\xcb\x15\x20\x57\xba\xf5\xc8\x0b\x5f\x55\x60\xb9\x92\xcb\x9c\x79\xa5\xf1\xbb\x6f\x39\xb2\x84\x3a\xcb\x3f\x4d\xbb\x32\x1e\x67\x7d\xc5\xfa\x49\x67\xa5\x05\x81\xc0\x61\x5b\xd6\x42\xaf\x60\xb4\x08\xdd\xa5\x11\x93\x0e\x37\x56\xcd\xfb\x4b\x02\x32\xa5\x91\xbd\x66\x7b\xc9\xfa\x92\xb2\x17\xa3\x24\xfb\x50\x1a\x8d\xa0\x91\x17\x3b\x04\x77\xc9\x3a\xe4\x5e\xd4\x80\x5e\x61\xfd\x8f\x52\xe1\x35\x08\x79\x46\x46\x23\x2e\x9d\x58\x59\x0b\xcb\x3e\x74\x9d\xc3\x1d\x5a\xa5\x57\x43\x5a\xbd\x60\xc8\x89\x22\x5f\x44\xe3\x29\x74\x90\xbc\x25\x7f\x87\x02\xbd\xe3\xa5\x91\x70\x9a\x44\x00\x8e\x99\x74\x9f\x81\x3a\xff\xb4\xf8\xf8\x91\x59\x40\x6f\xb5\x63\x58\x03\xb3\x20\xa4\x04\xcd\x9c\xfa\x0b\x98\xaa\x98\x05\xe7\x9b\xf1\x18\xd8\x46\x38\xa6\x0d\xb2\xbb\xe7\x9b\x1b\x26\xb4\xec\x2d\xf6\xd1\xc7\x30\x83\xa5\xc1\x1a\xec\x46\x39\x48\x93\xec\xa2\xcc\xb5\x4b\x4a\x1f\x53\x19\xfa\x24\xcc\x6f\xa0\x37\x4a\x5e\xab\x1c\x11\x1c\xb3\x1c\x0e\xda\x31\xa5\xd9\x2f\x8f\xf7\x77\xac\x32\x76\x2d\xf0\xfc\x6c\xc7\x4e\x71\xa7\xb2\xde\xe3\x68\xf6\x53\x87\x5f\x08\x07\x3f\x7c\xcf\x25\xc4\x07\x4d\x14\x59\xd8\x65\xa0\x3b\x91\x24\x7d\xb6\x97\xcd\x77\xda\xe8\x73\x00\x27\x82\x0d\x8a\x2c\xbc\x95\xe3\xb4\x3a\x14\x7b\x10\xcc\xc7\xb1\xb0\x52\x0e\xc1\xf2\xa2\x31\x05\xdf\x28\xac\xfb\xf1\x4b\x2b\x97\x84\x64\x6f\xbb\x78\xfb\x41\x81\xbb\x96\x5a\x10\x05\xb5\x7c\xe7\xd4\x21\x04\x26\x99\x65\xff\x73\x96\xdd\x23\xd1\x57\x18\xa1\xac\xb9\x92\xbc\xb2\x66\x7d\x74\x18\xd3\xa8\xb3\xcf\xe3\x64\x02\x7d\xea\x5c\xb8\xd1\x41\x32\x7c\x84\x79\x77\xf0\xb6\xf1\x2b\x5e\x79\x5d\xa2\x32\x3a\x8c\x48\x14\x24\xcc\x1a\xb0\x36\xf4\x6e\x8d\xa2\x54\xdf\xb5\x02\x6b\x82\xed\x05\x29\xe4\xf9\xbd\xec\x50\x58\x7c\x4d\x8e\xd8\x44\xaa\x94\xb5\x14\x28\x88\x4d\x2f\x48\x21\x51\xac\x1c\xff\xc3\x45\x21\x0e\xd2\x73\x4a\xab\x1a\x38\x2e\xab\x6a\xe0\x2b\x2b\xe9\xfb\xa8\x7a\xdd\xf3\xea\xf2\x08\xc9\x06\xe2\x7f\x89\xee\xdc\x9d\x1a\x9e\xf4\xf8\x16\x0d\xd2\xc9\xa5\xad\x05\xeb\xba\xd9\xa4\x4b\xe0\x0e\x90\x1c\x1d\x55\x91\x37\xe0\x4f\xd8\x85\x93\xa7\xfb\x4c\x0d\xa9\x61\xd7\x09\x80\xe7\x2c\x3f\x5d\xd6\xde\x36\x31\x11\x6f\x1b\x52\x54\x6f\x1b\x52\xa5\xee\xfb\x44\x83\x06\x84\x56\xd3\x5c\x57\xe7\x72\x3d\x33\x0e\x77\xbe\x98\x29\xed\x01\x41\xe8\xb5\x16\x2a\xb5\xa5\x7d\x30\x88\x4e\x04\xb7\x4a\x23\x97\x50\x78\x32\x50\x03\x31\x09\x83\xb0\x45\x7a\x05\x3a\xc1\xe9\x03\x42\x75\xfc\x5e\x74\x32\x52\x39\x09\x8e\x3c\x50\xfd\xf7\xbc\xef\xca\x42\x6a\x17\x0c\xc4\xc1\x12\xf5\x65\x72\x87\x2a\x45\xd3\x24\xc7\x3c\x51\xbc\x71\xb5\x78\xeb\x38\x1e\x8a\x22\xec\xca\xaf\x41\xa3\xe3\xdd\x89\x08\x6b\xc5\x6e\xb0\x3d\x28\x52\x41\xd7\x46\xd2\xf4\x7a\xc1\x1e\x79\x58\xc3\xe2\xd5\x73\xbf\xf0\x8f\xbf\x0b\x53\x6a\xd2\x76\xc6\xa9\x6d\x3f\xb0\xf9\x51\x41\x62\x5d\x8a\xdd\x80\x39\x50\x19\xec\x43\x9a\x31\x62\xbe\x1f\xc6\x7f\x0d\x74\x4f\x56\xb7\x91\x86\x47\x19\xa9\x26\x47\x5c\x01\x28\xf8\x06\x0a\xde\x5a\xb3\xdd\x91\x1d\x97\x68\xa2\xeb\x67\xb6\x3b\xee\x5a\x28\x8f\x5f\x89\x58\x37\xcf\x40\x39\x8e\x56\x94\x24\xf5\xbd\x6c\x7a\x2c\x8f\xcf\x26\xd2\x6b\x10\x88\xff\xe3\xde\x3d\xbd\x4a\x9c\x78\x2b\x8f\xc9\x10\x05\xa1\xa3\xe8\x5b\xa9\x82\x1f\x31\x8b\x0b\xd0\x52\x55\x0b\xc6\x18\xfb\x3b\x00\x00\xff\xff\x93\x64\x77\x79\x20\x13\x00\x00")
+var _assetsCoreApiGuestH = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x5a\x4b\x8f\xdc\x36\x12\xbe\xcf\xaf\x20\xc6\x97\xee\x60\x10\x27\xbb\x8b\x1c\x36\x41\x00\xc3\xf1\x21\x0b\xdb\xb3\xf0\x03\x9b\x9b\xc0\x96\xaa\x5b\x8c\x25\x52\x4b\x52\xd3\xd3\xf9\xf5\x29\x3e\xd4\x4d\x52\x94\xd4\x3d\x33\xf6\x7a\x81\xcc\x69\x54\xf5\x91\xf5\x60\xb1\xaa\x48\xf6\xd5\x33\xb6\xe5\x15\x6c\x49\x29\x24\x14\xb4\x63\x45\x7d\xf5\x0c\xbf\x19\x87\x90\x74\xf5\x8c\xf1\xb2\xe9\x2b\x20\x3f\x29\x5d\x31\xae\xbf\xad\x7f\xbe\x3a\x02\xff\xf3\xe2\xfd\x9b\xe2\xd5\x6f\xff\xbe\x7d\xf7\x81\x8c\xff\xe0\x5e\x83\xe4\xa4\x28\xa8\xd6\x92\x6d\x7a\x0d\x45\xb1\x5a\xf5\x0a\xaa\xf5\x3a\xa5\xde\x31\xc5\x36\xac\x61\xfa\x40\x56\xd7\x38\x3b\xed\x1b\x7d\xbd\x5e\xaf\x73\xa2\x8a\x17\xef\x57\x6f\x5f\xbc\x79\xb5\x3e\x89\x0a\x15\x49\x66\x86\xfb\x4e\x48\x5d\x70\xda\x82\x1b\x95\x4e\xfa\xeb\x1b\x33\x6c\xf5\xe6\xf6\x97\x8f\xaf\x5f\xdd\x04\x13\x27\x13\xb1\xd6\x4e\xd4\x8a\xaa\x6f\xc0\xc3\xd7\x63\x4b\x3c\x6c\x5a\xde\xcb\xdb\xb7\xef\x3f\xbc\xfb\xf8\xf2\xc3\xed\xbb\xd8\x5f\xc9\x44\xa5\xe0\x4a\xcb\xbe\xd4\x42\xe2\x14\x57\xa1\xae\xd7\x66\x85\xae\x6f\xc8\xf5\x0e\x74\xc1\x78\xd7\xeb\x62\xd3\x6f\xb7\x20\x0b\x56\x5d\xaf\x49\x8f\xeb\xf4\xf7\xbf\x15\x9a\x64\xd8\xab\xf5\x8f\xd3\x53\x89\x5e\xcf\xce\x95\xf2\x27\x27\x2b\x25\x50\xb4\x02\xee\xcb\x9a\xf2\x1d\xf8\x11\xe1\x74\x79\x84\x99\xf0\xf9\x73\xcf\x54\x84\x12\x47\x26\xfb\x5a\x28\x20\x48\xad\xcc\x07\x65\x5a\x91\xad\x90\x44\xd7\x40\xf6\x92\x61\x98\x11\x2d\xdc\x7f\x04\xc9\x65\x63\xd0\x4c\xdf\x10\xca\x2b\x3f\xd6\xc3\xdc\xd8\x7d\x0d\x1c\x47\x08\xd2\xf6\x65\x4d\x2a\xaa\x29\x61\x8a\x70\xa1\xad\x08\x72\x00\x3d\x6b\x16\x2e\x0b\xd0\x96\xf1\xdd\x19\x06\x4e\x62\x27\x7d\x67\x35\x9d\x9b\x39\x0b\x58\x21\x97\x1c\x17\xe6\x86\xd8\xe8\x21\x77\x82\x55\xe4\x1b\xfc\x5f\x03\xc7\x75\x3b\xa0\x53\x6f\x88\x41\x0e\xa4\x06\xf8\x4e\xd7\x17\xa9\x52\xd4\x76\x19\x16\x35\xf2\xb8\xbc\x62\x88\x94\xe4\x1b\xb3\x49\x8c\x87\xd0\x3d\x4e\x2d\x4b\x70\x3a\xc5\xc8\x3b\xda\xf4\x31\xd4\x51\x1e\xa2\xbf\xd2\x54\xf7\xaa\x28\x45\x05\xcb\x46\x04\xe0\xd4\x12\xf3\x19\xb0\x5d\xe8\x4a\xd0\xbd\xe4\xca\x86\xa6\x09\xa6\x0a\x23\x4d\xb1\x3f\x30\x1c\xb7\xf8\xad\x30\xad\xb9\x65\xc0\x48\x74\x11\xf7\xf6\xe3\xeb\xd7\x36\x4e\xcd\x88\x41\xfa\x10\xf7\x76\xa4\x40\x8e\xdc\x33\x05\x79\x23\x8d\x94\xb9\x70\xc9\xf1\x53\x53\x5c\x9c\x84\xfa\x39\xf3\x3c\xe5\xe8\xe5\xc4\x40\xaf\xa5\x5b\x68\x85\x23\xc8\xbf\xde\xdf\xbe\x35\x5b\xb3\xa5\x13\x5b\x28\xa7\x8d\x8f\x14\xb5\xa4\xf5\x80\x8b\xb5\x9f\x5a\xfc\xec\x0c\x13\x6b\xbf\x84\x1d\x49\x0c\x1c\x41\xf9\x68\xd9\xcc\xee\xa2\x8c\x63\xac\x5a\x37\x71\xac\x84\xa4\xa3\xd2\xc5\x18\xee\x3b\xe3\xa9\x93\xff\x6e\x6c\x56\x32\x60\x93\xd3\x98\x36\xa1\xc2\x01\x2a\xa8\xbe\x25\x1f\x6c\x18\x19\x41\x50\x0d\xb3\x63\xaa\x82\xb6\xc3\x4a\x29\x78\x09\x61\x0e\xac\x31\xa4\xb0\xce\x30\x55\x43\x75\x81\x4f\x8c\x66\x8b\xce\x30\xa0\x39\x2f\x7c\x6f\xb4\x4e\x74\xb1\x79\xb8\x8a\x2c\xfd\x6e\x29\x9a\x99\x1a\x89\x76\x1b\x73\x30\x2c\xd4\x74\x11\x9c\xd3\x58\xb1\x1d\xa7\x8d\x09\x60\xaa\xcd\x0e\xac\x8d\xe7\x5b\x14\x4f\xf6\xac\x69\xc8\x06\xf2\xab\x34\x51\x11\x8c\x89\xb3\x65\x20\x07\xc8\xa9\x65\x24\x61\xa5\x32\xa9\xb9\x63\xe0\xf6\x97\x12\xbd\x2c\x8f\x51\x85\x25\xce\x10\x35\x95\x58\x8b\x71\xf1\xe1\xa8\x20\x2d\x3f\xed\xa4\xe8\x31\x89\x98\x44\x62\x45\xaa\x10\x6b\xeb\x5d\x85\x23\x6e\x2c\x75\xdb\xf3\x52\x33\xc1\x49\x69\x63\x17\x23\xae\x05\xca\xf7\x35\x6b\x26\x16\x05\xe3\x72\x4f\xe5\x6c\x96\x99\x80\x58\x4b\x9d\x19\x45\x92\x3a\x9d\x6e\xc5\xe2\x3e\xde\x50\x05\x3f\xfc\xa3\xa8\x20\xdd\xb4\x11\x63\x15\x56\x0b\xe0\x86\x54\x45\xf5\x62\xa0\xcd\x57\x0c\x3f\xa7\x03\x67\x84\x39\xc6\x2a\xac\xae\xbe\xc1\x3a\x25\x4d\x47\x98\x97\x23\x61\xc7\x14\xee\x93\x62\xd3\x88\x4d\xb1\x67\xba\xb6\x1d\x63\xbc\x0d\xb3\x90\xd5\xc3\x0a\xe8\x50\xf0\xf5\xa1\x8b\x47\x44\x8c\x78\xe4\x23\xbb\x87\xc8\x80\x49\xcb\x56\xff\x63\x2d\x6d\x0c\x1a\x0f\x6b\x28\x6b\x0c\xc3\x62\x2b\x45\x3b\x5a\x8c\x69\xd4\xd9\xeb\xb1\xa8\x80\x55\xbd\xa0\xca\x4f\x90\x15\x9f\x60\x1e\x2d\xbc\x6b\xfa\x5d\x31\xe4\x83\x50\x62\xc4\x88\xc4\xb4\xa0\x6b\x11\xef\x2d\x4f\xca\xc5\x5d\x47\x31\x6e\x43\xac\x25\xe4\x90\xe7\xc7\x32\x16\x66\x3c\x61\x0d\xca\x45\x63\x12\x56\x6e\xb4\x69\xf7\xa3\x31\x96\x90\x43\x6a\xba\x53\xc5\xef\x2a\x11\x71\xa2\x9e\xe3\x5a\xcc\xa9\x63\xb7\x22\xf1\x2b\x73\xe9\xe3\x4c\xed\xb9\xb5\xcb\xe8\x11\x1a\x1b\x90\x3f\x93\xb9\x73\x7b\xca\xb5\x6f\xe9\x2e\x72\xd4\xc9\xc3\x57\x87\xed\xa5\xc9\x4d\xd8\x59\x15\x0a\xa2\xb6\x28\x61\x45\x35\xe0\x13\x1c\xc2\xcc\x63\x3e\x73\x49\xca\x9d\x59\x02\xe0\x39\x87\x98\x44\xae\xab\x04\x5a\x37\x33\xba\x1d\x31\x4f\xae\xa4\x8f\x0b\xdd\xa0\x18\x1c\x54\xa9\x73\xb4\xae\xa0\x01\x0d\x53\xfa\x3a\xee\x79\x9a\xba\x56\x69\xc7\xee\x7c\x7f\x73\x67\xe6\xc1\x46\x46\xb8\xde\x13\x81\xa6\xc5\xb4\xed\x0e\x76\x35\x95\x00\x77\xc6\xc2\x1e\x47\x4d\x9c\x47\x42\x4d\x4c\x7c\xf8\x19\xa7\x94\x0d\x20\xe7\xfa\x16\xbf\xb1\x71\xd0\xc3\xf1\x2a\x35\x81\xc3\xfe\x5c\x33\x6a\x7a\x07\xfe\x98\xd8\x41\xa9\xb1\xb5\xf6\x03\x97\x4d\x2b\x45\x8b\x1d\x3c\x14\xd8\x1f\xce\xc5\x75\x02\xbb\xd4\xc4\x41\xaf\xc1\x45\x97\x06\xd6\xd8\x4f\xc7\x13\x85\xb9\xf0\x69\x1a\xb1\x37\xcd\x3a\x6a\x88\xe2\x3b\x93\x60\x34\x6b\x2c\x06\x15\x6f\xcd\x85\x8f\x96\x94\x2b\x6a\x93\xbf\xb2\x38\xd7\x1f\xeb\xb2\x26\xb4\x63\xe4\x9f\x44\x01\xea\x46\x9b\x61\x52\x03\xa1\x5d\xd7\x30\x74\x26\xd5\xf6\x44\xb5\xec\xcc\x0d\xf6\x2f\xbc\x08\x44\x4d\xb9\x73\x04\xf4\xf7\x5b\x5e\xb8\xed\xd0\x4f\x4c\xd3\xce\x9b\xae\xde\xaa\x7b\x33\x1d\xe5\x83\xad\x3e\x42\xa8\x59\x0a\x77\x71\xe5\x5a\xef\x89\xb3\x5f\xb2\xce\x38\xc5\x39\x26\x8c\x91\xab\xf1\x42\x2d\xca\xa3\x1b\x73\x27\x7a\x86\xb8\x11\x70\xf6\xbe\xb2\x97\x4d\x9a\xdf\x91\x14\xd5\x1a\xfc\x8e\x8a\x87\xf9\x9e\xcf\xb7\x5b\x40\xf7\x47\x47\x1b\x43\x88\x26\x95\xf0\xdf\x1e\x94\x1e\x17\xcc\x88\x11\x66\xcc\x8d\xa8\x0e\xcb\x47\x9d\x24\xd3\xcc\x24\xa1\xb3\xd3\xe5\x59\x49\x4f\xf5\x9b\x99\xa4\x70\x42\x44\x4e\xe8\x24\x6c\xd9\x7d\x5c\x99\x1d\xe9\x82\x7a\x86\x27\xd0\xc9\x3a\x86\xbc\x48\x20\xba\x56\x1e\xc6\x3e\x0f\xc8\x97\x59\xed\x76\x8b\x9a\x33\xdb\x43\x3e\x9b\x1a\xbd\x02\x7b\x88\x50\x1d\x2d\x27\x0b\x64\x04\x1a\x75\xfc\x96\x3a\x6a\xf2\x1c\x75\xa1\x49\xc2\x5d\xa6\xf3\xe2\x13\xd6\x03\x84\xc6\x3d\x9c\x9d\x0f\xe2\x11\x03\x2d\x87\xa7\x65\x09\x4a\x45\x70\x4f\x5a\x3a\x72\xde\x89\x4f\x81\xb7\x0a\x2b\x24\x3e\x7b\xe6\x10\x5f\xc0\xc0\xb9\x3c\x76\x9c\x7b\xd4\xae\x9e\x38\x93\x89\xd0\xec\x93\xfc\x2a\xc6\x9c\x47\xdb\xf8\xd8\xc0\x8f\xec\xc9\xed\xbe\x2c\xe0\xab\x50\xbb\x12\x2d\xc5\x4a\xee\x2e\x17\x33\x2d\xe2\x14\x26\x52\xde\x03\xa2\xa3\xa7\x23\xcd\x2b\xc0\xd4\x30\xb7\xb9\xe8\xb3\xb7\x6c\xb6\x4f\xd3\x6c\x13\x1f\x2e\x67\x81\x4f\xa2\x4a\x2d\x9a\xca\xcb\x08\x05\x07\xe4\x27\x11\xd3\xf3\x09\x41\x11\xe3\x49\x44\x1d\xef\xa2\x3c\x1a\x83\xae\x6a\xd2\x07\x94\x2c\xe4\x22\xf1\x71\x48\x1e\xef\x27\x46\x07\xf4\x98\x73\x6e\x60\x8e\x76\x91\x27\xcf\x36\x4f\x78\x80\x93\x71\xd1\x3f\x51\x1f\x7d\xaf\xa4\xb2\x02\xd4\xe5\x02\x2e\x3a\x42\xe4\x7c\xac\xb2\xfe\x5d\x2a\x24\xee\x50\x9a\x31\x21\x62\x3c\xc9\xdd\x9f\x9b\x6a\x7c\x51\xe1\xc8\xd3\x37\x15\x28\x08\x17\x1a\x36\x7d\x74\x53\x18\x90\x23\xed\x34\xdc\xeb\xf8\x6e\xc7\x10\x96\xb5\xd3\x6c\x7c\x11\x6a\x68\x51\x03\x5a\x99\x9e\x37\x58\x15\xfb\xbd\xd0\x65\x4b\xc8\x3e\x23\x9c\xc8\xc1\x23\xc9\xaf\x93\x1d\x73\x49\x9b\x26\x7b\x7f\x19\x31\x1e\x78\x67\xfe\xd0\x7b\x46\xe7\x14\x2a\x77\x7d\x0b\x5c\xab\xc2\xac\x08\x95\x92\x1e\x7c\x27\x73\x64\xe4\x84\xb6\xa2\x8a\xd5\xb3\x84\xf0\x2c\xe1\xde\x17\xd2\x87\xaf\xe1\x59\xc5\xff\x46\x23\xc7\x8e\xba\x77\xa1\xb0\x53\x37\x37\x91\xe3\x24\x94\xf2\x72\xd6\x39\xcc\xc9\x14\x37\x3e\x34\x33\x45\xcc\xc7\x43\xb4\x5e\xee\xfe\xaa\x15\x78\xbe\x57\x93\x8b\x1a\x82\xfe\x5a\xe0\xaf\x66\x81\x53\x63\xfb\xd3\xe0\xc1\xdc\xfe\x41\xe1\x80\x78\xc6\x61\x21\x1c\x1c\xe8\xaf\x70\xf8\x3f\x0d\x87\x18\xed\x16\x33\x79\xc5\xb5\xa4\xf9\xe0\xf1\x3f\xf9\x33\xef\x3a\xe6\xd9\x36\x0c\x99\x84\x35\x59\x5d\x37\xa0\x69\xb1\x87\x4d\xd1\x49\x71\x7f\x88\x1e\x82\x23\x4e\x72\x23\x82\x94\x42\x75\x50\x8e\x8f\x1b\x29\x6f\xb1\xe5\xd7\x32\x39\xcd\x0d\xb4\xe9\x8e\xc0\xbf\x2d\xe9\xb8\xa4\x06\xe4\x2f\xbc\x2f\x96\xdf\xdb\x16\x1e\x94\xc6\xc6\x44\x8c\xc8\x1c\x16\x3f\x28\xb1\xa5\x97\x7e\xff\xfb\x3c\xf3\x33\x57\x2d\x3e\x41\xdc\x3c\x24\xbc\x07\xfa\x4d\x8a\x26\x46\x5a\xc2\x59\x37\x18\x59\xb5\x52\xde\x93\xb4\x9f\xc7\xd9\x46\x1d\xe8\x89\x63\x42\xee\xea\x19\xf0\x8a\x6d\xaf\xcc\x2f\x55\xff\x04\xaa\xe9\x56\x77\x3d\x2c\x00\x00")
 
 func assetsCoreApiGuestHBytes() ([]byte, error) {
 	return bindataRead(
@@ -116,12 +116,12 @@ func assetsCoreApiGuestH() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/core-api-guest.h", size: 4896, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/core-api-guest.h", size: 11325, mode: os.FileMode(436), modTime: time.Unix(1792311904, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsCoreApiGuestSyms = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x90\x51\x6e\x33\x21\x0c\x84\xdf\x39\xcf\xaf\xff\x3a\x96\x81\x59\xd6\xd2\x06\x90\x6d\x94\xe4\xf6\xd5\x12\x9a\x2a\x69\x2b\xf5\x8d\xf9\xc6\x36\x63\x17\x38\x49\xed\xc3\x29\x8e\x6d\x83\x92\xe4\x70\xb2\x36\xfc\x15\x26\x05\x3b\x08\xb7\xb4\x73\x2d\x58\x4e\xb8\xaa\xfc\x95\xd2\x0e\xce\xbf\x9a\xe6\xec\xc3\x28\xb5\x8c\xa0\xe0\xfc\x6d\xe6\x4f\x70\x8d\xb4\x10\xd9\xf0\xff\x1f\x65\xcc\xfe\xa5\x50\xd7\xb4\x22\xe6\x50\x8a\x47\x8b\x74\x15\xdf\xa9\xf2\xe5\x8d\xcf\xa5\x67\x81\x23\xed\x24\x99\x36\x6d\x97\x47\xe1\xd3\x8a\x77\x87\x11\x1b\x99\xab\xd4\x12\xfa\x31\x0a\x6d\xa3\x26\x97\x56\x97\x92\x03\x61\xd4\xf9\xee\xec\xfb\x6c\x7e\xec\x16\x3a\xd4\xce\x1f\x6b\x02\x19\x7c\x5a\x43\x8f\x17\x5e\xe0\xef\x9a\x6c\xc4\xb3\xbc\xab\x54\xa7\x8c\x38\xca\x6c\x75\xb9\x20\x6c\x8a\xe7\x85\x12\x1f\xc7\x57\x1c\xdc\x7a\x53\xa7\xcc\xce\xe7\x3d\x42\x84\x33\x5d\x11\xa9\x6b\xbb\xdd\x83\x18\xb9\x72\xc2\x33\xb6\x43\x3f\x83\x2f\xf5\x11\x00\x00\xff\xff\x2e\x1f\xe9\xa7\x1c\x02\x00\x00")
+var _assetsCoreApiGuestSyms = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x54\xdb\x92\xa5\x20\x0c\x7c\xe7\x7b\xb6\xf6\x77\x52\x01\xa2\x52\x83\x60\x41\x98\x73\xe6\xef\x37\x20\xde\x8e\x4e\xed\x93\xa6\x13\x92\x26\xdd\x3a\x12\x83\x0b\x4b\x61\xd0\x65\x18\x28\x81\xb3\x6a\x14\x2c\x16\xbe\x82\x26\x11\x32\x01\xbd\xcd\x84\x61\xa4\x9e\xd9\xe0\xcc\xf2\x9c\x5d\x18\x6f\x05\xaf\xe4\x1e\x8e\x3d\xa2\x30\x11\xda\x5f\x93\x99\x91\x4b\x06\x13\x2d\x29\x19\x66\x6f\x3d\x9f\xc0\xde\x32\x3f\x27\xff\xd7\x12\x16\x4c\xac\x5c\xbe\xe1\x2b\xc3\xc1\x05\x97\x27\x92\xdd\xf8\x98\xef\x77\x1c\x62\x7a\x61\xba\xf3\xd4\x98\xe9\xef\x1f\xb0\xd4\xe6\xf6\x88\x42\x67\x31\xba\xcc\x32\x41\xfb\xa8\xe1\xe5\x78\x82\x80\xf3\x07\xde\x04\x6a\x05\x4c\x66\x12\x71\x60\x48\x71\x5e\x0b\xf7\x94\xfe\x61\xca\x80\xb9\x4a\x23\xba\xa8\xc5\x97\x11\x86\x12\x0c\xbb\x18\x7a\xe4\x3c\xa9\x12\xda\xfb\x82\x3c\xb5\xc3\xeb\x4e\xd4\x22\x4b\xab\x13\x83\x11\x71\x89\x3f\xe3\x95\x1a\xb3\xbf\x24\x2c\x79\x62\xba\x40\xb5\xe5\x77\x8d\xeb\xd0\x13\x6e\xe2\x2c\xcb\x25\xc0\x60\x6f\xfd\xb5\x5c\x36\x00\x27\x0c\x19\x3b\xdd\xeb\xc9\xd9\xf1\xaf\x69\xd4\x31\x5d\xb3\x95\x42\x49\x5e\x0d\xc4\x66\xfa\x24\x77\x23\x9b\x8b\xbe\xdd\xd7\x60\xb8\xd5\xad\x9a\x5e\x17\x55\xc4\x05\x55\x85\xbc\xa0\x11\x29\x84\x03\x9f\xe2\x44\xdf\xf1\xeb\x54\x00\xad\xa0\xd1\xdb\xb1\xac\xea\xb0\x73\x93\x73\x76\x1f\x5a\x51\x1b\x67\x94\x35\xad\x56\xdc\x56\x2c\x5e\xed\xb8\x46\xf3\xd5\xec\xd7\x36\xcd\x4e\x8b\xd6\x53\xf4\xb6\xe7\x45\xf7\x73\xb4\xfb\xab\x9f\x96\x39\xd6\x8b\x57\x8f\x41\xeb\xd0\x4c\xf2\xc1\xb3\xca\xc7\xeb\xaa\xf9\x16\x1d\x35\xb2\x19\xf1\x9d\x9c\x26\x5d\xc6\x86\xb3\x13\x7f\x0e\x89\x8e\x7f\x07\x7a\x7f\x58\xf2\x12\xad\xfe\x9a\x63\x09\xd2\xe8\x21\x43\x41\x2c\x42\x8a\xde\x4b\x55\xdb\x22\x63\xfd\x8e\x94\x26\x46\x78\x91\x86\x25\xc5\xf7\x4f\xdd\x86\x18\x41\xb6\xb8\xd9\x5d\x6e\xb8\x19\xbe\x47\xfd\xff\x85\x8b\x03\x16\x75\xc2\xa6\xd2\x01\x54\xea\x7b\x94\xd5\x3f\xff\x2c\xa1\x15\x31\x05\x00\x00")
 
 func assetsCoreApiGuestSymsBytes() ([]byte, error) {
 	return bindataRead(
@@ -136,12 +136,12 @@ func assetsCoreApiGuestSyms() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/core-api-guest.syms", size: 540, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/core-api-guest.syms", size: 1329, mode: os.FileMode(436), modTime: time.Unix(1792311185, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsCore_api_guestRs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x1b\x6b\x6f\xdb\xc8\xf1\xbb\x7e\xc5\x20\x45\x73\x94\xe1\xd8\x39\xfb\x7a\x30\xe8\xd8\x40\x5b\x04\xe8\x15\xe9\x5d\x71\x87\xde\x97\x43\x40\xac\xc8\x91\xb4\x0d\xb5\x24\xf6\x71\x92\x1a\xf8\xbf\x17\xc3\x97\x96\xe4\x2e\x45\xc9\x4e\xdc\xa4\x22\x90\x58\xda\x79\x3f\x76\xf6\xc1\xd1\xc4\x28\x04\xa5\x93\x30\x8c\xb3\x34\xc5\x58\xf3\x4c\xa8\x30\xfc\x1b\x53\xcb\x7f\xb0\xfc\x76\x07\xe6\x59\x18\x7e\xfc\xab\x91\x2a\x93\xe7\xf0\x33\xb2\xe4\xa1\x04\xce\xb6\x1a\x33\x99\xa0\x0c\xc3\x8f\xef\xb8\xd6\x29\xbe\x15\x09\x67\xa2\x44\xfa\xcb\x56\xa3\x7a\xbb\xd1\x0f\xb7\x93\xc9\xe5\xd9\xd9\x04\xce\xe0\x9b\x38\x93\xf8\x0d\x2c\x0c\x2a\x0d\x7f\xfe\xe7\x0f\x30\xe3\x22\xe1\x62\xa1\x60\x9e\x49\x90\x46\x69\xc2\xa2\x7f\x5c\x43\xcc\x04\xcc\x10\x62\x96\xa6\x98\xc0\x5c\x66\xab\x02\x03\xe2\x2c\x41\x88\xb3\x55\xce\x69\x9c\x0b\x9d\xc1\x9a\xa9\x15\x30\x91\x00\x6e\x30\x36\x1a\x13\x98\x6d\x61\xb5\x7d\x95\xad\xc5\xab\x38\x35\x4a\xa3\xac\x19\x6f\x33\x53\x70\x26\xfd\xb9\x26\x3c\x96\x90\x0a\xa0\x97\x4c\x03\x17\x84\x50\xaa\x02\x2a\x33\x32\x46\x98\xf3\x14\x15\x30\x0d\x7a\x89\x30\xc3\x05\x17\x82\xf0\xc3\x9a\x23\xac\xb2\x04\xc8\xb0\x88\xe5\x3c\x2a\x6c\xbb\x2d\xc6\x49\x44\x7b\x3c\x0c\xcf\x08\x74\x39\x99\x5c\x5e\x02\x5f\xe5\x99\x2c\xb9\x56\x7e\x59\x65\x89\x49\x71\x92\x9b\x59\xc1\x53\xb2\x35\x7c\x9c\x00\x00\xfc\xe1\xb7\x94\x8b\x0f\x01\x99\x19\x95\x64\x51\x89\x0b\x77\xf0\x82\x68\x5f\x4c\xdf\x17\x88\xb8\xd1\x28\x45\x45\x45\x0f\xf1\x9a\x0b\x58\xa0\x8e\xb8\xc8\x8d\x8e\x66\x66\x3e\x47\x19\xf1\x24\x98\xc2\xab\x7b\x30\xd7\x57\xb7\x2e\xe4\xcc\xe8\x91\xd8\xb1\x44\xa6\x31\xc2\x4d\xbc\x64\x62\x81\x15\x89\x1f\x7f\x2d\xb9\x03\xbd\x11\x14\x9a\xeb\xab\x73\x88\x33\xa1\x51\xe8\x88\x32\x4c\x85\x70\x16\x67\x42\x69\x30\x37\x3b\x48\x8a\x62\xa1\x97\x21\x89\x38\x4c\x54\xb4\x44\x96\xf4\x25\x0a\xb6\xc2\x48\x69\xc9\xc5\xa2\x25\xaf\x18\xb7\x84\x9d\xc3\xef\x2c\x35\x4e\xd4\x12\x70\xbc\x62\x4a\x33\x6d\x54\x44\xf9\xdd\xd5\xce\x02\x85\x4e\xc6\x97\x97\x20\x51\x1b\x29\x54\x91\x50\x12\x59\x92\xa0\x00\xc5\xff\x83\xc0\xe7\x20\x51\x99\xb4\x72\x27\xcd\x16\x10\x99\x86\x1f\xff\xf5\xee\x5d\x31\x6b\x88\xa2\x56\x06\x4a\xc9\x25\x65\xa6\x97\x28\xd7\x5c\x61\xd7\x00\xe2\xbf\x2f\x86\xb6\xcc\x10\xce\x56\xa6\xf4\x52\x35\x3c\xe8\xa6\x8e\x35\x95\x4a\x65\xe0\x14\x4d\xd2\xbf\xff\xf2\xd3\x8f\x54\x33\x56\x4c\x8f\x51\xad\x8a\xb9\x6a\xab\xe8\x0d\xcf\x8c\x29\xfc\xfe\xbb\x28\xc1\x22\x14\x28\xe8\x4f\xe2\x8a\x78\x0d\x1a\x13\xf3\x8a\x69\x49\x12\x54\x93\xb1\x97\xde\xe5\xf8\x18\x7e\x12\x17\x9c\x0a\x5b\x34\x4b\xb3\x59\xb4\xe6\x7a\x19\x51\xae\x06\xe3\x13\xb9\x9e\x4a\x7a\x9b\x3b\x29\x5a\x70\x27\xe5\xe3\xa6\x67\xcb\x82\xe0\xb9\xb5\xa1\xaa\x57\xb8\x52\x63\xbc\x8c\x78\x12\xd1\x8a\x73\x98\x4b\xf7\xf3\x2e\x54\x8c\x98\xaa\xb8\x3d\x9e\x73\x9e\x9a\x45\x34\x37\xa2\x58\xbc\x83\x15\xea\x65\xe6\xcc\xd4\x0a\xd2\xf2\x5b\xce\xf4\xd2\x85\x5b\x8c\xb7\x30\xc7\xe7\x94\xd2\x4c\xea\x46\x21\x17\x4d\x07\xa3\x45\x9d\x30\xcd\x5c\x34\xc5\x78\x0b\x53\xb3\x85\x8a\xfe\xad\xdc\x22\x76\xc0\xf1\x2e\xe4\x29\xfe\x0f\xb8\xef\x09\xcd\x32\xa2\x30\x8c\xb4\xf9\x44\x86\x0d\xa6\x7b\xb9\x62\xf9\xb7\x01\x39\x4a\x45\xb3\x5f\xc4\x18\x29\xd4\xc1\x07\xdc\x3a\x26\x30\x8d\x3a\x16\xdf\x3e\xe2\xf8\xb5\x97\x94\x33\x32\x0d\x8c\x4c\x5d\x46\xd2\xf0\xa8\xac\xb1\xf4\x5f\x8c\xd5\x7f\x2c\xb7\x48\x99\x19\x39\x25\x97\x38\xe7\x1b\x67\x2c\x4a\xc8\x28\xde\x92\x0b\x1d\x25\x38\x33\x8b\x40\xe3\x46\x3b\x53\x8b\xc6\xc7\x7a\x4f\xf3\x15\x06\x09\x2a\x57\xc5\x2d\x86\xc7\x30\x9a\x4b\xec\x6c\x1c\x7e\x18\x5e\x94\xe9\x1c\xb0\xab\x74\x9f\xab\x22\x31\xb9\x30\x2b\x14\x5a\x45\xe4\x44\x26\x25\xdb\xee\x68\xdb\x08\x2d\xba\x55\x96\x38\xf5\x2b\xc6\x5b\x98\xe5\x8a\xdf\xdd\xb0\xd4\x9b\xa8\x6a\x1f\xee\x03\xe7\x99\xe2\x9b\xa2\x78\x45\x3e\x8f\x74\x51\xda\x53\xbd\x00\xee\x6c\x28\xe9\xbb\x76\xde\xf4\x11\xc7\x84\x18\x37\xc5\x49\x85\x8a\x37\x6d\x7e\xfc\xb5\x60\x86\x9a\x45\x6b\x9c\x45\xb9\xcc\x36\xdb\xa0\xf8\x3f\x52\x39\xc6\xde\x52\xd8\x45\x19\xa3\x0e\x57\x91\x96\x2c\x1e\xd0\xa3\x5e\x09\x34\xca\xcf\x96\x60\x63\x97\xbc\x7d\x75\xbe\x52\x9b\x3b\xab\x3c\xf7\x6c\x51\x0b\x5e\x0f\x93\x87\xc9\x64\xc4\x39\xb1\x3a\x55\x1a\xa1\xd8\x1c\xe1\x23\x1d\x4f\xc3\xd0\x4d\xd0\x65\xe9\x3d\x4d\x7a\x79\x3a\x28\x6c\xa6\x7b\x0e\x9d\x4e\xb6\x5e\x1a\x9b\xf1\x21\xa7\xd3\x10\x5e\xfe\x66\x6e\xde\x0f\x0a\xdd\xc3\xaf\xe1\x75\xc1\x54\x94\x6b\x19\x4c\x77\x23\x29\x8a\x60\x0a\x4c\x95\x21\xdb\xab\xe4\xc0\xb9\x36\x84\x97\x4a\xcb\x6a\x91\x2c\xbf\x1c\xae\x74\x8f\x7f\xc9\x9b\x14\x2f\x96\x81\x60\x6a\xd9\xd0\x85\xd8\xb6\x54\x7a\xb8\x09\x7b\xa0\x43\xbd\x70\xf8\x21\xfa\x00\x17\x38\x99\xb7\x18\xb7\x55\xdc\x7f\x4c\x2e\x94\xf8\xb9\x38\x12\xbf\xf9\x15\xe3\x37\xe6\xe6\xfe\x9c\x94\xba\xaf\xb4\x4a\x51\xd7\x27\xe6\xe2\x44\x7e\xd7\xd1\x73\x58\xc2\x79\x79\x7f\x97\x6b\x19\x86\xc2\xa4\x69\xb4\x32\x9a\xbc\xfc\x7a\x0a\x0f\xb7\x0d\x7f\x3a\x99\x97\x32\xe0\x0e\x7e\xc5\x38\x0c\x8b\xd3\x64\xcc\x72\x16\x73\xbd\x0d\x6c\xf9\x14\x08\xfa\x30\x2d\xc9\x4b\xd0\x85\x44\x1a\x73\x22\x92\xb0\x5b\x87\x83\xf7\x29\x5e\x71\x66\x8a\x74\xae\xb3\xc3\x12\xd0\x58\xf0\xd3\x87\x4a\xee\x74\x8f\xe3\x07\x2e\x01\xaa\x00\x54\x37\x9f\x6f\x7e\x29\x2a\xe8\x79\xf9\xc7\x1b\x8f\x86\xcd\xa8\xa0\xf4\xa5\x37\x16\x58\xf7\x33\x3b\x96\x77\xf0\x7a\x33\x9f\xcf\xe7\xd6\x5d\xde\x5b\x29\x83\x3f\x4d\xab\x9a\x4d\xff\x63\xaa\xd0\x82\xf7\x74\x83\x3b\x77\x06\x76\xa5\x4d\x77\x8b\xca\x8a\xe9\x78\xd9\xe1\xb2\x93\xd0\xf2\x77\x05\x9e\xc2\xdd\x7d\x07\xc5\x99\x58\x95\x73\xc3\x50\xe0\x3a\xb0\x24\xda\x04\x96\xda\x96\x04\x4a\x02\x95\x72\x5a\xb9\xfb\x64\xf6\x3e\xd2\x61\x97\x5f\xaf\x84\xe4\x94\x97\xda\xa5\x4e\x95\x39\x6e\xcd\x88\x24\x36\x52\xa2\xd0\xd1\x07\xdc\x42\x08\x65\x76\xc0\x1d\xbc\x78\x71\xa1\xb3\xfa\x40\x3f\x24\x52\xcc\x2a\xd5\x14\x59\x98\xc8\x8b\x22\x38\xe6\xfa\x2a\x0c\xdf\xd8\xd7\xe7\xf7\xc1\xf4\xc2\x88\xb5\x64\xb9\xcb\xe0\xf5\x92\xa7\x68\xf3\xba\x87\xd7\x0e\xff\xb7\x5d\x5a\x17\x8f\xa3\xa5\xd2\xe3\x95\x41\xc6\x35\xa1\x73\x14\x10\x5b\x87\x4e\x01\xe9\x3e\xcd\x44\xaa\x42\xaf\xb0\x38\x4e\x78\x58\x54\xf3\xa0\xfb\xd4\x56\x06\x2f\x77\x9a\xb9\x02\x53\xeb\x4f\xf1\x28\xc3\x19\x86\xc5\xad\x8f\xd1\xf3\x9b\x4a\x24\xc5\xf6\x77\x8c\x83\xe9\x51\xde\xe1\x73\x3b\x52\x7f\x84\xab\x62\x62\x7b\xc2\x45\x8f\x9d\x63\x77\xa0\xdc\xa2\x1e\xba\x33\xbf\xe7\x80\xb2\x78\x72\xa1\x50\xea\xc0\xe2\x79\x11\xa7\x99\x40\x2a\xa5\xca\x63\xc6\x83\xdb\x4d\xad\xdc\xb5\xbe\xbc\x82\x6f\x6f\xbd\x1e\x70\xf0\xb2\xaa\x75\x0b\xf3\xbc\xf5\x95\x2a\x1d\x4a\x5f\x69\x21\xe8\xf7\x5e\xfa\x87\xde\xa6\xd6\x79\x9d\x6b\x6d\x83\xc6\x2d\xbf\xfe\x72\xef\xe4\xef\xde\xde\x38\x80\x9d\x0d\xce\xe1\x4b\xc2\xb7\xa3\x96\x84\x47\xae\x05\xbe\x45\xc0\x13\xa2\xcb\xcb\x3d\x75\xf9\x53\xe5\xc5\xd5\xe1\x79\x61\xdf\xc8\xdb\x5b\xfa\x2a\x2d\xaa\xcd\xc0\xb1\x59\x61\x73\xb7\x12\xa1\xfc\xfe\xe8\xd8\x5f\x7f\x49\xdb\x81\x26\x11\xfb\xb5\xb6\xcd\x62\xa0\xd2\x3e\x5b\x62\x7d\x77\x40\x62\x0d\xbd\x9a\xa9\xcf\x62\xf6\xeb\x8c\xce\xd8\x13\x26\xe1\x90\x26\xc7\x9c\xdc\x6c\xad\xdd\xf4\x3e\x0c\x27\x9f\xfd\xa7\xde\xd3\x94\xf8\x0a\xa7\x44\xf0\x0c\xc9\x1f\x9c\x52\xf7\x94\xba\x47\xa5\xee\x9e\x17\xc3\xfd\x8d\xe4\xb1\xf9\xba\x47\xd0\xe1\xf5\xfa\x94\x84\x5f\x5f\x12\xba\x3a\x08\x3e\x41\x0a\xba\xc4\x9c\x12\xf0\xff\x38\x01\x5d\x8d\x26\xf5\xd2\x9d\x33\xbd\xac\x3f\xdb\x7b\xdc\xf6\x7b\xaf\x7a\x34\x61\x9a\xd5\x9f\x9b\x96\x86\x11\x6f\x25\x5c\x1a\xb8\x53\xb2\x0f\x6b\x2f\xe1\xa4\xaf\x9b\xb2\x0b\x69\xd3\x1d\xbb\x6b\x6e\xfb\xc1\xcd\xc1\x8f\xd3\xe6\x45\xde\x73\x73\xe8\x42\xda\x74\x8d\xa7\xdd\xc4\x4e\xb0\xff\xdd\x4b\xb7\x67\x66\x5f\x26\x1c\x1e\xe7\x1d\xef\x2f\x23\xc6\x4f\xeb\xdf\x7e\xf3\x4e\xdf\xc3\x83\x4e\xec\x33\xf8\x9c\x6e\xec\xbf\x01\xb6\xdb\x81\x9e\x60\x8d\x6a\xd8\x9d\xd6\x97\xaf\x65\x7d\xe9\xf7\x83\x55\x47\xc1\xdd\x7b\xeb\xbd\x6f\xdb\x1d\x4c\xac\x8c\xa5\x6f\x9e\x17\xd1\xad\xb7\xcf\xc3\x99\x5c\xf5\x8e\x3d\xe1\xfd\xb1\xc5\xd5\x3d\xd5\x3a\x80\xd3\x8d\xf1\x53\x26\xe9\x21\x37\xc6\x8e\xa6\x3f\xc7\x7d\xc5\xd1\x89\xe0\x60\x3f\x90\xbe\xa7\xd0\x3f\x63\xe8\xdb\x1d\x9a\xfd\x62\xf0\x54\xad\x04\x83\x62\x3d\x0b\x73\x0f\xf6\xe8\xac\x39\x75\x1d\x74\x9e\x53\xd7\xc1\xa9\xeb\xa0\xfb\x9c\xba\x0e\x4e\x5d\x07\xf6\x82\xd1\x69\xbb\x1f\x73\xf2\xed\x90\x78\x8e\x92\x1d\xc8\xf0\x76\xb5\x69\xd6\x1f\xb3\x7f\x6e\x11\xd8\x57\x0b\xf4\xd5\x2f\x67\x5f\x2f\xbf\x53\x96\x8b\xa8\xd3\xe4\xdb\xeb\xf8\x1f\xbe\x58\x6a\xba\xd3\x0b\x53\xaf\xaf\xde\x97\xed\xf6\x35\xf8\x29\x1b\xed\x9b\xd3\x78\xbb\x2d\x9e\x04\x13\x60\xd8\xcb\x7d\xbb\x9e\xfb\x52\xa9\x31\xc0\xa2\xdd\x8d\xb5\x71\xc9\xa5\x9e\xbb\x84\x0e\xa4\x4d\xe7\x73\xff\x80\xeb\x7b\x6e\xf7\x6c\x78\xfc\x48\x5d\x97\xd9\xcd\xb0\x75\x23\xac\x95\x71\xce\x5f\x2c\x3c\x76\x57\xdf\x67\x7a\xda\xb7\x7f\xbe\x7d\xfb\xf0\x6f\x4b\x46\x14\xe5\x61\x06\xbe\x2d\xb8\x17\xc9\x5f\x45\x7b\xbf\x4f\x71\xaa\x63\x61\xb9\x2e\x64\xeb\x9f\xae\x8c\xbd\x82\x7f\x8a\x0b\xb9\xae\xec\xe7\xae\x66\xc7\x5d\x91\x9f\xee\x12\xbf\x9a\xbb\xc4\xee\x4f\xa2\xc6\xdf\x97\x37\x24\xee\xfc\xe1\x43\x9d\x9b\xa4\xc0\x7f\x03\x00\x00\xff\xff\xb0\xa6\xef\xd7\x53\x45\x00\x00")
+var _assetsCore_api_guestRs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x1d\x6b\x8f\xe3\xb6\xf1\xfb\xfe\x0a\x22\x45\x13\xef\xc1\xf7\xc8\xe5\x7a\x38\xf8\x1e\x40\x5b\x04\x68\x8a\x34\x29\x92\xb6\x5f\x82\x40\xa0\x25\xda\x56\x4f\x96\x5c\x91\x3a\xaf\x1b\xdc\x7f\xef\x0c\x29\xc9\x22\x45\x52\x94\xed\xdd\xbd\xdb\xca\xc0\xdd\xae\x45\xce\x7b\x38\x1c\x52\x43\xee\x55\xc5\x19\xe1\x22\x59\x2c\xe2\x22\xcb\x58\x2c\xd2\x22\xe7\x8b\xc5\x5f\x28\xdf\xfc\x8d\xee\x5e\x5f\xb5\xcd\x69\xb1\x58\xfc\xf6\xe7\xaa\xe4\x45\x39\x27\x3f\x31\x9a\x7c\x54\x8d\xcb\x83\x60\x45\x99\xb0\x12\x9a\xbf\x4f\x85\xc8\xd8\xb7\x79\x92\xd2\x5c\x75\xfa\x13\xb4\xf2\x6f\x6f\x04\x74\xbe\x7a\xfa\xe8\xd1\x15\x79\x44\xbe\x8a\x8b\x92\x7d\x45\xd6\x15\xe3\x82\xfc\xf1\xef\xdf\x91\x65\x0a\x00\xf9\x9a\x93\x55\x51\x92\xb2\xe2\x02\x7b\xe1\xbf\x54\x90\x98\xe6\x64\xc9\xe0\x07\xf0\x96\x90\x55\x59\x6c\x65\x0f\x12\x17\x09\x3c\x2d\xb6\xbb\x14\x9f\xa7\xb9\x28\xc8\x9e\xf2\x2d\xa1\x79\x42\xd8\x0d\x8b\x2b\x01\x8f\x97\x07\xb2\x3d\x3c\x2e\xf6\xf9\xe3\x38\x03\x20\x56\x36\x88\x0f\x45\x25\x31\x23\xff\x40\x04\xfa\xd1\x04\x59\x20\x62\x43\x05\x60\xc3\x0e\x8a\x15\xc2\xe1\xb7\x98\x91\x15\xd0\xe1\x04\x1a\xc5\x06\x44\x66\xeb\x34\xcf\xb1\xff\xa2\xc1\x48\xb6\x45\x42\x50\xb0\x88\xee\xd2\x48\xca\xf6\x5a\x3e\x47\x12\xfa\xf3\xc5\xe2\x11\x36\x3d\x05\x85\x3c\x25\xe9\x76\x57\x94\x0a\x6b\xad\x17\x40\x54\x65\xec\x6a\x57\x2d\x25\xce\x92\xee\xc9\x6f\x57\x04\x3e\xbf\xfb\x25\x4b\xf3\xf7\x33\x14\x33\x52\x60\x91\xea\x4b\xde\x92\x2f\x10\xf6\x8b\xeb\x5f\x65\x47\x76\x03\xa2\xe6\x35\x14\x7e\x10\xd7\x2a\x27\x6b\x26\xa2\x34\xdf\x55\x22\x5a\x56\xab\x15\x2b\xa3\x34\x99\x5d\x93\xc7\xef\x48\xf5\xcd\xf3\xd7\xb6\xce\x45\x25\x02\x7b\xc7\x25\xa3\x82\x45\xec\x26\xde\xd0\x7c\xcd\x6a\x10\x4b\x7f\x10\x59\xf5\x05\x65\x12\xd5\x8b\xec\x37\x05\x28\x09\x9e\x26\xf8\x85\xa6\x42\x79\x02\xea\x64\x5f\xa6\x20\x0b\x41\xeb\xe2\x6f\x04\x1e\xc7\x59\x21\xad\x36\x97\xb6\x56\xb0\x75\x37\x05\xbb\xdf\xb0\x1c\x20\x0a\xb2\xad\xe2\x0d\x49\xa8\xa0\x24\xe5\x24\x2f\x84\x24\x41\x0e\x4c\x38\xb8\xe7\x02\x7e\x6e\xc1\xac\x01\x72\xd4\x90\x92\x70\xaf\x7b\xab\xb0\x05\xc0\xcc\xc1\xfc\xb9\x60\x39\xe8\x11\xc7\xc2\x82\x3c\x82\xef\xe0\x58\xd5\xab\x63\x4b\xc6\xf2\xb5\xd8\x2c\x90\xc4\x38\x52\xd1\x46\x6a\xcd\xa4\x98\xd3\xad\x14\x07\x64\xd1\xe8\xc9\xe7\x1d\x62\x73\xf2\x81\x66\x95\xb5\xab\x6a\x38\x9d\x31\x2e\xa8\xa8\x78\x84\xe3\xd4\xe4\xae\xd3\xb4\xb0\x22\x06\x27\x29\x99\xa8\xca\x9c\x4b\x27\x40\xb3\x25\x60\x53\x9e\xfe\x17\x0c\xbf\x82\xef\xbc\xca\x6a\x75\xe2\xa8\x97\xb6\xfd\xe1\x9f\xdf\x7f\x2f\x3d\x02\x21\x1a\x66\x1a\x0f\x93\x90\x05\xb4\x94\xfb\x94\x33\x53\x00\xc4\x3f\x64\xc3\x2e\x4d\xd0\xd3\xb6\x52\x5a\xaa\x1f\x7b\xd5\x64\x48\x53\xb3\xa4\x0c\xc7\x31\xd8\xfc\xf5\xe7\x1f\x7f\x40\x8f\xdf\xd2\x9e\x67\xda\x58\xab\x6d\xce\x75\x16\x9d\xe6\xb1\xa2\x70\x5a\xc7\xcb\x3e\x44\x4c\x53\xb3\xe8\xc1\x34\xcd\x55\xec\x64\x24\x87\xd8\x43\x76\x14\x22\x1a\x7a\x05\xf8\x36\xca\x77\x94\x7a\x2e\x87\x28\x76\xc6\x01\x0e\x91\x17\xac\x99\x33\x96\xb0\xe4\x09\xf9\x87\xb4\x34\x12\xc2\xc8\xad\xb0\xc3\xb8\x65\xdb\x9d\x38\x90\x22\x87\x18\xdc\x09\x08\x1b\xb0\xfa\x0a\xc8\xf2\x0d\x4b\x82\x04\x46\x9e\x46\x48\xfa\x35\x72\x66\xd0\x93\x81\x27\xd1\xa4\x79\xe6\x76\xaa\x94\xf7\x78\x50\x03\xa5\x61\x3b\x80\x1b\x9e\xae\x73\x9a\x71\x35\x2b\x81\x93\x6f\x50\x73\x5b\x88\xf4\x64\x9f\x66\x19\xce\x8b\x56\x2d\xf7\xc2\x1b\x32\xee\x77\x70\x2b\x79\xc4\x08\xe1\x15\x27\xdb\x5d\xca\x94\xf7\xd6\xd3\x61\x6d\x1f\x88\xcb\xf8\x50\xd0\x12\xa6\x0b\x30\x12\x6b\x19\xa1\xf1\xfb\x75\x59\x54\x30\x1e\x71\x4c\x4a\x0e\x78\xb7\xaf\x0c\xd2\x09\x40\xcc\xe5\xd3\x55\x95\xcb\xcc\x43\xce\xca\xec\x06\x3c\x63\xcb\x68\xbe\xdf\xc0\xac\x6b\x4a\x03\x9e\xb3\xa7\x65\x7f\xc0\x2a\xce\x22\x63\xdc\x2a\x72\x51\xd8\x50\x59\x52\xce\x5e\xbe\x88\x12\x26\xc7\x05\xcb\xf1\x47\x62\x0b\x8e\x4d\x53\x48\x78\xac\x91\x2a\x90\x59\x3d\xff\xf6\x66\x02\xf5\x3c\x04\x5f\x09\xc9\x07\xe6\x32\xd1\x32\x2b\x96\xd1\x3e\x15\x9b\x08\xc3\xfa\x2c\x3c\xe6\x37\xb3\x8e\x38\xec\xac\x10\x5a\xbb\x15\xf2\xbc\x99\x4c\x93\x60\x76\xdf\xdc\x48\xf7\x40\x55\x0a\x16\x6f\xc0\x43\x22\x4c\x32\xc7\xa9\x74\x18\xb7\x64\x31\xa2\xbc\xc6\x76\x3e\xe6\x5d\x56\xad\xa3\x66\xd4\xcc\xb6\x4c\x6c\x0a\xab\xa7\xd6\x2d\x9a\xde\x76\x14\x7c\xc6\xd2\x57\x3e\xd7\x7a\x86\xfb\x14\x4c\x28\x90\x8f\x36\x0c\xd9\x60\x8c\x1e\x1a\x34\x26\x69\x36\x18\xf9\x5c\xeb\x29\xe8\x9a\x47\xff\xe6\x76\x12\xc7\xc6\x70\x15\x42\x84\xf9\x04\xd4\x77\x41\xb1\xaa\x5c\x0a\x86\xdc\xdc\x92\x60\x5e\x77\x57\x99\x85\x3b\x63\xde\x41\xe6\x82\xa3\x1f\x26\xf4\x88\x33\x31\x7b\xcf\x0e\x96\x01\x8c\x4f\x2d\x79\x6a\xbf\x63\x78\x9a\x6a\x10\x56\x91\x13\x96\xab\x17\xe7\x00\x0c\x26\x32\x20\x01\x5d\x12\xee\x9d\x6f\xba\x2c\x25\x2c\x63\x82\x85\x31\x63\x9d\xad\xd7\xe9\x87\x7a\x8a\xfd\x80\x68\x61\x2e\x2d\x54\x0a\x03\xc0\x98\xa9\xc8\x19\x17\x26\xd6\xa4\x60\x2a\x63\x86\x69\x96\xf7\x12\xce\x2e\x4b\x68\xce\x1a\x57\xa8\x92\x54\x3e\x5c\x27\xc8\xe9\xcb\x17\x03\x9c\xe6\x6c\x1f\xca\xed\x86\x7e\x60\x75\x6e\xbf\x63\x31\xae\xee\x6b\x40\x9f\x04\xb8\x41\x40\x71\xed\x9d\x27\x63\x9c\xad\x21\xd1\x48\xbf\x40\x51\x46\x79\x40\x90\x1e\xda\xfc\x12\xd7\xbb\x59\x56\xec\x31\xbd\x03\x76\x81\xa1\x1d\x60\xce\x45\x9a\xc9\x3e\x20\xc5\x16\xd7\xbb\xa2\xa4\x39\xa7\x6a\x87\x46\xf6\x53\x99\x96\x80\x55\x2e\xdd\xa5\x64\x41\x38\x03\x86\x69\xd6\x20\xc5\x2e\x74\xb7\xcb\x52\x50\x16\x15\x32\x87\xf6\x29\x4b\xee\x6a\x44\x1d\x22\xf6\xe5\x7b\x8d\x5c\xe6\x72\xc7\xbe\x98\xf8\x61\xfe\x27\xd9\x99\xbb\x9d\xb1\x91\xa5\xb6\x30\x45\xe5\xab\x75\xb9\xca\xe6\x7a\xd9\xbc\x61\x4e\x00\xd6\x58\x1c\x56\xb4\x05\x0f\x5d\xe2\xe6\x89\x5f\xd2\x4e\x48\xab\xca\x6c\x06\xff\x6c\xa1\x11\x1f\x87\x04\x9f\x15\x03\xb5\x00\xb3\xff\xc1\x0d\x20\x67\xa0\xd7\xda\x35\x7f\x5a\x16\xc9\x21\x30\x89\x35\x46\xf0\xa9\x11\xc5\x11\x0f\x78\xb5\xc4\x81\xb4\x2b\xd9\x2a\xbd\xb1\x4e\x16\xaa\x65\x74\x44\x86\xc4\x7f\x06\xd2\x97\x07\xa7\x76\x3a\xad\xa7\x70\xae\xfc\x8b\xdf\x1e\x91\x0a\x16\x59\x38\xb3\xf3\x1d\x8d\x55\xee\x28\x7f\x73\x25\x00\xaa\x31\x68\x5a\x05\x47\x15\x67\xa1\x9e\x2b\x1c\xcc\x0a\xd1\x34\x69\xfd\x69\x1c\x33\xce\x6d\xdd\xeb\x96\xb0\x44\xff\x43\xf1\xbe\xa3\x94\x48\x92\xba\x03\xfe\xbd\xc3\xb9\xa5\xe0\xc9\x52\xd0\x1d\xcf\xd4\xf8\x45\xfd\x4c\xe3\xbb\x75\xe5\x4f\x82\xad\xa4\xd8\x52\x98\x37\xd4\xe6\x46\x93\x2e\xd4\x0f\x6d\x29\xbd\x6a\x09\xc1\x9e\xf2\x06\x39\x6e\x27\xc8\x45\xbf\x9c\xd0\x45\xba\x84\xa4\xfd\x22\x24\x36\x45\x96\xd4\x44\x2e\x83\xb0\xca\x2f\x8e\xb2\x5d\x2e\xd7\x30\x60\xfc\x24\x63\xe5\x28\xe4\xf3\x76\x87\x25\x72\x2d\x4a\xf4\x0e\xe3\xac\xef\x19\x49\x72\xd2\x60\x71\xc9\xc4\xf9\x8b\x5e\x3e\x1e\xd7\xa8\xac\xad\x51\x81\x35\xee\x1d\x1b\x43\x58\x55\xe9\xfc\xc5\x24\x3f\x6a\xd1\xb7\xb4\x02\xfc\x60\x11\xb6\xac\xd6\x33\xc1\x6e\x84\x75\x2d\x89\xcf\x43\x29\x8a\x74\x0b\xe3\x0c\x33\x92\xbe\xfa\xe4\xe3\xa0\xd4\xa7\x64\xc6\x9e\xe3\x77\xfe\x04\x06\xdf\xf5\x1d\xb7\x36\xee\x6a\x0b\x82\x96\xeb\x6a\xcb\x72\xc1\x23\x54\x22\x2d\x4b\x7a\x38\xc2\xea\x1d\x34\xb8\x6d\x91\x58\xf9\x93\xcf\xb5\x9e\x6a\x8b\xcf\xdc\x14\x6e\x36\x2a\xeb\x77\x6d\xae\xe6\x5d\xc1\x21\xab\xc2\xdd\x0a\xe7\x00\x36\xbb\xe8\x6b\x7b\xd9\x78\x94\x41\xc1\x9b\x72\xbe\xea\x77\x0c\x31\xb1\x66\x31\xb5\xb0\xde\x16\xb0\x7e\xe1\x93\xf5\x3e\x1d\xeb\xa1\x50\xd5\x11\xd8\x10\xb7\x3a\xc3\xd6\x00\x95\xe6\x63\x36\xa0\x27\x5b\x7f\x52\xb6\xc6\xf7\x19\x68\x41\xfb\x9b\x0e\xd9\x12\xe2\x19\xec\x46\xd6\x24\xe0\x9e\x2d\xbe\xf3\x70\xcf\x53\x4b\x26\x68\xb4\x67\xcb\x68\x57\x16\x37\x87\x99\xfc\x3f\xe2\x3b\x16\x3b\xd3\x52\xb3\x4b\x60\xf6\x08\x2b\xfc\xd8\xc3\x47\xb3\x01\x0c\xb9\xd5\x9d\x39\x6f\xe8\x4e\xf7\xd0\xf6\x6e\xcd\x76\x6a\xdd\xdc\x4d\xc3\xde\x4c\xd5\x65\x0f\x58\x98\x22\x60\x9d\x36\x66\xae\x2d\x8b\xcc\xda\x53\x3e\x1f\xb1\x34\x1c\x4f\xdb\x9b\xb0\xb4\xe8\xba\x59\x92\xec\xf8\xf1\xea\xe3\xd5\x55\x40\x1d\x4c\x5d\x35\x53\xe5\x9c\xae\x18\xf9\x0d\xcb\x6f\x16\x0b\x3b\x80\x89\xd2\x59\x2d\xe3\xc4\x69\x81\xe8\x22\x1d\x28\xaa\xb1\xa2\x75\xc2\x58\x10\x0f\xd7\xbb\xf8\x48\xf8\xa0\xbb\xc4\xc6\x94\xc8\x2c\xc8\x97\xbf\x54\xaf\x7e\xf5\x92\x1f\xc0\xd7\xe2\x7a\x42\x79\xb4\x13\xc0\xcd\xf1\x09\xb8\x11\x30\x47\xb9\xf2\xa3\x41\x26\x3d\xc5\x35\xc0\x28\xc8\x5f\x2f\x22\xd4\x97\xf1\x4c\xf7\xf0\x2b\xdc\xc8\xb8\xcc\xb7\x67\xd7\x1d\x19\xcc\x96\xae\x2c\x35\x1f\x76\xc0\x5e\xd3\x58\x2d\x8c\xaf\xe4\x19\xa1\x02\x2b\x72\x0d\xb1\xce\xe2\x70\xad\x8e\x64\xe2\x27\xb9\x2d\xfc\xe6\x5f\x2c\x7e\x53\xbd\x7a\x37\x47\xa6\xde\xd5\x5c\xc1\xba\xac\x29\xdb\x91\x65\x41\x6f\x0d\x3e\xfd\x14\xe6\xaa\x18\x12\x74\xbb\x58\xe4\x15\xa4\x42\xdb\x4a\xa0\x96\x9f\x01\x9b\xaf\x5b\xfc\xb8\x19\xad\x68\x00\x7a\x60\x02\xa4\xc7\x44\x29\xa6\x3b\x1a\xa7\xe2\x30\xeb\xd2\x47\x43\xe0\x2f\xd7\x0a\x5c\x35\x3d\x81\x1f\xf0\xcc\xda\x11\x89\xbd\xb6\x28\x78\x88\xf1\x1a\x33\x78\x02\xb0\xd7\x78\x47\x87\x40\x2b\xc1\x8f\xef\x6b\xba\xd7\x03\x8a\xf7\x54\x22\xd5\x06\xa8\xcb\x48\xdf\xfc\x2c\x03\xfb\x5c\xfd\x70\xda\xa3\x45\x13\x64\x94\x3e\xf5\x56\x82\x4e\x91\xd8\x11\xe5\x5b\xf2\xec\x66\x05\x9f\x4e\x61\xe4\xb7\x65\x39\xfb\xc3\x75\x3d\x41\xe0\xff\x2c\xe3\xac\xd3\xde\xe3\x0d\x18\xb3\xaa\xd9\xa4\x76\x7d\x9c\x9e\xb6\xf2\x05\x8d\x8e\xe5\x48\x41\xd3\x77\xdd\x7c\x4d\xde\xbe\x33\xba\x58\x1d\xab\x56\x2e\x38\x22\xdb\xcf\x3a\x14\xbb\x00\x1d\xb6\x3b\x14\xd0\x09\x78\x96\x62\x72\xd4\x07\xeb\x2e\xd8\x2d\x72\xb9\xf9\x4a\x90\x8e\xaa\x10\x56\x3c\xd5\xe2\xd8\x39\x43\x90\xb8\x2a\x4b\xac\xd3\xc0\x97\x40\x0b\xa2\xbc\x03\xab\x59\xbf\x78\x22\x8a\xa6\x54\xc2\x47\x32\x5f\xd6\xac\x71\x94\x30\x29\x9f\x48\xe3\x80\x73\x2d\x16\x6f\xba\xb5\xc8\xef\x20\xec\x55\xf9\xbe\xa4\x3b\x9b\xc0\xb2\xcc\xa8\x8b\xeb\x1d\x79\x66\xd1\xbf\xae\xd2\x26\x78\x9c\x4c\x15\x3f\x4e\x1a\x28\x5c\x6b\x3a\x4b\x00\xe9\xf2\x60\x04\x10\xf3\xd3\x0e\xa4\xda\xf4\xb8\x89\x86\x53\x80\x1d\x45\x3d\x0e\xcc\x4f\x23\xe5\xec\xcb\x23\x67\x36\xc3\x34\xfc\xa3\x3d\x94\x39\x17\x0b\x59\x4f\x53\x89\xd5\xab\x9a\x24\xda\xf6\x03\x8b\x67\xd7\x27\x69\x07\x2b\x06\x8f\x96\xfa\x3d\x79\x2e\x07\xb6\xc3\x5c\xf8\xe9\xfa\xd8\x5b\xc2\xed\xa4\x3e\x9a\x23\xbf\xa7\x00\x15\x3c\xd3\x9c\xb3\x52\xcc\x3a\x38\x9f\xc4\x59\x01\x8b\x5f\x08\xa5\xdc\x21\xc6\x47\xbb\x9a\x34\xdf\xed\x7c\x79\x4c\xbe\x7e\xed\xd4\x80\x05\x57\x27\x5a\x6b\x3d\xe7\xda\x57\x8c\x74\xac\x74\x85\x16\x6c\x7d\xe9\x84\xff\xd8\xcb\xa0\x4f\xab\x29\xb5\xa6\x05\xe1\xa8\x86\x13\x01\x57\x95\x67\x58\x36\x30\x72\xf6\xd1\x69\x9d\x34\xf5\x7c\x1d\x34\xf5\x9c\x39\xe7\xb8\x26\x1b\x87\x2b\x3c\x7d\x3a\x10\xff\x6f\xcb\xff\x9e\x8f\xf0\xbf\x13\x8b\x6b\xad\x0e\x38\x02\x97\xb1\x8a\x0a\x2f\xab\xb5\xaf\xa3\xfc\xf0\x3a\xb1\xcb\x55\xbd\x5a\x79\x09\x45\xdf\x47\xad\xb3\x69\xad\xa0\xed\xac\x8f\xce\x1d\x89\x56\xfc\xf6\x75\x8f\xa5\xd1\x58\xf9\x4c\x03\xf6\xce\x06\xac\xa5\x08\xba\xbb\xd6\xaf\xdd\xa2\x5e\x25\x9c\xea\x15\x5d\xec\x1d\x47\x50\xdf\xcf\xb6\xfd\x37\x9f\xd3\x3a\xa1\x75\xc4\x7e\x12\xa6\xa3\xf0\xa4\x60\xf7\xe6\x58\x2f\x46\x65\x22\xee\x6a\xf8\x66\x93\xa6\x5b\x41\x6e\x3c\xbb\xa0\x13\xfa\x38\x39\x65\x4b\xa7\xcb\xb5\x1d\xde\xd5\xc3\x8a\x67\x78\x3b\x6c\x1a\x12\x0f\x70\x48\xcc\xee\xc1\xf9\x67\x93\xeb\x4e\xae\xdb\xb6\x8e\x71\xdd\x81\xb3\x38\xfd\x44\xf2\x54\x7f\x1d\x20\x34\x3e\x5e\x4f\x4e\xf8\xf0\x9c\xd0\x76\x68\xeb\x16\x5c\xd0\x46\x66\x72\x40\xfd\xf3\x7f\xe5\x80\xb6\xb3\x7d\xcd\xd4\x8d\x67\xb1\x9a\xdf\xbb\x39\xae\x5e\x73\xd0\x3c\xc5\xaa\x82\xe6\xf7\xf6\x14\x59\xc0\xeb\x4a\x1b\x07\x76\x97\xec\xb7\xe9\x53\x38\xf2\x6b\x87\x34\x5b\x74\xb8\x53\xb3\x66\x5d\x0f\x76\x0c\xee\x3e\x3a\x2e\xd4\x9e\x1d\x83\xd9\xa2\xc3\xb5\x9a\xb6\x03\x5b\x9b\xdd\x2f\x65\xcd\x63\x8a\x43\x9e\x30\xde\xce\x47\xdc\x9f\x87\x8d\x2f\xab\xdf\xfe\x79\xc9\xbe\x86\xbd\x4a\xec\x23\xb8\x4b\x35\xf6\xeb\x50\xba\x27\x30\x2f\x30\x47\xb5\xe8\xa6\xf9\xe5\xa1\xcc\x2f\xfd\x23\xb8\xf5\x52\xf0\x58\xd0\x32\x58\x86\x63\x41\xd2\xf1\x58\xfc\xe6\xa8\x50\xd1\xca\x52\x3c\x61\xcf\x73\x5c\xd7\xca\xad\xf3\xd8\x6d\x08\xfb\x1a\xfa\x33\xe5\xd0\x18\x71\x0b\x75\x3c\xf0\x3b\x56\xdd\x47\x48\x0f\xa7\x6e\xc2\xc6\xb1\xde\x2e\xf5\xf4\xe5\x8b\x4e\x78\x38\x16\x1b\xc8\x03\xb0\x30\x32\x9e\xd9\xea\x4e\x3c\xb8\xbd\x9a\xfc\xf2\x88\xbf\x0d\x2e\xea\xab\x83\x71\xcb\x69\xde\xd6\xf6\xbe\x23\xbb\x17\x95\xd0\xc2\x84\x57\x4a\x93\xb1\x30\x0f\x1a\xad\x1b\xf7\xe1\xdd\x41\x77\xb2\x81\xba\x7c\xc7\x72\x02\xf7\x62\x6a\x35\x11\x8f\xd6\x81\xfb\x58\xef\xa0\x0e\x6c\xa0\xe6\xbc\x5a\x1f\x03\xbe\xe0\xdb\xac\x0e\x56\xfb\xc4\x6f\x34\x4c\xef\xaf\x74\x84\xe7\x4d\x99\x63\xde\x5f\xf5\xcf\x6f\x37\x49\xa2\xfb\x64\xb6\xfd\x45\x6b\x0f\x91\xdd\xf2\xae\x1e\x7a\x98\xd0\x89\x7b\x43\xbe\x19\xea\xcf\xf5\x5d\x0b\x7a\xff\x6c\x34\x79\xeb\x9d\x79\xab\xf7\xd0\x7e\x3f\x7e\x5d\xaa\x48\xd3\x4b\xd6\xb1\xb2\xe9\xb5\x9d\xed\x35\x53\x3d\xa7\xf1\x99\xea\x39\xa7\x7a\x4e\xf3\x33\xd5\x73\x4e\xf5\x9c\xae\xc5\xb6\x7e\x13\xcb\xe5\x5e\x78\x78\x88\xd8\x27\x07\x7b\xfb\xf4\xd2\xe3\xc1\x6f\x4a\xd9\xaf\xeb\xb9\x1d\x4f\xb4\xd3\x9a\x1c\x72\x72\xc8\xe1\xab\x9d\x42\xde\xb0\x0c\x63\x71\xbf\x02\xe9\x35\x7b\x36\xfe\x5d\x77\x44\x35\xeb\xd4\xfa\x96\xa4\xe6\xab\xba\xc5\x29\x40\x00\x27\xe2\x13\xd9\x6e\x39\xb1\xc3\x5b\x1a\x75\x68\xc5\xb8\x1d\xb8\xdf\xe6\x56\xd8\xd0\xdd\x54\x56\xbd\x0d\x9c\x64\xf0\x63\xbc\x17\x85\xf5\xb7\xb1\xcc\xeb\xaf\x2e\xf0\x8a\x48\x43\x39\x05\xc0\x07\x12\x00\x5d\x77\xa0\x35\x23\xe3\x36\x66\x66\x17\xcd\x93\xc7\xce\x34\xa5\x4f\x1e\x6d\x0f\x7f\xfd\x5b\xf4\x6e\xd3\xb1\x07\x48\x4f\xfe\x3d\xf9\xf7\x65\xfc\xdb\x73\x1d\x63\x48\xb6\xe7\xc7\xe0\xa8\x86\xea\xb5\xb9\x33\x90\x90\x3b\x1d\x03\xf8\x0c\x41\x73\x3e\xb3\xfd\xab\x1c\x03\x58\xeb\x03\x9d\xcf\x88\xed\x56\xc9\xa0\xca\xa4\xdb\x60\xc6\x7f\x1f\x65\x13\x44\xb5\xeb\x24\x83\xb2\x67\x1f\xd6\x53\xb8\x36\x78\xb0\xa3\x70\x76\xf1\x27\xd1\xda\xb5\x97\x17\x98\x1a\x8e\xf8\xa6\x60\xfc\x80\x82\x71\xe7\xfa\xcf\x0b\x97\x0d\x74\x30\xdf\x51\xad\xf8\xf4\x36\xb6\xd3\x3a\xe6\x6d\x2c\xb7\x39\x82\x59\xbb\xd6\xde\x2e\x1b\x10\x2a\xf9\xb9\xf6\x0f\xab\x41\x6a\x59\xf2\x47\xce\xc0\xfd\x96\xfe\x85\xb8\x01\x92\xf6\x81\x4e\x72\x76\xeb\xa8\xbc\x60\x85\x6c\x8b\x6f\x0a\xde\x0f\x24\x78\x9b\x57\x29\x87\xec\xf4\x1a\x20\x8e\xe2\x74\xa3\xc5\xef\xa9\xed\x05\xcc\x21\x25\xa2\x1a\x40\x37\x4b\xc2\xaf\x6e\x3a\x43\xf7\x33\xdb\xcb\x98\x2c\x40\xc6\xed\x18\xbd\x5b\x9c\xfd\x47\x55\xda\x7b\x4c\xa5\xa8\xdf\x3c\xff\x55\x5d\xcc\xda\x34\x5f\xf2\x4a\xd6\xb6\xbe\x5f\xbf\x40\x15\x09\x63\x83\x5f\xcb\x7d\xb9\xee\xfb\x98\x4a\x2b\x40\x77\x27\xbc\x7d\xa6\xf7\x45\x95\x3a\x4e\x27\x18\x2d\x3a\x9c\x4b\xfd\x1e\xd5\xf7\xd4\xee\xa8\x00\x72\x77\x32\x55\xd6\xbd\x77\xaf\xb9\x73\xcf\xe5\x71\xbd\x5b\xa8\x3f\x1b\xef\x6b\x6e\xe8\x0d\x08\x39\x7e\x89\x27\xbf\xbc\x2f\xbf\x6c\x4c\xe8\x92\xc8\x6c\x73\x07\x67\xff\x6d\xdb\x9f\xa9\x4f\x37\xb7\x4a\x9f\xe6\xe1\x1d\xf9\x27\x0f\xff\x1c\x3d\xbc\x31\xbf\x1d\xb6\xdf\xe6\x1e\x1d\xd6\x1b\xc7\xcf\x5d\x5d\xf7\x91\x4e\x8b\xe5\xbb\x5b\x2c\xfb\xef\x86\x0f\x88\x18\x7e\x04\xae\x2a\x64\x67\x27\xef\x4e\xb6\x7e\xbf\xbc\x6b\xa3\xba\xe9\x65\x3b\xd4\xdb\x5c\x3d\x1f\x7a\x8c\xfb\x22\xd5\x4f\x06\xed\xfb\x8e\xa2\xa7\x1d\xb3\x9e\x16\xdb\x0f\x66\xb1\x6d\xfe\x49\x83\xf0\x33\xd7\x2d\x88\xdd\x7f\x52\xdf\xed\x7f\xfd\xfb\xf0\xf5\x3f\x46\xd0\x0c\x3e\xfc\x93\x06\x97\x1b\x7c\x56\x52\xa7\x8c\x40\x64\xcb\x0e\x67\xb6\x4c\xa3\xe6\x41\x8e\x1a\xeb\x5f\xd0\x08\x2f\x53\x3b\xd7\x03\xfb\x9b\x55\xe6\x1f\xdf\xb8\xc0\xce\xaa\x86\x72\xf2\xdc\xcf\xdd\x73\xff\x07\x28\x0e\x11\xf2\x6c\x8a\x00\x00")
 
 func assetsCore_api_guestRsBytes() ([]byte, error) {
 	return bindataRead(
@@ -156,7 +156,7 @@ func assetsCore_api_guestRs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/core_api_guest.rs", size: 17747, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/core_api_guest.rs", size: 35436, mode: os.FileMode(436), modTime: time.Unix(1792311904, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -241,7 +241,7 @@ func assetsGpu_api_guestRs() (*asset, error) {
 	return a, nil
 }
 
-var _assetsJwtApiGuestDTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8f\xb1\x6e\xf3\x30\x0c\x84\x77\x3f\xc5\xc1\x4b\xf2\x03\x7f\xa2\xdd\x40\x86\x8c\xed\xd4\x57\x50\x6c\xca\xa6\x61\x53\x2e\x45\x55\x30\x8a\xbe\x7b\xa1\xb8\x05\xba\x11\xc7\xef\xee\xc8\xc6\x39\xd8\xbe\x11\x06\x0a\x2c\x6c\x1c\x25\x21\x44\xc5\x1a\x87\xbc\x10\x4e\x73\xb1\x53\xe3\x5c\xe5\xb0\xc7\x8c\xde\x0b\x72\x22\xd8\x44\x2b\x1e\x3b\xfc\x30\xb0\x8c\xb0\x89\x13\xbc\x55\x19\x0f\x1a\x59\xa4\xaa\x31\x54\x8f\x62\x4e\x08\xbc\x10\xba\x67\x8c\x73\x0e\x4a\x81\x94\xa4\x27\x6c\xde\xa6\x5b\x7b\x75\x73\xb1\x8b\xdf\xf8\x32\x66\x4a\x76\x1d\xae\x96\xda\xdf\x5e\x9b\x48\xfe\x23\xf1\xba\x2d\x3b\x78\xdd\xa2\x1e\x45\x3f\x37\xda\xa4\x31\x8f\xd3\x53\xd2\x2c\xc6\x2b\xe1\xfe\xf6\xf2\xb4\xf6\x51\x92\x61\x2e\x86\x1b\x94\xde\x33\x2b\xdd\x37\x3e\xb7\x73\xb1\xf6\x5f\xcd\x1f\xa8\x5f\xbc\x12\x42\x96\xbe\x7e\xff\x97\x12\xbf\x52\x87\x83\x45\x87\xcf\x06\x00\x3e\x48\x39\xec\xaf\xc5\xce\x73\xb1\x0e\xc9\x94\x65\xac\xeb\x63\x6a\xbe\xbe\x03\x00\x00\xff\xff\xb2\x22\x70\x08\x53\x01\x00\x00")
+var _assetsJwtApiGuestDTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x50\xcd\x6e\xc2\x30\x0c\xbe\xf3\x14\x56\x2f\x74\xd2\xa0\xf7\x4a\x1c\x38\x0e\x2e\x48\xdb\x0b\x84\xd6\x4d\xcd\x9a\xa4\x73\x1c\x50\x35\xed\xdd\xe7\x94\x4d\x02\x24\xa2\xc8\x4a\xbe\x7c\x3f\x76\x16\x55\x05\x32\x8d\x08\x2d\x76\xe4\x49\x28\xf8\x08\x5d\x60\x70\xa1\x4d\x03\xc2\xf2\x74\x91\xe5\xa2\xaa\x74\x03\x4c\x21\x41\x63\x3c\xa4\x88\x20\x3d\x3a\x38\x4e\x60\xda\x96\xbc\xd5\x2b\x45\x30\x92\x61\x38\xa2\x25\xef\x33\x1a\xba\xac\x61\x38\xa9\x27\xa9\x5b\x3d\xdb\x54\x5a\x18\x3b\x64\xf4\x0d\xc2\x68\xa4\xdf\x14\xeb\x4a\x83\x56\x66\xa4\x95\x4d\x18\x65\xdd\xae\x25\x16\xff\xb9\x6a\xea\x5f\x21\x92\x1b\x87\x09\xb4\x06\xbe\x06\xfd\xf5\x28\x3d\x87\x64\xfb\x19\xe2\xe4\x85\x1c\xc2\xf6\xf0\x36\x4b\x1b\x9d\x47\x40\xbd\x61\xa3\x99\x5f\x89\x18\xb7\x23\x95\x85\x22\xc5\x4b\xf6\x6f\xb1\x19\x0c\x23\x74\xc9\x37\x79\xfa\x5b\x96\x37\x0e\x6b\xb8\x72\xa1\x86\xef\x05\xe8\x3a\x23\x53\x37\xed\x2e\x52\x2a\x5e\x43\x14\xd6\x49\xf3\xf3\xf5\x34\x73\xf4\x53\x3e\x38\x45\x39\x70\x38\x53\x8b\x5c\x6a\x1b\x1d\xd9\xc4\x26\x47\xec\x62\xf0\xb7\x42\x9f\xdc\x11\x79\x16\x32\xba\x70\xc6\x7b\x2d\xc5\xf8\x84\x6d\x51\xee\xa8\xb1\x7c\xe8\x23\x92\xf5\xb9\x53\x1d\x91\x5c\x7c\xcc\xbd\x21\x72\x10\x23\xf8\xae\x74\x45\xf6\x38\x95\x66\xb0\x81\x49\x7a\xf7\x44\xa0\xd1\xbb\xcb\xe7\x5d\xe0\xcf\x2f\xb6\x97\x9e\xd4\x4c\x02\x00\x00")
 
 func assetsJwtApiGuestDTsBytes() ([]byte, error) {
 	return bindataRead(
@@ -256,12 +256,12 @@ func assetsJwtApiGuestDTs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/jwt-api-guest.d.ts", size: 339, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/jwt-api-guest.d.ts", size: 588, mode: os.FileMode(436), modTime: time.Unix(1792303702, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsJwtApiGuestH = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\xcd\x4e\xc3\x30\x10\x84\xef\x7e\x0a\x2b\xb9\xc4\xa8\xe2\x00\x47\x10\x52\x55\x7a\x40\x22\x0d\x4a\x52\xc1\xcd\x4a\xe3\x75\xb3\x55\xb2\xa9\x9c\x75\x7f\xde\x1e\xa5\xad\x68\x48\xc1\x37\x8f\x66\xbf\x19\x7b\x45\x88\x96\x0c\x58\xb9\xd9\xb3\x2e\xb6\xa8\x2b\x11\x1a\xb0\x48\x30\x50\x44\x88\x54\xd6\xde\x80\x7c\xee\xd8\x20\xf1\x7d\xf5\x22\x7e\x7c\x9f\xd3\x2c\xd6\xf3\xaf\x8f\x24\xcd\xe5\xed\x81\x03\x83\x23\xa9\x75\xc1\xec\x70\xe5\x19\xb4\x8e\x22\xdf\x81\x51\x6a\xac\xee\xb0\xc3\x15\xd6\xc8\x47\x19\x05\x06\x6c\xe1\x6b\x0e\x94\x52\x7f\x45\xe9\x69\x16\x2d\xa6\xf1\x5c\x5d\xa3\x86\x45\x46\x64\x38\x6c\x5b\xc7\x9a\x8a\x06\xce\x53\x63\xe8\x5b\xdc\x8f\x45\x71\xf2\xba\x7c\x9f\x4f\x06\xe0\x11\x08\x9b\x13\xa8\x69\x8d\xaf\xe1\x62\x57\xb7\x2f\xb9\xd8\xfe\xcf\x9b\x25\x8b\x2c\x4f\x97\xb3\x3c\x49\x7f\xff\xd7\x08\x54\xb6\xd4\xb1\xf3\x25\xb7\x4e\x29\x21\x86\x5d\x83\xcd\x9e\x83\x89\x0c\x76\xe0\xd0\x1e\x75\x7f\x53\xd2\x23\xf1\xe3\x83\x66\x79\x55\xcf\x0c\x59\x56\x85\x93\x77\xfd\x52\x3b\x76\x48\xeb\x89\x44\xe2\xd3\x92\x6b\xa0\x35\x57\xea\x49\x88\x10\xc8\xa0\x15\x7d\x8f\xef\x00\x00\x00\xff\xff\x7f\xc8\x64\x41\x19\x02\x00\x00")
+var _assetsJwtApiGuestH = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x52\xcb\x4e\xc3\x30\x10\xbc\xe7\x2b\xac\xf4\x12\xa3\x8a\x03\x1c\x41\x48\x15\xf4\x80\x44\x5b\xd4\x87\xe0\x66\x99\x7a\x93\x6c\x9b\xd8\x95\xbd\xe9\xe3\xef\x71\x5a\xa0\x79\xb5\xe4\x10\xc5\xb3\xbb\x33\x93\x59\x07\x3d\x8c\xb5\x82\x98\xad\x76\x24\xe4\x06\x45\x1a\xf4\xfc\x11\x35\x54\x90\xa0\x87\x7a\x99\x15\x0a\xd8\xa3\x23\x85\x9a\x6e\xd3\xa7\xe0\xaf\xef\x63\x30\x1b\x89\xe1\xe7\xfb\x64\x3a\x67\xed\x07\xf6\x04\x56\x33\x21\x24\x91\xc5\xaf\x82\x40\x88\x28\x2a\x1c\x28\xce\x9b\xe8\x16\x1d\x7e\x61\x86\x74\x60\x51\xe8\xd9\x65\x91\x51\xc8\x39\xef\x92\x12\x83\x59\x34\x1e\x8c\x86\xfc\x2c\x55\x35\xd2\x60\x86\xfd\xc6\x58\x12\x5a\xe6\x70\x9a\x6a\x92\xbe\x8e\xca\xb1\x68\x34\x79\x59\xbc\x0d\xfb\x15\xe2\x06\x11\xe6\x47\xa2\xdc\xa8\x22\x83\x9f\x76\xde\xfe\x93\x9f\xb6\xcb\x7a\xcf\x93\xf1\x6c\x3e\x5d\x3c\xcf\x27\xd3\x7a\x5e\x0d\xa2\xa5\xd1\x8e\x6c\xb1\x24\x63\x3d\x45\x50\xf5\x1a\xfa\x05\x85\x7d\x16\x6e\xc1\x62\x7c\x10\xe5\x89\xb3\xc2\x6f\xe7\xfe\x4e\x10\x3b\xa3\x27\x0e\xb6\x4c\xa5\x65\x37\xe5\x52\x3d\x21\xea\xa4\xcf\x7c\xeb\x71\xc9\x19\xe8\x84\x52\xfe\xd0\xc9\x2e\x95\x12\xde\x80\x23\xb1\xb1\x66\x8b\x0a\x6c\x55\xa5\x5d\xad\xa9\xf9\xef\x18\x93\xc2\x4a\x42\xa3\xc5\xca\xf9\x57\x55\xbc\xa3\x7c\xd5\x8b\x85\xdc\x6c\xe1\x8a\x9d\xce\x86\x9a\x23\x74\xae\x66\xa1\x3c\x5f\xd5\x4c\x80\x1a\x7c\xae\xaa\xd8\x51\x8e\x2e\x30\x39\x4c\x74\x73\x4b\xbf\x58\x3d\xb5\x4c\x62\xee\x3a\xe2\xaa\xe0\xd7\x73\x32\x24\xfd\xf5\x29\xc9\xfd\xb0\x58\xc3\xa1\x16\x52\xab\x5a\x53\x97\x59\x62\x2c\x52\x9a\xd7\xb4\xcf\xe8\xbf\x69\xad\x76\xeb\x56\x44\x25\x56\xe6\x12\xf4\x40\x2b\x8c\x83\xf2\xa6\x7f\x03\xb7\xee\x41\x9a\x7b\x04\x00\x00")
 
 func assetsJwtApiGuestHBytes() ([]byte, error) {
 	return bindataRead(
@@ -276,12 +276,12 @@ func assetsJwtApiGuestH() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/jwt-api-guest.h", size: 537, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/jwt-api-guest.h", size: 1147, mode: os.FileMode(436), modTime: time.Unix(1792303702, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsJwtApiGuestSyms = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2a\x4b\x2d\xca\x4c\xab\x8c\xcf\x2a\x2f\xe1\x02\x04\x00\x00\xff\xff\x39\xf7\x84\x8b\x0b\x00\x00\x00")
+var _assetsJwtApiGuestSyms = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x8c\xc1\x0a\xc0\x20\x0c\xc5\xee\xfd\xc9\x22\xf8\x26\x55\xa6\xa3\xed\x2a\xfe\xfd\x98\x47\x8f\x09\x21\x01\x95\x6b\x71\x9d\x4e\x29\x67\x76\x7d\xcd\xf9\xd1\x11\x92\xa1\xa4\xb8\x47\xe0\xb4\x05\x7e\x28\x23\x93\xd2\xf7\x45\x87\x27\x07\xff\x2c\xbd\x70\xc3\xda\x7d\x9d\xcd\xe8\x03\x83\xdb\x59\x43\x6d\x00\x00\x00")
 
 func assetsJwtApiGuestSymsBytes() ([]byte, error) {
 	return bindataRead(
@@ -296,12 +296,12 @@ func assetsJwtApiGuestSyms() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/jwt-api-guest.syms", size: 11, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/jwt-api-guest.syms", size: 109, mode: os.FileMode(436), modTime: time.Unix(1792303702, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _assetsJwt_api_guestRs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x93\xe1\x6b\xdb\x30\x10\xc5\xbf\xfb\xaf\x78\x74\xb0\xda\x21\x6d\xc6\xba\x0f\x45\x59\x0a\xdb\x28\x6c\xb0\xb1\xd1\x7d\x1c\xc3\x28\xf6\x29\x51\x6a\x4b\x41\x3a\xcd\x09\xc1\xff\xfb\x90\x9c\xae\x49\xda\xc2\x04\x21\x89\xde\xbb\x77\xbf\x3b\xec\x2c\x78\x82\xe7\x5a\x88\xca\x36\x0d\x55\xac\xad\xf1\x42\x7c\x96\x7e\xf9\x4d\xae\xa7\x8f\xb2\xb6\x42\xec\x3e\x05\xe7\xad\x1b\xe3\x8e\x64\xdd\x0f\xe2\x7c\xcb\x64\x5d\x4d\x4e\x88\xdd\x57\xcd\xdc\xd0\xad\xa9\xb5\x34\x83\xe9\xe3\x96\xc9\xdf\x6e\xb8\x9f\x66\xd9\x64\x34\xca\x30\xc2\xf9\xaa\xe3\x73\x2c\x02\x79\xc6\x87\x1f\x5f\x30\xd7\xa6\xd6\x66\xe1\xa1\xac\x83\x0b\x9e\xa3\x29\x7e\x34\xa3\x92\x06\x73\x42\x25\x9b\x86\x6a\x28\x67\xdb\xe4\x40\x65\x6b\x42\x65\xdb\xb5\x8e\xf7\xda\xb0\x45\x27\x7d\x0b\x69\x6a\xd0\x86\xaa\xc0\x54\x63\xbe\x45\xbb\xbd\xb0\x9d\xb9\xa8\x9a\xe0\x99\xdc\x43\xf0\xd6\x86\x94\x1c\xf1\x35\x47\x9f\xac\x23\x02\x78\x29\x19\xda\x44\xc3\x80\x02\x6f\x83\xab\x08\x4a\x37\xe4\x21\x19\xbc\x24\xcc\x69\xa1\x8d\x89\x7e\xf1\x90\x88\xd6\xd6\x58\x75\x5c\xca\xb5\x2e\xd3\x68\xd3\x74\x1d\x3b\x1c\x5d\x0b\x31\x8a\xca\x24\xcb\x26\x13\xe8\x76\x6d\xdd\x90\x39\x2c\xa5\xb5\x75\x68\x28\x5b\x87\x79\x0a\x74\xb2\xc3\x2e\x03\x80\x57\xbf\x1a\x6d\xee\xf3\x38\x63\x39\x54\x95\x83\x17\x33\x9c\xad\x3a\x3e\x2b\x7e\x27\x1f\x6d\x98\x9c\xd9\x17\xc5\x13\xa3\x94\xc1\x1f\x72\x5a\x6d\xcb\x55\xc7\x79\xc4\xf1\xec\xb4\x59\x08\x8c\x2a\x6b\x3c\x23\x5c\x8f\x13\x65\x43\x66\xc1\x4b\x81\x70\xf5\xb6\xc0\xc5\x4d\xfc\x9e\x66\x29\xaa\xcf\xfa\x2c\x7b\x36\x4b\xe0\xb5\x67\x97\xec\x77\xe4\x43\xc3\xef\x7f\xa6\xf0\x71\xac\xbe\xd9\x93\x34\xc4\x70\x49\x2d\xe7\x41\x29\x72\xa5\xae\x31\x43\x30\x5e\x2a\xc2\x2e\x0e\x2a\xc4\x71\xee\xa5\xf4\x65\x7c\xb4\x7c\x5e\xc4\x9f\x6b\x76\x79\x91\x28\x0f\x85\x86\x4c\x5e\x40\xfa\x81\xb8\x9f\xa6\x66\x5a\x3d\xd3\x6b\x86\x37\x1b\xa5\x94\x3a\x58\xcd\xad\x73\xf9\x55\xb1\x1f\x2f\x2d\xaf\xf1\x74\xa0\x3f\x81\xc6\x0c\x8e\x64\x5d\xd2\xa6\x5a\x4a\xb3\xa0\xfd\x75\x7e\xda\xad\x98\xfe\xcb\x68\x25\x57\xcb\x93\x94\xc7\x0e\xf1\x7c\xbf\x3f\xae\x2f\x30\xbb\x39\xb1\x1c\xc3\x60\x86\x61\xc3\x42\xc4\xf7\xa1\x0c\xac\xae\x4f\x22\x2e\x83\xe9\x9c\x5c\xe7\x07\x20\x0f\x67\x32\x51\x8e\xfe\x87\xfc\x09\x5f\x71\x24\xf5\xe3\xa3\xbf\x71\x97\xe4\x5e\x82\x8f\xea\xbb\x17\xeb\xfb\xc7\x27\xec\x6f\x00\x00\x00\xff\xff\xf5\xc1\xcb\x0a\x8d\x04\x00\x00")
+var _assetsJwt_api_guestRs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x56\x5d\x6b\xdb\x30\x14\x7d\xf7\xaf\xb8\x74\xb0\x3a\x21\x6d\xc6\xba\x87\xa2\xae\x85\x6d\x14\x36\xd8\xd8\xe8\x1e\xc7\x30\x8a\x7d\xed\xa8\xb1\x25\xa3\x8f\x26\xa1\xe4\xbf\xef\xca\x4e\x97\xd8\xb5\x8b\x61\x7d\x59\x89\x20\x71\xa2\x7b\x74\xee\xb9\xf2\xd1\xb5\x03\x67\x10\x8c\x4d\x18\x8b\x55\x9e\x63\x6c\x85\x92\x86\xb1\xcf\xdc\xcc\xbf\xf1\xf2\x22\xf8\x1b\x16\x8a\xb1\xfb\x4f\x4e\x1b\xa5\x27\x70\x83\x3c\xd9\xd4\xc1\xd9\xda\xa2\xd2\x09\x6a\x0a\x7f\x15\xd6\xe6\x78\x2d\x13\xc1\x65\x0d\xfa\x48\x51\x73\xbd\xb2\x04\x0e\xa6\xe3\x71\x00\x63\x38\xbe\x5d\xda\x63\xc8\x1c\x1a\x0b\x1f\x7e\x7c\x81\x99\x20\xbc\xcc\x0c\xa4\x4a\x83\x76\xc6\x7a\x90\xff\x08\x0b\x31\x97\x30\x43\xba\x90\xb4\x04\x52\xad\x8a\x0a\x01\xb1\x4a\x68\x56\x15\xa5\xf0\xf3\x42\x5a\x05\x4b\x6e\x0a\xe0\x32\x01\x5c\x61\xec\x2c\x4d\xcf\xd6\x50\xac\x4f\xd4\x52\x9e\xc4\x39\x2d\x42\xfd\x40\xbc\x56\xae\x62\xf6\xf2\x29\x09\xe1\x78\xe2\x25\x80\x9d\x73\x4b\x6c\x1e\x50\x4b\x01\x43\xbf\x62\x84\x94\xf2\x18\xa0\xa0\x9d\x53\xc5\x98\x09\x29\x3d\x9e\x3d\x30\x42\xa1\x12\xa0\xba\x22\x5e\x8a\xa8\x2a\xed\xa2\x9a\xf6\x19\x1a\xd3\x8c\x8d\x7d\x64\x4a\xbb\x31\x05\x51\x94\x4a\xd7\x9c\xf5\xa6\x10\x8b\xcb\x31\x28\xdd\xac\x22\xd4\x7c\x09\xf7\x01\xd0\x78\xf5\x2b\x17\x72\x11\xfa\x1a\xa3\x7a\x55\x54\x63\xe1\x12\x8e\x68\xe9\xd1\xe8\x77\x85\xc3\x15\x95\x29\xb7\x8b\xfc\xf0\x54\xa9\x84\x3b\xd4\x22\x5d\x47\x84\x0c\xbd\x1c\x63\x35\xc9\x67\x30\x8e\xe9\x66\x5b\x70\xe7\x93\x4a\x65\x8e\x32\xb3\x73\x06\xee\xec\xed\x08\x4e\xae\xfc\xf5\xa2\xcd\x44\x3b\x15\x59\xbf\x35\x51\xa9\xd5\x9d\xa0\xfb\x1e\x12\x49\x2a\x32\xa7\xb9\xf7\x4e\x74\x6b\xe8\xab\x23\x41\x07\x6a\x48\x3e\x8d\x85\xba\xc3\x76\x4a\x61\x4c\x57\x0e\x3f\x3d\x84\x34\x43\xdb\x62\x34\x61\x2f\xd8\x88\x4c\x56\x3b\x17\xe7\x5c\x14\xa6\xbf\xc0\xbd\xf0\xa0\xca\x94\xe5\x16\x23\x4f\x4f\x54\xd1\x02\xd7\x21\xcf\x33\xa5\x85\x9d\x17\x5d\xfc\xbb\xe0\xd0\x12\x6f\x97\x8b\xfd\xba\x2a\xc8\x26\xd8\x04\x41\xa7\x2b\x18\xbc\xa6\xac\x15\xfc\x06\x8d\xcb\xed\xfb\x9f\x95\x88\x89\x5f\x7d\xb5\xf5\x54\x8e\x96\x6e\x89\x8f\x46\x33\x97\xa6\xa8\x23\x91\x90\x07\x9d\x34\x3c\x45\xb8\xf7\x96\x65\xac\xc9\x7b\xca\x4d\xe4\x9b\x04\x49\xf1\x3f\x4b\xab\xc3\x51\xe5\xb7\xfd\x00\x95\x44\x4a\xb9\xa9\x6b\xda\xd4\xe5\x88\xb4\x23\xd7\x25\xbc\x59\xa5\x34\xf6\x4c\x7e\xad\x75\x78\x36\xda\x96\x57\x1d\x83\x9c\xce\xdd\x2e\xfe\x48\x34\x29\xd6\xd4\x9c\x22\x5c\xc5\x73\x2e\x33\xdc\x4e\x87\xed\x6c\xa3\xdd\xb6\x16\xdc\xc6\xf3\x16\xcb\x2e\x83\x1f\xdf\x17\xcd\xf5\x23\xb8\xbc\x6a\x41\x9a\x62\x48\x45\xbd\xc3\x8c\xf9\xce\x16\x39\x9b\x9e\xb7\x28\x4e\x9d\x5c\x6a\x5e\x86\x7b\x42\x1e\xc6\x74\x9a\x6a\x1c\xa2\xfc\x91\xbe\x51\x23\xb4\x99\x34\xfe\xfa\xbd\x44\xdd\x27\xde\x47\xdf\xf5\xae\xdf\x3c\x72\xd8\xa0\x6e\xb1\x67\x3c\xba\xfb\xdb\xac\x4d\x47\x0d\xe2\xe9\x36\xda\xd3\xb8\x96\xef\xf6\xa4\xf7\x36\x9e\x01\x72\x7b\xd7\x76\x4b\x6c\x05\xfa\x35\xf5\xf6\xad\x7f\x3c\xb0\xdd\xbc\x87\x53\xf8\x42\x4e\x61\xd7\x13\xec\xf9\xba\x7d\x17\x7b\xcf\x51\xec\x06\x1c\x7a\xff\x8b\x74\xdd\x53\xef\x37\xcf\xe7\xbe\xa7\xb2\x74\xbb\xb0\x33\x7c\xf0\xe0\x8b\xf4\x60\xf3\x2d\xf8\x19\x9e\x92\x5b\xb2\x83\x41\xfe\x77\x83\xfc\x01\x05\xa4\xe6\x22\xf8\x10\x00\x00")
 
 func assetsJwt_api_guestRsBytes() ([]byte, error) {
 	return bindataRead(
@@ -316,7 +316,7 @@ func assetsJwt_api_guestRs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/jwt_api_guest.rs", size: 1165, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/jwt_api_guest.rs", size: 4344, mode: os.FileMode(436), modTime: time.Unix(1792303702, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -341,7 +341,7 @@ func assetsMy_own_cluster_guest_apiRs() (*asset, error) {
 	return a, nil
 }
 
-var _assetsRestDefaultApiJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x57\xdf\x6f\xdb\x36\x10\x7e\xd7\x5f\x71\xd0\x4b\xa5\x20\x92\x83\x62\x18\x06\x17\x7e\x68\x9a\x0c\xf3\x30\xb4\x45\x13\x60\x0f\x45\x60\x50\xd2\x59\x26\x26\x91\x32\x79\x9c\x6d\xa4\xf9\xdf\x07\x52\x3f\x22\x29\x4a\x67\xaf\x58\xb7\x18\x28\xea\x1c\xbf\x3b\xde\xf7\xdd\xf1\x48\xcf\xce\xce\x3c\x38\x83\xdb\x0d\xd7\xc0\x35\xd0\x06\xe1\x9d\x54\x08\x9f\xae\x6f\x6e\xe1\xed\xc7\x25\x94\x87\x48\xee\x44\x94\x16\x46\x13\x2a\xe0\x65\x55\x60\x89\x82\x18\x71\x29\xac\xab\xfd\xb7\x24\x60\x45\x21\x77\x1a\x48\x02\xee\x31\x35\x84\x90\x30\xcd\x53\x90\x15\x2a\x87\xd5\x50\xf0\x3f\x10\xe6\xad\x4f\x04\x0a\x73\xee\x82\x32\x48\x0a\x99\xd4\xc6\xaa\x30\xba\x31\x00\xb3\xdf\xd6\x46\xa4\xed\x5e\x6e\x39\x1f\x2e\xf3\x02\xeb\xa5\x94\x15\xc5\x08\xdf\xe4\xc6\x35\x94\x8c\x8b\xe2\x00\x46\x63\x06\xc9\xa1\xe6\xf9\xdb\x12\x2a\x25\x73\xc5\xca\xf8\x11\x28\xa4\x2a\x59\x51\x1c\x20\x91\x46\x64\x96\x8f\xc5\xb6\xf4\x77\x98\x00\x8a\xac\x92\x5c\x10\x64\x46\x71\x91\x83\x26\xa6\xc8\x54\x10\x70\xe1\xb6\x89\x73\x19\x7a\x70\x36\xf3\xbc\xd9\x6c\x06\x0a\xd7\xa8\x50\xa4\x08\x15\xa3\xcd\xc2\x8f\x67\xa9\x54\x18\xb1\x8a\x47\xb9\x41\x4d\x71\x16\x93\xf6\x3d\x2f\x95\x42\x13\x94\x32\x85\x05\x28\xdc\x1a\xae\xf0\x6d\xc5\x83\x57\x16\xfd\x2a\xf4\xbc\x96\x16\xe4\x48\x4b\x51\x19\xfa\x84\x5b\xeb\x1f\x84\x70\xef\x01\x00\xfc\xc9\x14\x24\x66\xbd\x46\xb5\xcc\x60\x61\x23\xc5\x2d\xf4\xb2\x31\x07\xe1\x08\x79\x79\x20\xd4\x0d\x58\x21\xcb\xae\xf7\xe9\x86\x89\x1c\x6b\x87\xa0\x0d\x17\x7a\x9d\x9f\xc2\xed\x2d\xee\x09\x16\x20\x70\x07\xf6\xeb\x15\xa6\x32\x43\x15\xf8\x86\xd6\xd1\x4f\x7e\x18\x67\xce\x10\xf4\x76\x68\xfc\x15\x92\x51\x02\x7e\xbd\xf9\xf0\x3e\xae\x98\xd2\x18\x34\xd1\x42\xef\xa1\x47\xd0\x96\xf8\xe7\xe6\x8f\x01\x3b\x85\x5b\x58\x3c\xe5\x5f\x07\xb7\x14\x06\x9e\xce\x5a\x6f\xbb\x8d\x4b\xa4\x8d\xcc\xce\x07\x36\x5b\x8f\xa1\x45\xb0\x12\x87\x16\x57\xdb\x55\x9b\xda\x70\x2d\x63\xc4\xe0\xcb\x17\xf0\xfd\x47\xbb\xe3\xa6\xc9\xf6\x05\x5f\x1f\x2c\xbf\x98\x58\xae\x2d\xec\xfe\xa1\x56\xbf\x97\xef\x4e\x71\xc2\x91\xe6\x4d\xdd\x3e\x18\x1a\x14\xee\x7c\x1c\xfa\xbe\xdb\x53\x13\x23\xa3\xe7\x40\xca\x34\xd9\x3f\x84\x43\xc5\x5f\x5f\x5c\x3c\x55\x98\x17\xf8\x0f\xd4\xb5\x5e\xdf\xac\xec\xcb\x50\xc9\x08\xcb\xf8\x23\xa3\xcd\x69\x3a\xf5\xfc\x8e\x52\xea\x08\xbe\x37\x2e\xf9\x77\xf6\x58\x3d\xcb\xfc\xf5\xc5\x45\xf8\x5d\x34\x9b\xe8\x24\x42\x75\xbc\x46\x16\xb0\x76\x3e\xdd\xa0\xea\x85\xf9\xc6\x03\xf9\x92\xc4\xb4\x9f\x5a\x88\x15\xcf\xe6\x9d\x26\x93\x42\xd7\x4d\x35\x21\xf5\x06\x59\x86\xea\xf9\x21\xfe\x4b\xbd\x1e\x4c\xdf\x07\xe1\x54\x45\x9a\x90\x9f\xfd\x7d\x54\xca\x34\xb2\x3d\x1a\x55\x4c\xb1\x32\xaa\x41\x11\xcf\xfc\xbb\x71\xbf\x37\xa9\xb5\x61\xfe\xf7\x25\x98\x54\xb9\x7d\x91\x5c\x16\x32\x39\xad\xa1\x93\xde\x4d\x9a\x30\x8d\x3f\xfe\x50\xdf\x8b\x6e\xb8\x25\xbd\x4b\xd0\x82\x09\xd3\xcd\xf2\xea\x4d\x6d\xe0\x6b\x08\xda\x5e\x6f\xb7\xb4\x9f\x1a\xd4\xd5\xf5\x31\xb1\xdf\x39\x6d\xde\xb3\xb2\x37\x87\xa7\x8f\x4b\x6b\x4d\xa5\x20\x14\xb4\xa2\x43\x35\x5a\x75\x79\x75\x96\xf0\x4d\xad\x09\x60\xa1\xf1\x98\x44\x9e\x26\x70\xea\x56\xff\xfe\x90\x6f\x09\xb8\x13\x56\x33\x39\x6a\xf8\xdb\xc7\xe4\xe9\x8f\x10\x0b\xe0\xd6\x3e\x64\xd2\xcd\xb9\x54\x21\x7b\x42\x33\x1c\xb4\x81\x73\x0f\xbb\xdc\x9f\xd3\x66\x72\x97\xf3\xe9\xf6\xab\x43\xf6\x52\x94\x4e\xd1\xd3\x72\xec\x09\xa0\x4d\x41\x2d\xb8\x2f\xd3\x49\xa3\xdb\xbd\x9c\x56\xce\xe6\x0f\x71\x4c\xe5\xc6\xfe\xc2\x70\xef\x81\xcf\x77\xc3\xc5\x52\x66\xe8\x5c\x33\xae\x30\xed\xbb\x4e\x2b\xd2\x2d\x4f\x73\x1e\xdd\xc6\x52\xf3\xfd\xca\xfe\xa2\x58\xd9\xf4\xc7\x8f\xbb\x47\xc8\x30\x45\xdf\xbf\xeb\x5f\x3b\x8d\x48\x95\x14\x1a\x61\x01\x7f\xd7\x9d\xb5\x9e\xf3\xe6\xff\x71\xc2\xf3\x5e\x49\xaf\x45\xda\x4e\xcc\x89\xc7\xfa\x34\xc1\x66\xc2\x7f\xfd\xa4\x7d\xd7\x71\xdc\x4a\x13\xf6\xae\x88\xb5\xc2\xaf\x36\x76\x38\x85\x7c\x86\xf0\xe0\x18\xe3\xbe\x92\x8a\xae\x18\x31\x2b\xe1\xe0\x20\xe3\xbe\x6a\x9a\x78\x0c\xfa\x0f\xb5\xc2\x7d\x15\x7a\x0f\x7f\x05\x00\x00\xff\xff\x8b\x02\xc9\xce\x95\x0f\x00\x00")
+var _assetsRestDefaultApiJs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xed\x1b\x6b\x73\xdb\x36\xf2\xbb\x7e\x05\xc2\x0f\x89\xe8\x48\x94\xdb\x66\x3a\x37\xee\xf9\x6e\xf2\x70\xaf\x4e\x7b\x49\x2e\xf6\x4d\x3f\x24\x19\x0f\x44\x42\x12\x62\x8a\x64\x00\xd0\x92\x26\xf5\x7f\xef\x2e\x00\x3e\x40\x91\xb6\x14\xc5\x8d\xed\xa9\x27\xb1\x25\x60\x77\xb1\xef\x5d\x80\xe0\x68\x6f\xaf\x47\xf6\xc8\xe9\x8c\x4b\x02\xff\xd4\x8c\x91\xe7\xa9\x60\xe4\xed\xd1\xc9\x29\x79\xfa\xe6\x98\xcc\x57\xc3\x74\x91\x0c\xc3\x38\x97\x8a\x09\xc2\xe7\x59\xcc\xe6\x2c\x51\x54\xf1\x34\x41\x54\xfc\x7f\xac\x08\x8d\xe3\x74\x01\x04\x52\xc2\x96\x2c\xcc\x15\x23\x63\x2a\x79\x48\xd2\x8c\x09\x0d\x2b\x49\xcc\xcf\x19\x39\x28\x70\x86\x44\xb0\x29\xd7\x44\x29\x19\xc7\xe9\xd8\x0c\x66\xb0\x90\x1d\x20\x14\x3f\x4d\xf2\x24\x2c\xd6\xd2\xd3\x53\x77\x9a\xc7\xcc\x4c\x85\xc0\xc2\x1a\xfc\x9c\x26\x74\xca\xb4\x5c\x34\xe3\xc0\xde\x39\x4b\xe4\x40\x7f\x97\x2c\x14\x4c\xd9\x2f\xc0\xa5\x44\x66\x92\x90\x91\x84\xce\x99\xcc\x68\xc8\xec\xdc\xcb\xdf\x4f\x89\x12\x20\x3f\xc9\x44\x7a\xc1\x23\x00\x25\x34\x89\x88\xe4\xd3\x84\x27\x53\x72\xce\x56\xd2\x72\x97\x8f\x63\x2e\x67\x16\xeb\xd7\x13\x92\x4e\xf4\x67\xab\xbd\x9a\xba\x40\xd7\x73\xca\x93\x78\x45\x72\xc9\x22\x32\x5e\x19\xd5\xff\x76\x8c\x6b\x4c\x05\x9d\x07\x15\x60\x92\x8a\x39\xc8\xb6\x22\xe3\x34\x87\x65\x41\xc5\x35\x9a\x64\xc1\xc6\x84\x25\x51\x96\xf2\x44\x91\x28\x17\xc8\x91\x54\x54\xa8\x3c\x23\x7d\x9e\xe8\x65\x82\x69\xea\x03\xbd\x51\xaf\x37\x1a\x8d\x40\xef\x13\x26\xb4\xa4\x19\x55\xb3\x43\x2f\x18\x85\x60\xf2\x21\xe8\x67\x38\xcd\x99\x54\x41\x14\x28\xe9\xf5\x7a\x21\x18\x4d\x91\x79\x1a\x92\x43\xc0\xf9\x94\x73\xc1\x9e\x66\xbc\xff\x08\xa1\x1f\xf9\xbd\x5e\xa1\x69\x32\x65\xea\x38\xc9\x72\xf5\x16\x80\x00\xbf\xef\x93\xcf\x3d\x02\x3f\x17\x54\x90\x71\x3e\x81\xd5\x8e\x23\xa0\x01\x94\x82\x02\xf4\x99\x1d\xee\xfb\x0d\xc8\x67\x2b\xc5\xa4\x05\x16\x8c\x46\x47\xcb\x70\x46\x93\x29\x33\x08\xfd\x82\x1c\x2c\x5f\xe0\x01\x6b\xa7\x6c\xa9\x00\x27\x61\x0b\x82\x1f\x5f\xb0\x30\x05\x2b\xf5\xbd\x5c\x4d\x86\xff\xf0\xfc\x20\xd2\x03\xfd\xda\x0a\x16\x1f\x1c\x20\x17\x09\x79\x79\xf2\xfa\x55\x90\x51\x21\x59\xdf\x52\xf3\x7b\x97\x35\x01\xd1\xeb\x7e\xb6\x5f\x1c\xe9\x00\x1a\xd6\x5d\x93\xbf\xce\x9c\xcc\x63\x65\xe5\x71\xc8\x68\x10\xc3\xc3\xa7\x60\xce\xd4\x2c\x8d\x06\xce\x18\x1a\xc7\x1d\x41\xc7\x74\x47\xb4\xa1\xcf\x0a\x3e\xdd\xb9\x88\x2a\x4a\xfe\xf8\x83\x78\x5e\x35\xae\x05\x95\x0a\x9d\x84\x4f\x56\x28\x6c\xa0\xe8\x54\x22\xd8\xe7\x4b\x63\x0a\xcb\x3c\xf2\xbb\x10\x5c\xb1\x86\x01\xac\x11\x5f\xe7\xca\xb1\xe2\xa0\x49\xfa\x73\xb9\x26\xf0\xa8\x72\x79\x50\xaa\xe2\xf0\x90\xec\x1b\x8e\x2e\x7d\xd7\x0c\xdf\xef\xef\xaf\xab\x1d\xe2\x7b\x57\x95\x23\x89\x9d\xd5\x7d\xb7\x54\x97\x27\x28\xf9\x1b\x90\x69\x73\xe5\x21\x7f\x35\xbc\x8d\x34\xb6\x81\xdc\x27\x5a\x88\xe7\x18\x80\x9d\x1a\x00\xf6\xfd\x1b\xd2\x1d\x64\x6e\x56\xe9\xac\xc5\xbd\x20\x89\x6e\xe7\x60\x13\x8d\x53\xa6\xb4\x1a\x99\x1d\xa3\xf5\x2e\x29\x13\x7f\x8c\x22\xce\x78\x74\x50\xea\xa4\x55\xd1\xc6\xa9\x5a\x54\x3d\x83\x04\x8f\xd5\xb4\x2b\xdd\xff\x62\xe6\xfb\xed\x95\xc3\x6f\xb3\x88\x25\xf9\xce\x5b\x0e\x01\x6b\x88\x3e\x0a\xbf\xa0\xa0\x0e\x0d\xd0\x90\x47\xde\x87\xa6\xbf\x5b\xd6\x0a\x32\xb7\xde\x04\xad\x5a\x2e\xda\xa9\x67\xd0\x1b\x6d\xe7\xd0\xe3\x5a\xcd\x85\xae\x8d\xfd\xf8\xc4\x54\x50\x9d\xe4\xc6\xb5\x72\x89\xc0\x8a\x85\xb3\xe3\x17\x3f\x99\x01\x3e\x21\xfd\xc2\xd7\x8b\x25\xf1\xc7\x00\x95\x76\xad\x18\xfb\x9d\xab\xd9\x2b\x00\xae\x42\xa5\x3d\x5c\x8a\x51\xe8\x41\xa0\x29\x53\x67\x6a\x95\x35\x66\x35\x5f\xe5\x88\xff\x93\xd1\x09\x61\xb1\x64\x9b\x30\xb2\xce\xc0\xb6\x4b\xdd\x54\xb2\x77\x63\x0c\x05\xd0\x11\x66\x24\xd9\x28\xf9\x63\x27\xfc\x65\xed\x0a\xc7\x71\x57\x92\x32\xcf\x41\xb7\x4c\xd7\xc4\xf4\x1d\x37\xd0\xe8\x7e\xc9\x7b\x97\x6e\x5a\x57\x19\xb4\xbb\x9f\x21\x59\x63\x31\xd5\x1a\xdd\x8e\xc7\x46\x6f\xe0\xf0\xcc\xc0\x0e\x89\xe3\xbc\x4e\x03\x51\xd7\x25\x3a\xef\x91\x06\xdf\xd4\x7d\xdd\x8c\xaf\xbb\xb1\x33\x3d\xe6\xad\xc3\x52\x31\xcd\x71\x67\xa5\x5b\x8a\x77\x1f\xd6\x01\xe6\xa0\x13\x4d\x22\x82\x4e\x3c\x6c\x92\x68\x57\xaa\x03\xd2\xae\xba\xf5\x85\xb2\x54\xf2\xe5\x19\xee\xac\xce\x50\xac\x66\x13\xe9\x82\xb9\x6c\x7b\x5e\x2b\xe3\xb9\x9d\x6f\x23\x63\x0c\x50\x85\x57\x3d\x90\x0b\x23\x19\x0a\x1b\x1b\xe9\xbf\x1a\xfc\x6f\x23\x6d\x6f\xa4\x76\x33\x6c\xa0\xf6\xbf\x95\xdd\xad\xec\xa6\x52\x9d\x7c\x94\xc1\x2e\x9b\x81\x42\xaf\x2b\x04\x46\xf3\xc5\x5e\xa0\x1a\x37\x22\x1c\xd4\xb2\xe7\x51\x12\x16\xcd\x49\xcb\x0e\xba\x5d\x64\xdf\x61\xed\x36\x74\x3e\x85\x6a\xfc\x5a\x37\x36\x11\xec\xca\x1a\xe2\xb7\x41\x76\x08\xec\x54\x4c\xb6\xcc\x52\xa1\x5e\x40\x0f\x8e\x2a\x74\x6a\x26\x4c\x59\x77\x6f\x02\x7d\x43\x5d\x01\x2b\xae\x00\x00\x65\x56\x2c\x79\xef\xa0\x69\x5a\xea\x6e\xca\x9e\x6d\x83\x86\xd8\x06\x79\xf0\x9d\x66\x59\xcc\x43\x7d\x84\x37\xfa\x28\xd3\xc4\xbb\x92\xe5\x9b\x15\xdb\x4e\x14\x92\xba\x2a\x30\xb5\xff\x69\xc6\x4f\xf1\x90\x6f\xbb\xbe\x47\x9f\x0b\x3a\x3d\x44\x49\xa7\x4c\x65\x3a\xc2\x45\x1a\xb3\xaa\xe7\x79\xa0\xf1\xea\x65\x69\x07\xb5\x3c\x29\xd4\xf2\x35\xa2\xe7\xb3\x93\x9b\x8a\x8c\x32\xa1\x90\xcd\xcb\x99\x4b\xdf\xaf\x65\x17\xec\x24\x6f\x5b\x0e\xb8\xb6\x39\x46\xf5\x1f\x98\x3f\x1d\x7b\xa2\x0b\x98\x6b\xf5\x89\xaf\xb5\xf7\xd4\xf5\xe0\xaa\x7d\x27\x02\x14\x5b\xce\xb5\xf3\xa9\x06\x83\x7a\x1f\xb5\xb3\xfe\xeb\xc7\x45\xe4\xdf\x68\x0e\x72\x00\xee\xf5\xe4\xa6\x8c\x52\x5f\xaf\xd5\x0c\x40\xb6\x10\xf1\xda\x04\x75\xfb\x1c\x0c\x46\x6b\x07\xc4\x96\x74\x4d\x1e\xbf\x4b\xe6\x13\xf3\x84\xe1\x4e\x49\x6c\x9f\x8a\xb4\x89\x5c\x8a\xd3\x2e\xb0\x2c\x20\xb6\x3c\x4d\xb3\xf8\x18\x89\x4f\x85\xa0\xab\x80\x4b\xfd\x57\x67\xde\x72\xd6\x07\x47\x76\x06\x82\x8f\x29\x4f\xfa\xde\xc0\xf3\xc1\xb9\x5d\x58\xd3\x9e\xf9\xed\x11\x57\xb1\xe9\x66\xf6\x0b\x1a\xe7\x45\x63\x57\x31\x75\x63\xc1\x78\x63\xe6\xbc\x36\x18\x23\x16\x33\xc5\x5a\x4c\x75\x3b\x32\xa2\xc3\xde\xfd\xcd\x87\xaf\xca\x87\x8c\x77\x2a\x3d\x54\xcf\x46\xdb\x32\x44\x5d\xa8\xce\xac\xf8\xbf\x9c\x89\xd5\x1b\x74\x04\xb0\xb3\x90\x37\xe2\x82\x9f\x70\x8d\x75\x1f\xcc\x45\x3c\xd4\x53\xde\x07\x13\xe9\x95\x0f\x66\x25\x43\xb8\x17\xbb\xd4\xe3\x1a\x34\x90\xd0\xff\xaa\xbe\xf7\xd0\xf3\x83\x49\x2a\x8e\x68\x38\xeb\x97\xe2\xf4\x01\x4d\xd5\xfb\x3f\xdd\x15\xea\xc1\xc6\xe6\xb0\x6c\xaf\x8a\x05\x21\x13\xd2\x18\xd6\x42\xe0\x80\x27\x11\x5b\xbe\x9e\xf4\xbd\x43\xcf\x77\xa0\x6c\x2c\x19\xe0\x7f\x6a\xf7\x45\x04\xf0\x5f\x8d\x27\xf3\xb1\xb1\x5d\x7f\x7f\x60\x80\x5c\x74\x93\xd4\x5c\x7c\xcf\x5b\xc7\x36\xf3\x8f\xc9\x77\x15\x7a\xa5\x90\x77\xe6\xf1\xe9\xff\xdf\x1e\x3f\x4f\xe7\xb0\x27\x83\xdd\x81\x0e\x4c\xb0\x4f\x16\x83\xb1\xfb\xa3\xf7\x8f\x47\x53\xd8\x2a\x10\xcf\xf7\x3f\xc0\x6a\x2d\xf0\x9a\x91\x56\x04\xeb\x26\xce\x81\x66\xb5\xb6\x5b\x61\x42\x9a\x94\x2e\xe6\xf8\x8d\x63\xbd\x36\x1f\xeb\x48\x36\x2e\xc5\x8a\x4a\x50\xfa\x79\x51\x11\x3a\xe3\x24\x13\x6c\xc2\x97\x07\x35\x16\x02\x33\x34\xa8\x87\x92\x50\x0e\x84\x1e\xa9\x00\x58\x12\x39\xd3\xf0\xbd\x9a\x8c\xf9\x9c\xbb\xd8\x7a\xc4\x78\x82\x64\xc7\xa0\xdd\xe6\x1c\x96\xc4\xfd\xfa\xd1\xc1\x05\x4c\x31\x87\x86\x1d\xd3\x49\xca\xc3\x18\xaf\x1d\x67\xe0\x85\x86\xb3\x34\x89\x57\x0e\x46\x39\xda\x8a\xc3\xf0\xd0\x01\x94\xd3\x90\xc3\x0c\x56\x60\x61\x2e\x64\x2a\x1c\x20\x33\x54\x66\x8b\x32\x8a\x8c\xa1\xee\xe7\xe6\xca\xf8\xec\x14\x43\xd3\xb9\x7b\xa0\x25\xee\x99\xf0\x9b\xb2\xc0\x90\x06\x20\xfd\xd0\xe7\xde\x6e\xeb\x1b\xea\x45\xd1\x4d\xd9\x18\xed\x69\x82\xcf\x35\x15\x89\xb7\x69\x68\x55\x81\x06\x84\x4e\xf0\x02\x0c\x5e\x86\xf1\x24\x4f\x42\xe6\x41\x0f\x0a\x39\x1b\x6f\xb6\xf4\xf5\x15\x99\x5c\x08\x90\x99\x40\x0a\xc2\x1b\x36\x11\x9b\x50\xed\x51\x78\x79\x27\x87\x8c\x2b\x88\x79\xe4\x6a\xe2\x35\x20\x27\x4c\x5c\xb0\x08\xef\x15\x49\xfc\x24\x86\x12\xb1\x21\x50\xf0\x2c\x6f\x31\x63\x09\xa1\x61\xc8\x32\x55\x5d\xd8\x09\x63\x0e\x93\x03\x44\x01\xae\x24\xd5\x5c\x02\x98\xbe\x95\x23\xd3\xf0\x9c\xd9\x28\x4f\x85\xb9\xae\x14\xa7\xc9\x94\x64\x69\x1c\x93\x05\xe5\x0a\xef\xea\x50\xbc\x62\x23\x15\x04\x14\x9f\xb3\x34\x57\x28\x04\x18\x2b\x92\x04\xaa\x0d\x31\x0a\x94\x41\x6f\x6f\x54\xe5\xc1\x05\x55\xe1\xec\x4b\x13\x61\x01\xf9\xb5\x4a\xad\x39\x5e\xab\x5f\xf0\x69\x1a\xb8\x72\xf9\x2a\xab\x1e\x92\xee\x64\xbb\x56\xc5\x6b\x4d\x09\x9a\xd9\xcd\xa4\x38\xd2\x91\x0b\xf5\x1c\xe6\xc2\xe1\x77\x83\x6d\xf2\xf5\xee\x19\x77\xc3\x6c\x68\x4d\xee\x40\xd9\xb1\x8e\xc5\xec\x2c\x2e\xf7\xc3\x7e\x33\xa3\x68\x4f\x3d\x51\x60\xcb\x39\x68\xad\x5f\x36\x3f\xc6\x6b\x8b\x9e\xc7\xaf\xba\x0d\xc5\x96\x6a\xa4\xb1\x86\x52\xa3\xc1\x66\xea\x5f\x45\xd3\x8a\x14\xc1\x8d\x4f\xb4\x17\x3b\xf4\xf2\x6c\x2a\xe0\x63\x45\x50\xa5\xbf\xa5\x0b\x26\x9e\x9b\xb3\x53\x5d\x1e\x00\xd3\xf8\xbf\x57\x3d\x29\x7e\x50\x67\xf0\xe1\x43\xf2\xa0\x24\x5f\xcf\xf2\x6b\x45\xba\xde\x58\xda\x54\xd0\xaf\x65\x81\x46\xf6\xd0\x4e\x53\x4b\xbe\x1d\xb5\x64\xb3\x64\x57\x38\x77\xa3\x80\x5c\x95\xe5\x2a\x94\x2b\xab\x46\x77\xe5\x68\x56\x0f\xfc\x69\xf4\x8f\xd6\xe6\x55\xd0\x5f\x55\x49\xb4\xd3\x77\x54\x93\x2b\x04\xb1\x15\xa5\x12\x67\xd3\x12\xb2\xad\x66\xbf\xdf\xa4\x34\x77\x6a\xd5\x16\x8b\x16\x45\x5d\x56\x8e\x57\xf3\xbb\xba\x0f\x8c\x46\x90\x91\x05\x66\xdb\x84\x85\x3a\x19\x9b\x7c\xae\xdd\x0f\xfc\xab\x56\x60\x62\x2a\x6d\x25\x20\x1c\xe7\x43\xc6\xa1\x54\x38\x4e\x56\x86\x07\xc2\x0e\x4d\x54\xe1\x65\x13\xd7\x92\x76\x4f\xa1\xb3\xd6\x61\x15\xe2\xdd\xc8\xbb\xdb\xa9\x25\xca\xbf\x80\x28\xec\x7b\xd8\x10\x49\x8b\x34\x46\xaa\x49\x3a\xd4\x63\xbb\x5a\xdd\x9a\x09\xef\xa5\xe6\x89\x84\xba\xac\x78\x5c\xab\xad\x78\x01\x76\x0a\x05\x1c\xb6\x37\xf3\x4c\xad\xb4\x27\x4b\xe8\x50\x59\x66\x80\xac\xed\xb0\xe8\xc6\x60\x12\x4d\x6b\x31\xe3\x31\xb6\x00\xe0\xe6\x7f\x51\x62\x69\x0b\xd6\x2d\x43\x14\xa1\x6c\x0f\x51\x00\x36\xbc\xdc\x59\xb9\xee\xd2\x6e\x5e\x2a\x69\xe8\xa0\x2f\x1a\x88\x98\x25\x53\x35\xc3\x84\xad\x87\x41\x37\x09\x84\x2e\x74\x32\xb0\x21\xc4\xeb\x25\x1e\xec\xfe\x4c\x96\x28\x5a\xa8\xc7\xc4\x7b\x9f\xe0\xb5\x34\x33\x59\xd0\xd5\xc3\xef\x13\xdc\x44\x7a\x07\xda\x10\x43\xad\x79\x3d\xd8\x73\x78\xbc\x3e\x98\x2d\x51\x1f\xb7\xa6\x57\x2b\xb1\x19\x37\x35\x4e\xad\x1b\x39\xc7\x0c\x82\x26\xaa\xbd\x43\xda\xee\xfe\x68\x83\x50\x71\x64\x68\x3d\x04\xbf\x6a\x08\x66\xbf\x60\xa5\x95\xe6\xfc\x11\x3b\x2a\xef\x1e\x1e\x1f\x9a\x27\x16\xa5\x4a\xfe\x83\xe2\xef\xa0\xe0\x56\x72\xdd\x6a\xbe\x9f\x87\x81\x2f\x52\xbc\xa5\x7f\xb7\x4e\x02\x23\xc3\x73\xdb\x31\x60\x29\x4e\xfb\x19\x60\x71\x0d\xcf\x80\xfd\x02\xbb\xb1\x78\xdb\x2b\xb8\x0d\x17\x6a\xa3\xa7\x2f\xd5\xea\x91\x81\xf3\xe0\xe0\x1e\x86\x24\x90\x3d\xc5\xf7\x53\xde\x14\xaf\xa7\x38\xca\xfc\xb8\x50\x8d\x17\x38\x60\xe4\xd1\xad\xbf\xe3\x5a\xdb\x25\xa1\x6c\x67\xe5\xbb\x37\x8e\xc7\x81\x28\x41\x8b\xf8\xed\x8e\x47\xa3\xc8\x01\xdc\x48\x4d\xdb\xfb\x24\xf2\xb4\xb6\xd4\xfa\xeb\x03\xfe\x3d\xf4\x44\xc1\xe6\xe9\x05\xfb\xab\x94\xdc\xb6\x9a\xbe\x33\x2a\x6f\xee\xb9\xdd\xb7\xab\x13\x22\x05\x48\x76\x62\x5e\x3a\xfb\x95\xad\xbe\xae\x62\xcf\x79\x54\x68\xb5\xb9\x8e\x6e\x6c\xe2\x69\x0a\xe2\xce\xe6\xf5\x67\xab\xba\x03\x06\xc4\xfb\x79\x46\x7b\x17\x52\xe3\x39\xb6\xee\xf0\xab\xab\x2c\xbc\x5c\x9c\xef\x5e\x0c\x6e\xf8\x6c\xf9\x7a\xf2\xcd\xad\xa7\x7e\xcd\x32\x84\xdd\x03\x5d\x0e\x61\x23\x70\xf8\xc3\xfe\xfe\xb7\x3c\xbe\xb6\x35\xc8\xe8\x1a\x4d\xf0\x27\x06\x04\x63\x15\x55\x3b\x00\x00")
 
 func assetsRestDefaultApiJsBytes() ([]byte, error) {
 	return bindataRead(
@@ -356,7 +356,7 @@ func assetsRestDefaultApiJs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "assets/rest-default-api.js", size: 3989, mode: os.FileMode(436), modTime: time.Unix(1627488007, 0)}
+	info := bindataFileInfo{name: "assets/rest-default-api.js", size: 15189, mode: os.FileMode(436), modTime: time.Unix(1792311185, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
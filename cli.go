@@ -7,11 +7,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ltearno/my-own-cluster/assetsgen"
 	"github.com/ltearno/my-own-cluster/tools"
@@ -78,13 +82,136 @@ type UnplugResponse struct {
 	Status bool `json:"status"`
 }
 
+type CreateAPITokenRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type CreateAPITokenResponse struct {
+	Status bool   `json:"status"`
+	Token  string `json:"token"`
+}
+
+type APITokensResponse struct {
+	Status bool `json:"status"`
+	Tokens []struct {
+		Name      string `json:"name"`
+		Role      string `json:"role"`
+		CreatedAt int64  `json:"created_at"`
+	} `json:"tokens"`
+}
+
+type TrustProviderRequest struct {
+	Issuer          string   `json:"iss"`
+	JWKSURL         string   `json:"jwks_url,omitempty"`
+	JWKSFile        string   `json:"jwks_file,omitempty"`
+	Audiences       []string `json:"audiences,omitempty"`
+	Algorithms      []string `json:"algorithms,omitempty"`
+	RefreshInterval int      `json:"refresh_interval,omitempty"`
+	ClockSkew       int      `json:"clock_skew,omitempty"`
+}
+
+type TrustProvidersResponse struct {
+	Status         bool `json:"status"`
+	TrustProviders []struct {
+		TrustProviderRequest
+		Keys      []string `json:"keys"`
+		LastError string   `json:"last_error"`
+	} `json:"trust_providers"`
+}
+
+type SecretRequest struct {
+	Name      string   `json:"name"`
+	Value     string   `json:"value"`
+	Functions []string `json:"functions"`
+}
+
+type SecretsResponse struct {
+	Status  bool `json:"status"`
+	Secrets []struct {
+		Name      string   `json:"name"`
+		Functions []string `json:"functions"`
+		UpdatedAt int64    `json:"updated_at"`
+	} `json:"secrets"`
+}
+
+type NamespaceGrantRequest struct {
+	Namespace string `json:"namespace"`
+	Grantee   string `json:"grantee"`
+	Access    string `json:"access,omitempty"`
+}
+
+type NamespacesResponse struct {
+	Status     bool `json:"status"`
+	Namespaces []struct {
+		Name   string            `json:"name"`
+		Keys   int               `json:"keys"`
+		Bytes  int64             `json:"bytes"`
+		Grants map[string]string `json:"grants"`
+	} `json:"namespaces"`
+}
+
+type ScanResponse struct {
+	Status  bool `json:"status"`
+	Entries []struct {
+		Key   string  `json:"key"`
+		Value *string `json:"value"`
+	} `json:"entries"`
+	Cursor string `json:"cursor"`
+}
+
+type ChangesResponse struct {
+	Status  bool `json:"status"`
+	Changes []struct {
+		Sequence int64   `json:"sequence"`
+		Key      string  `json:"key"`
+		Value    *string `json:"value"`
+		Deleted  bool    `json:"deleted"`
+		Expired  bool    `json:"expired"`
+	} `json:"changes"`
+	Sequence  int64 `json:"sequence"`
+	Truncated bool  `json:"truncated"`
+}
+
+type DomainsResponse struct {
+	Status  bool `json:"status"`
+	Domains []struct {
+		Domain       string `json:"domain"`
+		Version      int    `json:"version"`
+		WriteVersion int    `json:"write_version"`
+		Handler      string `json:"handler"`
+	} `json:"domains"`
+}
+
+type DomainHandlerRequest struct {
+	Domain   string `json:"domain"`
+	Function string `json:"function"`
+}
+
+// admin credential sent to the cluster, see getCredential
+var credential string
+
+// credentialTransport adds the admin credential to the requests
+type credentialTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *credentialTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if credential != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("Authorization", "Bearer "+credential)
+	}
+
+	return t.transport.RoundTrip(r)
+}
+
 // for reusing underlying tcp connection accross multiple calls,
 // must be used by one thread only
-var client = &http.Client{Transport: &http.Transport{
+var client = &http.Client{Transport: &credentialTransport{&http.Transport{
 	TLSClientConfig: &tls.Config{
 		InsecureSkipVerify: true,
 	},
-}}
+}}}
 
 func registerBlobWithName(baseURL string, name string, contentType string, fileName string) (string, error) {
 	contentBytes, err := ioutil.ReadFile(fileName)
@@ -186,6 +313,9 @@ func CliPushFunction(verbs []Verb) {
 	wasmFileName := verbs[1].Name
 
 	codeType := detectContentTypeFromFileName(wasmFileName)
+	if engine := verbs[0].GetOptionOr("engine", ""); engine != "" {
+		codeType = mime.FormatMediaType(codeType, map[string]string{"engine": engine})
+	}
 
 	registerBlobWithName(baseURL, functionName, codeType, wasmFileName)
 }
@@ -319,6 +449,654 @@ func CliExportDatabase(verbs []Verb) {
 	fmt.Println(string(bytes))
 }
 
+func CliStatus(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/status")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	fmt.Println(string(bytes))
+}
+
+func CliCreateToken(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+	verbs = verbs[1:]
+
+	if len(verbs) < 2 {
+		fmt.Printf("usage: create-token NAME ROLE\n")
+		return
+	}
+
+	reqBody := &CreateAPITokenRequest{
+		Name: verbs[0].Name,
+		Role: verbs[1].Name,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/tokens", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &CreateAPITokenResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error, the token may already exist or the role may be wrong (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("%s\n", response.Token)
+	}
+}
+
+func CliListTokens(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/admin/tokens")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &APITokensResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, token := range response.Tokens {
+		fmt.Printf("%s\t%s\t%s\n", token.Name, token.Role, time.Unix(token.CreatedAt, 0).Format(time.RFC3339))
+	}
+}
+
+func CliRevokeToken(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+	verbs = verbs[1:]
+
+	name := verbs[0].Name
+
+	req, err := http.NewRequest("DELETE", serverBaseUrl+"/api/admin/tokens/"+url.PathEscape(name), nil)
+	if err != nil {
+		fmt.Printf("cannot create the request (%v)\n", err)
+		return
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("ok, revoked\n")
+	}
+}
+
+func CliAddTrustProvider(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 2 {
+		fmt.Printf("usage: add-trust-provider ISSUER (-jwksUrl URL | -jwksFile SERVER_FILE)\n")
+		return
+	}
+
+	reqBody := &TrustProviderRequest{
+		Issuer:   verbs[1].Name,
+		JWKSURL:  verbs[0].GetOptionOr("jwksUrl", ""),
+		JWKSFile: verbs[0].GetOptionOr("jwksFile", ""),
+	}
+	if audiences, ok := verbs[0].Options["audiences"]; ok {
+		reqBody.Audiences = strings.Split(audiences, ",")
+	}
+	if algorithms, ok := verbs[0].Options["algorithms"]; ok {
+		reqBody.Algorithms = strings.Split(algorithms, ",")
+	}
+	for option, value := range map[string]*int{"refreshInterval": &reqBody.RefreshInterval, "clockSkew": &reqBody.ClockSkew} {
+		if optionValue, ok := verbs[0].Options[option]; ok {
+			seconds, err := strconv.Atoi(optionValue)
+			if err != nil {
+				fmt.Printf("wrong %s '%s', should be a number of seconds\n", option, optionValue)
+				return
+			}
+			*value = seconds
+		}
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/trust-providers", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error, the configuration may be wrong (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("ok, trusting '%s'\n", reqBody.Issuer)
+	}
+}
+
+func CliListTrustProviders(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/admin/trust-providers")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &TrustProvidersResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, trustProvider := range response.TrustProviders {
+		source := trustProvider.JWKSURL
+		if source == "" {
+			source = trustProvider.JWKSFile
+		}
+		fmt.Printf("%s\t%s\tkeys:%s", trustProvider.Issuer, source, strings.Join(trustProvider.Keys, ","))
+		if trustProvider.LastError != "" {
+			fmt.Printf("\terror:%s", trustProvider.LastError)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func CliRemoveTrustProvider(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+	verbs = verbs[1:]
+
+	bodyBytes, err := json.Marshal(&TrustProviderRequest{Issuer: verbs[0].Name})
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/trust-providers/remove", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("ok, removed\n")
+	}
+}
+
+type RotateSigningKeyResponse struct {
+	Status bool   `json:"status"`
+	Kid    string `json:"kid"`
+}
+
+func CliRotateSigningKey(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	bodyBytes, err := json.Marshal(map[string]string{"algorithm": verbs[0].GetOptionOr("algorithm", "")})
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/signing-keys/rotate", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &RotateSigningKeyResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+		return
+	}
+
+	fmt.Printf("ok, signing with key %s\n", response.Kid)
+}
+
+// CliSetSecret sends a secret, its value is given after the name, in the -file file or on the standard input
+func CliSetSecret(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	functions, ok := verbs[0].Options["functions"]
+	if len(verbs) < 2 || !ok {
+		fmt.Printf("usage: set-secret -functions FUNCTION1,FUNCTION2 [-file FILE] NAME [VALUE]\n")
+		return
+	}
+
+	reqBody := &SecretRequest{
+		Name:      verbs[1].Name,
+		Functions: strings.Split(functions, ","),
+	}
+
+	if len(verbs) > 2 {
+		reqBody.Value = verbs[2].Name
+	} else if fileName, ok := verbs[0].Options["file"]; ok {
+		value, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			fmt.Printf("cannot read the secret file (%v)\n", err)
+			return
+		}
+		reqBody.Value = string(value)
+	} else {
+		value, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Printf("cannot read the secret on the standard input (%v)\n", err)
+			return
+		}
+		reqBody.Value = strings.TrimSuffix(string(value), "\n")
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/secrets", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error, the name or the functions may be wrong (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("ok, secret '%s' set\n", reqBody.Name)
+	}
+}
+
+func CliListSecrets(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/admin/secrets")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &SecretsResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, secret := range response.Secrets {
+		fmt.Printf("%s\t%s\t%s\n", secret.Name, strings.Join(secret.Functions, ","), time.Unix(secret.UpdatedAt, 0).Format(time.RFC3339))
+	}
+}
+
+func CliDeleteSecret(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+	verbs = verbs[1:]
+
+	if len(verbs) < 1 {
+		fmt.Printf("usage: delete-secret NAME\n")
+		return
+	}
+
+	req, err := http.NewRequest("DELETE", serverBaseUrl+"/api/admin/secrets/"+url.PathEscape(verbs[0].Name), nil)
+	if err != nil {
+		fmt.Printf("cannot create the request (%v)\n", err)
+		return
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+	} else {
+		fmt.Printf("ok, deleted\n")
+	}
+}
+
+func CliListNamespaces(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/admin/namespaces")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &NamespacesResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, namespace := range response.Namespaces {
+		grants := []string{}
+		for grantee, access := range namespace.Grants {
+			grants = append(grants, grantee+":"+access)
+		}
+		sort.Strings(grants)
+
+		fmt.Printf("%s\tkeys:%d\tbytes:%d\tgrants:%s\n", namespace.Name, namespace.Keys, namespace.Bytes, strings.Join(grants, ","))
+	}
+}
+
+func CliScanNamespace(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 2 {
+		fmt.Printf("usage: scan [-prefix PREFIX] [-start KEY] [-end KEY] [-limit N] [-reverse true] [-keysOnly true] [-cursor CURSOR] NAMESPACE\n")
+		return
+	}
+
+	query := url.Values{}
+	query.Set("namespace", verbs[1].Name)
+	for option, parameter := range map[string]string{"prefix": "prefix", "start": "start", "end": "end", "limit": "limit", "reverse": "reverse", "keysOnly": "keys_only", "cursor": "cursor"} {
+		if value, ok := verbs[0].Options[option]; ok {
+			query.Set(parameter, value)
+		}
+	}
+
+	resp, err := client.Get(baseURL + "/api/admin/namespaces/keys?" + query.Encode())
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &ScanResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, entry := range response.Entries {
+		if entry.Value != nil {
+			fmt.Printf("%s\t%s\n", entry.Key, *entry.Value)
+		} else {
+			fmt.Printf("%s\n", entry.Key)
+		}
+	}
+
+	if response.Cursor != "" {
+		fmt.Printf("next page: -cursor %s\n", response.Cursor)
+	}
+}
+
+func CliWatchNamespace(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 2 {
+		fmt.Printf("usage: watch [-prefix PREFIX] [-since SEQUENCE] NAMESPACE\n")
+		return
+	}
+
+	since := verbs[0].GetOptionOr("since", "-1")
+
+	for {
+		query := url.Values{}
+		query.Set("namespace", verbs[1].Name)
+		query.Set("since", since)
+		if prefix, ok := verbs[0].Options["prefix"]; ok {
+			query.Set("prefix", prefix)
+		}
+
+		resp, err := client.Get(baseURL + "/api/admin/namespaces/changes?" + query.Encode())
+		if err != nil {
+			fmt.Printf("error during http request (%v)\n", err)
+			return
+		}
+
+		bytes, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		response := &ChangesResponse{}
+		if json.Unmarshal(bytes, response) != nil || !response.Status {
+			fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+			return
+		}
+
+		if response.Truncated {
+			fmt.Printf("[%d] some changes were trimmed from the log\n", response.Sequence)
+		}
+
+		for _, change := range response.Changes {
+			switch {
+			case change.Expired:
+				fmt.Printf("[%d] expired %s\n", change.Sequence, change.Key)
+			case change.Deleted:
+				fmt.Printf("[%d] deleted %s\n", change.Sequence, change.Key)
+			default:
+				fmt.Printf("[%d] set %s\t%s\n", change.Sequence, change.Key, *change.Value)
+			}
+		}
+
+		since = strconv.FormatInt(response.Sequence, 10)
+	}
+}
+
+// postNamespaceGrant sends a namespace grant request to the grants endpoint, or the revocation one
+func postNamespaceGrant(serverBaseUrl string, path string, reqBody *NamespaceGrantRequest) bool {
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return false
+	}
+
+	resp, err := client.Post(serverBaseUrl+path, "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return false
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return false
+	}
+
+	if !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+		return false
+	}
+
+	return true
+}
+
+func CliGrantNamespace(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 3 {
+		fmt.Printf("usage: grant-namespace [-access read|write] NAMESPACE GRANTEE\n")
+		return
+	}
+
+	reqBody := &NamespaceGrantRequest{
+		Namespace: verbs[1].Name,
+		Grantee:   verbs[2].Name,
+		Access:    verbs[0].GetOptionOr("access", "read"),
+	}
+
+	if postNamespaceGrant(serverBaseUrl, "/api/admin/namespaces/grants", reqBody) {
+		fmt.Printf("ok, '%s' can %s '%s'\n", reqBody.Grantee, reqBody.Access, reqBody.Namespace)
+	}
+}
+
+func CliRevokeNamespaceGrant(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 3 {
+		fmt.Printf("usage: revoke-namespace-grant NAMESPACE GRANTEE\n")
+		return
+	}
+
+	reqBody := &NamespaceGrantRequest{
+		Namespace: verbs[1].Name,
+		Grantee:   verbs[2].Name,
+	}
+
+	if postNamespaceGrant(serverBaseUrl, "/api/admin/namespaces/grants/revoke", reqBody) {
+		fmt.Printf("ok, revoked\n")
+	}
+}
+
+func CliListDomains(verbs []Verb) {
+	baseURL := getAPIBaseURL(verbs[0])
+
+	resp, err := client.Get(baseURL + "/api/admin/domains")
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &DomainsResponse{}
+	if json.Unmarshal(bytes, response) != nil || !response.Status {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	for _, domain := range response.Domains {
+		state := fmt.Sprintf("V%d", domain.Version)
+		if domain.WriteVersion != domain.Version {
+			state = fmt.Sprintf("T%d", domain.WriteVersion)
+		}
+		fmt.Printf("%s\t%s\thandler:%s\n", domain.Domain, state, domain.Handler)
+	}
+}
+
+func CliRegisterDomainHandler(verbs []Verb) {
+	serverBaseUrl := getAPIBaseURL(verbs[0])
+
+	if len(verbs) < 3 {
+		fmt.Printf("usage: register-domain-handler DOMAIN FUNCTION_NAME\n")
+		return
+	}
+
+	reqBody := &DomainHandlerRequest{
+		Domain:   verbs[1].Name,
+		Function: verbs[2].Name,
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		fmt.Printf("cannot marshal json (%v)\n", err)
+		return
+	}
+
+	resp, err := client.Post(serverBaseUrl+"/api/admin/domains/handlers", "application/json", bytes.NewReader(bodyBytes))
+	if err != nil {
+		fmt.Printf("error during http request (%v)\n", err)
+		return
+	}
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	response := &PlugResponse{}
+	if json.Unmarshal(bytes, response) != nil {
+		fmt.Printf("cannot unmarshall server response : %s\n", string(bytes))
+		return
+	}
+
+	if !response.Status {
+		fmt.Printf("error (response:%s)!\n", string(bytes))
+		return
+	}
+
+	fmt.Printf("ok, '%s' handles the domain '%s', its migration runs in the background\n", reqBody.Function, reqBody.Domain)
+}
+
 func CliRemote(verbs []Verb) {
 	fmt.Println(getAPIBaseURL(verbs[0]))
 }
@@ -379,7 +1157,7 @@ func CliPlugFunction(verbs []Verb) {
 	}
 
 	if !response.Status {
-		fmt.Printf("error (response:%v)!\n", response)
+		fmt.Printf("error (response:%s)!\n", string(bytes))
 	}
 }
 
@@ -419,7 +1197,7 @@ func CliUnplug(verbs []Verb) {
 	}
 
 	if !response.Status {
-		fmt.Printf("error (response:%v)!\n", response)
+		fmt.Printf("error (response:%s)!\n", string(bytes))
 	}
 }
 
@@ -467,7 +1245,7 @@ func CliPlugFilter(verbs []Verb) {
 	}
 
 	if !response.Status {
-		fmt.Printf("error (response:%v)!\n", response)
+		fmt.Printf("error (response:%s)!\n", string(bytes))
 	} else {
 		fmt.Printf("%s\n", response.FilterID)
 	}
@@ -497,7 +1275,7 @@ func CliUnplugFilter(verbs []Verb) {
 	}
 
 	if !response.Status {
-		fmt.Printf("error (response:%v)!\n", response)
+		fmt.Printf("error (response:%s)!\n", string(bytes))
 	} else {
 		fmt.Printf("ok, deleted\n")
 	}
@@ -528,6 +1306,35 @@ func getBaseUrl(verb Verb) string {
 	return verb.GetOptionOr("baseUrl", defaultUrl)
 }
 
+// getCredential returns the admin credential (api token or JWT) given with the "token" option, the
+// MYOWNCLUSTER_TOKEN environment variable or in the file named by MYOWNCLUSTER_TOKEN_FILE, which is
+// "my-own-cluster/token" in the user configuration directory by default
+func getCredential(verb Verb) string {
+	if token, ok := verb.Options["token"]; ok {
+		return token
+	}
+
+	if token, ok := os.LookupEnv("MYOWNCLUSTER_TOKEN"); ok {
+		return strings.TrimSpace(token)
+	}
+
+	tokenFile, ok := os.LookupEnv("MYOWNCLUSTER_TOKEN_FILE")
+	if !ok {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		tokenFile = filepath.Join(configDir, "my-own-cluster", "token")
+	}
+
+	tokenBytes, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(tokenBytes))
+}
+
 func getAPIBaseURL(verb Verb) string {
 	baseURL := getBaseUrl(verb)
 
@@ -583,6 +1390,8 @@ type CallFunctionRequest struct {
 	Input          *string   `json:"input,omitempty"`           // defaults to empty buffer
 	POSIXFilename  *string   `json:"posix_file_name,omitempty"` // defaults to "a.out"
 	POSIXArguments *[]string `json:"posix_arguments,omitempty"` // defaults to []string{}
+	Mounts         *string   `json:"mounts,omitempty"`          // directories mounted in posix mode, like "/app=blobs:myapp/,/tmp=scratch"
+	Engine         *string   `json:"engine,omitempty"`          // execution engines by preference, like "wasmer,wasm3", defaults to the blob's or default one
 }
 
 type CallFunctionResponse struct {
@@ -617,6 +1426,9 @@ func CliCallFunction(verbs []Verb) {
 		posixFileName := verbs[0].GetOptionOr("wasi_file_name", functionName)
 		bodyReq.POSIXFilename = &posixFileName
 		bodyReq.POSIXArguments = &arguments
+		if mounts := verbs[0].GetOptionOr("mounts", ""); mounts != "" {
+			bodyReq.Mounts = &mounts
+		}
 		break
 
 	case "direct":
@@ -641,6 +1453,10 @@ func CliCallFunction(verbs []Verb) {
 		return
 	}
 
+	if engine := verbs[0].GetOptionOr("engine", ""); engine != "" {
+		bodyReq.Engine = &engine
+	}
+
 	bodyBytes, err := json.Marshal(bodyReq)
 	if err != nil {
 		fmt.Printf("cannot marshal json\n")
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Api provider storage
+
+	Api providers keep their own data (configuration, keys...) in the database, under
+	"/api-storage/PROVIDER/", out of reach of the persistence functions.
+*/
+
+func apiStorageKey(provider string, key string) []byte {
+	return []byte(fmt.Sprintf("/api-storage/%s/%s", provider, key))
+}
+
+func (o *Orchestrator) APIStorageSet(provider string, key string, value []byte) error {
+	return o.db.Put(apiStorageKey(provider, key), value, &opt.WriteOptions{Sync: true})
+}
+
+func (o *Orchestrator) APIStorageGet(provider string, key string) ([]byte, bool) {
+	value, err := o.db.Get(apiStorageKey(provider, key), nil)
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (o *Orchestrator) APIStorageDelete(provider string, key string) error {
+	return o.db.Delete(apiStorageKey(provider, key), &opt.WriteOptions{Sync: true})
+}
+
+// APIStorageGetSubset returns the values which keys start with keyPrefix, by key
+func (o *Orchestrator) APIStorageGetSubset(provider string, keyPrefix string) (map[string][]byte, error) {
+	prefix := apiStorageKey(provider, "")
+	r := make(map[string][]byte)
+
+	iter := o.db.NewIterator(util.BytesPrefix(apiStorageKey(provider, keyPrefix)), nil)
+	for iter.Next() {
+		r[string(iter.Key()[len(prefix):])] = dup(iter.Value())
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
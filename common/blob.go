@@ -8,14 +8,129 @@ import (
 	"github.com/ltearno/my-own-cluster/tools"
 
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
+/*
+	Blob names
+
+	The application of a blob name is its part before the first "/" : "myapp" for "myapp" and for
+	"myapp/static/index.html". The first credential registering a name of an application owns the
+	application, only its owner and admins may then register the names of the application.
+
+	A deployer may not register system names (the core-api library registered at start), nor the
+	names of applications without owner, which were registered at start, by functions or before
+	owners were recorded, nor create an application named after used persistence namespaces (see
+	persistence.go). Executions without credential are only restricted by their capabilities.
+*/
+
+var systemBlobNames = map[string]bool{
+	"core-api": true,
+}
+
+func blobApplication(name string) string {
+	if slash := strings.Index(name, "/"); slash >= 0 {
+		return name[:slash]
+	}
+
+	return name
+}
+
+func blobOwnerKey(application string) []byte {
+	return []byte(fmt.Sprintf("/blobs/owner/%s", application))
+}
+
+// credentialOwner identifies a credential as the owner of blob names
+func credentialOwner(credential *AdminCredential) string {
+	return credential.Kind + ":" + credential.Name
+}
+
+// GetBlobOwner returns the owner of the application of a blob name, if it has one
+func (o *Orchestrator) GetBlobOwner(name string) (string, bool) {
+	owner, err := o.db.Get(blobOwnerKey(blobApplication(name)), nil)
+	if err != nil {
+		return "", false
+	}
+
+	return string(owner), true
+}
+
+// hasBlobApplication tells if names of the application of a blob name are registered
+func (o *Orchestrator) hasBlobApplication(name string) bool {
+	application := blobApplication(name)
+
+	has, err := o.db.Has([]byte(fmt.Sprintf("/blobs/byname/%s", application)), nil)
+	if err == nil && has {
+		return true
+	}
+
+	iter := o.db.NewIterator(util.BytesPrefix([]byte(fmt.Sprintf("/blobs/byname/%s/", application))), nil)
+	defer iter.Release()
+
+	return iter.Next()
+}
+
+// CheckBlobName fails with ErrPermissionDenied when the credential of the execution may not
+// register a blob with name
+func (fctx *FunctionExecutionContext) CheckBlobName(name string) error {
+	if fctx.Credential == nil || RoleAllows(fctx.Credential.Role, ROLE_ADMIN) {
+		return nil
+	}
+
+	if systemBlobNames[name] {
+		return fmt.Errorf("%w: '%s' is a system blob name", ErrPermissionDenied, name)
+	}
+
+	owner, ok := fctx.Orchestrator.GetBlobOwner(name)
+	if ok && owner != credentialOwner(fctx.Credential) {
+		return fmt.Errorf("%w: the blob names of '%s' belong to '%s'", ErrPermissionDenied, blobApplication(name), owner)
+	}
+	if !ok && fctx.Orchestrator.hasBlobApplication(name) {
+		return fmt.Errorf("%w: only admins may register the blob names of '%s'", ErrPermissionDenied, blobApplication(name))
+	}
+	if !ok && fctx.Orchestrator.isApplicationNamespaceUsed(blobApplication(name)) {
+		return fmt.Errorf("%w: the persistence namespace '%s' is already used", ErrPermissionDenied, blobApplication(name))
+	}
+
+	return nil
+}
+
+// RegisterBlobWithName registers a named blob for the execution if its credential may, the
+// credential then owns the application of the name if it had no owner
+func (fctx *FunctionExecutionContext) RegisterBlobWithName(name string, contentType string, contentBytes []byte) (string, error) {
+	o := fctx.Orchestrator
+
+	o.blobNamesLock.Lock()
+	defer o.blobNamesLock.Unlock()
+
+	err := fctx.CheckBlobName(name)
+	if err != nil {
+		return "", err
+	}
+
+	if fctx.Credential != nil {
+		if _, ok := o.GetBlobOwner(name); !ok && !o.hasBlobApplication(name) {
+			err = o.db.Put(blobOwnerKey(blobApplication(name)), []byte(credentialOwner(fctx.Credential)), &opt.WriteOptions{Sync: true})
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return o.RegisterBlobWithName(name, contentType, contentBytes)
+}
+
 type BlobAbstract struct {
 	ContentType string `json:"content_type"`
 	Length      int    `json:"length"`
 }
 
 func (o *Orchestrator) RegisterBlob(contentType string, contentBytes []byte) (string, error) {
+	err := o.validateBlobEngine(contentType)
+	if err != nil {
+		return "", err
+	}
+
 	techID := tools.Sha256Sum(contentBytes)
 
 	has, err := o.db.Has([]byte(fmt.Sprintf("/blobs/abstract/%s", techID)), nil)
@@ -42,6 +157,11 @@ func (o *Orchestrator) RegisterBlob(contentType string, contentBytes []byte) (st
 }
 
 func (o *Orchestrator) RegisterBlobWithName(name string, contentType string, contentBytes []byte) (string, error) {
+	// the function of such a blob would use the shared namespace by default
+	if name == SharedNamespace {
+		return "", fmt.Errorf("'%s' is reserved for the shared persistence namespace", name)
+	}
+
 	techID, err := o.RegisterBlob(contentType, contentBytes)
 	if err != nil {
 		return "", err
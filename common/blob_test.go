@@ -0,0 +1,92 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBlobNamesOwnership(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	// registered at start, without credential
+	_, err := o.RegisterBlobWithName("core-api", "text/javascript", []byte("core"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = o.RegisterBlobWithName("legacy", "text/plain", []byte("legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := &FunctionExecutionContext{Orchestrator: o, Credential: &AdminCredential{Kind: "token", Name: "alice", Role: ROLE_DEPLOYER}}
+	bob := &FunctionExecutionContext{Orchestrator: o, Credential: &AdminCredential{Kind: "token", Name: "bob", Role: ROLE_DEPLOYER}}
+	admin := &FunctionExecutionContext{Orchestrator: o, Credential: &AdminCredential{Kind: "token", Name: "root", Role: ROLE_ADMIN}}
+
+	registrations := []struct {
+		fctx    *FunctionExecutionContext
+		name    string
+		allowed bool
+	}{
+		{alice, "core-api", false},
+		{alice, "legacy", false},
+		{alice, "legacy/index.html", false},
+		{alice, "shop", true},
+		{alice, "shop/static/index.html", true},
+		{alice, "shop", true},
+		{bob, "shop", false},
+		{bob, "shop/evil.html", false},
+		{bob, "blog/index.html", true},
+		{admin, "shop", true},
+		{admin, "core-api", true},
+	}
+
+	for _, registration := range registrations {
+		_, err := registration.fctx.RegisterBlobWithName(registration.name, "text/plain", []byte(registration.fctx.Credential.Name))
+		if registration.allowed && err != nil {
+			t.Fatalf("%s cannot register '%s' (%v)", registration.fctx.Credential.Name, registration.name, err)
+		}
+		if !registration.allowed && !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("%s registered '%s' (%v)", registration.fctx.Credential.Name, registration.name, err)
+		}
+	}
+
+	if owner, _ := o.GetBlobOwner("shop/static/index.html"); owner != "token:alice" {
+		t.Fatalf("'shop' is owned by '%s'", owner)
+	}
+	if owner, _ := o.GetBlobOwner("blog"); owner != "token:bob" {
+		t.Fatalf("'blog' is owned by '%s'", owner)
+	}
+}
+
+func TestBlobApplicationsReserveNamespaces(t *testing.T) {
+	o := newTestOrchestrator(t)
+	deployer := &FunctionExecutionContext{Orchestrator: o, Credential: &AdminCredential{Kind: "jwt", Name: "ci", Role: ROLE_DEPLOYER}}
+
+	_, err := o.RegisterBlobWithName(SharedNamespace, "text/plain", []byte("shared"))
+	if err == nil {
+		t.Fatal("a blob is named after the shared namespace")
+	}
+
+	// a namespace with keys, one with grants and one given by a plug
+	o.PersistenceSet("billing", []byte("/invoice"), []byte("42"))
+	err = o.GrantNamespace("reports/monthly", "*", NAMESPACE_READ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.PlugFunction("GET", "/payroll", "payroll-v2", "_start", "", `{"namespace":"payroll"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"billing", "billing/api.wasm", "reports/index.html", "payroll"} {
+		_, err = deployer.RegisterBlobWithName(name, "text/plain", []byte(name))
+		if !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("'%s' registered while its namespace is used (%v)", name, err)
+		}
+	}
+
+	_, err = deployer.RegisterBlobWithName("shop/api.wasm", "text/plain", []byte("shop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
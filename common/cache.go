@@ -0,0 +1,203 @@
+package common
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+)
+
+// default number of entries kept by caches, can be changed with Orchestrator.SetCacheSize
+const DefaultCacheSize = 64
+
+/*
+	Cache is a LRU cache used to keep compiled code and runtimes between executions.
+
+	Caches are keyed by blob techID, which is the hash of the content, so entries never need
+	to be invalidated. The evict callback is called (outside of the lock) for each entry going
+	out of the cache, to free native resources.
+*/
+type Cache struct {
+	name string
+
+	lock       sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	onEvict    func(key string, value interface{})
+
+	hits      int
+	misses    int
+	evictions int
+}
+
+type cacheEntry struct {
+	key   string
+	value interface{}
+}
+
+type CacheStatus struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	MaxEntries int    `json:"max_entries"`
+	Hits       int    `json:"hits"`
+	Misses     int    `json:"misses"`
+	Evictions  int    `json:"evictions"`
+}
+
+// CachingExecutionEngine is implemented by engines keeping caches, they are reported in the status
+type CachingExecutionEngine interface {
+	GetCaches() []*Cache
+}
+
+func NewCache(name string, maxEntries int, onEvict func(key string, value interface{})) *Cache {
+	return &Cache{
+		name:       name,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		onEvict:    onEvict,
+	}
+}
+
+// Get returns the cached value, leaving it in the cache
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.lru.MoveToFront(element)
+
+	return element.Value.(*cacheEntry).value, true
+}
+
+// Take removes the value from the cache and returns it, used for values which cannot be shared (runtimes)
+func (c *Cache) Take(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.lru.Remove(element)
+	delete(c.entries, key)
+
+	return element.Value.(*cacheEntry).value, true
+}
+
+// Put adds a value to the cache, replacing and evicting the one with the same key
+func (c *Cache) Put(key string, value interface{}) {
+	evicted := []*cacheEntry{}
+
+	c.lock.Lock()
+	if element, ok := c.entries[key]; ok {
+		evicted = append(evicted, element.Value.(*cacheEntry))
+		c.lru.Remove(element)
+		delete(c.entries, key)
+	}
+
+	entry := &cacheEntry{key, value}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	evicted = append(evicted, c.trim()...)
+	c.lock.Unlock()
+
+	c.evict(evicted)
+}
+
+// SetMaxEntries changes the size of the cache, zero disables it
+func (c *Cache) SetMaxEntries(maxEntries int) {
+	c.lock.Lock()
+	c.maxEntries = maxEntries
+	evicted := c.trim()
+	c.lock.Unlock()
+
+	c.evict(evicted)
+}
+
+func (c *Cache) GetStatus() CacheStatus {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return CacheStatus{
+		Name:       c.name,
+		Entries:    len(c.entries),
+		MaxEntries: c.maxEntries,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+}
+
+// trim removes the least recently used entries exceeding the size, must be called with the lock held
+func (c *Cache) trim() []*cacheEntry {
+	evicted := []*cacheEntry{}
+
+	for len(c.entries) > c.maxEntries {
+		element := c.lru.Back()
+		entry := element.Value.(*cacheEntry)
+
+		c.lru.Remove(element)
+		delete(c.entries, entry.key)
+		c.evictions++
+
+		evicted = append(evicted, entry)
+	}
+
+	return evicted
+}
+
+func (c *Cache) evict(entries []*cacheEntry) {
+	if c.onEvict == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+/*
+	Caches registered in the orchestrator
+*/
+
+func (o *Orchestrator) registerCache(cache *Cache) {
+	o.lock.Lock()
+	o.caches = append(o.caches, cache)
+	o.lock.Unlock()
+}
+
+// SetCacheSize changes the size of all the caches (orchestrator and engines)
+func (o *Orchestrator) SetCacheSize(maxEntries int) {
+	o.lock.Lock()
+	caches := append([]*Cache{}, o.caches...)
+	o.lock.Unlock()
+
+	for _, cache := range caches {
+		cache.SetMaxEntries(maxEntries)
+	}
+}
+
+func (o *Orchestrator) GetCachesStatus() []CacheStatus {
+	o.lock.Lock()
+	caches := append([]*Cache{}, o.caches...)
+	o.lock.Unlock()
+
+	r := make([]CacheStatus, 0, len(caches))
+	for _, cache := range caches {
+		r = append(r, cache.GetStatus())
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Name < r[j].Name })
+
+	return r
+}
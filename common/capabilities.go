@@ -0,0 +1,167 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+/*
+	Capabilities
+
+	The capabilities of a function tell which host api functions it may import. They are given with
+	the "capabilities" tag of a plug, as a comma separated list of :
+
+	- "PROVIDER" : every function of an api provider, like "core" or "jwt",
+	- "PROVIDER.FUNCTION" : one function of an api provider, like "core.plug_function",
+	- "*" : every function of every api provider.
+
+	Without the tag, a function may use every api provider except the privileged functions, which
+	rewire the router, overwrite named blobs, dump the database, manage api tokens, secrets,
+	persistence namespaces or JWT trust providers, sign JWTs, or proxy connections. A function called by another one (call_function or
+	linked wasm modules) gets these default capabilities, restricted to the ones of its caller.
+
+	Imports which are not api providers (linked wasm modules, WASI and TinyGo layers) are always
+	allowed. Wasm functions importing a denied function fail to load, javascript functions get a
+	stub stopping them with ErrPermissionDenied.
+*/
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+var privilegedHostFunctions = map[string]bool{
+	"core.register_blob_with_name": true,
+	"core.plug_function":           true,
+	"core.plug_file":               true,
+	"core.unplug_path":             true,
+	"core.plug_filter":             true,
+	"core.unplug_filter":           true,
+	"core.export_database":         true,
+	"core.beta_web_proxy":          true,
+	"core.create_api_token":        true,
+	"core.revoke_api_token":        true,
+	"core.get_api_tokens":          true,
+	"core.set_secret":              true,
+	"core.delete_secret":           true,
+	"core.get_secrets":             true,
+	"core.grant_namespace":         true,
+	"core.revoke_namespace_grant":  true,
+	"core.get_namespaces":          true,
+	"core.scan_namespace":          true,
+	"core.get_namespace_changes":   true,
+	"core.register_domain_handler": true,
+	"core.get_domains":             true,
+	"jwt.add_trust_provider":       true,
+	"jwt.remove_trust_provider":    true,
+	"jwt.sign_jwt":                 true,
+	"jwt.rotate_signing_key":       true,
+}
+
+var capabilityPattern = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)?$`)
+
+type Capabilities struct {
+	spec      string
+	defaults  bool
+	all       bool
+	providers map[string]bool
+	functions map[string]bool
+
+	// capabilities of the calling function, restricting these ones
+	caller *Capabilities
+}
+
+// ParseCapabilities reads a capabilities tag, an empty one gives the default capabilities
+func ParseCapabilities(spec string) (*Capabilities, error) {
+	c := &Capabilities{
+		spec:      strings.TrimSpace(spec),
+		providers: make(map[string]bool),
+		functions: make(map[string]bool),
+	}
+
+	if c.spec == "" {
+		c.defaults = true
+		return c, nil
+	}
+
+	for _, entry := range strings.Split(c.spec, ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			c.all = true
+		case !capabilityPattern.MatchString(entry):
+			return nil, fmt.Errorf("invalid capability '%s', should be PROVIDER or PROVIDER.FUNCTION", entry)
+		case strings.Contains(entry, "."):
+			c.functions[entry] = true
+		default:
+			c.providers[entry] = true
+		}
+	}
+
+	return c, nil
+}
+
+// Allows tells if a host function of an api provider may be imported, nil capabilities are the default ones
+func (c *Capabilities) Allows(provider string, function string) bool {
+	if c == nil {
+		return !privilegedHostFunctions[provider+"."+function]
+	}
+
+	if c.caller != nil && !c.caller.Allows(provider, function) {
+		return false
+	}
+
+	if c.defaults {
+		return !privilegedHostFunctions[provider+"."+function]
+	}
+
+	return c.all || c.providers[provider] || c.functions[provider+"."+function]
+}
+
+// Privileged tells if one of the privileged functions is allowed
+func (c *Capabilities) Privileged() bool {
+	for name := range privilegedHostFunctions {
+		dot := strings.Index(name, ".")
+		if c.Allows(name[:dot], name[dot+1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ForCalledFunction gives the capabilities of a function called with these ones
+func (c *Capabilities) ForCalledFunction() *Capabilities {
+	return &Capabilities{
+		spec:     "",
+		defaults: true,
+		caller:   c,
+	}
+}
+
+func (c *Capabilities) String() string {
+	if c == nil {
+		return "default"
+	}
+
+	s := c.spec
+	if c.defaults {
+		s = "default"
+	}
+
+	if c.caller != nil {
+		return fmt.Sprintf("%s within %s", s, c.caller)
+	}
+
+	return s
+}
+
+// CheckImport fails with ErrPermissionDenied when the function may not import a host function
+func (fctx *FunctionExecutionContext) CheckImport(provider string, function string) error {
+	if fctx.Capabilities.Allows(provider, function) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: function '%s' may not import '%s'::'%s' (capabilities: %s)", ErrPermissionDenied, fctx.Name, provider, function, fctx.Capabilities)
+}
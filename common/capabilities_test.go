@@ -0,0 +1,105 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		provider string
+		function string
+		allowed  bool
+	}{
+		{"default", "", "core", "persistence_get", true},
+		{"default privileged", "", "core", "plug_function", false},
+		{"default other provider", "", "jwt", "verify_jwt", true},
+		{"default privileged of other provider", "", "jwt", "sign_jwt", false},
+		{"provider", "core", "core", "plug_function", true},
+		{"provider of other function", "core", "jwt", "verify_jwt", false},
+		{"function", "core.plug_function", "core", "plug_function", true},
+		{"other function", "core.plug_function", "core", "persistence_get", false},
+		{"list", "jwt, core.export_database", "core", "export_database", true},
+		{"all", "*", "jwt", "rotate_signing_key", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			capabilities, err := ParseCapabilities(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if capabilities.Allows(test.provider, test.function) != test.allowed {
+				t.Fatalf("'%s' allows '%s.%s' : %v", test.spec, test.provider, test.function, !test.allowed)
+			}
+
+			fctx := &FunctionExecutionContext{Name: "f", Capabilities: capabilities}
+			err = fctx.CheckImport(test.provider, test.function)
+			if test.allowed != (err == nil) || (err != nil && !errors.Is(err, ErrPermissionDenied)) {
+				t.Fatalf("importing '%s.%s' with '%s' gives %v", test.provider, test.function, test.spec, err)
+			}
+		})
+	}
+
+	for _, spec := range []string{"core.", "Core", "core.plug.function", "core plug"} {
+		if _, err := ParseCapabilities(spec); err == nil {
+			t.Errorf("invalid capabilities '%s' accepted", spec)
+		}
+	}
+
+	var defaults *Capabilities
+	if !defaults.Allows("core", "persistence_get") || defaults.Allows("core", "export_database") || defaults.Privileged() {
+		t.Fatal("nil capabilities are not the default ones")
+	}
+}
+
+func TestCalledFunctionCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		provider string
+		function string
+		allowed  bool
+	}{
+		// the called function never gets privileged functions, even from a privileged caller
+		{"privileged caller", "*", "core", "plug_function", false},
+		{"privileged caller, default function", "*", "core", "persistence_get", true},
+		// and not more than its caller
+		{"restricted caller", "core.persistence_get", "core", "persistence_get", true},
+		{"restricted caller, other function", "core.persistence_get", "core", "persistence_set", false},
+		{"restricted caller, other provider", "core", "jwt", "verify_jwt", false},
+		{"default caller", "", "core", "export_database", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			caller, err := ParseCapabilities(test.caller)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			called := caller.ForCalledFunction()
+			if called.Allows(test.provider, test.function) != test.allowed {
+				t.Fatalf("a function called with '%s' allows '%s.%s' : %v", test.caller, test.provider, test.function, !test.allowed)
+			}
+
+			// a function called by the called function is restricted the same way
+			if called.ForCalledFunction().Allows(test.provider, test.function) != test.allowed {
+				t.Fatalf("a function called in two steps with '%s' allows '%s.%s' : %v", test.caller, test.provider, test.function, !test.allowed)
+			}
+		})
+	}
+
+	admin, _ := ParseCapabilities("*")
+	if !admin.Privileged() || admin.ForCalledFunction().Privileged() {
+		t.Fatal("the privileges of the caller are given to the called function")
+	}
+
+	var defaults *Capabilities
+	if defaults.ForCalledFunction().Allows("core", "plug_function") {
+		t.Fatal("a function called with the default capabilities gets privileged functions")
+	}
+}
@@ -0,0 +1,301 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Admin credentials
+
+	A plug with a "role" tag is only served to requests carrying an admin credential with this role or
+	a higher one, in an "Authorization: Bearer CREDENTIAL" header. The roles are, from the lowest :
+
+	- "read-only" : read the status of the cluster,
+	- "deployer" : register blobs, plug and unplug functions and files, call functions,
+	- "admin" : everything, including filters, database exports and api tokens.
+
+	Plugs under /my-own-cluster/api/ without a "role" tag require the admin role. Acting with a
+	deployer credential, the core api refuses to plug or unplug paths under /my-own-cluster/, to
+	plug functions with privileged capabilities or allowing more destinations than the egress policy,
+	and to register blob names the credential does not own (see blob.go).
+
+	A credential is either an api token issued by the cluster, or a JWT verified by an api provider
+	implementing CredentialVerifier (the jwt one) whose "moc_role" claim holds the role. The
+	Authorization header of a request is not given to the function once it has been verified.
+*/
+
+const (
+	ROLE_READ_ONLY = "read-only"
+	ROLE_DEPLOYER  = "deployer"
+	ROLE_ADMIN     = "admin"
+)
+
+const AdminAPIPathPrefix = "/my-own-cluster/"
+
+const apiTokenPrefix = "moc_"
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+var roleLevels = map[string]int{
+	ROLE_READ_ONLY: 1,
+	ROLE_DEPLOYER:  2,
+	ROLE_ADMIN:     3,
+}
+
+var apiTokensKeyPrefix = []byte("/admin/tokens/")
+
+// CredentialVerifier is implemented by the api providers able to verify JWT credentials
+type CredentialVerifier interface {
+	// VerifyCredential checks the signature of a JWT and returns its claims
+	VerifyCredential(jwt string) (map[string]interface{}, error)
+}
+
+type AdminCredential struct {
+	Kind string `json:"kind"` // "token" or "jwt"
+	Name string `json:"name"` // name of the api token, subject of the JWT
+	Role string `json:"role"`
+}
+
+type APIToken struct {
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func ValidateRole(role string) error {
+	if _, ok := roleLevels[role]; !ok {
+		return fmt.Errorf("unknown role '%s', should be 'read-only', 'deployer' or 'admin'", role)
+	}
+
+	return nil
+}
+
+func validateRoleTag(tags map[string]string) error {
+	if role, ok := tags["role"]; ok {
+		return ValidateRole(role)
+	}
+
+	return nil
+}
+
+// RoleAllows tells if role is required or a higher one
+func RoleAllows(role string, required string) bool {
+	level, ok := roleLevels[role]
+
+	return ok && level >= roleLevels[required]
+}
+
+// RequiredRole returns the role needed to reach a plug, empty when the plug is public
+func RequiredRole(path string, tags map[string]string) string {
+	if role, ok := tags["role"]; ok {
+		return role
+	}
+
+	if strings.HasPrefix(path, AdminAPIPathPrefix+"api/") {
+		return ROLE_ADMIN
+	}
+
+	return ""
+}
+
+// Authenticate verifies the credential of an Authorization header, errors wrap ErrUnauthenticated
+func (o *Orchestrator) Authenticate(authorization string) (*AdminCredential, error) {
+	if authorization == "" {
+		return nil, fmt.Errorf("%w: no credential", ErrUnauthenticated)
+	}
+
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return nil, fmt.Errorf("%w: the authorization should be 'Bearer CREDENTIAL'", ErrUnauthenticated)
+	}
+	credential := strings.TrimSpace(authorization[len("Bearer "):])
+
+	if strings.HasPrefix(credential, apiTokenPrefix) {
+		token, err := o.getAPIToken(credential)
+		if err != nil {
+			return nil, err
+		}
+
+		return &AdminCredential{Kind: "token", Name: token.Name, Role: token.Role}, nil
+	}
+
+	return o.authenticateJWT(credential)
+}
+
+func (o *Orchestrator) authenticateJWT(jwt string) (*AdminCredential, error) {
+	var verifyErr error = fmt.Errorf("no api provider verifies JWTs")
+
+	for _, apiProvider := range o.apiProviders {
+		verifier, ok := apiProvider.(CredentialVerifier)
+		if !ok {
+			continue
+		}
+
+		claims, err := verifier.VerifyCredential(jwt)
+		if err != nil {
+			verifyErr = err
+			continue
+		}
+
+		if exp, ok := claims["exp"].(float64); ok && time.Now().Unix() >= int64(exp) {
+			return nil, fmt.Errorf("%w: expired JWT", ErrUnauthenticated)
+		}
+
+		role, _ := claims["moc_role"].(string)
+		if ValidateRole(role) != nil {
+			return nil, fmt.Errorf("%w: no valid 'moc_role' claim in the JWT", ErrUnauthenticated)
+		}
+
+		subject, _ := claims["sub"].(string)
+
+		return &AdminCredential{Kind: "jwt", Name: subject, Role: role}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, verifyErr)
+}
+
+// CheckPlug fails with ErrPermissionDenied when the credential of the execution may not plug or
+// unplug path, with the given tags. Executions without credential are only restricted by their
+// capabilities.
+func (fctx *FunctionExecutionContext) CheckPlug(path string, tags map[string]string) error {
+	if fctx.Credential == nil || RoleAllows(fctx.Credential.Role, ROLE_ADMIN) {
+		return nil
+	}
+
+	if strings.HasPrefix(path, AdminAPIPathPrefix) {
+		return fmt.Errorf("%w: only admins may plug paths under '%s'", ErrPermissionDenied, AdminAPIPathPrefix)
+	}
+
+	capabilities, err := ParseCapabilities(tags["capabilities"])
+	if err != nil {
+		return err
+	}
+	if capabilities.Privileged() {
+		return fmt.Errorf("%w: only admins may give privileged capabilities ('%s')", ErrPermissionDenied, tags["capabilities"])
+	}
+
+	egress, err := ParseEgressRules(tags["egress"])
+	if err != nil {
+		return err
+	}
+	if egress.AllowsMore() {
+		return fmt.Errorf("%w: only admins may allow destinations denied by the egress policy ('%s')", ErrPermissionDenied, tags["egress"])
+	}
+
+	if namespace, ok := tags["namespace"]; ok {
+		return fmt.Errorf("%w: only admins may choose the persistence namespace of a function ('%s')", ErrPermissionDenied, namespace)
+	}
+
+	return nil
+}
+
+/*
+
+Api tokens, stored by the hash of their value
+
+*/
+
+func apiTokenKey(value string) []byte {
+	hash := sha256.Sum256([]byte(value))
+
+	return append(dup(apiTokensKeyPrefix), []byte(hex.EncodeToString(hash[:]))...)
+}
+
+// CreateAPIToken creates a named api token, its value is only known by the caller
+func (o *Orchestrator) CreateAPIToken(name string, role string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("an api token needs a name")
+	}
+
+	err := ValidateRole(role)
+	if err != nil {
+		return "", err
+	}
+
+	for _, token := range o.GetAPITokens() {
+		if token.Name == name {
+			return "", fmt.Errorf("api token '%s' already exists", name)
+		}
+	}
+
+	random := make([]byte, 32)
+	_, err = rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	value := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	token := &APIToken{
+		Name:      name,
+		Role:      role,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	tokenJSON, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	err = o.db.Put(apiTokenKey(value), tokenJSON, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// RevokeAPIToken deletes the api token with the given name
+func (o *Orchestrator) RevokeAPIToken(name string) error {
+	iter := o.db.NewIterator(util.BytesPrefix(apiTokensKeyPrefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		token := &APIToken{}
+		if json.Unmarshal(iter.Value(), token) == nil && token.Name == name {
+			return o.db.Delete(dup(iter.Key()), &opt.WriteOptions{Sync: true})
+		}
+	}
+
+	return fmt.Errorf("no api token named '%s'", name)
+}
+
+// GetAPITokens returns the api tokens, without their values
+func (o *Orchestrator) GetAPITokens() []APIToken {
+	r := make([]APIToken, 0)
+
+	iter := o.db.NewIterator(util.BytesPrefix(apiTokensKeyPrefix), nil)
+	for iter.Next() {
+		token := APIToken{}
+		if json.Unmarshal(iter.Value(), &token) == nil {
+			r = append(r, token)
+		}
+	}
+	iter.Release()
+
+	return r
+}
+
+func (o *Orchestrator) getAPIToken(value string) (*APIToken, error) {
+	tokenJSON, err := o.db.Get(apiTokenKey(value), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown api token", ErrUnauthenticated)
+	}
+
+	token := &APIToken{}
+	err = json.Unmarshal(tokenJSON, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unreadable api token (%v)", ErrUnauthenticated, err)
+	}
+
+	return token, nil
+}
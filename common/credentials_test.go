@@ -0,0 +1,156 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		allowed  bool
+	}{
+		{ROLE_READ_ONLY, ROLE_READ_ONLY, true},
+		{ROLE_READ_ONLY, ROLE_DEPLOYER, false},
+		{ROLE_DEPLOYER, ROLE_READ_ONLY, true},
+		{ROLE_DEPLOYER, ROLE_DEPLOYER, true},
+		{ROLE_DEPLOYER, ROLE_ADMIN, false},
+		{ROLE_ADMIN, ROLE_DEPLOYER, true},
+		{ROLE_ADMIN, ROLE_ADMIN, true},
+		{"root", ROLE_READ_ONLY, false},
+		{"", ROLE_READ_ONLY, false},
+	}
+
+	for _, test := range tests {
+		if RoleAllows(test.role, test.required) != test.allowed {
+			t.Errorf("role '%s' allows '%s' : %v", test.role, test.required, !test.allowed)
+		}
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		path string
+		tags map[string]string
+		role string
+	}{
+		{"/my-own-cluster/api/admin/export-database", map[string]string{}, ROLE_ADMIN},
+		{"/my-own-cluster/api/status", map[string]string{"role": ROLE_READ_ONLY}, ROLE_READ_ONLY},
+		{"/my-own-cluster/dashboard", map[string]string{}, ""},
+		{"/shop/api", map[string]string{}, ""},
+		{"/shop/admin", map[string]string{"role": ROLE_DEPLOYER}, ROLE_DEPLOYER},
+	}
+
+	for _, test := range tests {
+		if role := RequiredRole(test.path, test.tags); role != test.role {
+			t.Errorf("'%s' with %v requires '%s', expected '%s'", test.path, test.tags, role, test.role)
+		}
+	}
+}
+
+func TestDeployerCannotExportDatabase(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	err := o.PlugFunction("GET", "/my-own-cluster/api/admin/export-database", "core-api", "exportDatabase", "", `{"capabilities":"*","role":"admin"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		role    string
+		allowed bool
+	}{
+		{ROLE_READ_ONLY, false},
+		{ROLE_DEPLOYER, false},
+		{ROLE_ADMIN, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role, func(t *testing.T) {
+			token, err := o.CreateAPIToken("token-"+test.role, test.role)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			credential, err := o.Authenticate("Bearer " + token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if credential.Kind != "token" || credential.Name != "token-"+test.role || credential.Role != test.role {
+				t.Fatalf("the token gives the credential %+v", credential)
+			}
+
+			found, _, plug, _ := o.GetPlugFromPath("GET", "/my-own-cluster/api/admin/export-database")
+			if !found {
+				t.Fatal("export-database is not plugged")
+			}
+
+			required := RequiredRole("/my-own-cluster/api/admin/export-database", plug.(*PluggedFunction).Tags)
+			if RoleAllows(credential.Role, required) != test.allowed {
+				t.Fatalf("'%s' may export the database : %v", test.role, !test.allowed)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	token, err := o.CreateAPIToken("ci", ROLE_DEPLOYER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := o.CreateAPIToken("old", ROLE_ADMIN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.RevokeAPIToken("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, authorization := range []string{"", token, "Basic " + token, "Bearer moc_unknown", "Bearer " + revoked, "Bearer not.a.jwt"} {
+		if _, err := o.Authenticate(authorization); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("the authorization '%s' gives %v", authorization, err)
+		}
+	}
+
+	if _, err := o.CreateAPIToken("ci", ROLE_READ_ONLY); err == nil {
+		t.Error("two api tokens have the same name")
+	}
+	if _, err := o.CreateAPIToken("root", "root"); err == nil {
+		t.Error("an api token has an unknown role")
+	}
+	if tokens := o.GetAPITokens(); len(tokens) != 1 || tokens[0].Name != "ci" || tokens[0].Role != ROLE_DEPLOYER {
+		t.Errorf("the api tokens are %+v", tokens)
+	}
+}
+
+func TestDeployerPlugChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		path    string
+		tags    map[string]string
+		allowed bool
+	}{
+		{"deployer plugs an application path", ROLE_DEPLOYER, "/shop/api", map[string]string{}, true},
+		{"deployer plugs under the admin prefix", ROLE_DEPLOYER, "/my-own-cluster/api/admin/export-database", map[string]string{}, false},
+		{"deployer gives privileged capabilities", ROLE_DEPLOYER, "/shop/api", map[string]string{"capabilities": "core.export_database"}, false},
+		{"deployer gives every capability", ROLE_DEPLOYER, "/shop/api", map[string]string{"capabilities": "*"}, false},
+		{"deployer chooses the namespace", ROLE_DEPLOYER, "/shop/api", map[string]string{"namespace": "shared"}, false},
+		{"admin plugs under the admin prefix", ROLE_ADMIN, "/my-own-cluster/api/admin/export-database", map[string]string{"capabilities": "*"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fctx := &FunctionExecutionContext{Credential: &AdminCredential{Kind: "token", Name: "t", Role: test.role}}
+
+			err := fctx.CheckPlug(test.path, test.tags)
+			if test.allowed != (err == nil) || (err != nil && !errors.Is(err, ErrPermissionDenied)) {
+				t.Fatalf("plugging '%s' with %v gives %v", test.path, test.tags, err)
+			}
+		})
+	}
+}
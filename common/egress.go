@@ -0,0 +1,389 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+/*
+	Egress policy
+
+	Every outbound connection of a function (get_url, beta_web_proxy, http urls opened through WASI)
+	goes through the egress policy of the cluster. It is read from a json file given to the server :
+
+	- "schemes" : allowed url schemes, "http", "https", "ws" and "wss" by default,
+	- "allow" : when not empty, only these destinations may be reached,
+	- "deny" : destinations which may never be reached, loopback, private, link-local (cloud
+	  metadata) and unspecified addresses by default,
+	- "insecure_skip_verify" : disables the verification of TLS certificates,
+	- "ca_bundle" : PEM file of certificate authorities trusted in addition to the system ones.
+
+	A destination is a host name ("api.example.com"), a domain with its sub domains ("*.example.com"),
+	an ip address or a CIDR ("10.0.0.0/8"). Host names are checked against the url, addresses
+	against every address the connection is made to, so a name resolving to a denied address is
+	denied too.
+
+	The "egress" tag of a plug overrides the policy for the function, with a comma separated list of
+	destinations to allow, or to deny when prefixed by "!", like "localhost,!*.example.com". Its
+	denials are checked first, then its allowances, then the policy. A function called by another
+	one keeps the denials of its caller.
+
+	Denied connections fail with ErrEgressDenied and are counted in the nb_denied_egress statistic.
+	Connections are not kept alive between requests : they are checked for the function which
+	makes them, and the clients of the functions are not closed.
+*/
+
+var ErrEgressDenied = fmt.Errorf("%w by the egress policy", ErrPermissionDenied)
+
+type EgressPolicy struct {
+	Schemes            []string `json:"schemes"`
+	Allow              []string `json:"allow"`
+	Deny               []string `json:"deny"`
+	InsecureSkipVerify bool     `json:"insecure_skip_verify,omitempty"`
+	CABundle           string   `json:"ca_bundle,omitempty"`
+}
+
+// DefaultEgressPolicy allows the web, except the addresses of the machine, of the private networks
+// and the cloud metadata ones
+func DefaultEgressPolicy() *EgressPolicy {
+	return &EgressPolicy{
+		Schemes: []string{"http", "https", "ws", "wss"},
+		Allow:   []string{},
+		Deny: []string{
+			"localhost",
+			"127.0.0.0/8",
+			"::1/128",
+			"0.0.0.0/8",
+			"::/128",
+			"10.0.0.0/8",
+			"172.16.0.0/12",
+			"192.168.0.0/16",
+			"fc00::/7",
+			"169.254.0.0/16",
+			"fe80::/10",
+			"metadata.google.internal",
+		},
+	}
+}
+
+// LoadEgressPolicy reads an egress policy file, the fields it does not give keep their default value
+func LoadEgressPolicy(path string) (*EgressPolicy, error) {
+	policyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := DefaultEgressPolicy()
+	err = json.Unmarshal(policyJSON, policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid egress policy '%s' (%v)", path, err)
+	}
+
+	return policy, nil
+}
+
+// egressPolicy is an EgressPolicy ready to be applied
+type egressPolicy struct {
+	schemes   map[string]bool
+	allowed   []egressDestination
+	denied    []egressDestination
+	tlsConfig *tls.Config
+}
+
+// EgressRules are the destinations allowed and denied by the "egress" tag of a plug
+type EgressRules struct {
+	allowed []egressDestination
+	denied  []egressDestination
+}
+
+type egressDestination struct {
+	spec string
+	host string // exact host name, or domain of the sub domains when sub is set
+	sub  bool
+	net  *net.IPNet
+}
+
+func parseEgressDestination(spec string) (egressDestination, error) {
+	destination := egressDestination{spec: spec}
+
+	if _, ipNet, err := net.ParseCIDR(spec); err == nil {
+		destination.net = ipNet
+		return destination, nil
+	}
+
+	if ip := net.ParseIP(spec); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		destination.net = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		return destination, nil
+	}
+
+	host := strings.ToLower(spec)
+	if strings.HasPrefix(host, "*.") {
+		destination.sub = true
+		host = host[2:]
+	}
+	if host == "" || strings.ContainsAny(host, "*/:@ ") {
+		return destination, fmt.Errorf("invalid egress destination '%s', should be a host, *.DOMAIN, an ip or a CIDR", spec)
+	}
+	destination.host = host
+
+	return destination, nil
+}
+
+// matches tells if a host, or the address it resolved to when ip is not nil, is this destination
+func (d *egressDestination) matches(host string, ip net.IP) bool {
+	if d.net != nil {
+		if ip != nil && d.net.Contains(ip) {
+			return true
+		}
+
+		hostIP := net.ParseIP(host)
+		return hostIP != nil && d.net.Contains(hostIP)
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	return host == d.host || (d.sub && strings.HasSuffix(host, "."+d.host))
+}
+
+func findEgressDestination(destinations []egressDestination, host string, ip net.IP) *egressDestination {
+	for i := range destinations {
+		if destinations[i].matches(host, ip) {
+			return &destinations[i]
+		}
+	}
+
+	return nil
+}
+
+func parseEgressDestinations(specs []string) ([]egressDestination, error) {
+	destinations := []egressDestination{}
+	for _, spec := range specs {
+		destination, err := parseEgressDestination(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		destinations = append(destinations, destination)
+	}
+
+	return destinations, nil
+}
+
+// ParseEgressRules reads the "egress" tag of a plug, an empty one gives no rules
+func ParseEgressRules(spec string) (*EgressRules, error) {
+	rules := &EgressRules{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		denied := strings.HasPrefix(entry, "!")
+		destination, err := parseEgressDestination(strings.TrimPrefix(entry, "!"))
+		if err != nil {
+			return nil, err
+		}
+
+		if denied {
+			rules.denied = append(rules.denied, destination)
+		} else {
+			rules.allowed = append(rules.allowed, destination)
+		}
+	}
+
+	return rules, nil
+}
+
+// AllowsMore tells if the rules allow destinations denied by the policy
+func (r *EgressRules) AllowsMore() bool {
+	return r != nil && len(r.allowed) > 0
+}
+
+// ForCalledFunction gives the rules of a function called with these ones, which keeps their denials
+func (r *EgressRules) ForCalledFunction() *EgressRules {
+	if r == nil {
+		return nil
+	}
+
+	return &EgressRules{denied: r.denied}
+}
+
+// SetEgressPolicy replaces the egress policy of the cluster
+func (o *Orchestrator) SetEgressPolicy(policy *EgressPolicy) error {
+	p := &egressPolicy{
+		schemes: make(map[string]bool),
+	}
+
+	for _, scheme := range policy.Schemes {
+		p.schemes[strings.ToLower(scheme)] = true
+	}
+
+	var err error
+	p.allowed, err = parseEgressDestinations(policy.Allow)
+	if err != nil {
+		return err
+	}
+	p.denied, err = parseEgressDestinations(policy.Deny)
+	if err != nil {
+		return err
+	}
+
+	p.tlsConfig = &tls.Config{InsecureSkipVerify: policy.InsecureSkipVerify}
+	if policy.CABundle != "" {
+		pem, err := ioutil.ReadFile(policy.CABundle)
+		if err != nil {
+			return fmt.Errorf("cannot read the egress ca bundle (%v)", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate in the egress ca bundle '%s'", policy.CABundle)
+		}
+		p.tlsConfig.RootCAs = pool
+	}
+
+	o.egressLock.Lock()
+	o.egress = p
+	o.egressLock.Unlock()
+
+	return nil
+}
+
+func (o *Orchestrator) egressPolicy() *egressPolicy {
+	o.egressLock.RLock()
+	defer o.egressLock.RUnlock()
+
+	return o.egress
+}
+
+// checkEgress returns why a host, resolved to ip when not nil, is denied, empty when it is allowed
+func (p *egressPolicy) checkEgress(rules *EgressRules, host string, ip net.IP) string {
+	if rules != nil {
+		if denied := findEgressDestination(rules.denied, host, ip); denied != nil {
+			return fmt.Sprintf("denied by the function: '%s'", denied.spec)
+		}
+		if findEgressDestination(rules.allowed, host, ip) != nil {
+			return ""
+		}
+	}
+
+	if denied := findEgressDestination(p.denied, host, ip); denied != nil {
+		return fmt.Sprintf("denied: '%s'", denied.spec)
+	}
+
+	if len(p.allowed) > 0 && findEgressDestination(p.allowed, host, ip) == nil {
+		return "not in the allowed destinations"
+	}
+
+	return ""
+}
+
+func (fctx *FunctionExecutionContext) egressDenied(target string, reason string) error {
+	fctx.Orchestrator.StatIncrement(STAT_NB_DENIED_EGRESS)
+
+	return fmt.Errorf("%w: function '%s' may not reach '%s' (%s)", ErrEgressDenied, fctx.Name, target, reason)
+}
+
+// CheckEgress fails with ErrEgressDenied when the function may not reach an url. The addresses
+// it resolves to are checked when connecting, by the clients of EgressHTTPClient and EgressWebSocketDialer.
+func (fctx *FunctionExecutionContext) CheckEgress(rawURL string) error {
+	policy := fctx.Orchestrator.egressPolicy()
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if !policy.schemes[strings.ToLower(u.Scheme)] {
+		return fctx.egressDenied(rawURL, fmt.Sprintf("scheme '%s' is not allowed", u.Scheme))
+	}
+
+	if reason := policy.checkEgress(fctx.Egress, u.Hostname(), nil); reason != "" {
+		return fctx.egressDenied(rawURL, reason)
+	}
+
+	return nil
+}
+
+// egressDialContext connects to an address after checking the one it resolves to
+func (fctx *FunctionExecutionContext) egressDialContext(policy *egressPolicy) func(ctx context.Context, network string, address string) (net.Conn, error) {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(network string, resolved string, c syscall.RawConn) error {
+				ipString, _, err := net.SplitHostPort(resolved)
+				if err != nil {
+					return err
+				}
+
+				if reason := policy.checkEgress(fctx.Egress, host, net.ParseIP(ipString)); reason != "" {
+					return fctx.egressDenied(fmt.Sprintf("%s (%s)", address, ipString), reason)
+				}
+
+				return nil
+			},
+		}
+
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+// EgressHTTPClient returns an http client applying the egress policy to the function, redirections
+// included. Its connections are closed after each request, so it needs no cleanup
+func (fctx *FunctionExecutionContext) EgressHTTPClient() *http.Client {
+	policy := fctx.Orchestrator.egressPolicy()
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         fctx.egressDialContext(policy),
+			TLSClientConfig:     policy.tlsConfig.Clone(),
+			TLSHandshakeTimeout: 10 * time.Second,
+			ForceAttemptHTTP2:   true,
+			DisableKeepAlives:   true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+
+			return fctx.CheckEgress(req.URL.String())
+		},
+	}
+}
+
+// EgressWebSocketDialer returns a websocket dialer applying the egress policy to the function, it
+// keeps no connection : the one it dials is closed with its websocket
+func (fctx *FunctionExecutionContext) EgressWebSocketDialer() *websocket.Dialer {
+	policy := fctx.Orchestrator.egressPolicy()
+
+	return &websocket.Dialer{
+		NetDialContext:   fctx.egressDialContext(policy),
+		TLSClientConfig:  policy.tlsConfig.Clone(),
+		HandshakeTimeout: 45 * time.Second,
+	}
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultEgressPolicyDeniesPrivateNetworks(t *testing.T) {
+	fctx := &FunctionExecutionContext{Name: "egress", Orchestrator: newTestOrchestrator(t)}
+
+	destinations := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://example.com/", true},
+		{"http://8.8.8.8/", true},
+		{"http://[2001:4860:4860::8888]/", true},
+		{"http://localhost:8080/", false},
+		{"http://127.0.0.1/", false},
+		{"http://10.1.2.3/", false},
+		{"http://172.16.0.1/", false},
+		{"http://172.31.255.255/", false},
+		{"http://172.32.0.1/", true},
+		{"http://192.168.1.1/", false},
+		{"http://[fd12:3456::1]/", false},
+		{"http://[fc00::1]/", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://[::ffff:10.0.0.1]/", false},
+		{"ftp://example.com/", false},
+	}
+
+	for _, destination := range destinations {
+		err := fctx.CheckEgress(destination.url)
+		if destination.allowed && err != nil {
+			t.Errorf("'%s' is denied (%v)", destination.url, err)
+		}
+		if !destination.allowed && !errors.Is(err, ErrEgressDenied) {
+			t.Errorf("'%s' is not denied (%v)", destination.url, err)
+		}
+	}
+}
+
+func TestEgressHTTPClientClosesConnections(t *testing.T) {
+	var closed int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	rules, err := ParseEgressRules("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fctx := &FunctionExecutionContext{Name: "egress", Orchestrator: newTestOrchestrator(t), Egress: rules}
+
+	for i := 0; i < 3; i++ {
+		resp, err := fctx.EgressHTTPClient().Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "hello" {
+			t.Fatalf("unexpected body %q", body)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&closed) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if closed := atomic.LoadInt32(&closed); closed != 3 {
+		t.Fatalf("%d connections closed after 3 requests", closed)
+	}
+
+	// without the rules of the function, the server address is denied when connecting
+	fctx.Egress = nil
+	_, err = fctx.EgressHTTPClient().Get(server.URL)
+	if !errors.Is(err, ErrEgressDenied) {
+		t.Fatalf("connection not denied (%v)", err)
+	}
+}
@@ -0,0 +1,227 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+/*
+	Execution engines
+
+	Several engines can be registered for the same content type (for example wasm3 and wasmer for
+	"application/wasm"), they are identified by their name. The engines of a content type are kept
+	in their registration order, which is the fallback order : a function runs with the first one
+	unless it asks for a specific engine.
+
+	A plugged function asks for an engine with the "engine" tag. The tag can list several engines
+	separated by commas, like "wasmer,wasm3" : the first one registered for the function content
+	type is used.
+
+	A blob asks for its engines with the "engine" parameter of its content type, like
+	"application/wasm; engine=wasmer" or 'application/wasm; engine="wasmer,wasm3"'. It applies to
+	every execution of the blob which does not ask for engines itself : filters, functions called
+	with call_function and plugged functions without "engine" tag. A called function of the
+	caller's content type and without engine parameter runs with the caller's engines.
+*/
+
+var ErrExecutionEngineUnavailable = errors.New("execution engine unavailable")
+
+type registeredEngine struct {
+	name   string
+	engine ExecutionEngine
+}
+
+func (o *Orchestrator) AddExecutionEngine(contentType string, name string, engine ExecutionEngine) {
+	o.executionEngines[contentType] = append(o.executionEngines[contentType], &registeredEngine{name, engine})
+	if cachingEngine, ok := engine.(CachingExecutionEngine); ok {
+		for _, cache := range cachingEngine.GetCaches() {
+			o.registerCache(cache)
+		}
+	}
+	fmt.Printf("registered '%s' execution engine '%s'\n", contentType, name)
+}
+
+// HasExecutionEngine tells if an engine with this name is registered, for any content type
+func (o *Orchestrator) HasExecutionEngine(name string) bool {
+	for _, engines := range o.executionEngines {
+		for _, registered := range engines {
+			if registered.name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ParseEngineNames returns the engines listed in an "engine" tag, in preference order
+func ParseEngineNames(tag string) []string {
+	names := []string{}
+	for _, name := range strings.Split(tag, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// blobContentType splits the content type of a blob into its media type and the engines asked by
+// its "engine" parameter
+func blobContentType(contentType string) (string, string) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType, ""
+	}
+
+	return mediaType, params["engine"]
+}
+
+// validateEngineNames checks that engines listed in an "engine" tag or parameter are known
+func (o *Orchestrator) validateEngineNames(tag string) ([]string, error) {
+	names := ParseEngineNames(tag)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("invalid engine '%s'", tag)
+	}
+
+	for _, name := range names {
+		if !o.HasExecutionEngine(name) {
+			return nil, fmt.Errorf("unknown execution engine '%s'", name)
+		}
+	}
+
+	return names, nil
+}
+
+// validateEngineTag checks that the engines asked by plug tags are known, and that one of them can
+// run the plugged blob if it is already registered
+func (o *Orchestrator) validateEngineTag(name string, tags map[string]string) error {
+	tag, ok := tags["engine"]
+	if !ok {
+		return nil
+	}
+
+	names, err := o.validateEngineNames(tag)
+	if err != nil {
+		return err
+	}
+
+	techID, err := o.GetBlobTechIDFromReference(name)
+	if err != nil {
+		return nil
+	}
+
+	abstract, err := o.GetBlobAbstractByTechID(techID)
+	if err != nil {
+		return nil
+	}
+
+	mediaType, _ := blobContentType(abstract.ContentType)
+	_, _, err = o.findExecutionEngine(mediaType, names)
+
+	return err
+}
+
+// validateBlobEngine checks that the engines asked by the content type of a blob can run it
+func (o *Orchestrator) validateBlobEngine(contentType string) error {
+	mediaType, tag := blobContentType(contentType)
+	if tag == "" {
+		return nil
+	}
+
+	names, err := o.validateEngineNames(tag)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = o.findExecutionEngine(mediaType, names)
+
+	return err
+}
+
+// selectExecutionEngine returns the engine for the content type of a blob : the first of the
+// requested ones registered for it, or of the ones asked by the blob, or the first registered one
+func (o *Orchestrator) selectExecutionEngine(contentType string, requested string) (string, ExecutionEngine, error) {
+	mediaType, blobEngines := blobContentType(contentType)
+
+	engines := o.executionEngines[mediaType]
+	if len(engines) == 0 {
+		return "", nil, fmt.Errorf("unknown function type '%s'", mediaType)
+	}
+
+	names := ParseEngineNames(requested)
+	if len(names) == 0 {
+		names = ParseEngineNames(blobEngines)
+	}
+	if len(names) == 0 {
+		return engines[0].name, engines[0].engine, nil
+	}
+
+	return o.findExecutionEngine(mediaType, names)
+}
+
+// findExecutionEngine returns the first of the named engines registered for the media type
+func (o *Orchestrator) findExecutionEngine(mediaType string, names []string) (string, ExecutionEngine, error) {
+	engines := o.executionEngines[mediaType]
+
+	for _, name := range names {
+		for _, registered := range engines {
+			if registered.name == name {
+				return registered.name, registered.engine, nil
+			}
+		}
+	}
+
+	available := []string{}
+	for _, registered := range engines {
+		available = append(available, registered.name)
+	}
+
+	return "", nil, fmt.Errorf("%w: '%s' requested for '%s', available engines are '%s'", ErrExecutionEngineUnavailable, strings.Join(names, ","), mediaType, strings.Join(available, ","))
+}
+
+// CalledFunctionEngine returns the engine a function called by the execution runs with : the
+// caller's one when the called blob has the caller's content type and asks for no engine
+func (fctx *FunctionExecutionContext) CalledFunctionEngine(name string) string {
+	if fctx.engineName == "" {
+		return ""
+	}
+
+	callerAbstract, _, err := fctx.Orchestrator.getFunctionCode(fctx.TechID)
+	if err != nil {
+		return ""
+	}
+
+	techID, err := fctx.Orchestrator.GetBlobTechIDFromReference(name)
+	if err != nil {
+		return ""
+	}
+
+	calledAbstract, _, err := fctx.Orchestrator.getFunctionCode(techID)
+	if err != nil {
+		return ""
+	}
+
+	callerType, _ := blobContentType(callerAbstract.ContentType)
+	calledType, calledEngines := blobContentType(calledAbstract.ContentType)
+	if calledType != callerType || calledEngines != "" {
+		return ""
+	}
+
+	return fctx.engineName
+}
+
+// GetExecutionEngines returns the names of the engines by content type, in fallback order
+func (o *Orchestrator) GetExecutionEngines() map[string][]string {
+	r := make(map[string][]string)
+	for contentType, engines := range o.executionEngines {
+		for _, registered := range engines {
+			r[contentType] = append(r[contentType], registered.name)
+		}
+	}
+
+	return r
+}
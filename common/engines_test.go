@@ -0,0 +1,139 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+type testEngine struct{}
+
+func (e *testEngine) PrepareContext(fctx *FunctionExecutionContext) (ExecutionEngineContext, error) {
+	return nil, errors.New("test engine")
+}
+
+func newEnginesTestOrchestrator(t *testing.T) *Orchestrator {
+	o := newTestOrchestrator(t)
+	o.AddExecutionEngine("application/wasm", "wasm3", &testEngine{})
+	o.AddExecutionEngine("application/wasm", "wasmer", &testEngine{})
+	o.AddExecutionEngine("text/javascript", "duktape", &testEngine{})
+
+	return o
+}
+
+func TestSelectExecutionEngine(t *testing.T) {
+	o := newEnginesTestOrchestrator(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		requested   string
+		engine      string
+		unavailable bool
+	}{
+		{"default", "application/wasm", "", "wasm3", false},
+		{"requested", "application/wasm", "wasmer", "wasmer", false},
+		{"preference order", "application/wasm", "wasmer,wasm3", "wasmer", false},
+		{"fallback", "application/wasm", "duktape, wasm3", "wasm3", false},
+		{"blob engine", "application/wasm; engine=wasmer", "", "wasmer", false},
+		{"blob engines", `application/wasm; engine="duktape,wasmer"`, "", "wasmer", false},
+		{"requested over blob", "application/wasm; engine=wasmer", "wasm3", "wasm3", false},
+		{"other content type", "text/javascript", "", "duktape", false},
+		{"unavailable", "application/wasm", "duktape", "", true},
+		{"unavailable for blob", "text/javascript; engine=wasmer", "", "", true},
+		{"unknown", "application/wasm", "v8", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, engine, err := o.selectExecutionEngine(test.contentType, test.requested)
+			if test.unavailable {
+				if !errors.Is(err, ErrExecutionEngineUnavailable) {
+					t.Fatalf("expected an unavailable engine error, got '%s' (%v)", name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != test.engine || engine == nil {
+				t.Fatalf("selected '%s', expected '%s'", name, test.engine)
+			}
+		})
+	}
+
+	_, _, err := o.selectExecutionEngine("text/plain", "")
+	if err == nil || errors.Is(err, ErrExecutionEngineUnavailable) {
+		t.Fatalf("a content type without engines gives '%v'", err)
+	}
+}
+
+func TestEngineTagChecksBlobContentType(t *testing.T) {
+	o := newEnginesTestOrchestrator(t)
+
+	_, err := o.RegisterBlobWithName("compute.wasm", "application/wasm", []byte("wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = o.RegisterBlobWithName("script.js", "text/javascript", []byte("js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		blob  string
+		tag   string
+		valid bool
+	}{
+		{"wasm engine for wasm", "compute.wasm", "wasmer", true},
+		{"one available engine", "compute.wasm", "duktape,wasm3", true},
+		{"javascript engine for wasm", "compute.wasm", "duktape", false},
+		{"wasm engine for javascript", "script.js", "wasm3", false},
+		{"unknown engine", "compute.wasm", "v8", false},
+		{"empty tag", "compute.wasm", " , ", false},
+		{"blob not yet registered", "later.wasm", "duktape", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := o.validateEngineTag(test.blob, map[string]string{"engine": test.tag})
+			if (err == nil) != test.valid {
+				t.Fatalf("validation of '%s' for '%s' gives '%v'", test.tag, test.blob, err)
+			}
+		})
+	}
+
+	_, err = o.RegisterBlob("text/javascript; engine=wasmer", []byte("other js"))
+	if !errors.Is(err, ErrExecutionEngineUnavailable) {
+		t.Fatalf("registering a blob with an engine of another content type gives '%v'", err)
+	}
+}
+
+func TestCalledFunctionEngine(t *testing.T) {
+	o := newEnginesTestOrchestrator(t)
+
+	callerTechID, _ := o.RegisterBlobWithName("caller.wasm", "application/wasm", []byte("caller"))
+	o.RegisterBlobWithName("called.wasm", "application/wasm", []byte("called"))
+	o.RegisterBlobWithName("tagged.wasm", "application/wasm; engine=wasm3", []byte("tagged"))
+	o.RegisterBlobWithName("called.js", "text/javascript", []byte("called js"))
+
+	caller := o.NewFunctionExecutionContext("caller.wasm", "_start", []int{}, false, "direct", nil, nil, 0, 0)
+	caller.TechID = callerTechID
+	caller.engineName = "wasmer"
+
+	tests := []struct {
+		called string
+		engine string
+	}{
+		{"called.wasm", "wasmer"},
+		{"tagged.wasm", ""},
+		{"called.js", ""},
+		{"missing.wasm", ""},
+	}
+
+	for _, test := range tests {
+		if engine := caller.CalledFunctionEngine(test.called); engine != test.engine {
+			t.Errorf("'%s' is called with the engine '%s', expected '%s'", test.called, engine, test.engine)
+		}
+	}
+}
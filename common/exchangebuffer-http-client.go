@@ -1,14 +1,15 @@
 package common
 
 import (
-	"crypto/tls"
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/ltearno/my-own-cluster/tools"
 
-	"github.com/golang-collections/go-datastructures/queue"
 	"github.com/gorilla/websocket"
 )
 
@@ -22,22 +23,38 @@ req, err := NewRequest("POST", url, body)
 
 /*
 	to send data to the remote server
+
+	the request body is a pipe : the guest writes parts that the http client reads
+	while sending the request, the guest is blocked when the client is late. The body
+	can also be read from another exchange buffer, the pipe is then left unused
 */
 type requestWrapper struct {
-	request *http.Request
-	headers map[string]string
+	*PipeExchangeBuffer
 
-	queue          *queue.Queue
-	availableBytes []byte
+	request *http.Request
+	reader  *ExchangeBufferReader
 }
 
-func newRequestWrapper(method string, url string, headers map[string]string) (*requestWrapper, error) {
+// maximum number of parts waiting to be sent to the remote server
+const requestWrapperMaxParts = 16
+
+func newRequestWrapper(method string, url string, headers map[string]string, body ExchangeBuffer) (*requestWrapper, error) {
 	w := &requestWrapper{
-		headers: make(map[string]string),
-		queue:   queue.New(1),
+		PipeExchangeBuffer: NewPipeExchangeBuffer(requestWrapperMaxParts),
+	}
+	w.reader = NewExchangeBufferReader(w.PipeExchangeBuffer)
+
+	var bodyReader io.Reader = w
+	if body != nil {
+		if body.IsWriteFinished() {
+			// the length is known, and there is no body at all when it is empty
+			bodyReader = bytes.NewReader(body.GetBuffer())
+		} else {
+			bodyReader = NewExchangeBufferReader(body)
+		}
 	}
 
-	req, err := http.NewRequest(method, url, w)
+	req, err := http.NewRequest(method, url, bodyReader)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +62,7 @@ func newRequestWrapper(method string, url string, headers map[string]string) (*r
 	w.request = req
 
 	for k, v := range headers {
-		w.headers[k] = v
+		w.PipeExchangeBuffer.SetHeader(k, v)
 		req.Header.Set(k, v)
 	}
 
@@ -54,47 +71,7 @@ func newRequestWrapper(method string, url string, headers map[string]string) (*r
 
 // called by http client when sending the request body
 func (w *requestWrapper) Read(buffer []byte) (int, error) {
-	//fmt.Printf("rw read\n")
-	for {
-		if w.availableBytes != nil {
-			// first purge available bytes
-			toGive := tools.Min(len(buffer), len(w.availableBytes))
-			copy(buffer[0:toGive], w.availableBytes[0:toGive])
-			w.availableBytes = w.availableBytes[toGive:]
-			if len(w.availableBytes) == 0 {
-				w.availableBytes = nil
-			}
-			//fmt.Printf("rw give %d bytes\n", toGive)
-			return toGive, nil
-		} else if w.queue.Disposed() {
-			// if finished, say it
-			//fmt.Printf("rw says EOF\n")
-			return 0, io.EOF
-		} else {
-			// or else wait for next available bytes
-			//fmt.Printf("rw waits on queue\n")
-			b, _ := w.queue.Get(1)
-			//fmt.Printf("rw wait is finished with %v\n", b)
-			if b != nil && len(b) > 0 && len(b[0].([]byte)) > 0 {
-				w.availableBytes = b[0].([]byte)
-			}
-		}
-	}
-}
-
-func (w *requestWrapper) GetHeader(name string) (string, bool) {
-	v, ok := w.headers[name]
-	return v, ok
-}
-
-func (w *requestWrapper) GetHeadersCount() int {
-	return len(w.headers)
-}
-
-func (w *requestWrapper) GetHeaders(cb func(name string, value string)) {
-	for k, v := range w.headers {
-		cb(k, v)
-	}
+	return w.reader.Read(buffer)
 }
 
 func (w *requestWrapper) GetStatusCode() int {
@@ -107,32 +84,30 @@ func (w *requestWrapper) GetBuffer() []byte {
 	return nil
 }
 
-func (w *requestWrapper) SetHeader(name string, value string) {
-	w.headers[name] = value
+func (w *requestWrapper) ReadPart() ([]byte, error) {
+	return nil, fmt.Errorf("cannot read from an http client request")
 }
 
 func (w *requestWrapper) WriteStatusCode(statusCode int) {
 	fmt.Printf("err KJKENEBKJ\n")
 }
 
-func (w *requestWrapper) Write(buffer []byte) (int, error) {
-	o := make([]byte, len(buffer))
-	copy(o, buffer)
-	w.queue.Put(o)
-	return len(buffer), nil
-}
-
-func (w *requestWrapper) Close() int {
-	w.queue.Dispose()
-	return 0
-}
+// header telling why an http client request failed, set on the response buffer
+const HttpClientErrorHeader = "x-moc-fetch-error"
 
 /*
 	to receive data from the remote server
+
+	header names are lower case. When reading the body fails (timeout, broken connection...)
+	the body ends there and the error is given by the HttpClientErrorHeader header
 */
 type responseWrapper struct {
 	response *http.Response
 	headers  map[string]string
+
+	bodyFinished bool
+	// remaining body, once materialized by GetBuffer
+	body []byte
 }
 
 func newResponseWrapper(r *http.Response) (*responseWrapper, error) {
@@ -147,7 +122,7 @@ func newResponseWrapper(r *http.Response) (*responseWrapper, error) {
 		}
 
 		if len(v) > 0 {
-			w.headers[k] = v[0]
+			w.headers[strings.ToLower(k)] = v[0]
 		}
 	}
 
@@ -155,7 +130,7 @@ func newResponseWrapper(r *http.Response) (*responseWrapper, error) {
 }
 
 func (w *responseWrapper) GetHeader(name string) (string, bool) {
-	h, ok := w.headers[name]
+	h, ok := w.headers[strings.ToLower(name)]
 	return h, ok
 }
 
@@ -174,19 +149,60 @@ func (w *responseWrapper) GetStatusCode() int {
 }
 
 func (w *responseWrapper) GetBuffer() []byte {
-	buffer := make([]byte, 1024*1024)
-	n, err := w.response.Body.Read(buffer)
-	if n > 0 {
-		return buffer[0:n]
+	if w.bodyFinished {
+		return w.body
 	}
+
+	body, err := ioutil.ReadAll(w.response.Body)
 	if err != nil {
-		//fmt.Printf("Error while reading http response '%v'\n", err)
+		w.headers[HttpClientErrorHeader] = err.Error()
 	}
-	return nil
+
+	if len(body) == 0 {
+		body = nil
+	}
+
+	w.body = body
+	w.bodyFinished = true
+
+	return body
+}
+
+func (w *responseWrapper) ReadPart() ([]byte, error) {
+	if w.bodyFinished {
+		if w.body == nil {
+			return nil, io.EOF
+		}
+
+		part := w.body
+		w.body = nil
+		return part, nil
+	}
+
+	part := make([]byte, exchangeBufferPartSize)
+	n, err := w.response.Body.Read(part)
+	if err == io.EOF {
+		w.bodyFinished = true
+		if n == 0 {
+			return nil, io.EOF
+		}
+	} else if err != nil {
+		w.headers[HttpClientErrorHeader] = err.Error()
+		w.bodyFinished = true
+		if n == 0 {
+			return nil, io.EOF
+		}
+	}
+
+	return part[:n], nil
+}
+
+func (w *responseWrapper) IsWriteFinished() bool {
+	return w.bodyFinished && w.body == nil
 }
 
 func (w *responseWrapper) SetHeader(name string, value string) {
-	w.headers[name] = value
+	w.headers[strings.ToLower(name)] = value
 }
 
 func (w *responseWrapper) WriteStatusCode(statusCode int) {
@@ -202,19 +218,15 @@ func (w *responseWrapper) Close() int {
 	return 0
 }
 
-func (o *Orchestrator) CreateExchangeBuffersFromHttpClientRequest(method string, url string, headers map[string]string) (int, int, error) {
-	requestWrapper, err := newRequestWrapper(method, url, headers)
+// CreateExchangeBuffersFromHttpClientRequest sends a request, the body being written in the
+// returned request buffer or read from body when not nil, and returns once the response headers
+// are received. The response body is streamed from the returned response buffer.
+func (o *Orchestrator) CreateExchangeBuffersFromHttpClientRequest(client *http.Client, method string, url string, headers map[string]string, body ExchangeBuffer) (int, int, error) {
+	requestWrapper, err := newRequestWrapper(method, url, headers, body)
 	if err != nil {
 		return -1, -1, err
 	}
 
-	client := &http.Client{Transport: &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-			MaxVersion:         tls.VersionTLS12,
-		},
-	}}
-
 	response, err := client.Do(requestWrapper.request)
 	if err != nil {
 		return -1, -1, err
@@ -228,7 +240,7 @@ func (o *Orchestrator) CreateExchangeBuffersFromHttpClientRequest(method string,
 	return o.RegisterExchangeBuffer(requestWrapper), o.RegisterExchangeBuffer(responseWrapper), nil
 }
 
-func (o *Orchestrator) CreateExchangeBuffersFromWebSocketClient(method string, url string, headers map[string]string) (int, int, error) {
+func (o *Orchestrator) CreateExchangeBuffersFromWebSocketClient(dialer *websocket.Dialer, method string, url string, headers map[string]string) (int, int, error) {
 	h := make(map[string][]string)
 	for k, v := range headers {
 		h[k] = []string{v}
@@ -241,7 +253,7 @@ func (o *Orchestrator) CreateExchangeBuffersFromWebSocketClient(method string, u
 	}
 	ctx := httptrace.WithClientTrace(context.Background(), trace)*/
 
-	con, response, err := websocket.DefaultDialer.Dial(url, h)
+	con, response, err := dialer.Dial(url, h)
 	if err != nil {
 		return -1, -1, err
 	}
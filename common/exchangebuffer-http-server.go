@@ -2,9 +2,11 @@ package common
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 )
 
 func (o *Orchestrator) CreateWrappedHttpRequestExchangeBuffer(r *http.Request) int {
@@ -19,6 +21,10 @@ type HttpReaderExchangeBuffer struct {
 	r           *http.Request
 	headersRead bool
 	headers     map[string]string
+
+	bodyFinished bool
+	// remaining body, once materialized by GetBuffer
+	body []byte
 }
 
 func WrapHttpReaderAsExchangeBuffer(r *http.Request) *HttpReaderExchangeBuffer {
@@ -59,17 +65,56 @@ func (b *HttpReaderExchangeBuffer) GetStatusCode() int {
 
 func (b *HttpReaderExchangeBuffer) GetBuffer() []byte {
 	b.ensureHeadersReadFromRequest()
+
+	if b.bodyFinished {
+		return b.body
+	}
+
 	body, err := ioutil.ReadAll(b.r.Body)
-	if err == nil {
-		if body != nil && len(body) == 0 {
-			body = nil
+	if err != nil {
+		fmt.Printf("ERROR http wrapped request CANNOT READ BODY from request with method='%s', url='%s' (%v)\n", b.r.Method, b.r.RequestURI, err)
+		return nil
+	}
+
+	if len(body) == 0 {
+		body = nil
+	}
+
+	b.body = body
+	b.bodyFinished = true
+
+	return body
+}
+
+func (b *HttpReaderExchangeBuffer) ReadPart() ([]byte, error) {
+	b.ensureHeadersReadFromRequest()
+
+	if b.bodyFinished {
+		if b.body == nil {
+			return nil, io.EOF
 		}
 
-		return body
-	} else {
-		fmt.Printf("ERROR http wrapped request CANNOT READ BODY from request with method='%s', url='%s', maybe tried to read it twice ? (%v)\n", b.r.Method, b.r.RequestURI, err)
-		return nil
+		part := b.body
+		b.body = nil
+		return part, nil
+	}
+
+	part := make([]byte, exchangeBufferPartSize)
+	n, err := io.ReadFull(b.r.Body, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		b.bodyFinished = true
+		if n == 0 {
+			return nil, io.EOF
+		}
+	} else if err != nil {
+		return nil, err
 	}
+
+	return part[:n], nil
+}
+
+func (b *HttpReaderExchangeBuffer) IsWriteFinished() bool {
+	return b.bodyFinished && b.body == nil
 }
 
 func (b *HttpReaderExchangeBuffer) WriteStatusCode(statusCode int) {
@@ -100,8 +145,13 @@ func (b *HttpReaderExchangeBuffer) ensureHeadersReadFromRequest() {
 	WRITER
 */
 
+// once closed, the writer does not touch the response anymore, so that the web server
+// can answer by itself when the function is aborted
 type HttpWriterExchangeBuffer struct {
-	w http.ResponseWriter
+	lock     sync.Mutex
+	w        http.ResponseWriter
+	started  bool // the status code or a part of the body has been sent
+	finished bool
 }
 
 func WrapHttpWriterAsExchangeBuffer(w http.ResponseWriter) *HttpWriterExchangeBuffer {
@@ -111,6 +161,13 @@ func WrapHttpWriterAsExchangeBuffer(w http.ResponseWriter) *HttpWriterExchangeBu
 }
 
 func (b *HttpWriterExchangeBuffer) GetHeader(name string) (string, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.finished {
+		return "", false
+	}
+
 	return b.w.Header().Get(name), true
 }
 
@@ -120,6 +177,13 @@ func (b *HttpWriterExchangeBuffer) GetStatusCode() int {
 }
 
 func (b *HttpWriterExchangeBuffer) SetHeader(name string, value string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.finished {
+		return
+	}
+
 	b.w.Header().Set(name, value)
 }
 
@@ -137,19 +201,63 @@ func (b *HttpWriterExchangeBuffer) GetBuffer() []byte {
 	return nil
 }
 
+func (b *HttpWriterExchangeBuffer) ReadPart() ([]byte, error) {
+	return nil, fmt.Errorf("cannot read from a wrapped http response writer")
+}
+
+func (b *HttpWriterExchangeBuffer) IsWriteFinished() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.finished
+}
+
 func (b *HttpWriterExchangeBuffer) WriteStatusCode(statusCode int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.finished {
+		return
+	}
+
 	b.w.WriteHeader(statusCode)
+	b.started = true
 }
 
 func (b *HttpWriterExchangeBuffer) Write(buffer []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.finished {
+		return -1, fmt.Errorf("cannot write on a closed http response")
+	}
+
+	b.started = true
 	_, err := b.w.Write(buffer)
 	if err != nil {
 		return -1, err
 	}
 
+	// send the parts as soon as they are written, so that long responses are streamed to the client
+	if flusher, ok := b.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
 	return len(buffer), nil
 }
 
+// HasStartedResponse tells if the status code or a part of the body has already been sent
+func (b *HttpWriterExchangeBuffer) HasStartedResponse() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.started
+}
+
 func (b *HttpWriterExchangeBuffer) Close() int {
+	b.lock.Lock()
+	b.finished = true
+	b.lock.Unlock()
+
 	return 0
 }
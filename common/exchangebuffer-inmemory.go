@@ -1,80 +1,285 @@
 package common
 
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
 func NewMemoryExchangeBuffer() *InMemoryExchangeBuffer {
-	return &InMemoryExchangeBuffer{
+	p := &InMemoryExchangeBuffer{
 		headers:    make(map[string]string),
 		buffer:     []byte{},
 		statusCode: 200,
 	}
+
+	p.cond = sync.NewCond(&p.lock)
+
+	return p
+}
+
+// NewStreamingMemoryExchangeBuffer creates an in memory buffer whose readers wait for the writer
+// to produce data or close the buffer, and whose writer waits for the reader when too much data
+// is pending, so that a reader and a writer can run concurrently
+func NewStreamingMemoryExchangeBuffer() *InMemoryExchangeBuffer {
+	p := NewMemoryExchangeBuffer()
+	p.streaming = true
+	return p
 }
 
 /*
 	In memory implementation of ExchangeBuffer
+
+	It is safe for one writer and one reader running concurrently. The reader and the writer
+	have their own position in the buffer, which can be moved with SeekRead and SeekWrite.
+
+	When streaming, the writer waits while streamingMaxPendingBytes are written but not yet read
+	(backpressure), unless a reader waits in GetBuffer for the whole content.
 */
 type InMemoryExchangeBuffer struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
 	headers    map[string]string
 	buffer     []byte
 	statusCode int
+
+	readPos       int
+	writePos      int
+	writeFinished bool
+
+	// when streaming, readers block until data arrives or the buffer is closed
+	streaming bool
+	draining  int // readers waiting in GetBuffer, the writer does not wait for them
 }
 
+// amount of data not yet read beyond which the writer of a streaming buffer waits
+const streamingMaxPendingBytes = 4 * exchangeBufferPartSize
+
 func (o *Orchestrator) CreateExchangeBuffer() int {
 	return o.RegisterExchangeBuffer(NewMemoryExchangeBuffer())
 }
 
 func (p *InMemoryExchangeBuffer) GetHeader(name string) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	s, ok := p.headers[name]
 	return s, ok
 }
 
 func (p *InMemoryExchangeBuffer) SetHeader(name string, value string) {
+	p.lock.Lock()
 	p.headers[name] = value
+	p.lock.Unlock()
 }
 
 func (p *InMemoryExchangeBuffer) GetHeadersCount() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	return len(p.headers)
 }
 
 func (p *InMemoryExchangeBuffer) GetHeaders(cb func(name string, value string)) {
+	// callback is called outside of the lock, so that it can use the buffer
+	p.lock.Lock()
+	headers := make(map[string]string, len(p.headers))
 	for name, value := range p.headers {
+		headers[name] = value
+	}
+	p.lock.Unlock()
+
+	for name, value := range headers {
 		cb(name, value)
 	}
 }
 
 func (p *InMemoryExchangeBuffer) GetStatusCode() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
 	return p.statusCode
 }
 
+// GetBuffer returns a copy of the content after the read position, without moving it.
+// When streaming, it waits for the writer to close the buffer.
 func (p *InMemoryExchangeBuffer) GetBuffer() []byte {
-	return p.buffer
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.streaming && !p.writeFinished {
+		p.draining++
+		p.cond.Broadcast()
+		for !p.writeFinished {
+			p.cond.Wait()
+		}
+		p.draining--
+	}
+
+	if p.readPos >= len(p.buffer) {
+		return []byte{}
+	}
+
+	return dup(p.buffer[p.readPos:])
+}
+
+// ReadPart returns what has been written after the read position. When not streaming, the buffer
+// is filled before being read, so ReadPart does not wait for the writer and returns io.EOF when there is nothing left.
+func (p *InMemoryExchangeBuffer) ReadPart() ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for p.streaming && p.readPos >= len(p.buffer) && !p.writeFinished {
+		p.cond.Wait()
+	}
+
+	if p.readPos >= len(p.buffer) {
+		return nil, io.EOF
+	}
+
+	part := dup(p.buffer[p.readPos:])
+	p.readPos = len(p.buffer)
+
+	p.cond.Broadcast()
+
+	return part, nil
+}
+
+func (p *InMemoryExchangeBuffer) IsWriteFinished() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.writeFinished
 }
 
 func (p *InMemoryExchangeBuffer) WriteStatusCode(statusCode int) {
+	p.lock.Lock()
 	p.statusCode = statusCode
+	p.lock.Unlock()
 }
 
+// Write writes at the write position, overwriting existing data and extending the buffer if needed.
+// When streaming, it first waits for the reader to catch up.
 func (p *InMemoryExchangeBuffer) Write(buffer []byte) (int, error) {
-	// TODO WARNING THIS DOES NOT TAKE WRITE POS IN ACCOUNT !!!
-	p.buffer = appendSlice(p.buffer, buffer)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for p.streaming && p.draining == 0 && !p.writeFinished && len(p.buffer)-p.readPos >= streamingMaxPendingBytes {
+		p.cond.Wait()
+	}
+
+	if p.writeFinished {
+		return -1, fmt.Errorf("cannot write on a closed exchange buffer")
+	}
+
+	end := p.writePos + len(buffer)
+	if end > len(p.buffer) {
+		p.buffer = resizeSlice(p.buffer, end)
+	}
+	copy(p.buffer[p.writePos:end], buffer)
+	p.writePos = end
+
+	p.cond.Broadcast()
 
 	return len(buffer), nil
 }
 
-/*
-	TODO : should be called 'Release' and trigger the exchange buffer GC
-*/
+// SeekRead moves the read position, whence is io.SeekStart, io.SeekCurrent or io.SeekEnd
+func (p *InMemoryExchangeBuffer) SeekRead(offset int64, whence int) (int64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pos, err := p.seekPosition(p.readPos, offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	p.readPos = pos
+	p.cond.Broadcast()
+
+	return int64(pos), nil
+}
+
+// SeekWrite moves the write position, whence is io.SeekStart, io.SeekCurrent or io.SeekEnd.
+// Seeking after the end of the buffer is allowed, the gap is filled with zeros on next write.
+func (p *InMemoryExchangeBuffer) SeekWrite(offset int64, whence int) (int64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pos, err := p.seekPosition(p.writePos, offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	p.writePos = pos
+
+	return int64(pos), nil
+}
+
+func (p *InMemoryExchangeBuffer) seekPosition(current int, offset int64, whence int) (int, error) {
+	var base int64
+	switch whence {
+	case io.SeekStart:
+		base = 0
+	case io.SeekCurrent:
+		base = int64(current)
+	case io.SeekEnd:
+		base = int64(len(p.buffer))
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	pos := base + offset
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position %d", pos)
+	}
+
+	return int(pos), nil
+}
+
+// Truncate changes the buffer size, extending it with zeros if needed
+func (p *InMemoryExchangeBuffer) Truncate(size int) error {
+	if size < 0 {
+		return fmt.Errorf("negative size %d", size)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.buffer = resizeSlice(p.buffer, size)
+	if p.readPos > size {
+		p.readPos = size
+	}
+
+	p.cond.Broadcast()
+
+	return nil
+}
+
+// Close signals that the writer has finished and wakes up the waiting readers
 func (p *InMemoryExchangeBuffer) Close() int {
+	p.lock.Lock()
+	p.writeFinished = true
+	p.cond.Broadcast()
+	p.lock.Unlock()
+
 	return 0
 }
 
-func appendSlice(slice []byte, data []byte) []byte {
+// resizeSlice changes the length of a slice, new bytes being zeroed
+func resizeSlice(slice []byte, n int) []byte {
 	m := len(slice)
-	n := m + len(data)
 	if n > cap(slice) {
-		newSlice := make([]byte, (n+1)*2)
+		newSlice := make([]byte, n, (n+1)*2)
 		copy(newSlice, slice)
-		slice = newSlice
+		return newSlice
 	}
+
 	slice = slice[0:n]
-	copy(slice[m:n], data)
+	for i := m; i < n; i++ {
+		slice[i] = 0
+	}
+
 	return slice
 }
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// NewPipeExchangeBuffer creates a pipe queueing at most maxParts parts, at least one
+func NewPipeExchangeBuffer(maxParts int) *PipeExchangeBuffer {
+	if maxParts < 1 {
+		maxParts = 1
+	}
+
+	p := &PipeExchangeBuffer{
+		headers:    make(map[string]string),
+		statusCode: 200,
+		parts:      make([][]byte, 0),
+		maxParts:   maxParts,
+	}
+
+	p.cond = sync.NewCond(&p.lock)
+
+	return p
+}
+
+/*
+	Pipe implementation of ExchangeBuffer
+
+	A queue of parts with one writer and one reader running concurrently. The writer
+	blocks when the queue is full (backpressure) and the reader blocks when it is empty,
+	until the writer closes the buffer.
+*/
+type PipeExchangeBuffer struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	headers    map[string]string
+	statusCode int
+
+	parts         [][]byte
+	maxParts      int
+	writeFinished bool
+}
+
+func (p *PipeExchangeBuffer) GetHeader(name string) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s, ok := p.headers[strings.ToLower(name)]
+	return s, ok
+}
+
+func (p *PipeExchangeBuffer) SetHeader(name string, value string) {
+	p.lock.Lock()
+	p.headers[strings.ToLower(name)] = value
+	p.lock.Unlock()
+}
+
+func (p *PipeExchangeBuffer) GetHeadersCount() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return len(p.headers)
+}
+
+func (p *PipeExchangeBuffer) GetHeaders(cb func(name string, value string)) {
+	p.lock.Lock()
+	headers := make(map[string]string, len(p.headers))
+	for name, value := range p.headers {
+		headers[name] = value
+	}
+	p.lock.Unlock()
+
+	for name, value := range headers {
+		cb(name, value)
+	}
+}
+
+func (p *PipeExchangeBuffer) GetStatusCode() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.statusCode
+}
+
+func (p *PipeExchangeBuffer) WriteStatusCode(statusCode int) {
+	p.lock.Lock()
+	p.statusCode = statusCode
+	p.lock.Unlock()
+}
+
+func (p *PipeExchangeBuffer) Write(buffer []byte) (int, error) {
+	part := make([]byte, len(buffer))
+	copy(part, buffer)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for len(p.parts) >= p.maxParts && !p.writeFinished {
+		p.cond.Wait()
+	}
+
+	if p.writeFinished {
+		return -1, fmt.Errorf("cannot write on a closed pipe exchange buffer")
+	}
+
+	p.parts = append(p.parts, part)
+	p.cond.Broadcast()
+
+	return len(buffer), nil
+}
+
+func (p *PipeExchangeBuffer) ReadPart() ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for len(p.parts) == 0 && !p.writeFinished {
+		p.cond.Wait()
+	}
+
+	if len(p.parts) == 0 {
+		return nil, io.EOF
+	}
+
+	part := p.parts[0]
+	p.parts = p.parts[1:]
+	p.cond.Broadcast()
+
+	return part, nil
+}
+
+func (p *PipeExchangeBuffer) IsWriteFinished() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.writeFinished
+}
+
+// GetBuffer reads the parts until the writer closes the pipe, and queues them back as one part
+func (p *PipeExchangeBuffer) GetBuffer() []byte {
+	var r []byte
+
+	for {
+		part, err := p.ReadPart()
+		if err != nil {
+			break
+		}
+
+		r = append(r, part...)
+	}
+
+	// everything is consumed, keep the result so that next calls give the same content
+	if r != nil {
+		p.lock.Lock()
+		p.parts = [][]byte{r}
+		p.lock.Unlock()
+	}
+
+	return r
+}
+
+func (p *PipeExchangeBuffer) Close() int {
+	p.lock.Lock()
+	p.writeFinished = true
+	p.cond.Broadcast()
+	p.lock.Unlock()
+
+	return 0
+}
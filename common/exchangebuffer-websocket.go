@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -18,6 +19,10 @@ type WebSocketExchangeBuffer struct {
 	headers map[string]string
 	// kind of hacky, could use a special header to signal that.
 	currentMessageFormat int
+	finished             bool
+	// message read by GetBuffer and not yet consumed by ReadPart
+	pending    []byte
+	hasPending bool
 }
 
 func WrapWebSocketAsExchangeBuffer(headers map[string]string, c *websocket.Conn) *WebSocketExchangeBuffer {
@@ -60,17 +65,54 @@ func (b *WebSocketExchangeBuffer) GetStatusCode() int {
 	return 101
 }
 
+// GetBuffer returns the next message, web sockets are never materialized as a whole. The message
+// is kept until ReadPart consumes it, so calling GetBuffer again gives the same one.
 func (b *WebSocketExchangeBuffer) GetBuffer() []byte {
+	if !b.hasPending {
+		buf, err := b.readMessage()
+		if err != nil {
+			return nil
+		}
+		b.pending, b.hasPending = buf, true
+	}
+
+	return b.pending
+}
+
+// ReadPart reads the next message, each message is a part
+func (b *WebSocketExchangeBuffer) ReadPart() ([]byte, error) {
+	if b.hasPending {
+		buf := b.pending
+		b.pending, b.hasPending = nil, false
+		return buf, nil
+	}
+
+	return b.readMessage()
+}
+
+func (b *WebSocketExchangeBuffer) readMessage() ([]byte, error) {
+	if b.finished {
+		return nil, io.EOF
+	}
+
 	mt, buf, err := b.c.ReadMessage()
 	if err != nil {
-		return nil
+		b.finished = true
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return nil, io.EOF
+		}
+		return nil, err
 	}
 
 	b.currentMessageFormat = mt
 
 	fmt.Printf("websocket : just read message (%d) from client\n", mt)
 
-	return buf
+	return buf, nil
+}
+
+func (b *WebSocketExchangeBuffer) IsWriteFinished() bool {
+	return b.finished
 }
 
 func (b *WebSocketExchangeBuffer) WriteStatusCode(statusCode int) {
@@ -1,5 +1,12 @@
 package common
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
 /*
 	ExchangeBuffer is a byte buffer together with a set of headers.
 
@@ -16,21 +23,113 @@ type ExchangeBuffer interface {
 
 	GetStatusCode() int
 
+	// GetBuffer materializes the content not yet consumed by ReadPart, calling it twice gives the same result
 	GetBuffer() []byte
 
+	// ReadPart returns the next part written in the buffer, blocking until one is available.
+	// It returns io.EOF once the writer has finished and all the parts have been read.
+	ReadPart() ([]byte, error)
+
+	// IsWriteFinished returns true once the writer has closed the buffer
+	IsWriteFinished() bool
+
 	SetHeader(name string, value string)
 
 	WriteStatusCode(statusCode int)
 	Write(buffer []byte) (int, error)
 
+	// Close signals that the writer has finished
 	Close() int
 }
 
+/*
+	SeekableExchangeBuffer is implemented by exchange buffers which keep their content,
+	allowing to move the read and write positions like in a file.
+*/
+type SeekableExchangeBuffer interface {
+	ExchangeBuffer
+
+	SeekRead(offset int64, whence int) (int64, error)
+	SeekWrite(offset int64, whence int) (int64, error)
+	Truncate(size int) error
+}
+
+// size of the parts read from underlying streams (http bodies, ...)
+const exchangeBufferPartSize = 64 * 1024
+
+/*
+	ExchangeBufferReader adapts an exchange buffer to the io.Reader interface, reading it part by part
+*/
+type ExchangeBufferReader struct {
+	b         ExchangeBuffer
+	available []byte
+}
+
+func NewExchangeBufferReader(b ExchangeBuffer) *ExchangeBufferReader {
+	return &ExchangeBufferReader{
+		b: b,
+	}
+}
+
+func (r *ExchangeBufferReader) Read(buffer []byte) (int, error) {
+	for len(r.available) == 0 {
+		part, err := r.b.ReadPart()
+		if err != nil {
+			return 0, err
+		}
+
+		r.available = part
+	}
+
+	n := copy(buffer, r.available)
+	r.available = r.available[n:]
+
+	return n, nil
+}
+
+// Buffered returns the number of bytes fetched from the exchange buffer but not yet read
+func (r *ExchangeBufferReader) Buffered() int {
+	return len(r.available)
+}
+
+/*
+	Exchange buffers are reference counted.
+
+	Registering a buffer gives one reference to the registering code. Function execution contexts
+	acquire a reference on their input and output buffers for the duration of their execution, and
+	own the buffers they create. Once the last reference is released, the buffer is closed, so that
+	the resources behind it are freed (like the body of a fetched response which was not read),
+	forgotten by the orchestrator and whoever waits for it (typically the web server, to complete
+	the http response) is notified.
+*/
+type exchangeBufferEntry struct {
+	buffer     ExchangeBuffer
+	references int
+	creator    string
+	released   chan struct{}
+}
+
+type ExchangeBufferStatus struct {
+	ID         int    `json:"id"`
+	Creator    string `json:"creator"`
+	References int    `json:"references"`
+	Type       string `json:"type"`
+}
+
 func (o *Orchestrator) RegisterExchangeBuffer(exchangeBuffer ExchangeBuffer) int {
+	return o.registerExchangeBuffer(exchangeBuffer, "host")
+}
+
+func (o *Orchestrator) registerExchangeBuffer(exchangeBuffer ExchangeBuffer, creator string) int {
 	o.lock.Lock()
 	bufferID := o.nextExchangeBufferID
 	o.nextExchangeBufferID++
-	o.exchangeBuffers[int(bufferID)] = exchangeBuffer
+	o.exchangeBuffers[int(bufferID)] = &exchangeBufferEntry{
+		buffer:     exchangeBuffer,
+		references: 1,
+		creator:    creator,
+		released:   make(chan struct{}),
+	}
 	o.lock.Unlock()
 
 	o.StatIncrement(STAT_NB_CREATED_BUFFERS)
@@ -40,13 +139,284 @@ func (o *Orchestrator) RegisterExchangeBuffer(exchangeBuffer ExchangeBuffer) int
 
 func (o *Orchestrator) GetExchangeBuffer(bufferID int) ExchangeBuffer {
 	o.lock.Lock()
-	buffer := o.exchangeBuffers[bufferID]
-	o.lock.Unlock()
-	return buffer
+	defer o.lock.Unlock()
+
+	entry, ok := o.exchangeBuffers[bufferID]
+	if !ok {
+		return nil
+	}
+
+	return entry.buffer
+}
+
+// AcquireExchangeBuffer adds a reference to the buffer, returns false if the buffer does not exist
+func (o *Orchestrator) AcquireExchangeBuffer(bufferID int) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	entry, ok := o.exchangeBuffers[bufferID]
+	if !ok {
+		return false
+	}
+
+	entry.references++
+
+	return true
 }
 
+// ReleaseExchangeBuffer removes a reference to the buffer, which is closed and freed when no reference remains
 func (o *Orchestrator) ReleaseExchangeBuffer(bufferID int) {
 	o.lock.Lock()
+	entry, ok := o.exchangeBuffers[bufferID]
+	if !ok {
+		o.lock.Unlock()
+		return
+	}
+
+	entry.references--
+	if entry.references > 0 {
+		o.lock.Unlock()
+		return
+	}
+
 	delete(o.exchangeBuffers, bufferID)
 	o.lock.Unlock()
+
+	entry.buffer.Close()
+	close(entry.released)
+
+	o.StatIncrement(STAT_NB_RELEASED_BUFFERS)
+}
+
+// WaitExchangeBufferRelease returns a channel closed when the last reference to the buffer disappears
+func (o *Orchestrator) WaitExchangeBufferRelease(bufferID int) <-chan struct{} {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	entry, ok := o.exchangeBuffers[bufferID]
+	if !ok {
+		released := make(chan struct{})
+		close(released)
+		return released
+	}
+
+	return entry.released
+}
+
+// GetExchangeBuffersStatus lists the buffers still alive, used to track leaks
+func (o *Orchestrator) GetExchangeBuffersStatus() []ExchangeBufferStatus {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	r := make([]ExchangeBufferStatus, 0, len(o.exchangeBuffers))
+	for id, entry := range o.exchangeBuffers {
+		r = append(r, ExchangeBufferStatus{
+			ID:         id,
+			Creator:    entry.creator,
+			References: entry.references,
+			Type:       fmt.Sprintf("%T", entry.buffer),
+		})
+	}
+
+	sort.Slice(r, func(i, j int) bool { return r[i].ID < r[j].ID })
+
+	return r
+}
+
+/*
+	Exchange buffer handles of function execution contexts
+
+	Functions never see the orchestrator buffer ids. Each execution context has its own table of
+	handles to the buffers it was given (its input and output buffers, the buffers passed by its
+	caller) or it created, each handle holding a reference on its buffer. Handles are numbered from
+	1 and are only valid for the function owning them : any other number is refused with
+	ErrExchangeBufferAccessDenied, and a buffer is given to a called function by passing its handle,
+	which is then translated to a handle of the called function.
+*/
+
+var ErrExchangeBufferAccessDenied = errors.New("exchange buffer access denied")
+
+func (fctx *FunctionExecutionContext) holdExchangeBuffer(bufferID int) int {
+	fctx.buffersLock.Lock()
+	defer fctx.buffersLock.Unlock()
+
+	if fctx.exchangeBufferHandles == nil {
+		fctx.exchangeBufferHandles = make(map[int]int)
+	}
+
+	fctx.nextExchangeBufferHandle++
+	handle := fctx.nextExchangeBufferHandle
+	fctx.exchangeBufferHandles[handle] = bufferID
+
+	return handle
+}
+
+func (fctx *FunctionExecutionContext) unholdExchangeBuffer(handle int) (int, bool) {
+	fctx.buffersLock.Lock()
+	defer fctx.buffersLock.Unlock()
+
+	bufferID, ok := fctx.exchangeBufferHandles[handle]
+	if !ok {
+		return -1, false
+	}
+
+	delete(fctx.exchangeBufferHandles, handle)
+
+	return bufferID, true
+}
+
+// ExchangeBufferID returns the orchestrator id of the buffer of a handle held by the function
+func (fctx *FunctionExecutionContext) ExchangeBufferID(handle int) (int, error) {
+	fctx.buffersLock.Lock()
+	defer fctx.buffersLock.Unlock()
+
+	bufferID, ok := fctx.exchangeBufferHandles[handle]
+	if !ok {
+		return -1, fmt.Errorf("%w (handle %d in function '%s')", ErrExchangeBufferAccessDenied, handle, fctx.Name)
+	}
+
+	return bufferID, nil
+}
+
+// GetExchangeBuffer returns the buffer of a handle held by the function
+func (fctx *FunctionExecutionContext) GetExchangeBuffer(handle int) (ExchangeBuffer, error) {
+	bufferID, err := fctx.ExchangeBufferID(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeBuffer := fctx.Orchestrator.GetExchangeBuffer(bufferID)
+	if exchangeBuffer == nil {
+		return nil, fmt.Errorf("exchange buffer of handle %d has been freed", handle)
+	}
+
+	return exchangeBuffer, nil
+}
+
+// CreateExchangeBuffer creates an in memory exchange buffer owned by the function, returns its handle.
+// It is not a streaming one : functions read back what they wrote, and fill the input of the
+// functions they call before calling them, without closing it.
+func (fctx *FunctionExecutionContext) CreateExchangeBuffer() int {
+	bufferID := fctx.Orchestrator.registerExchangeBuffer(NewMemoryExchangeBuffer(), fctx.Name)
+	return fctx.holdExchangeBuffer(bufferID)
+}
+
+// CreateStreamingExchangeBuffer creates a streaming in memory exchange buffer owned by the function,
+// returns its handle. Its reader waits for the writer, which waits when too much data is not read
+// yet : the function gives it to a called function as output while the host forwards it, see
+// ForwardExchangeBuffer, and the two run at the same time.
+func (fctx *FunctionExecutionContext) CreateStreamingExchangeBuffer() int {
+	bufferID := fctx.Orchestrator.registerExchangeBuffer(NewStreamingMemoryExchangeBuffer(), fctx.Name)
+	return fctx.holdExchangeBuffer(bufferID)
+}
+
+// AdoptExchangeBuffer gives the reference obtained when registering a buffer to the function,
+// returns the handle of the buffer
+func (fctx *FunctionExecutionContext) AdoptExchangeBuffer(bufferID int) int {
+	fctx.Orchestrator.lock.Lock()
+	if entry, ok := fctx.Orchestrator.exchangeBuffers[bufferID]; ok {
+		entry.creator = fctx.Name
+	}
+	fctx.Orchestrator.lock.Unlock()
+
+	return fctx.holdExchangeBuffer(bufferID)
+}
+
+// AcquireExchangeBuffer adds a reference to the buffer, held by the function with the returned handle
+// until it is released. It returns false if the buffer does not exist
+func (fctx *FunctionExecutionContext) AcquireExchangeBuffer(bufferID int) (int, bool) {
+	if !fctx.Orchestrator.AcquireExchangeBuffer(bufferID) {
+		return -1, false
+	}
+
+	return fctx.holdExchangeBuffer(bufferID), true
+}
+
+// ReleaseExchangeBuffer releases the reference of a handle held by the function, returns false if it holds none
+func (fctx *FunctionExecutionContext) ReleaseExchangeBuffer(handle int) bool {
+	bufferID, ok := fctx.unholdExchangeBuffer(handle)
+	if !ok {
+		return false
+	}
+
+	fctx.Orchestrator.ReleaseExchangeBuffer(bufferID)
+
+	return true
+}
+
+/*
+	Hand-off to the host
+
+	ForwardExchangeBuffer gives the rest of the work on a buffer to the host : it copies the parts
+	of a source buffer to a target one in the background, holding its own reference on the target,
+	and closes the target once the source is finished. The function can then exit while the host
+	keeps writing to the target, typically its output : the http response is complete when the copy
+	ends. The source is read until its writer closes it, or until its last reference is released.
+	Regular in memory buffers are filled before being read, so only their current content is copied.
+*/
+func (fctx *FunctionExecutionContext) ForwardExchangeBuffer(sourceHandle int, targetHandle int) error {
+	sourceID, err := fctx.ExchangeBufferID(sourceHandle)
+	if err != nil {
+		return err
+	}
+
+	targetID, err := fctx.ExchangeBufferID(targetHandle)
+	if err != nil {
+		return err
+	}
+
+	if sourceID == targetID {
+		return fmt.Errorf("cannot forward exchange buffer of handle %d to itself", sourceHandle)
+	}
+
+	source := fctx.Orchestrator.GetExchangeBuffer(sourceID)
+	if source == nil || !fctx.Orchestrator.AcquireExchangeBuffer(targetID) {
+		return fmt.Errorf("exchange buffers of handles %d and %d have been freed", sourceHandle, targetHandle)
+	}
+
+	go fctx.Orchestrator.forwardExchangeBuffer(source, targetID)
+
+	return nil
+}
+
+func (o *Orchestrator) forwardExchangeBuffer(source ExchangeBuffer, targetID int) {
+	defer o.ReleaseExchangeBuffer(targetID)
+
+	target := o.GetExchangeBuffer(targetID)
+
+	for {
+		part, err := source.ReadPart()
+		if err != nil {
+			if err != io.EOF {
+				fmt.Printf("[error] cannot read the forwarded exchange buffer (%v)\n", err)
+			}
+			break
+		}
+
+		_, err = target.Write(part)
+		if err != nil {
+			fmt.Printf("[error] cannot forward to exchange buffer %d (%v)\n", targetID, err)
+			break
+		}
+	}
+
+	target.Close()
+}
+
+// HoldInputOutputExchangeBuffers gives the function handles on its input and output buffers
+func (fctx *FunctionExecutionContext) HoldInputOutputExchangeBuffers() {
+	fctx.InputExchangeBufferHandle, _ = fctx.AcquireExchangeBuffer(fctx.InputExchangeBufferID)
+	fctx.OutputExchangeBufferHandle, _ = fctx.AcquireExchangeBuffer(fctx.OutputExchangeBufferID)
+}
+
+// ReleaseHeldExchangeBuffers releases the references of all the handles held by the function
+func (fctx *FunctionExecutionContext) ReleaseHeldExchangeBuffers() {
+	fctx.buffersLock.Lock()
+	held := fctx.exchangeBufferHandles
+	fctx.exchangeBufferHandles = nil
+	fctx.buffersLock.Unlock()
+
+	for _, bufferID := range held {
+		fctx.Orchestrator.ReleaseExchangeBuffer(bufferID)
+	}
 }
@@ -0,0 +1,352 @@
+package common
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newTestOrchestrator(t *testing.T) *Orchestrator {
+	o, err := NewOrchestrator(newTestDB(t), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func TestExchangeBufferClosedOnLastRelease(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	b := NewMemoryExchangeBuffer()
+	bufferID := o.RegisterExchangeBuffer(b)
+	if !o.AcquireExchangeBuffer(bufferID) {
+		t.Fatal("cannot acquire the buffer")
+	}
+	released := o.WaitExchangeBufferRelease(bufferID)
+
+	o.ReleaseExchangeBuffer(bufferID)
+	if b.IsWriteFinished() {
+		t.Fatal("buffer closed while still referenced")
+	}
+
+	o.ReleaseExchangeBuffer(bufferID)
+	if !b.IsWriteFinished() {
+		t.Fatal("buffer not closed after its last release")
+	}
+
+	select {
+	case <-released:
+	default:
+		t.Fatal("release not notified")
+	}
+
+	if o.GetExchangeBuffer(bufferID) != nil {
+		t.Fatal("released buffer still registered")
+	}
+}
+
+func TestMemoryExchangeBufferGetBuffer(t *testing.T) {
+	b := NewMemoryExchangeBuffer()
+	b.Write([]byte("hello "))
+
+	part, err := b.ReadPart()
+	if err != nil || string(part) != "hello " {
+		t.Fatalf("unexpected part '%s' (%v)", string(part), err)
+	}
+
+	b.Write([]byte("world"))
+	b.Close()
+
+	// only the content not yet consumed, as many times as needed
+	for i := 0; i < 2; i++ {
+		if content := string(b.GetBuffer()); content != "world" {
+			t.Fatalf("unexpected content '%s'", content)
+		}
+	}
+}
+
+func TestStreamingMemoryExchangeBufferBackpressure(t *testing.T) {
+	b := NewStreamingMemoryExchangeBuffer()
+
+	var written int64
+	go func() {
+		part := make([]byte, exchangeBufferPartSize)
+		for i := 0; i < 8; i++ {
+			n, err := b.Write(part)
+			if err != nil {
+				break
+			}
+			atomic.AddInt64(&written, int64(n))
+		}
+		b.Close()
+	}()
+
+	// the writer stops once too much data is pending
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt64(&written); n != streamingMaxPendingBytes {
+		t.Fatalf("writer not stopped, %d bytes written", n)
+	}
+
+	// reading lets it go on
+	read := 0
+	for {
+		part, err := b.ReadPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		read += len(part)
+	}
+
+	if read != 8*exchangeBufferPartSize {
+		t.Fatalf("read %d bytes", read)
+	}
+}
+
+func TestStreamingMemoryExchangeBufferGetBufferDrains(t *testing.T) {
+	b := NewStreamingMemoryExchangeBuffer()
+
+	go func() {
+		part := make([]byte, exchangeBufferPartSize)
+		for i := 0; i < 8; i++ {
+			b.Write(part)
+		}
+		b.Close()
+	}()
+
+	// GetBuffer does not wait for a writer waiting for it
+	if n := len(b.GetBuffer()); n != 8*exchangeBufferPartSize {
+		t.Fatalf("got %d bytes", n)
+	}
+}
+
+func TestPipeExchangeBufferZeroSize(t *testing.T) {
+	p := NewPipeExchangeBuffer(0)
+
+	done := make(chan struct{})
+	go func() {
+		p.Write([]byte("a"))
+		p.Write([]byte("b"))
+		p.Close()
+		close(done)
+	}()
+
+	if content := string(p.GetBuffer()); content != "ab" {
+		t.Fatalf("unexpected content '%s'", content)
+	}
+	<-done
+}
+
+func TestForwardedOutputOutlivesTheFunction(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	output := NewMemoryExchangeBuffer()
+	outputID := o.RegisterExchangeBuffer(output)
+	outputReleased := o.WaitExchangeBufferRelease(outputID)
+
+	// the host writes a stream, the function called with it hands its output off and exits
+	source := NewStreamingMemoryExchangeBuffer()
+	sourceID := o.RegisterExchangeBuffer(source)
+
+	callee := &FunctionExecutionContext{Orchestrator: o, Name: "callee", InputExchangeBufferID: sourceID, OutputExchangeBufferID: outputID}
+	callee.HoldInputOutputExchangeBuffers()
+	err := callee.ForwardExchangeBuffer(callee.InputExchangeBufferHandle, callee.OutputExchangeBufferHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callee.ReleaseHeldExchangeBuffers()
+	o.ReleaseExchangeBuffer(outputID)
+
+	source.Write([]byte("hello "))
+	source.Write([]byte("world"))
+
+	select {
+	case <-outputReleased:
+		t.Fatal("output released while the host still writes it")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	o.ReleaseExchangeBuffer(sourceID)
+
+	select {
+	case <-outputReleased:
+	case <-time.After(5 * time.Second):
+		t.Fatal("output not released once the source is finished")
+	}
+
+	if !output.IsWriteFinished() || string(output.GetBuffer()) != "hello world" {
+		t.Fatalf("unexpected output '%s'", string(output.GetBuffer()))
+	}
+
+	if err := callee.ForwardExchangeBuffer(1, 2); !errors.Is(err, ErrExchangeBufferAccessDenied) {
+		t.Fatalf("forwarded a released handle (%v)", err)
+	}
+}
+
+func TestWebSocketExchangeBufferGetBuffer(t *testing.T) {
+	parts := make(chan []string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+
+		b := WrapWebSocketAsExchangeBuffer(nil, c)
+
+		// the message is kept until it is consumed
+		read := []string{string(b.GetBuffer()), string(b.GetBuffer())}
+		for {
+			part, err := b.ReadPart()
+			if err != nil {
+				break
+			}
+			read = append(read, string(part))
+		}
+		parts <- read
+	}))
+	defer server.Close()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.WriteMessage(websocket.TextMessage, []byte("first"))
+	c.WriteMessage(websocket.TextMessage, []byte("second"))
+	c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	defer c.Close()
+
+	read := <-parts
+	if strings.Join(read, ",") != "first,first,first,second" {
+		t.Fatalf("unexpected messages %v", read)
+	}
+}
+
+func TestStreamingExchangeBufferPipeline(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	output := NewMemoryExchangeBuffer()
+	outputID := o.RegisterExchangeBuffer(output)
+	outputReleased := o.WaitExchangeBufferRelease(outputID)
+
+	caller := &FunctionExecutionContext{Orchestrator: o, Name: "caller", OutputExchangeBufferID: outputID}
+	caller.HoldInputOutputExchangeBuffers()
+	o.ReleaseExchangeBuffer(outputID)
+
+	stream := caller.CreateStreamingExchangeBuffer()
+	err := caller.ForwardExchangeBuffer(stream, caller.OutputExchangeBufferHandle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the called function writes more than a streaming buffer keeps, the host forwards it meanwhile
+	streamID, _ := caller.ExchangeBufferID(stream)
+	callee := &FunctionExecutionContext{Orchestrator: o, Name: "callee", OutputExchangeBufferID: streamID}
+	callee.HoldInputOutputExchangeBuffers()
+
+	written := make(chan struct{})
+	go func() {
+		b, _ := callee.GetExchangeBuffer(callee.OutputExchangeBufferHandle)
+		part := make([]byte, exchangeBufferPartSize)
+		for i := 0; i < 8; i++ {
+			b.Write(part)
+		}
+		callee.ReleaseHeldExchangeBuffers()
+		close(written)
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the called function is blocked by its output")
+	}
+
+	caller.ReleaseHeldExchangeBuffers()
+
+	select {
+	case <-outputReleased:
+	case <-time.After(5 * time.Second):
+		t.Fatal("output not released")
+	}
+
+	if n := len(output.GetBuffer()); n != 8*exchangeBufferPartSize {
+		t.Fatalf("%d bytes forwarded", n)
+	}
+}
+
+func TestExchangeBufferHandleAccess(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	// two tenants, the first one with more buffers than the second one
+	tenantA := &FunctionExecutionContext{Orchestrator: o, Name: "tenant-a/api.wasm"}
+	tenantB := &FunctionExecutionContext{Orchestrator: o, Name: "tenant-b/api.wasm"}
+	secretHandle := 0
+	for i := 0; i < 3; i++ {
+		secretHandle = tenantA.CreateExchangeBuffer()
+	}
+	secret, _ := tenantA.GetExchangeBuffer(secretHandle)
+	secret.Write([]byte("secret of a"))
+	secretID, _ := tenantA.ExchangeBufferID(secretHandle)
+
+	ownHandle := tenantB.CreateExchangeBuffer()
+	ownID, _ := tenantB.ExchangeBufferID(ownHandle)
+	released := tenantB.CreateExchangeBuffer()
+	tenantB.ReleaseExchangeBuffer(released)
+
+	tests := []struct {
+		name    string
+		handle  int
+		allowed bool
+		id      int
+	}{
+		{"own handle", ownHandle, true, ownID},
+		{"handle of the other tenant", secretHandle, false, -1},
+		{"orchestrator id of the other tenant's buffer", secretID, false, -1},
+		{"released handle", released, false, -1},
+		{"zero", 0, false, -1},
+		{"negative", -1, false, -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := tenantB.ExchangeBufferID(test.handle)
+			if test.allowed {
+				if err != nil || id != test.id {
+					t.Fatalf("handle %d gives the id %d (%v), expected %d", test.handle, id, err, test.id)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrExchangeBufferAccessDenied) || id != -1 {
+				t.Fatalf("handle %d gives the id %d (%v), expected an access denied error", test.handle, id, err)
+			}
+			if _, err := tenantB.GetExchangeBuffer(test.handle); !errors.Is(err, ErrExchangeBufferAccessDenied) {
+				t.Fatalf("handle %d gives a buffer (%v)", test.handle, err)
+			}
+			if tenantB.ReleaseExchangeBuffer(test.handle) {
+				t.Fatalf("handle %d was released", test.handle)
+			}
+		})
+	}
+
+	// guessing every handle number of the other tenant never reaches its buffers
+	for handle := -2; handle <= secretID+10; handle++ {
+		buffer, err := tenantB.GetExchangeBuffer(handle)
+		if err == nil && buffer == secret {
+			t.Fatalf("handle %d of the second tenant gives the buffer of the first one", handle)
+		}
+	}
+	if string(secret.GetBuffer()) != "secret of a" {
+		t.Fatalf("the buffer of the first tenant is %q", secret.GetBuffer())
+	}
+}
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+	Execution exit
+
+	Every execution ends with an ExecutionExit telling how the function stopped : it returned, it
+	called proc_exit (posix programs), it trapped (unreachable, out of bounds memory access, stack
+	overflow, javascript exception...) or a host function failed. The code is the returned value or
+	the exit code, it is also the Result of the execution context.
+
+	Host functions record the exit when they stop the program, the engine records it otherwise. The
+	first recorded exit is kept : with engines unable to stop a program from a host function, the
+	trap following a proc_exit is not the reason of the exit.
+
+	Traps and host errors make FunctionExecutionContext.Run fail with an ExecutionError.
+*/
+
+const (
+	EXIT_RETURNED   = "returned"
+	EXIT_EXITED     = "exited"
+	EXIT_TRAPPED    = "trapped"
+	EXIT_HOST_ERROR = "host_error"
+)
+
+const (
+	TRAP_UNREACHABLE                 = "unreachable"
+	TRAP_OUT_OF_BOUNDS_MEMORY_ACCESS = "out_of_bounds_memory_access"
+	TRAP_STACK_OVERFLOW              = "stack_overflow"
+	TRAP_DIVISION_BY_ZERO            = "division_by_zero"
+	TRAP_INTEGER_OVERFLOW            = "integer_overflow"
+	TRAP_INVALID_CONVERSION          = "invalid_conversion_to_integer"
+	TRAP_INDIRECT_CALL_MISMATCH      = "indirect_call_type_mismatch"
+	TRAP_UNDEFINED_ELEMENT           = "undefined_element"
+	TRAP_ABORT                       = "abort"
+	TRAP_EXCEPTION                   = "exception"
+	TRAP_UNKNOWN                     = "unknown"
+)
+
+var ErrExecutionTrapped = errors.New("execution trapped")
+var ErrHostFunctionFailed = errors.New("host function failed")
+
+type ExecutionExit struct {
+	Kind      string         `json:"kind"`
+	Code      int            `json:"code"`
+	Trap      string         `json:"trap,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	Backtrace []string       `json:"backtrace,omitempty"`
+	Cause     *ExecutionExit `json:"cause,omitempty"` // exit of the called function which made the host function fail
+
+	// error of the failed host function
+	err error
+}
+
+func ReturnedExit(code int) *ExecutionExit {
+	return &ExecutionExit{Kind: EXIT_RETURNED, Code: code}
+}
+
+func ProcExit(code int) *ExecutionExit {
+	return &ExecutionExit{Kind: EXIT_EXITED, Code: code}
+}
+
+func TrapExit(trap string, message string, backtrace []string) *ExecutionExit {
+	return &ExecutionExit{Kind: EXIT_TRAPPED, Code: -1, Trap: trap, Message: message, Backtrace: backtrace}
+}
+
+func HostErrorExit(err error) *ExecutionExit {
+	exit := &ExecutionExit{Kind: EXIT_HOST_ERROR, Code: -1, Message: err.Error(), err: err}
+
+	var executionError *ExecutionError
+	if errors.As(err, &executionError) {
+		exit.Cause = executionError.Exit
+	}
+
+	return exit
+}
+
+// Failed tells if the execution trapped or if a host function failed
+func (exit *ExecutionExit) Failed() bool {
+	return exit.Kind == EXIT_TRAPPED || exit.Kind == EXIT_HOST_ERROR
+}
+
+func (exit *ExecutionExit) String() string {
+	switch exit.Kind {
+	case EXIT_RETURNED:
+		return fmt.Sprintf("returned %d", exit.Code)
+	case EXIT_EXITED:
+		return fmt.Sprintf("exited with code %d", exit.Code)
+	case EXIT_TRAPPED:
+		return fmt.Sprintf("trapped (%s: %s)", exit.Trap, exit.Message)
+	default:
+		return fmt.Sprintf("host function failed (%s)", exit.Message)
+	}
+}
+
+// ExecutionError is the error of an execution which trapped or which host function failed
+type ExecutionError struct {
+	Name string
+	Exit *ExecutionExit
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("function '%s' %s", e.Name, e.Exit)
+}
+
+func (e *ExecutionError) Unwrap() error {
+	if e.Exit.Kind == EXIT_TRAPPED {
+		return ErrExecutionTrapped
+	}
+
+	return ErrHostFunctionFailed
+}
+
+// Is tells if the host function failed with the target error, like a called function exceeding its limits
+func (e *ExecutionError) Is(target error) bool {
+	return e.Exit.err != nil && errors.Is(e.Exit.err, target)
+}
+
+// SetExit records how the execution stops, the first recorded exit is kept
+func (fctx *FunctionExecutionContext) SetExit(exit *ExecutionExit) {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	if fctx.Exit == nil {
+		fctx.Exit = exit
+	}
+}
+
+// FinishRunning ends the execution with exit unless an exit has already been recorded or a limit
+// has been exceeded, the result becomes the code of the kept exit
+func (fctx *FunctionExecutionContext) FinishRunning(exit *ExecutionExit) {
+	fctx.limitsLock.Lock()
+	if fctx.Exit == nil && fctx.limitError != nil {
+		fctx.Exit = HostErrorExit(fctx.limitError)
+	}
+	if fctx.Exit == nil {
+		fctx.Exit = exit
+	}
+	fctx.Result = fctx.Exit.Code
+	fctx.limitsLock.Unlock()
+
+	fctx.HasFinishedRunning = true
+}
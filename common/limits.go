@@ -0,0 +1,262 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+/*
+	Execution resource limits
+
+	Limits are given in the tags of a plugged function, the ones it does not give are the default
+	limits of the cluster (see SetDefaultLimits), which also apply to the filters :
+
+	- "timeout" : wall-clock duration of the execution, like "500ms" or "10s",
+	- "max_memory" : maximum linear memory size of a wasm function, or heap size of a javascript one, in bytes,
+	- "fuel" : maximum number of metering points of the execution.
+
+	Wasm modules are instrumented with metering points (see enginewasm/metering.go) : each function
+	call and each loop iteration consumes one unit of fuel, and the limits are checked every
+	meteringSlice points, so a wasm function is stopped even when it never calls the host api.
+	Javascript functions consume one unit of fuel per host api call. Their heap counts its
+	allocations, "max_memory" is the size of the whole heap, and duktape throws an uncatchable
+	error from its execution timeout check once the deadline or the memory limit is reached.
+
+	Host api calls also check the limits. When the timeout is reached, the caller gets the error
+	immediately and the function is stopped at its next check.
+*/
+type ExecutionLimits struct {
+	Timeout   time.Duration `json:"timeout,omitempty"`
+	MaxMemory int           `json:"max_memory,omitempty"`
+	Fuel      int64         `json:"fuel,omitempty"`
+}
+
+var ErrExecutionTimeout = errors.New("execution timeout")
+var ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+var ErrFuelExhausted = errors.New("fuel exhausted")
+
+// IsExecutionLimitError tells if the error comes from a function exceeding its limits
+func IsExecutionLimitError(err error) bool {
+	return errors.Is(err, ErrExecutionTimeout) || errors.Is(err, ErrMemoryLimitExceeded) || errors.Is(err, ErrFuelExhausted)
+}
+
+// ParseExecutionLimits reads the limits from plug tags, unknown tags are ignored
+func ParseExecutionLimits(tags map[string]string) (ExecutionLimits, error) {
+	return parseExecutionLimits(ExecutionLimits{}, tags)
+}
+
+// parseExecutionLimits overrides the limits with the ones given in the tags, "0" being unlimited
+func parseExecutionLimits(limits ExecutionLimits, tags map[string]string) (ExecutionLimits, error) {
+
+	if value, ok := tags["timeout"]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return limits, fmt.Errorf("invalid timeout '%s'", value)
+		}
+		limits.Timeout = timeout
+	}
+
+	if value, ok := tags["max_memory"]; ok {
+		maxMemory, err := strconv.Atoi(value)
+		if err != nil || maxMemory < 0 {
+			return limits, fmt.Errorf("invalid max_memory '%s'", value)
+		}
+		limits.MaxMemory = maxMemory
+	}
+
+	if value, ok := tags["fuel"]; ok {
+		fuel, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || fuel < 0 {
+			return limits, fmt.Errorf("invalid fuel '%s'", value)
+		}
+		limits.Fuel = fuel
+	}
+
+	return limits, nil
+}
+
+// SetDefaultLimits sets the limits of the functions and filters whose plug does not give them
+func (o *Orchestrator) SetDefaultLimits(limits ExecutionLimits) {
+	o.defaultLimits = limits
+}
+
+// DefaultLimits returns the limits of the functions and filters whose plug does not give them
+func (o *Orchestrator) DefaultLimits() ExecutionLimits {
+	return o.defaultLimits
+}
+
+// SetLimits applies limits to the execution, must be called before Run
+func (fctx *FunctionExecutionContext) SetLimits(limits ExecutionLimits) {
+	fctx.Limits = limits
+}
+
+// number of metering points a wasm function runs between two checks of its limits
+const meteringSlice = 10000
+
+// HasLimits tells if the execution has limits to enforce
+func (fctx *FunctionExecutionContext) HasLimits() bool {
+	return fctx.Limits != ExecutionLimits{} || !fctx.Deadline.IsZero()
+}
+
+// CalledFunctionLimits gives the limits of a function called by this execution : its limits with the
+// fuel it has left, the points granted to a wasm function being counted as consumed
+func (fctx *FunctionExecutionContext) CalledFunctionLimits() (ExecutionLimits, error) {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	limits := fctx.Limits
+	if limits.Fuel > 0 {
+		limits.Fuel -= fctx.fuelConsumed + int64(fctx.meteringGranted)
+		if limits.Fuel <= 0 {
+			return limits, ErrFuelExhausted
+		}
+	}
+
+	return limits, nil
+}
+
+// ChargeCalledFunction counts the fuel consumed by a called function as consumed by this execution
+func (fctx *FunctionExecutionContext) ChargeCalledFunction(called *FunctionExecutionContext) {
+	called.limitsLock.Lock()
+	consumed := called.fuelConsumed
+	called.limitsLock.Unlock()
+
+	fctx.limitsLock.Lock()
+	fctx.fuelConsumed += consumed
+	fctx.limitsLock.Unlock()
+}
+
+// CheckLimits is called by the engines at each host api call, an error means the execution must be aborted
+func (fctx *FunctionExecutionContext) CheckLimits() error {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	if fctx.limitError != nil {
+		return fctx.limitError
+	}
+
+	if !fctx.Deadline.IsZero() && time.Now().After(fctx.Deadline) {
+		fctx.limitError = ErrExecutionTimeout
+		return fctx.limitError
+	}
+
+	fctx.fuelConsumed++
+	if fctx.Limits.Fuel > 0 && fctx.fuelConsumed > fctx.Limits.Fuel {
+		fctx.limitError = ErrFuelExhausted
+		return fctx.limitError
+	}
+
+	return nil
+}
+
+// Meter is called by the instrumented wasm functions when they ran all the metering points they were
+// given. It counts them as fuel and returns the number of points to run before the next call
+func (fctx *FunctionExecutionContext) Meter() (int, error) {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	if fctx.limitError != nil {
+		return 0, fctx.limitError
+	}
+
+	fctx.fuelConsumed += int64(fctx.meteringGranted)
+	fctx.meteringGranted = 0
+
+	if !fctx.Deadline.IsZero() && time.Now().After(fctx.Deadline) {
+		fctx.limitError = ErrExecutionTimeout
+		return 0, fctx.limitError
+	}
+
+	granted := meteringSlice
+	if fctx.Limits.Fuel > 0 {
+		remaining := fctx.Limits.Fuel - fctx.fuelConsumed
+		if remaining <= 0 {
+			fctx.limitError = ErrFuelExhausted
+			return 0, fctx.limitError
+		}
+		if remaining < int64(granted) {
+			granted = int(remaining)
+		}
+	}
+
+	fctx.meteringGranted = granted
+
+	return granted, nil
+}
+
+// CheckMemory is called by the engines with the current memory size of the function
+func (fctx *FunctionExecutionContext) CheckMemory(size int) error {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	if fctx.limitError != nil {
+		return fctx.limitError
+	}
+
+	if fctx.Limits.MaxMemory > 0 && size > fctx.Limits.MaxMemory {
+		fctx.limitError = ErrMemoryLimitExceeded
+		return fctx.limitError
+	}
+
+	return nil
+}
+
+// LimitError returns the limit which aborted the execution, if any
+func (fctx *FunctionExecutionContext) LimitError() error {
+	fctx.limitsLock.Lock()
+	defer fctx.limitsLock.Unlock()
+
+	return fctx.limitError
+}
+
+func (fctx *FunctionExecutionContext) abort(err error) {
+	fctx.limitsLock.Lock()
+	if fctx.limitError == nil {
+		fctx.limitError = err
+	}
+	fctx.limitsLock.Unlock()
+}
+
+// runEngine runs the engine context within the deadline. When the deadline is reached, it returns
+// with ErrExecutionTimeout and detached is true : the held exchange buffers and domains are then
+// released when the engine returns, at its next check of the limits.
+func (fctx *FunctionExecutionContext) runEngine(ectx ExecutionEngineContext) (detached bool, err error) {
+	if fctx.Limits.Timeout > 0 {
+		deadline := time.Now().Add(fctx.Limits.Timeout)
+		if fctx.Deadline.IsZero() || deadline.Before(fctx.Deadline) {
+			fctx.Deadline = deadline
+		}
+	}
+
+	if fctx.Deadline.IsZero() {
+		return false, ectx.Run()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ectx.Run()
+	}()
+
+	timer := time.NewTimer(time.Until(fctx.Deadline))
+	defer timer.Stop()
+
+	select {
+	case err = <-done:
+		return false, err
+
+	case <-timer.C:
+		fctx.abort(ErrExecutionTimeout)
+		fctx.Orchestrator.StatIncrement(STAT_NB_DETACHED_EXECUTIONS)
+
+		go func() {
+			<-done
+			fctx.ReleaseHeldExchangeBuffers()
+			fctx.releaseHeldDomains()
+			fmt.Printf("detached function '%s' finally stopped\n", fctx.Name)
+		}()
+
+		return true, ErrExecutionTimeout
+	}
+}
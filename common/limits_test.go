@@ -0,0 +1,61 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDefaultLimits(t *testing.T) {
+	o := newTestOrchestrator(t)
+	o.SetDefaultLimits(ExecutionLimits{Timeout: 10 * time.Second, Fuel: 1000})
+
+	// filters and functions without limits in their plug run with the defaults
+	fctx := o.NewFunctionExecutionContext("filter.wasm", "filter", []int{}, false, "direct", nil, nil, 0, 0)
+	if fctx.Limits != o.DefaultLimits() {
+		t.Fatalf("the execution has the limits %+v", fctx.Limits)
+	}
+
+	plug := &PluggedFunction{Tags: map[string]string{"timeout": "0", "max_memory": "65536"}}
+	limits, err := plug.GetExecutionLimits(o.DefaultLimits())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.Timeout != 0 || limits.MaxMemory != 65536 || limits.Fuel != 1000 {
+		t.Fatalf("the plug limits are %+v", limits)
+	}
+}
+
+func TestCalledFunctionSharesFuel(t *testing.T) {
+	caller := &FunctionExecutionContext{Limits: ExecutionLimits{Fuel: 100}}
+	for i := 0; i < 30; i++ {
+		caller.CheckLimits()
+	}
+
+	limits, err := caller.CalledFunctionLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.Fuel != 70 {
+		t.Fatalf("the called function gets %d fuel, expected 70", limits.Fuel)
+	}
+
+	called := &FunctionExecutionContext{Limits: limits}
+	for called.CheckLimits() == nil {
+	}
+	caller.ChargeCalledFunction(called)
+
+	if _, err := caller.CalledFunctionLimits(); !errors.Is(err, ErrFuelExhausted) {
+		t.Fatalf("the caller calls another function after the fuel is exhausted (%v)", err)
+	}
+	if err := caller.CheckLimits(); !errors.Is(err, ErrFuelExhausted) {
+		t.Fatalf("the caller runs after the fuel is exhausted (%v)", err)
+	}
+
+	// the caller fails with the limit error of the called function
+	err = &ExecutionError{"caller", HostErrorExit(fmt.Errorf("called function failed: %w", called.LimitError()))}
+	if !IsExecutionLimitError(err) || !errors.Is(err, ErrHostFunctionFailed) {
+		t.Fatalf("the limit error of the called function is lost (%v)", err)
+	}
+}
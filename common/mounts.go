@@ -0,0 +1,114 @@
+package common
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+/*
+	Mounts
+
+	A posix function sees an in-memory working directory, lost at the end of the execution, over
+	which directories can be mounted. Mounts are given on the call or with the "mounts" tag of a
+	plug, as a comma separated list of "PATH=TYPE[:SOURCE]" :
+
+	- "/app=blobs:myapp/" : read-only directory of the blobs which names start with "myapp/", the
+	  blob "myapp/static/index.html" is the file "/app/static/index.html",
+	- "/data=persistence:/myapp/files" : writable directory stored in the persistence keyspace, the
+	  file "/data/db.sqlite" is the persistence key "/myapp/files/db.sqlite",
+	- "/tmp=scratch" : an other writable in-memory directory.
+
+	Mounts cannot be nested.
+*/
+
+const (
+	MOUNT_BLOBS       = "blobs"
+	MOUNT_PERSISTENCE = "persistence"
+	MOUNT_SCRATCH     = "scratch"
+)
+
+type Mount struct {
+	Path   string `json:"path"`
+	Type   string `json:"type"`
+	Source string `json:"source,omitempty"`
+}
+
+// ParseMounts reads a mount specification, mounts are returned in their path order
+func ParseMounts(spec string) ([]Mount, error) {
+	mounts := []Mount{}
+	paths := make(map[string]bool)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		equal := strings.Index(entry, "=")
+		if equal < 0 {
+			return nil, fmt.Errorf("invalid mount '%s', should be PATH=TYPE[:SOURCE]", entry)
+		}
+
+		mount := Mount{
+			Path: entry[:equal],
+			Type: entry[equal+1:],
+		}
+		if colon := strings.Index(mount.Type, ":"); colon >= 0 {
+			mount.Source = mount.Type[colon+1:]
+			mount.Type = mount.Type[:colon]
+		}
+
+		if !strings.HasPrefix(mount.Path, "/") {
+			return nil, fmt.Errorf("invalid mount path '%s', should be absolute", mount.Path)
+		}
+		mount.Path = path.Clean(mount.Path)
+		if mount.Path == "/" {
+			return nil, fmt.Errorf("cannot mount on '/'")
+		}
+		if paths[mount.Path] {
+			return nil, fmt.Errorf("'%s' is mounted twice", mount.Path)
+		}
+		paths[mount.Path] = true
+
+		switch mount.Type {
+		case MOUNT_BLOBS, MOUNT_PERSISTENCE:
+			if mount.Source == "" {
+				return nil, fmt.Errorf("missing source for the %s mount '%s'", mount.Type, mount.Path)
+			}
+		case MOUNT_SCRATCH:
+			if mount.Source != "" {
+				return nil, fmt.Errorf("a scratch mount has no source ('%s')", mount.Path)
+			}
+		default:
+			return nil, fmt.Errorf("unknown mount type '%s' for '%s'", mount.Type, mount.Path)
+		}
+
+		mounts = append(mounts, mount)
+	}
+
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Path < mounts[j].Path })
+
+	for i := range mounts {
+		for j := range mounts {
+			if strings.HasPrefix(mounts[j].Path, mounts[i].Path+"/") {
+				return nil, fmt.Errorf("'%s' cannot be mounted inside '%s'", mounts[j].Path, mounts[i].Path)
+			}
+		}
+	}
+
+	return mounts, nil
+}
+
+// GetBlobNamesWithPrefix returns the techIDs of the blobs which names start with prefix, by name
+func (o *Orchestrator) GetBlobNamesWithPrefix(prefix string) map[string]string {
+	r := make(map[string]string)
+	for _, blob := range o.GetBlobsByName() {
+		if strings.HasPrefix(blob.Name, prefix) {
+			r[blob.Name] = blob.TechID
+		}
+	}
+
+	return r
+}
@@ -1,14 +1,15 @@
 package common
 
 import (
+	"crypto/cipher"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
@@ -28,13 +29,14 @@ type APIProvider interface {
 
 type Orchestrator struct {
 	nextExchangeBufferID int32
-	exchangeBuffers      map[int]ExchangeBuffer
+	exchangeBuffers      map[int]*exchangeBufferEntry
 
 	lock sync.Mutex
 
 	db *leveldb.DB
 
-	executionEngines map[string]ExecutionEngine
+	// by content type, in fallback order
+	executionEngines map[string][]*registeredEngine
 	apiProviders     map[string]APIProvider
 
 	trace bool
@@ -43,24 +45,57 @@ type Orchestrator struct {
 	statsLock sync.Mutex
 
 	plugs *PlugSystem
+
+	egress     *egressPolicy
+	egressLock sync.RWMutex
+
+	// serializes the persistence commits, persistenceSequence is loaded by the first one
+	persistenceLock      sync.Mutex
+	persistenceSequence  int64
+	persistenceCommitted chan struct{} // closed and replaced at each commit
+
+	// encrypts the secrets, nil without a master key
+	secretsCipher cipher.AEAD
+
+	// versions of the data domains, see versionning.go
+	versionning *Versionning
+
+	// limits of the executions whose plug does not give them
+	defaultLimits ExecutionLimits
+
+	// code of the executed functions, by techID
+	codeCache *Cache
+	caches    []*Cache
+
+	// serializes the checks and the registrations of blob names by credentials
+	blobNamesLock sync.Mutex
 }
 
-func NewOrchestrator(db *leveldb.DB, trace bool) *Orchestrator {
-	return &Orchestrator{
+func NewOrchestrator(db *leveldb.DB, trace bool) (*Orchestrator, error) {
+	plugs, err := NewPlugSystem(db, "plugs", trace)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Orchestrator{
 		nextExchangeBufferID: 0,
-		exchangeBuffers:      make(map[int]ExchangeBuffer),
+		exchangeBuffers:      make(map[int]*exchangeBufferEntry),
 		db:                   db,
-		executionEngines:     make(map[string]ExecutionEngine),
+		executionEngines:     make(map[string][]*registeredEngine),
 		apiProviders:         make(map[string]APIProvider),
 		trace:                trace,
 		stats:                make(map[string]int),
-		plugs:                NewPlugSystem(db, "plugs", trace),
+		plugs:                plugs,
+		codeCache:            NewCache("code", DefaultCacheSize, nil),
+		persistenceCommitted: make(chan struct{}),
 	}
-}
 
-func (o *Orchestrator) AddExecutionEngine(contentType string, engine ExecutionEngine) {
-	o.executionEngines[contentType] = engine
-	fmt.Printf("registered '%s' execution engine\n", contentType)
+	o.registerCache(o.codeCache)
+	o.SetEgressPolicy(DefaultEgressPolicy())
+
+	go o.maintainPersistence()
+
+	return o, nil
 }
 
 func (o *Orchestrator) AddAPIProvider(moduleName string, apiProvider APIProvider) {
@@ -81,19 +116,56 @@ type FunctionExecutionContext struct {
 	Orchestrator *Orchestrator
 
 	CodeBytes     []byte
+	TechID        string // of the code, used by engines as a cache key
 	Name          string
 	StartFunction string
 	Arguments     []int
 
 	Trace                 bool
 	Mode                  string // direct or posix
+	Engine                string // requested execution engines, in preference order, empty for the blob's or the default one
+	engineName            string // engine running the execution
 	POSIXFileName         *string
 	POSIXArguments        *[]string
+	Mounts                []Mount          // directories mounted in the file system of a posix execution
+	Capabilities          *Capabilities    // host api functions the function may import, nil for the default ones
+	Egress                *EgressRules     // overrides of the egress policy, nil for none
+	Environment           []string         // "NAME=VALUE" environment variables of a posix execution
+	Credential            *AdminCredential // admin credential of the request, nil without one
+	Namespace             string           // persistence namespace of the function, see persistence.go
 	InputExchangeBufferID int
 
 	HasFinishedRunning     bool
 	OutputExchangeBufferID int
 	Result                 int
+	Exit                   *ExecutionExit // how the execution stopped, set with SetExit and FinishRunning
+
+	// handles of the input and output buffers, the ones seen by the function
+	InputExchangeBufferHandle  int
+	OutputExchangeBufferHandle int
+
+	// handle => orchestrator id of the exchange buffers held by the function
+	exchangeBufferHandles    map[int]int
+	nextExchangeBufferHandle int
+	buffersLock              sync.Mutex
+
+	// namespace selected by UseNamespace, the function's one when empty
+	persistenceNamespace string
+	persistenceReadOnly  bool
+	transaction          *persistenceTransaction // started by BeginPersistenceTransaction
+
+	// domains held by the function, and the ones held for it by the versionning or by its caller
+	heldDomains      map[string]*Unholder
+	inheritedDomains map[string]bool
+
+	// resource limits of the execution, the deadline is inherited by called functions
+	Limits       ExecutionLimits
+	Deadline     time.Time
+	limitsLock   sync.Mutex
+	fuelConsumed int64
+	limitError   error
+	// metering points given to the wasm function and not yet counted as fuel
+	meteringGranted int
 }
 
 /*
@@ -118,6 +190,7 @@ func (o *Orchestrator) NewFunctionExecutionContext(
 
 		Name:          functionName,
 		StartFunction: startFunction,
+		Namespace:     functionName,
 
 		Trace:          trace,
 		Mode:           mode,
@@ -129,6 +202,8 @@ func (o *Orchestrator) NewFunctionExecutionContext(
 		InputExchangeBufferID:  inputExchangeBufferID,
 		OutputExchangeBufferID: outputExchangeBufferID,
 		Result:                 -1,
+
+		Limits: o.defaultLimits,
 	}
 }
 
@@ -138,76 +213,91 @@ func (fctx *FunctionExecutionContext) Run() error {
 		return fmt.Errorf("can't find plugged function (%s)", fctx.Name)
 	}
 
-	pluggedFunctionAbstract, err := fctx.Orchestrator.GetBlobAbstractByTechID(pluggedFunctionTechID)
+	pluggedFunctionAbstract, codeBytes, err := fctx.Orchestrator.getFunctionCode(pluggedFunctionTechID)
 	if err != nil {
-		return fmt.Errorf("can't find plugged function abstract (%s)", fctx.Name)
-	}
-
-	codeBytes, err := fctx.Orchestrator.GetBlobBytesByTechID(pluggedFunctionTechID)
-	if err != nil {
-		return fmt.Errorf("can't find plugged function bytes (%s)", fctx.Name)
+		return fmt.Errorf("can't find plugged function (%s): %v", fctx.Name, err)
 	}
 
+	fctx.TechID = pluggedFunctionTechID
 	fctx.CodeBytes = codeBytes
 
-	engine, hasEngine := fctx.Orchestrator.executionEngines[pluggedFunctionAbstract.ContentType]
-	if hasEngine {
-		ectx, err := engine.PrepareContext(fctx)
-		if err != nil || ectx == nil {
-			return fmt.Errorf("cannot create %s context for function: %v", pluggedFunctionAbstract.ContentType, err)
+	// the function holds its input and output buffers while running, and releases
+	// everything it still holds when finished (including the buffers it created)
+	fctx.HoldInputOutputExchangeBuffers()
+	detached := false
+	defer func() {
+		if !detached {
+			fctx.ReleaseHeldExchangeBuffers()
+			fctx.releaseHeldDomains()
 		}
+	}()
 
-		fmt.Printf("call '%s'::'%s' type:%s mode:%s\n", fctx.Name, fctx.StartFunction, pluggedFunctionAbstract.ContentType, fctx.Mode)
-
-		err = ectx.Run()
-		if err != nil {
-			return fmt.Errorf("execution %s error in function: %v", pluggedFunctionAbstract.ContentType, err)
-		}
-	} else {
-		return fmt.Errorf("unknown function type '%s'", pluggedFunctionAbstract.ContentType)
+	engineName, engine, err := fctx.Orchestrator.selectExecutionEngine(pluggedFunctionAbstract.ContentType, fctx.Engine)
+	if err != nil {
+		return fmt.Errorf("cannot run function '%s': %w", fctx.Name, err)
 	}
+	fctx.engineName = engineName
 
-	if fctx.Trace {
-		fmt.Printf(" -> result:%d\n", fctx.Result)
+	ectx, err := engine.PrepareContext(fctx)
+	if err != nil || ectx == nil {
+		return fmt.Errorf("cannot create %s context for function: %v", pluggedFunctionAbstract.ContentType, err)
 	}
 
-	/*
-		Instead of waiting for the end of the call, we should count references to the exchange buffer
-		and wait for the last reference to dissappear. At this moment, the http response is complete and
-		can be sent back to the client. This allows the first callee to transfer its output exchange
-		buffer to another function and exit. The other function will then do whatever it wants to do
-		(fan out, fan in and so on...).
-		At this point in time, resources associated with this call can be destroyed.
-	*/
+	fmt.Printf("call '%s'::'%s' type:%s engine:%s mode:%s\n", fctx.Name, fctx.StartFunction, pluggedFunctionAbstract.ContentType, engineName, fctx.Mode)
 
-	return nil
-}
+	detached, err = fctx.runEngine(ectx)
 
-/*
+	// engines abort the execution with their own error (or none) when a limit is exceeded
+	if limitErr := fctx.LimitError(); limitErr != nil {
+		fctx.Orchestrator.StatIncrement(STAT_NB_LIMITED_EXECUTIONS)
+		return fmt.Errorf("function '%s' aborted: %w", fctx.Name, limitErr)
+	}
 
-Persistence service for applications, a simple key value database
+	if err != nil {
+		return fmt.Errorf("execution %s error in function: %w", pluggedFunctionAbstract.ContentType, err)
+	}
 
-*/
+	if fctx.Exit == nil {
+		fctx.FinishRunning(ReturnedExit(fctx.Result))
+	}
 
-var persistencePrefix = []byte("/persistence")
+	if fctx.Trace {
+		fmt.Printf(" -> %s\n", fctx.Exit)
+	}
 
-func (o *Orchestrator) PersistenceSet(key []byte, value []byte) bool {
-	key = append(persistencePrefix, key...)
+	if fctx.Exit.Failed() {
+		fctx.Orchestrator.StatIncrement(STAT_NB_FAILED_EXECUTIONS)
+		return &ExecutionError{fctx.Name, fctx.Exit}
+	}
 
-	o.db.Put(key, value, &opt.WriteOptions{Sync: true})
+	return nil
+}
 
-	return true
+type cachedCode struct {
+	abstract *BlobAbstract
+	bytes    []byte
 }
 
-func (o *Orchestrator) PersistenceGet(key []byte) ([]byte, bool) {
-	key = append(persistencePrefix, key...)
+// getFunctionCode returns the abstract and bytes of a function, blobs being immutable they are cached
+func (o *Orchestrator) getFunctionCode(techID string) (*BlobAbstract, []byte, error) {
+	if cached, ok := o.codeCache.Get(techID); ok {
+		code := cached.(*cachedCode)
+		return code.abstract, code.bytes, nil
+	}
 
-	value, err := o.db.Get(key, nil)
+	abstract, err := o.GetBlobAbstractByTechID(techID)
 	if err != nil {
-		return nil, false
+		return nil, nil, fmt.Errorf("can't find abstract (%v)", err)
 	}
 
-	return value, true
+	codeBytes, err := o.GetBlobBytesByTechID(techID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find bytes (%v)", err)
+	}
+
+	o.codeCache.Put(techID, &cachedCode{abstract, codeBytes})
+
+	return abstract, codeBytes, nil
 }
 
 func dup(b []byte) []byte {
@@ -216,28 +306,6 @@ func dup(b []byte) []byte {
 	return r
 }
 
-func (o *Orchestrator) PersistenceGetSubset(keyPrefix []byte) ([][]byte, error) {
-	keyPrefix = append(persistencePrefix, keyPrefix...)
-
-	r := make([][]byte, 0)
-
-	iter := o.db.NewIterator(util.BytesPrefix(keyPrefix), nil)
-	for iter.Next() {
-		key := iter.Key()[len(persistencePrefix):]
-		value := iter.Value()
-
-		r = append(r, dup(key), dup(value))
-	}
-	iter.Release()
-
-	err := iter.Error()
-	if err != nil {
-		return nil, err
-	}
-
-	return r, nil
-}
-
 type Plug struct {
 	Type string            `json:"type"`
 	Name string            `json:"name"`
@@ -258,6 +326,54 @@ type PluggedFunction struct {
 	Data          string            `json:"data,omitempty"`
 }
 
+// GetExecutionLimits returns the resource limits given in the plug tags, the other ones being the defaults
+func (p *PluggedFunction) GetExecutionLimits(defaults ExecutionLimits) (ExecutionLimits, error) {
+	return parseExecutionLimits(defaults, p.Tags)
+}
+
+// GetEngine returns the execution engines requested in the plug tags, empty for the default one
+func (p *PluggedFunction) GetEngine() string {
+	return p.Tags["engine"]
+}
+
+// GetMode returns the execution mode given in the plug tags, "direct" by default
+func (p *PluggedFunction) GetMode() string {
+	if mode, ok := p.Tags["mode"]; ok {
+		return mode
+	}
+
+	return "direct"
+}
+
+// GetMounts returns the mounts given in the plug tags
+func (p *PluggedFunction) GetMounts() ([]Mount, error) {
+	return ParseMounts(p.Tags["mounts"])
+}
+
+// GetCapabilities returns the capabilities given in the plug tags
+func (p *PluggedFunction) GetCapabilities() (*Capabilities, error) {
+	return ParseCapabilities(p.Tags["capabilities"])
+}
+
+// GetEgressRules returns the egress policy overrides given in the plug tags
+func (p *PluggedFunction) GetEgressRules() (*EgressRules, error) {
+	return ParseEgressRules(p.Tags["egress"])
+}
+
+// GetNamespace returns the persistence namespace given in the plug tags, the function name by default
+func (p *PluggedFunction) GetNamespace() string {
+	if namespace, ok := p.Tags["namespace"]; ok {
+		return namespace
+	}
+
+	return p.Name
+}
+
+// GetSecretsEnvironment returns the secrets given as environment variables in the plug tags
+func (p *PluggedFunction) GetSecretsEnvironment() (map[string]string, error) {
+	return ParseSecretsEnvironment(p.Tags["secrets_env"])
+}
+
 /**
 URL plugging and routing
 */
@@ -271,6 +387,52 @@ func (o *Orchestrator) PlugFunction(method string, path string, name string, sta
 		return err
 	}
 
+	_, err = ParseExecutionLimits(tags)
+	if err != nil {
+		return err
+	}
+
+	err = o.validateEngineTag(name, tags)
+	if err != nil {
+		return err
+	}
+
+	if mode, ok := tags["mode"]; ok && mode != "direct" && mode != "posix" {
+		return fmt.Errorf("unknown mode '%s', should be 'direct' or 'posix'", mode)
+	}
+
+	_, err = ParseMounts(tags["mounts"])
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseCapabilities(tags["capabilities"])
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseEgressRules(tags["egress"])
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseSecretsEnvironment(tags["secrets_env"])
+	if err != nil {
+		return err
+	}
+
+	if namespace, ok := tags["namespace"]; ok {
+		err = ValidateNamespace(namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = validateRoleTag(tags)
+	if err != nil {
+		return err
+	}
+
 	data := &PluggedFunction{
 		Type:          "function",
 		Name:          name,
@@ -284,7 +446,10 @@ func (o *Orchestrator) PlugFunction(method string, path string, name string, sta
 		return err
 	}
 
-	o.plugs.PlugPath(method, path, dataJSON)
+	err = o.plugs.PlugPath(method, path, dataJSON)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("plugged_function on method:%s, path:'%s', name:%s, start_function:%s, data:'%s'\n", method, path, name, startFunction, plugData)
 
@@ -300,6 +465,11 @@ func (o *Orchestrator) PlugFile(method string, path string, name string, tagsJSO
 		return err
 	}
 
+	err = validateRoleTag(tags)
+	if err != nil {
+		return err
+	}
+
 	data := &PluggedFile{
 		Type: "file",
 		Name: name,
@@ -311,7 +481,10 @@ func (o *Orchestrator) PlugFile(method string, path string, name string, tagsJSO
 		return err
 	}
 
-	o.plugs.PlugPath(method, path, dataJSON)
+	err = o.plugs.PlugPath(method, path, dataJSON)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("plugged_file on method:%s, path:'%s', name:%s\n", method, path, name)
 
@@ -321,9 +494,7 @@ func (o *Orchestrator) PlugFile(method string, path string, name string, tagsJSO
 func (o *Orchestrator) UnplugPath(method string, path string) error {
 	method = strings.ToLower(method)
 
-	o.plugs.UnplugPath(method, path)
-
-	return nil
+	return o.plugs.UnplugPath(method, path)
 }
 
 func (o *Orchestrator) GetPlugs() map[string]string {
@@ -429,22 +600,28 @@ GetStatus
 */
 
 type status struct {
-	Plugs      map[string]string `json:"plugs"`
-	BlobNames  []BlobNameStatus  `json:"blob_names"`
-	Blobs      []BlobStatus      `json:"blobs"`
-	Filters    []Filter          `json:"filters"`
-	Statistics map[string]int    `json:"statistics"`
+	Plugs           map[string]string      `json:"plugs"`
+	BlobNames       []BlobNameStatus       `json:"blob_names"`
+	Blobs           []BlobStatus           `json:"blobs"`
+	Filters         []Filter               `json:"filters"`
+	ExchangeBuffers []ExchangeBufferStatus `json:"exchange_buffers"`
+	Caches          []CacheStatus          `json:"caches"`
+	Engines         map[string][]string    `json:"engines"`
+	Statistics      map[string]int         `json:"statistics"`
 }
 
 func (o *Orchestrator) GetStatus() string {
 	status := &status{}
 
-	o.StatSet(STAT_NB_CURRENT_BUFFERS, len(o.exchangeBuffers))
+	status.ExchangeBuffers = o.GetExchangeBuffersStatus()
+	o.StatSet(STAT_NB_CURRENT_BUFFERS, len(status.ExchangeBuffers))
 
 	status.Plugs = o.GetPlugs()
 	status.BlobNames = o.GetBlobsByName()
 	status.Blobs = o.GetBlobs()
 	status.Filters = o.GetFilters()
+	status.Caches = o.GetCachesStatus()
+	status.Engines = o.GetExecutionEngines()
 
 	o.statsLock.Lock()
 	defer o.statsLock.Unlock()
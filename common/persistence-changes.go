@@ -0,0 +1,338 @@
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence changes
+
+	Every persistence commit also writes its changes in a log, in the same batch, so that the log
+	has every committed change and only them. The changes of a commit are kept per namespace
+	("/namespaces/changes/NAMESPACE/SEQUENCE"), a reader only goes through the commits of its
+	namespace. Each change has the sequence of its commit, a key which was written, deleted or
+	swept because it expired, and the written value. Values larger than maxLoggedValueSize are
+	not copied in the log : they are read from the data while the key keeps the version of the
+	change, otherwise the change is flagged as overwritten and a later change has the key.
+
+	Changes of a namespace are read after a sequence, restricted to a key prefix : the result
+	gives the sequence to read the following ones from. A read may wait for new changes, this is
+	how the core REST API serves them as a long poll, server-sent events or web socket messages.
+
+	The log keeps the changes of the last persistenceChangesRetention commits, older ones are
+	trimmed by the persistence maintenance which records the last trimmed sequence. A read after
+	a trimmed sequence is flagged as truncated, the reader then reloads the data.
+*/
+
+var persistenceChangesPrefix = []byte("/namespaces/changes/")
+var persistenceChangesTrimmedKey = []byte("/namespaces/changes-trimmed")
+
+// number of commits kept in the change log
+const persistenceChangesRetention = 100000
+
+// values larger than this are read from the data instead of being copied in the log
+const maxLoggedValueSize = 4096
+
+const defaultChangesLimit = 100
+const maxChangesLimit = 1000
+
+// maximum time a read waits for changes
+const maxChangesTimeout = 60 * time.Second
+
+type PersistenceChangesQuery struct {
+	Since    int64  `json:"since"` // sequence after which the changes are read, -1 for the current one
+	Prefix   string `json:"prefix,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // "base64" for binary keys and values, strings by default
+	Timeout  int    `json:"timeout,omitempty"`  // seconds to wait for a change, 0 to return immediately
+}
+
+type PersistenceChange struct {
+	Sequence    int64   `json:"sequence"`
+	Key         string  `json:"key"`
+	Value       *string `json:"value,omitempty"`
+	Deleted     bool    `json:"deleted,omitempty"`
+	Expired     bool    `json:"expired,omitempty"`
+	Overwritten bool    `json:"overwritten,omitempty"` // the large value was written again since, without value
+}
+
+type PersistenceChangesResult struct {
+	Changes   []PersistenceChange `json:"changes"`
+	Sequence  int64               `json:"sequence"`            // to read the next changes from
+	Truncated bool                `json:"truncated,omitempty"` // changes after the queried sequence were trimmed
+}
+
+// storedChange is a change in the log
+type storedChange struct {
+	Key     []byte `json:"key"`
+	Value   []byte `json:"value,omitempty"`
+	Large   bool   `json:"large,omitempty"` // the value is not in the log
+	Deleted bool   `json:"deleted,omitempty"`
+	Expired bool   `json:"expired,omitempty"`
+}
+
+func namespaceChangesPrefix(namespace string) []byte {
+	return append(dup(persistenceChangesPrefix), []byte(url.PathEscape(namespace)+"/")...)
+}
+
+func changesKey(namespace string, sequence int64) []byte {
+	return append(namespaceChangesPrefix(namespace), []byte(fmt.Sprintf("%020d", sequence))...)
+}
+
+// writeChanges adds to a batch the log of the writes of a commit, one entry per namespace
+func writeChanges(batch *leveldb.Batch, sequence int64, writes []PersistenceWrite) error {
+	namespaces := []string{}
+	changes := make(map[string][]storedChange)
+	for _, write := range writes {
+		if _, ok := changes[write.Namespace]; !ok {
+			namespaces = append(namespaces, write.Namespace)
+		}
+
+		change := storedChange{Key: write.Key, Deleted: write.Delete, Expired: write.expired}
+		if len(write.Value) > maxLoggedValueSize {
+			change.Large = true
+		} else {
+			change.Value = write.Value
+		}
+		changes[write.Namespace] = append(changes[write.Namespace], change)
+	}
+
+	for _, namespace := range namespaces {
+		changesBytes, err := json.Marshal(changes[namespace])
+		if err != nil {
+			return err
+		}
+
+		batch.Put(changesKey(namespace, sequence), changesBytes)
+	}
+
+	return nil
+}
+
+// trimmedPersistenceChanges returns the last sequence trimmed from the change log, 0 when none was
+func trimmedPersistenceChanges(reader persistenceReader) int64 {
+	trimmedBytes, err := reader.Get(persistenceChangesTrimmedKey, nil)
+	if err != nil {
+		return 0
+	}
+
+	trimmed, _ := strconv.ParseInt(string(trimmedBytes), 10, 64)
+
+	return trimmed
+}
+
+// loadPersistenceSequence reads the sequence of the last commit, with the persistence lock held
+func (o *Orchestrator) loadPersistenceSequence() error {
+	if o.persistenceSequence != 0 {
+		return nil
+	}
+
+	o.persistenceSequence = legacyPersistenceVersion
+	if sequenceBytes, err := o.db.Get(persistenceSequenceKey, nil); err == nil {
+		sequence, err := strconv.ParseInt(string(sequenceBytes), 10, 64)
+		if err != nil {
+			o.persistenceSequence = 0
+			return fmt.Errorf("invalid persistence sequence '%s'", string(sequenceBytes))
+		}
+		o.persistenceSequence = sequence
+	}
+
+	return nil
+}
+
+// persistenceHead returns the sequence of the last commit, and a channel closed at the next one
+func (o *Orchestrator) persistenceHead() (int64, chan struct{}, error) {
+	o.persistenceLock.Lock()
+	defer o.persistenceLock.Unlock()
+
+	err := o.loadPersistenceSequence()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return o.persistenceSequence, o.persistenceCommitted, nil
+}
+
+// PersistenceGetChanges reads the changes of a namespace, waiting for them when the query has a timeout
+func (o *Orchestrator) PersistenceGetChanges(namespace string, query *PersistenceChangesQuery, deadline time.Time) (*PersistenceChangesResult, error) {
+	if query.Encoding != "" && query.Encoding != "base64" {
+		return nil, fmt.Errorf("unknown changes encoding '%s', should be 'base64' or nothing", query.Encoding)
+	}
+
+	timeout := time.Duration(query.Timeout) * time.Second
+	if timeout > maxChangesTimeout {
+		timeout = maxChangesTimeout
+	}
+	waitDeadline := time.Now().Add(timeout)
+	if !deadline.IsZero() && deadline.Before(waitDeadline) {
+		waitDeadline = deadline
+	}
+
+	since := query.Since
+
+	for {
+		head, committed, err := o.persistenceHead()
+		if err != nil {
+			return nil, err
+		}
+
+		if since < 0 {
+			since = head
+		}
+
+		result, err := o.readChanges(namespace, query, since)
+		if err != nil {
+			return nil, err
+		}
+
+		wait := time.Until(waitDeadline)
+		if len(result.Changes) > 0 || result.Truncated || wait <= 0 {
+			return result, nil
+		}
+
+		// the changes of other namespaces were skipped
+		since = result.Sequence
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-committed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// readChanges reads the logged changes of a namespace after a sequence
+func (o *Orchestrator) readChanges(namespace string, query *PersistenceChangesQuery, since int64) (*PersistenceChangesResult, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	if limit > maxChangesLimit {
+		limit = maxChangesLimit
+	}
+
+	encode := func(b []byte) string {
+		if query.Encoding == "base64" {
+			return base64.StdEncoding.EncodeToString(b)
+		}
+		return string(b)
+	}
+
+	prefix := []byte(query.Prefix)
+	if query.Encoding == "base64" {
+		var err error
+		prefix, err = base64.StdEncoding.DecodeString(query.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 changes prefix '%s' (%v)", query.Prefix, err)
+		}
+	}
+
+	snapshot, err := o.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	result := &PersistenceChangesResult{Changes: []PersistenceChange{}, Sequence: since}
+
+	// the commits following since were trimmed from the log
+	if since < trimmedPersistenceChanges(snapshot) {
+		result.Truncated = true
+	}
+
+	nsPrefix := namespaceChangesPrefix(namespace)
+	iter := snapshot.NewIterator(&util.Range{Start: changesKey(namespace, since+1), Limit: util.BytesPrefix(nsPrefix).Limit}, nil)
+	defer iter.Release()
+
+	for iter.Next() && len(result.Changes) < limit {
+		sequence, err := strconv.ParseInt(string(iter.Key()[len(nsPrefix):]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		changes := []storedChange{}
+		err = json.Unmarshal(iter.Value(), &changes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid persistence change %d (%v)", sequence, err)
+		}
+
+		for _, change := range changes {
+			if !bytes.HasPrefix(change.Key, prefix) {
+				continue
+			}
+
+			c := PersistenceChange{Sequence: sequence, Key: encode(change.Key), Deleted: change.Deleted, Expired: change.Expired}
+			switch {
+			case change.Deleted:
+			case !change.Large:
+				value := encode(change.Value)
+				c.Value = &value
+			case persistenceVersion(snapshot, namespace, change.Key) == sequence:
+				data, _ := snapshot.Get(namespaceKey(namespace, change.Key), nil)
+				value := encode(data)
+				c.Value = &value
+			default:
+				c.Overwritten = true
+			}
+			result.Changes = append(result.Changes, c)
+		}
+
+		result.Sequence = sequence
+	}
+
+	return result, iter.Error()
+}
+
+// TrimPersistenceChanges deletes the changes of the commits older than the retention
+func (o *Orchestrator) TrimPersistenceChanges() error {
+	head, _, err := o.persistenceHead()
+	if err != nil {
+		return err
+	}
+
+	if head <= persistenceChangesRetention {
+		return nil
+	}
+
+	trimmed := head - persistenceChangesRetention
+	if trimmedPersistenceChanges(o.db) >= trimmed {
+		return nil
+	}
+
+	// the sequences of a namespace are in order, the trim jumps to the next namespace after
+	// the first kept one
+	batch := new(leveldb.Batch)
+	iter := o.db.NewIterator(util.BytesPrefix(persistenceChangesPrefix), nil)
+	for valid := iter.First(); valid; {
+		key := iter.Key()
+		separator := bytes.LastIndexByte(key, '/')
+		sequence, err := strconv.ParseInt(string(key[separator+1:]), 10, 64)
+		if err == nil && sequence > trimmed && separator >= len(persistenceChangesPrefix) {
+			valid = iter.Seek(append(dup(key[:separator+1]), 0xff))
+			continue
+		}
+
+		batch.Delete(dup(key))
+		valid = iter.Next()
+	}
+	iter.Release()
+
+	err = iter.Error()
+	if err != nil {
+		return err
+	}
+
+	batch.Put(persistenceChangesTrimmedKey, encodeVersion(trimmed))
+
+	return o.db.Write(batch, nil)
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPersistenceChangesPerNamespace(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	large := bytes.Repeat([]byte("x"), 2*maxLoggedValueSize)
+	o.PersistenceSet("shop", []byte("/small"), []byte("small"))
+	o.PersistenceSet("blog", []byte("/post"), []byte("post"))
+	o.PersistenceSet("shop", []byte("/large"), large)
+
+	head, _, _ := o.persistenceHead()
+	logged, err := o.db.Get(changesKey("shop", head), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) > maxLoggedValueSize {
+		t.Fatalf("the large value is copied in the log (%d bytes)", len(logged))
+	}
+	if _, err := o.db.Get(changesKey("blog", head), nil); err == nil {
+		t.Fatal("the commit is logged for a namespace it did not write")
+	}
+
+	result, err := o.PersistenceGetChanges("shop", &PersistenceChangesQuery{Since: 0}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 2 || result.Sequence != head || result.Truncated {
+		t.Fatalf("unexpected changes %+v", result)
+	}
+	if value := result.Changes[1].Value; value == nil || *value != string(large) {
+		t.Fatal("the large value is not read from the data")
+	}
+
+	// the large value of the change is gone once written again
+	o.PersistenceSet("shop", []byte("/large"), []byte("smaller"))
+	result, err = o.PersistenceGetChanges("shop", &PersistenceChangesQuery{Since: 0}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 3 || result.Changes[1].Value != nil || !result.Changes[1].Overwritten || *result.Changes[2].Value != "smaller" {
+		t.Fatalf("unexpected changes after the large value is written again %+v", result.Changes)
+	}
+}
+
+func TestPersistenceChangesTrim(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	o.PersistenceSet("shop", []byte("/a"), []byte("a"))
+	o.PersistenceSet("blog", []byte("/b"), []byte("b"))
+	first, _, _ := o.persistenceHead()
+
+	// the commits of a long time ago
+	o.persistenceSequence = first + persistenceChangesRetention
+	o.PersistenceSet("shop", []byte("/c"), []byte("c"))
+
+	err := o.TrimPersistenceChanges()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, namespace := range []string{"shop", "blog"} {
+		result, err := o.PersistenceGetChanges(namespace, &PersistenceChangesQuery{Since: 0}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Truncated {
+			t.Fatalf("the trimmed changes of '%s' are not flagged", namespace)
+		}
+	}
+
+	// the commits up to the retention before /c are trimmed
+	result, err := o.PersistenceGetChanges("shop", &PersistenceChangesQuery{Since: first + 1}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Truncated || len(result.Changes) != 1 || result.Changes[0].Key != "/c" {
+		t.Fatalf("unexpected changes after the trim %+v", result)
+	}
+	if _, err := o.db.Get(changesKey("blog", first), nil); err == nil {
+		t.Fatal("the changes of 'blog' are not trimmed")
+	}
+}
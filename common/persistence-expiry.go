@@ -0,0 +1,200 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence expiry
+
+	A key written with a TTL expires after it : reads, scans and versions see it as missing from
+	then, and the persistence maintenance sweeps it a bit later. Writing the key again without a
+	TTL removes its expiry.
+
+	The expiry of a key is stored twice, with the key ("/namespaces/expiry/keys/") to check reads,
+	and in an index ordered by time ("/namespaces/expiry/index/TIME/") where the sweeper reads the
+	expired keys of a range. An index entry not matching the expiry of its key is left by a
+	later write, the sweeper drops it.
+
+	Swept keys are deleted by a persistence commit, and counted in the
+	nb_persistence_expired_keys statistic.
+*/
+
+var persistenceExpiryKeysPrefix = []byte("/namespaces/expiry/keys/")
+var persistenceExpiryIndexPrefix = []byte("/namespaces/expiry/index/")
+
+// maximum number of index entries read at once by the sweeper
+const persistenceSweepBatchSize = 1000
+
+// nowMilliseconds returns the current time, the unit of expiry times
+func nowMilliseconds() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// ExpiresAt returns the expiry time of a write done now with a TTL, 0 for no expiry
+func ExpiresAt(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return nowMilliseconds() + int64(ttl/time.Millisecond)
+}
+
+func expiryKey(namespace string, key []byte) []byte {
+	return append(append(dup(persistenceExpiryKeysPrefix), []byte(url.PathEscape(namespace)+"/")...), key...)
+}
+
+// expiryIndexTime formats a time so that the index is ordered by time
+func expiryIndexTime(expiresAt int64) []byte {
+	return []byte(fmt.Sprintf("%016d/", expiresAt))
+}
+
+func expiryIndexKey(expiresAt int64, namespace string, key []byte) []byte {
+	return append(append(append(dup(persistenceExpiryIndexPrefix), expiryIndexTime(expiresAt)...), []byte(url.PathEscape(namespace)+"/")...), key...)
+}
+
+// persistenceExpiry returns the expiry time of a key, 0 when it does not expire
+func persistenceExpiry(reader persistenceReader, namespace string, key []byte) int64 {
+	expiresAtBytes, err := reader.Get(expiryKey(namespace, key), nil)
+	if err != nil {
+		return 0
+	}
+
+	expiresAt, err := strconv.ParseInt(string(expiresAtBytes), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return expiresAt
+}
+
+// persistenceExpired tells if a key expired, even when not yet swept
+func persistenceExpired(reader persistenceReader, namespace string, key []byte) bool {
+	expiresAt := persistenceExpiry(reader, namespace, key)
+
+	return expiresAt != 0 && expiresAt <= nowMilliseconds()
+}
+
+// writeExpiry adds to a batch the expiry changes of a write
+func writeExpiry(batch *leveldb.Batch, reader persistenceReader, write *PersistenceWrite) {
+	previous := persistenceExpiry(reader, write.Namespace, write.Key)
+	if previous != 0 {
+		batch.Delete(expiryIndexKey(previous, write.Namespace, write.Key))
+		batch.Delete(expiryKey(write.Namespace, write.Key))
+	}
+
+	if !write.Delete && write.ExpiresAt != 0 {
+		batch.Put(expiryKey(write.Namespace, write.Key), encodeVersion(write.ExpiresAt))
+		batch.Put(expiryIndexKey(write.ExpiresAt, write.Namespace, write.Key), []byte{})
+	}
+}
+
+type expiryIndexEntry struct {
+	indexKey  []byte
+	expiresAt int64
+	namespace string
+	key       []byte
+}
+
+// parseExpiryIndexKey reads an index key, without the index prefix
+func parseExpiryIndexKey(indexKey []byte) (*expiryIndexEntry, bool) {
+	parts := bytes.SplitN(indexKey, []byte("/"), 3)
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	expiresAt, err := strconv.ParseInt(string(parts[0]), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	namespace, err := url.PathUnescape(string(parts[1]))
+	if err != nil {
+		return nil, false
+	}
+
+	return &expiryIndexEntry{expiresAt: expiresAt, namespace: namespace, key: parts[2]}, true
+}
+
+// SweepExpiredKeys deletes the expired keys and returns their number
+func (o *Orchestrator) SweepExpiredKeys() (int, error) {
+	swept := 0
+
+	for {
+		// index entries expired by now
+		expired := util.Range{
+			Start: dup(persistenceExpiryIndexPrefix),
+			Limit: append(dup(persistenceExpiryIndexPrefix), expiryIndexTime(nowMilliseconds()+1)...),
+		}
+
+		entries := make([]*expiryIndexEntry, 0)
+		iter := o.db.NewIterator(&expired, nil)
+		for iter.Next() && len(entries) < persistenceSweepBatchSize {
+			indexKey := dup(iter.Key())
+			entry, ok := parseExpiryIndexKey(indexKey[len(persistenceExpiryIndexPrefix):])
+			if !ok {
+				entry = &expiryIndexEntry{}
+			}
+			entry.indexKey = indexKey
+			entries = append(entries, entry)
+		}
+		iter.Release()
+
+		err := iter.Error()
+		if err != nil {
+			return swept, err
+		}
+
+		if len(entries) == 0 {
+			return swept, nil
+		}
+
+		n, err := o.sweepExpiryIndexEntries(entries)
+		swept += n
+		if err != nil {
+			return swept, err
+		}
+
+		if len(entries) < persistenceSweepBatchSize {
+			return swept, nil
+		}
+	}
+}
+
+// sweepExpiryIndexEntries deletes the keys of index entries still matching their expiry, and the entries
+func (o *Orchestrator) sweepExpiryIndexEntries(entries []*expiryIndexEntry) (int, error) {
+	o.persistenceLock.Lock()
+	defer o.persistenceLock.Unlock()
+
+	batch := new(leveldb.Batch)
+	deletes := make([]PersistenceWrite, 0)
+	for _, entry := range entries {
+		if entry.namespace != "" && persistenceExpiry(o.db, entry.namespace, entry.key) == entry.expiresAt {
+			deletes = append(deletes, PersistenceWrite{Namespace: entry.namespace, Key: entry.key, Delete: true, expired: true})
+		} else {
+			batch.Delete(entry.indexKey)
+		}
+	}
+
+	if len(deletes) == 0 {
+		return 0, o.db.Write(batch, nil)
+	}
+
+	_, err := o.persistenceApply(batch, deletes)
+	if err != nil {
+		return 0, err
+	}
+
+	for range deletes {
+		o.StatIncrement(STAT_NB_PERSISTENCE_EXPIRED_KEYS)
+	}
+
+	return len(deletes), nil
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// setExpiring writes a key which expires at expiresAt, in unix milliseconds
+func setExpiring(t *testing.T, o *Orchestrator, namespace string, key string, expiresAt int64) {
+	_, err := o.PersistenceCommit(nil, []PersistenceWrite{{Namespace: namespace, Key: []byte(key), Value: []byte(key), ExpiresAt: expiresAt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpiredKeysAreNotRead(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	setExpiring(t, o, "shop", "/items/expired", nowMilliseconds()-1)
+	setExpiring(t, o, "shop", "/items/later", ExpiresAt(time.Hour))
+	o.PersistenceSet("shop", []byte("/items/kept"), []byte("kept"))
+
+	if _, present := o.PersistenceGet("shop", []byte("/items/expired")); present {
+		t.Fatal("an expired key is read")
+	}
+	if version := o.PersistenceVersion("shop", []byte("/items/expired")); version != 0 {
+		t.Fatalf("an expired key has the version %d", version)
+	}
+	if _, present, version, _ := o.PersistenceGetVersioned("shop", []byte("/items/expired")); present || version != 0 {
+		t.Fatal("an expired key is read with its version")
+	}
+	if value, present := o.PersistenceGet("shop", []byte("/items/later")); !present || string(value) != "/items/later" {
+		t.Fatal("a key is missing before it expires")
+	}
+
+	keys, err := o.PersistenceGetKeys("shop", []byte("/items/"))
+	if err != nil || len(keys) != 2 || string(keys[0]) != "/items/kept" || string(keys[1]) != "/items/later" {
+		t.Fatalf("the keys are %q (%v)", keys, err)
+	}
+	subset, err := o.PersistenceGetSubset("shop", []byte("/items/"))
+	if err != nil || len(subset) != 4 {
+		t.Fatalf("the subset is %q (%v)", subset, err)
+	}
+	result, err := o.PersistenceScan("shop", &PersistenceScanQuery{Prefix: "/items/"})
+	if err != nil || len(result.Entries) != 2 {
+		t.Fatalf("the scan is %+v (%v)", result, err)
+	}
+
+	// an expired key is missing for compare-and-set too
+	version, err := o.PersistenceCompareAndSet("shop", []byte("/items/expired"), 0, []byte("again"))
+	if err != nil || version == 0 {
+		t.Fatalf("an expired key cannot be created again (%v)", err)
+	}
+
+	// writing it again without a TTL removes its expiry
+	if value, present := o.PersistenceGet("shop", []byte("/items/expired")); !present || string(value) != "again" {
+		t.Fatal("a key written again after it expired is missing")
+	}
+	if expiresAt := persistenceExpiry(o.db, "shop", []byte("/items/expired")); expiresAt != 0 {
+		t.Fatalf("a key written again without TTL expires at %d", expiresAt)
+	}
+}
+
+func TestSweepExpiredKeys(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	past := nowMilliseconds() - 1
+	setExpiring(t, o, "shop", "/expired", past)
+	setExpiring(t, o, "blog/posts", "/expired", past-1000)
+	setExpiring(t, o, "shop", "/later", ExpiresAt(time.Hour))
+	// written again without TTL, its index entry is left for the sweeper
+	setExpiring(t, o, "shop", "/rewritten", past)
+	o.PersistenceSet("shop", []byte("/rewritten"), []byte("rewritten"))
+
+	swept, err := o.SweepExpiredKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swept != 2 {
+		t.Fatalf("%d keys swept, expected 2", swept)
+	}
+
+	for _, namespace := range []string{"shop", "blog/posts"} {
+		if has, _ := o.db.Has(namespaceKey(namespace, []byte("/expired")), nil); has {
+			t.Fatalf("the expired key of '%s' is still stored", namespace)
+		}
+		if has, _ := o.db.Has(versionKey(namespace, []byte("/expired")), nil); has {
+			t.Fatalf("the version of the expired key of '%s' is still stored", namespace)
+		}
+		if has, _ := o.db.Has(expiryKey(namespace, []byte("/expired")), nil); has {
+			t.Fatalf("the expiry of the expired key of '%s' is still stored", namespace)
+		}
+	}
+	if _, present := o.PersistenceGet("shop", []byte("/later")); !present {
+		t.Fatal("a key was swept before it expired")
+	}
+	if value, present := o.PersistenceGet("shop", []byte("/rewritten")); !present || string(value) != "rewritten" {
+		t.Fatal("a key written again without TTL was swept")
+	}
+
+	// only the index entry of the key which did not expire is left
+	iter := o.db.NewIterator(util.BytesPrefix(persistenceExpiryIndexPrefix), nil)
+	entries := 0
+	for iter.Next() {
+		entries++
+	}
+	iter.Release()
+	if entries != 1 {
+		t.Fatalf("%d expiry index entries left, expected 1", entries)
+	}
+
+	// the sweep is logged as expired deletes and counted
+	result, err := o.PersistenceGetChanges("shop", &PersistenceChangesQuery{Since: 0}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := result.Changes[len(result.Changes)-1]
+	if last.Key != "/expired" || !last.Deleted || !last.Expired {
+		t.Fatalf("the sweep is logged as %+v", last)
+	}
+	if o.stats[string(STAT_NB_PERSISTENCE_EXPIRED_KEYS)] != 2 {
+		t.Fatalf("the sweep is counted %d times", o.stats[string(STAT_NB_PERSISTENCE_EXPIRED_KEYS)])
+	}
+
+	if swept, err := o.SweepExpiredKeys(); swept != 0 || err != nil {
+		t.Fatalf("the second sweep swept %d keys (%v)", swept, err)
+	}
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence scans
+
+	A scan reads a page of the keys of a namespace, in key order or in reverse order, between a
+	start key (included) and an end key (excluded), restricted to a prefix. It returns at most
+	limit entries and a cursor when there are more : the same query with this cursor gives the
+	next page. Keys and values are returned as strings, or in base64 with the "base64" encoding,
+	the prefix, start and end keys of the query are then given in base64 too.
+
+	Scans read the committed data, they are not recorded by transactions.
+*/
+
+const defaultScanLimit = 100
+const maxScanLimit = 1000
+
+type PersistenceScanQuery struct {
+	Prefix   string `json:"prefix,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+	Reverse  bool   `json:"reverse,omitempty"`
+	KeysOnly bool   `json:"keys_only,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // "base64" for binary keys and values, strings by default
+	Cursor   string `json:"cursor,omitempty"`   // of the previous page
+}
+
+type PersistenceScanEntry struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value,omitempty"`
+}
+
+type PersistenceScanResult struct {
+	Entries []PersistenceScanEntry `json:"entries"`
+	Cursor  string                 `json:"cursor,omitempty"` // empty for the last page
+}
+
+// decodeKey returns the bytes of a key given in the query, in base64 with the "base64" encoding
+func (q *PersistenceScanQuery) decodeKey(name string, key string) ([]byte, error) {
+	if q.Encoding != "base64" {
+		return []byte(key), nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 scan %s '%s' (%v)", name, key, err)
+	}
+
+	return decoded, nil
+}
+
+// scanRange returns the keys of a query, in the namespace keyspace, a nil limit being the end of the namespace
+func (q *PersistenceScanQuery) scanRange(namespace string) (*util.Range, error) {
+	nsPrefix := namespacePrefix(namespace)
+
+	prefix, err := q.decodeKey("prefix", q.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	r := util.BytesPrefix(append(dup(nsPrefix), prefix...))
+
+	if q.Start != "" {
+		startKey, err := q.decodeKey("start", q.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		start := append(dup(nsPrefix), startKey...)
+		if bytes.Compare(start, r.Start) > 0 {
+			r.Start = start
+		}
+	}
+
+	if q.End != "" {
+		endKey, err := q.decodeKey("end", q.End)
+		if err != nil {
+			return nil, err
+		}
+
+		end := append(dup(nsPrefix), endKey...)
+		if r.Limit == nil || bytes.Compare(end, r.Limit) < 0 {
+			r.Limit = end
+		}
+	}
+
+	if q.Cursor != "" {
+		last, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scan cursor '%s'", q.Cursor)
+		}
+		last = append(dup(nsPrefix), last...)
+
+		if q.Reverse {
+			if r.Limit == nil || bytes.Compare(last, r.Limit) < 0 {
+				r.Limit = last
+			}
+		} else {
+			// the first key after the last one
+			next := append(last, 0)
+			if bytes.Compare(next, r.Start) > 0 {
+				r.Start = next
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// PersistenceScan reads a page of the keys of a namespace
+func (o *Orchestrator) PersistenceScan(namespace string, query *PersistenceScanQuery) (*PersistenceScanResult, error) {
+	if query.Encoding != "" && query.Encoding != "base64" {
+		return nil, fmt.Errorf("unknown scan encoding '%s', should be 'base64' or nothing", query.Encoding)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultScanLimit
+	}
+	if limit > maxScanLimit {
+		limit = maxScanLimit
+	}
+
+	scanRange, err := query.scanRange(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	encode := func(b []byte) string {
+		if query.Encoding == "base64" {
+			return base64.StdEncoding.EncodeToString(b)
+		}
+		return string(b)
+	}
+
+	result := &PersistenceScanResult{Entries: []PersistenceScanEntry{}}
+	nsPrefixLength := len(namespacePrefix(namespace))
+
+	iter := o.db.NewIterator(scanRange, nil)
+	valid, advance := iter.First(), iter.Next
+	if query.Reverse {
+		valid, advance = iter.Last(), iter.Prev
+	}
+
+	var last []byte
+	for ; valid; valid = advance() {
+		key := iter.Key()[nsPrefixLength:]
+		if persistenceExpired(o.db, namespace, key) {
+			continue
+		}
+
+		if len(result.Entries) == limit {
+			result.Cursor = base64.RawURLEncoding.EncodeToString(last)
+			break
+		}
+
+		entry := PersistenceScanEntry{Key: encode(key)}
+		if !query.KeysOnly {
+			value := encode(iter.Value())
+			entry.Value = &value
+		}
+		result.Entries = append(result.Entries, entry)
+
+		last = dup(key)
+	}
+	iter.Release()
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestPersistenceScanBase64Bounds(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	for _, key := range [][]byte{{0x01, 0x00}, {0x01, 0x7f}, {0x01, 0xff, 0x00}, {0x01, 0xff, 0x01}, {0x02}} {
+		o.PersistenceSet("binary", key, []byte{0x00})
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	scan := func(query PersistenceScanQuery) []string {
+		query.Encoding = "base64"
+		result, err := o.PersistenceScan("binary", &query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys := []string{}
+		for _, entry := range result.Entries {
+			keys = append(keys, entry.Key)
+		}
+		return keys
+	}
+
+	if keys := scan(PersistenceScanQuery{Prefix: encode([]byte{0x01, 0xff})}); len(keys) != 2 || keys[0] != encode([]byte{0x01, 0xff, 0x00}) {
+		t.Fatalf("prefix scan returned %v", keys)
+	}
+	if keys := scan(PersistenceScanQuery{Start: encode([]byte{0x01, 0x7f}), End: encode([]byte{0x01, 0xff, 0x01})}); len(keys) != 2 || keys[1] != encode([]byte{0x01, 0xff, 0x00}) {
+		t.Fatalf("range scan returned %v", keys)
+	}
+
+	_, err := o.PersistenceScan("binary", &PersistenceScanQuery{Encoding: "base64", Start: "not base64!"})
+	if err == nil {
+		t.Fatal("an invalid base64 start key is accepted")
+	}
+}
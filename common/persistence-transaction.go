@@ -0,0 +1,457 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence versions and transactions
+
+	Every persistence write is a commit, numbered by a sequence stored in the database. The keys
+	written by a commit get its number as version, so a key deleted and written again never gets
+	back an older version. The version of a missing key is 0, the one of a key written before
+	versions existed is 1.
+
+	A commit is applied with a LevelDB write batch, after checking that the keys it read still have
+	the version they had when read. This gives :
+
+	- compare-and-set : a write of one key expecting a version, 0 for a missing key,
+	- optimistic transactions : between persistence_begin_transaction and
+	  persistence_commit_transaction, the writes of a function are kept until the commit and the
+	  versions of the keys it reads are recorded. The commit fails when one of them changed, the
+	  function then retries. The prefixes of the subsets it reads are recorded with their keys, the
+	  commit also fails when a key was added or removed under one of them.
+
+	A transaction without writes commits nothing : it returns the current sequence after checking
+	its reads.
+
+	Conflicts are counted in the nb_persistence_conflicts statistic. The changes of each commit are
+	logged, see persistence-changes.go.
+*/
+
+var ErrPersistenceTransaction = errors.New("persistence transaction")
+
+var persistenceVersionsPrefix = []byte("/namespaces/versions/")
+var persistenceSequenceKey = []byte("/namespaces/sequence")
+
+// the version of the keys written before versions existed
+const legacyPersistenceVersion = 1
+
+type PersistenceWrite struct {
+	Namespace string
+	Key       []byte
+	Value     []byte
+	Delete    bool
+	ExpiresAt int64 // in unix milliseconds, 0 for a key which does not expire, see ExpiresAt
+
+	expired bool // a delete of the sweeper
+}
+
+type PersistenceRead struct {
+	Namespace string
+	Key       []byte
+	Version   int64
+}
+
+// PersistenceSubsetRead is the read of the keys starting with a prefix
+type PersistenceSubsetRead struct {
+	Namespace string
+	Prefix    []byte
+	Keys      [][]byte // in the order of the database
+}
+
+func versionKey(namespace string, key []byte) []byte {
+	return append(append(dup(persistenceVersionsPrefix), []byte(url.PathEscape(namespace)+"/")...), key...)
+}
+
+func encodeVersion(version int64) []byte {
+	return []byte(strconv.FormatInt(version, 10))
+}
+
+type persistenceReader interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Has(key []byte, ro *opt.ReadOptions) (bool, error)
+}
+
+// persistenceVersion returns the version of a key, 0 when it does not exist or expired
+func persistenceVersion(reader persistenceReader, namespace string, key []byte) int64 {
+	if persistenceExpired(reader, namespace, key) {
+		return 0
+	}
+
+	versionBytes, err := reader.Get(versionKey(namespace, key), nil)
+	if err == nil {
+		version, err := strconv.ParseInt(string(versionBytes), 10, 64)
+		if err == nil {
+			return version
+		}
+	}
+
+	if has, _ := reader.Has(namespaceKey(namespace, key), nil); has {
+		return legacyPersistenceVersion
+	}
+
+	return 0
+}
+
+// PersistenceVersion returns the version of a key, 0 when it does not exist
+func (o *Orchestrator) PersistenceVersion(namespace string, key []byte) int64 {
+	return persistenceVersion(o.db, namespace, key)
+}
+
+// PersistenceGetVersioned returns the value of a key with its version, read at the same time
+func (o *Orchestrator) PersistenceGetVersioned(namespace string, key []byte) ([]byte, bool, int64, error) {
+	snapshot, err := o.db.GetSnapshot()
+	if err != nil {
+		return nil, false, 0, err
+	}
+	defer snapshot.Release()
+
+	value, err := snapshot.Get(namespaceKey(namespace, key), nil)
+	if err == leveldb.ErrNotFound || (err == nil && persistenceExpired(snapshot, namespace, key)) {
+		return nil, false, 0, nil
+	}
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	return value, true, persistenceVersion(snapshot, namespace, key), nil
+}
+
+/*
+	PersistenceCommit applies writes atomically when every read key still has its version. It
+	returns the version of the commit, 0 when a read key changed.
+*/
+func (o *Orchestrator) PersistenceCommit(reads []PersistenceRead, writes []PersistenceWrite) (int64, error) {
+	return o.persistenceCommit(reads, nil, writes)
+}
+
+// persistenceCommit also checks that the subsets read have the same keys. Without writes, it
+// returns the sequence of the last commit.
+func (o *Orchestrator) persistenceCommit(reads []PersistenceRead, subsets []PersistenceSubsetRead, writes []PersistenceWrite) (int64, error) {
+	o.persistenceLock.Lock()
+	defer o.persistenceLock.Unlock()
+
+	for _, read := range reads {
+		if persistenceVersion(o.db, read.Namespace, read.Key) != read.Version {
+			o.StatIncrement(STAT_NB_PERSISTENCE_CONFLICTS)
+			return 0, nil
+		}
+	}
+
+	for i := range subsets {
+		changed, err := o.persistenceSubsetChanged(&subsets[i])
+		if err != nil {
+			return 0, err
+		}
+		if changed {
+			o.StatIncrement(STAT_NB_PERSISTENCE_CONFLICTS)
+			return 0, nil
+		}
+	}
+
+	if len(writes) == 0 {
+		err := o.loadPersistenceSequence()
+		if err != nil {
+			return 0, err
+		}
+
+		return o.persistenceSequence, nil
+	}
+
+	return o.persistenceApply(new(leveldb.Batch), writes)
+}
+
+// persistenceSubsetChanged tells if keys were added or removed under the prefix of a subset read
+func (o *Orchestrator) persistenceSubsetChanged(subset *PersistenceSubsetRead) (bool, error) {
+	prefix := namespacePrefix(subset.Namespace)
+	iter := o.db.NewIterator(util.BytesPrefix(append(dup(prefix), subset.Prefix...)), nil)
+	defer iter.Release()
+
+	i := 0
+	for iter.Next() {
+		key := iter.Key()[len(prefix):]
+		if persistenceExpired(o.db, subset.Namespace, key) {
+			continue
+		}
+		if i >= len(subset.Keys) || !bytes.Equal(key, subset.Keys[i]) {
+			return true, nil
+		}
+		i++
+	}
+
+	return i != len(subset.Keys), iter.Error()
+}
+
+// persistenceApply writes a batch with writes in a new commit, with the persistence lock held
+func (o *Orchestrator) persistenceApply(batch *leveldb.Batch, writes []PersistenceWrite) (int64, error) {
+	err := o.loadPersistenceSequence()
+	if err != nil {
+		return 0, err
+	}
+
+	sequence := o.persistenceSequence + 1
+
+	for i := range writes {
+		write := &writes[i]
+		if write.Delete {
+			batch.Delete(namespaceKey(write.Namespace, write.Key))
+			batch.Delete(versionKey(write.Namespace, write.Key))
+		} else {
+			batch.Put(namespaceKey(write.Namespace, write.Key), write.Value)
+			batch.Put(versionKey(write.Namespace, write.Key), encodeVersion(sequence))
+		}
+		writeExpiry(batch, o.db, write)
+	}
+	batch.Put(persistenceSequenceKey, encodeVersion(sequence))
+
+	err = writeChanges(batch, sequence, writes)
+	if err != nil {
+		return 0, err
+	}
+
+	err = o.db.Write(batch, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return 0, err
+	}
+
+	o.persistenceSequence = sequence
+
+	// wakes up the readers waiting for changes
+	close(o.persistenceCommitted)
+	o.persistenceCommitted = make(chan struct{})
+
+	return sequence, nil
+}
+
+// PersistenceCompareAndSet writes a key if it has the expected version, 0 for a missing key. It
+// returns the new version, 0 when the key has another version.
+func (o *Orchestrator) PersistenceCompareAndSet(namespace string, key []byte, expectedVersion int64, value []byte) (int64, error) {
+	return o.PersistenceCommit(
+		[]PersistenceRead{{Namespace: namespace, Key: key, Version: expectedVersion}},
+		[]PersistenceWrite{{Namespace: namespace, Key: key, Value: value}},
+	)
+}
+
+// persistenceTransaction keeps the reads and the writes of a function until it commits
+type persistenceTransaction struct {
+	reads   []PersistenceRead
+	read    map[string]bool
+	subsets []PersistenceSubsetRead
+	writes  []PersistenceWrite
+}
+
+func transactionKey(namespace string, key []byte) string {
+	return namespace + "\x00" + string(key)
+}
+
+func (t *persistenceTransaction) recordRead(namespace string, key []byte, version int64) {
+	if t.read[transactionKey(namespace, key)] {
+		return
+	}
+
+	t.read[transactionKey(namespace, key)] = true
+	t.reads = append(t.reads, PersistenceRead{Namespace: namespace, Key: dup(key), Version: version})
+}
+
+// written returns the last write of the transaction on a key, nil when there is none
+func (t *persistenceTransaction) written(namespace string, key []byte) *PersistenceWrite {
+	for i := len(t.writes) - 1; i >= 0; i-- {
+		if t.writes[i].Namespace == namespace && bytes.Equal(t.writes[i].Key, key) {
+			return &t.writes[i]
+		}
+	}
+
+	return nil
+}
+
+func (fctx *FunctionExecutionContext) BeginPersistenceTransaction() error {
+	if fctx.transaction != nil {
+		return fmt.Errorf("%w already started", ErrPersistenceTransaction)
+	}
+
+	fctx.transaction = &persistenceTransaction{read: make(map[string]bool)}
+
+	return nil
+}
+
+// CommitPersistenceTransaction applies the writes of the transaction, and returns the version of the
+// commit, 0 when a key it read changed or a key was added or removed under a prefix it read. The
+// transaction ends in both cases.
+func (fctx *FunctionExecutionContext) CommitPersistenceTransaction() (int64, error) {
+	t := fctx.transaction
+	if t == nil {
+		return 0, fmt.Errorf("no %w started", ErrPersistenceTransaction)
+	}
+	fctx.transaction = nil
+
+	return fctx.Orchestrator.persistenceCommit(t.reads, t.subsets, t.writes)
+}
+
+func (fctx *FunctionExecutionContext) AbortPersistenceTransaction() error {
+	if fctx.transaction == nil {
+		return fmt.Errorf("no %w started", ErrPersistenceTransaction)
+	}
+	fctx.transaction = nil
+
+	return nil
+}
+
+// PersistenceGet reads a key of the namespace used by the function, through its transaction if any
+func (fctx *FunctionExecutionContext) PersistenceGet(key []byte) ([]byte, bool, error) {
+	namespace := fctx.PersistenceNamespace()
+
+	if fctx.transaction == nil {
+		value, present := fctx.Orchestrator.PersistenceGet(namespace, key)
+		return value, present, nil
+	}
+
+	if write := fctx.transaction.written(namespace, key); write != nil {
+		return write.Value, !write.Delete, nil
+	}
+
+	value, present, version, err := fctx.Orchestrator.PersistenceGetVersioned(namespace, key)
+	if err != nil {
+		return nil, false, err
+	}
+	fctx.transaction.recordRead(namespace, key, version)
+
+	return value, present, nil
+}
+
+// PersistenceGetVersion returns the version of a key of the namespace used by the function
+func (fctx *FunctionExecutionContext) PersistenceGetVersion(key []byte) int64 {
+	namespace := fctx.PersistenceNamespace()
+	version := fctx.Orchestrator.PersistenceVersion(namespace, key)
+
+	if fctx.transaction != nil {
+		fctx.transaction.recordRead(namespace, key, version)
+	}
+
+	return version
+}
+
+// PersistenceGetSubset returns the keys starting with a prefix and their values, one after the other,
+// through the transaction of the function if any
+func (fctx *FunctionExecutionContext) PersistenceGetSubset(keyPrefix []byte) ([][]byte, error) {
+	namespace := fctx.PersistenceNamespace()
+
+	if fctx.transaction == nil {
+		return fctx.Orchestrator.PersistenceGetSubset(namespace, keyPrefix)
+	}
+
+	snapshot, err := fctx.Orchestrator.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	values := make(map[string][]byte)
+	subset := PersistenceSubsetRead{Namespace: namespace, Prefix: dup(keyPrefix)}
+	prefix := namespacePrefix(namespace)
+	iter := snapshot.NewIterator(util.BytesPrefix(append(dup(prefix), keyPrefix...)), nil)
+	for iter.Next() {
+		key := dup(iter.Key()[len(prefix):])
+		if persistenceExpired(snapshot, namespace, key) {
+			continue
+		}
+		values[string(key)] = dup(iter.Value())
+		subset.Keys = append(subset.Keys, key)
+		fctx.transaction.recordRead(namespace, key, persistenceVersion(snapshot, namespace, key))
+	}
+	iter.Release()
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	// keys added or removed under the prefix are conflicts too
+	fctx.transaction.subsets = append(fctx.transaction.subsets, subset)
+
+	for _, write := range fctx.transaction.writes {
+		if write.Namespace != namespace || !bytes.HasPrefix(write.Key, keyPrefix) {
+			continue
+		}
+		if write.Delete {
+			delete(values, string(write.Key))
+		} else {
+			values[string(write.Key)] = write.Value
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	r := make([][]byte, 0, 2*len(keys))
+	for _, key := range keys {
+		r = append(r, []byte(key), values[key])
+	}
+
+	return r, nil
+}
+
+// PersistenceSet writes a key of the namespace used by the function, at the commit of its transaction if any
+func (fctx *FunctionExecutionContext) PersistenceSet(key []byte, value []byte) error {
+	return fctx.persistenceWrite(PersistenceWrite{Key: dup(key), Value: dup(value)})
+}
+
+// PersistenceSetWithTTL writes a key of the namespace used by the function which expires after ttl
+func (fctx *FunctionExecutionContext) PersistenceSetWithTTL(key []byte, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid ttl %v, should be positive", ttl)
+	}
+
+	return fctx.persistenceWrite(PersistenceWrite{Key: dup(key), Value: dup(value), ExpiresAt: ExpiresAt(ttl)})
+}
+
+// PersistenceDelete deletes a key of the namespace used by the function, at the commit of its transaction if any
+func (fctx *FunctionExecutionContext) PersistenceDelete(key []byte) error {
+	return fctx.persistenceWrite(PersistenceWrite{Key: dup(key), Delete: true})
+}
+
+func (fctx *FunctionExecutionContext) persistenceWrite(write PersistenceWrite) error {
+	err := fctx.CheckPersistenceWrite()
+	if err != nil {
+		return err
+	}
+
+	write.Namespace = fctx.PersistenceNamespace()
+
+	if fctx.transaction != nil {
+		fctx.transaction.writes = append(fctx.transaction.writes, write)
+		return nil
+	}
+
+	_, err = fctx.Orchestrator.PersistenceCommit(nil, []PersistenceWrite{write})
+
+	return err
+}
+
+// PersistenceCompareAndSet writes a key of the namespace used by the function if it has the expected
+// version, see Orchestrator.PersistenceCompareAndSet. It cannot be used in a transaction.
+func (fctx *FunctionExecutionContext) PersistenceCompareAndSet(key []byte, expectedVersion int64, value []byte) (int64, error) {
+	if fctx.transaction != nil {
+		return 0, fmt.Errorf("compare-and-set cannot be used in a %w", ErrPersistenceTransaction)
+	}
+
+	err := fctx.CheckPersistenceWrite()
+	if err != nil {
+		return 0, err
+	}
+
+	return fctx.Orchestrator.PersistenceCompareAndSet(fctx.PersistenceNamespace(), key, expectedVersion, value)
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"testing"
+)
+
+func TestReadOnlyTransactionCommitsNothing(t *testing.T) {
+	o := newTestOrchestrator(t)
+	fctx := &FunctionExecutionContext{Orchestrator: o, Namespace: "shop"}
+
+	o.PersistenceSet("shop", []byte("/count"), []byte("1"))
+	head, committed, err := o.persistenceHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fctx.BeginPersistenceTransaction()
+	fctx.PersistenceGet([]byte("/count"))
+	version, err := fctx.CommitPersistenceTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != head {
+		t.Fatalf("the read only commit returned %d, the sequence is %d", version, head)
+	}
+
+	after, _, _ := o.persistenceHead()
+	if after != head {
+		t.Fatalf("the read only commit moved the sequence from %d to %d", head, after)
+	}
+	if _, err := o.db.Get(changesKey("shop", head+1), nil); err == nil {
+		t.Fatal("the read only commit was logged")
+	}
+	select {
+	case <-committed:
+		t.Fatal("the read only commit woke up the readers")
+	default:
+	}
+}
+
+func TestTransactionSubsetConflicts(t *testing.T) {
+	o := newTestOrchestrator(t)
+	fctx := &FunctionExecutionContext{Orchestrator: o, Namespace: "shop"}
+
+	o.PersistenceSet("shop", []byte("/items/a"), []byte("a"))
+	o.PersistenceSet("shop", []byte("/items/b"), []byte("b"))
+
+	commitAfter := func(concurrent func()) int64 {
+		fctx.BeginPersistenceTransaction()
+		items, err := fctx.PersistenceGetSubset([]byte("/items/"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fctx.PersistenceSet([]byte("/count"), []byte{byte('0' + len(items)/2)})
+
+		concurrent()
+
+		version, err := fctx.CommitPersistenceTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return version
+	}
+
+	if version := commitAfter(func() { o.PersistenceSet("shop", []byte("/items/c"), []byte("c")) }); version != 0 {
+		t.Fatal("a key added under the prefix is not a conflict")
+	}
+	if version := commitAfter(func() { o.PersistenceDelete("shop", []byte("/items/a")) }); version != 0 {
+		t.Fatal("a key removed under the prefix is not a conflict")
+	}
+	if version := commitAfter(func() { o.PersistenceSet("shop", []byte("/other"), []byte("x")) }); version == 0 {
+		t.Fatal("a key added outside of the prefix is a conflict")
+	}
+	if value, _ := o.PersistenceGet("shop", []byte("/count")); string(value) != "2" {
+		t.Fatalf("count is %q", value)
+	}
+}
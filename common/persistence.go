@@ -0,0 +1,385 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence
+
+	Persistence service for applications, a simple key value database divided in namespaces. A
+	function reads and writes the namespace of its plug "namespace" tag, or the one named after its
+	blob without the tag. Functions called with call_function use the namespace of their name,
+	linked wasm modules the one of their caller.
+
+	Another namespace is used after persistence_use_namespace, when it is shared with the namespace
+	of the function by a grant : "read" or "write" access, given to a namespace or to "*" for
+	every function. Grants are managed by admins, who may also list the namespaces with their
+	number of keys and size.
+
+	The "shared" namespace is the keyspace used before namespaces, so that older data stays
+	reachable : functions plugged with {"namespace":"shared"} or granted access to it use it. The
+	plugs of a database having such data are given this tag by the persistence domain migration
+	(see versionning-persistence.go). No blob may be named "shared".
+
+	As namespaces default to blob names, a deployer may not create a blob application (see blob.go)
+	named after namespaces which are already used : having keys, grants or plugs with their
+	"namespace" tag.
+*/
+
+const SharedNamespace = "shared"
+
+const (
+	NAMESPACE_READ  = "read"
+	NAMESPACE_WRITE = "write"
+)
+
+// interval of the persistence maintenance : expired keys sweeping and change log trimming
+const persistenceMaintenanceInterval = 10 * time.Second
+
+// keys of the shared namespace, the historical keyspace
+var persistencePrefix = []byte("/persistence")
+
+var namespacesDataPrefix = []byte("/namespaces/data/")
+var namespacesGrantsPrefix = []byte("/namespaces/grants/")
+
+type NamespaceInfo struct {
+	Name   string            `json:"name"`
+	Keys   int               `json:"keys"`
+	Bytes  int64             `json:"bytes"`  // of the keys and values
+	Grants map[string]string `json:"grants"` // granted namespace ("*" for all) => access
+}
+
+// ValidateNamespace fails for names which cannot be namespaces
+func ValidateNamespace(namespace string) error {
+	if namespace == "" || namespace == "*" || len(namespace) > 255 {
+		return fmt.Errorf("invalid namespace '%s'", namespace)
+	}
+
+	for _, c := range namespace {
+		if c < ' ' || c == 0x7f {
+			return fmt.Errorf("invalid namespace '%s', it has control characters", namespace)
+		}
+	}
+
+	return nil
+}
+
+// namespacePrefix returns the database prefix of the keys of a namespace
+func namespacePrefix(namespace string) []byte {
+	if namespace == SharedNamespace {
+		return dup(persistencePrefix)
+	}
+
+	return append(dup(namespacesDataPrefix), []byte(url.PathEscape(namespace)+"/")...)
+}
+
+func namespaceKey(namespace string, key []byte) []byte {
+	return append(namespacePrefix(namespace), key...)
+}
+
+func (o *Orchestrator) PersistenceSet(namespace string, key []byte, value []byte) bool {
+	_, err := o.PersistenceCommit(nil, []PersistenceWrite{{Namespace: namespace, Key: key, Value: value}})
+
+	return err == nil
+}
+
+func (o *Orchestrator) PersistenceGet(namespace string, key []byte) ([]byte, bool) {
+	value, err := o.db.Get(namespaceKey(namespace, key), nil)
+	if err != nil || persistenceExpired(o.db, namespace, key) {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func (o *Orchestrator) PersistenceDelete(namespace string, key []byte) bool {
+	_, err := o.PersistenceCommit(nil, []PersistenceWrite{{Namespace: namespace, Key: key, Delete: true}})
+
+	return err == nil
+}
+
+// PersistenceGetKeys returns the keys starting with keyPrefix, without their values
+func (o *Orchestrator) PersistenceGetKeys(namespace string, keyPrefix []byte) ([][]byte, error) {
+	prefix := namespacePrefix(namespace)
+
+	r := make([][]byte, 0)
+
+	iter := o.db.NewIterator(util.BytesPrefix(append(dup(prefix), keyPrefix...)), nil)
+	for iter.Next() {
+		key := iter.Key()[len(prefix):]
+		if persistenceExpired(o.db, namespace, key) {
+			continue
+		}
+
+		r = append(r, dup(key))
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// PersistenceGetSubset returns the keys starting with keyPrefix and their values, one after the other
+func (o *Orchestrator) PersistenceGetSubset(namespace string, keyPrefix []byte) ([][]byte, error) {
+	prefix := namespacePrefix(namespace)
+
+	r := make([][]byte, 0)
+
+	iter := o.db.NewIterator(util.BytesPrefix(append(dup(prefix), keyPrefix...)), nil)
+	for iter.Next() {
+		key := iter.Key()[len(prefix):]
+		if persistenceExpired(o.db, namespace, key) {
+			continue
+		}
+		value := iter.Value()
+
+		r = append(r, dup(key), dup(value))
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func namespaceGrantKey(namespace string, grantee string) []byte {
+	return append(dup(namespacesGrantsPrefix), []byte(url.PathEscape(namespace)+"/"+url.PathEscape(grantee))...)
+}
+
+// GrantNamespace gives a namespace ("*" for all) read or write access to another one
+func (o *Orchestrator) GrantNamespace(namespace string, grantee string, access string) error {
+	err := ValidateNamespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	if grantee != "*" {
+		err = ValidateNamespace(grantee)
+		if err != nil {
+			return err
+		}
+	}
+
+	if access != NAMESPACE_READ && access != NAMESPACE_WRITE {
+		return fmt.Errorf("unknown access '%s', should be '%s' or '%s'", access, NAMESPACE_READ, NAMESPACE_WRITE)
+	}
+
+	return o.db.Put(namespaceGrantKey(namespace, grantee), []byte(access), &opt.WriteOptions{Sync: true})
+}
+
+func (o *Orchestrator) RevokeNamespaceGrant(namespace string, grantee string) error {
+	key := namespaceGrantKey(namespace, grantee)
+
+	has, err := o.db.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return fmt.Errorf("namespace '%s' is not granted to '%s'", namespace, grantee)
+	}
+
+	return o.db.Delete(key, &opt.WriteOptions{Sync: true})
+}
+
+// namespaceAccess returns the access of a namespace to another one, empty for none
+func (o *Orchestrator) namespaceAccess(namespace string, grantee string) string {
+	for _, key := range [][]byte{namespaceGrantKey(namespace, grantee), namespaceGrantKey(namespace, "*")} {
+		access, err := o.db.Get(key, nil)
+		if err == nil {
+			if string(access) == NAMESPACE_WRITE {
+				return NAMESPACE_WRITE
+			}
+			return NAMESPACE_READ
+		}
+	}
+
+	return ""
+}
+
+// GetNamespaces returns the namespaces having keys or grants
+func (o *Orchestrator) GetNamespaces() ([]*NamespaceInfo, error) {
+	namespaces := make(map[string]*NamespaceInfo)
+	get := func(name string) *NamespaceInfo {
+		info, ok := namespaces[name]
+		if !ok {
+			info = &NamespaceInfo{Name: name, Grants: make(map[string]string)}
+			namespaces[name] = info
+		}
+		return info
+	}
+
+	// namespaceOf returns the namespace of a key, and the length of its namespace part
+	count := func(prefix []byte, namespaceOf func(key []byte) (string, int, bool)) error {
+		iter := o.db.NewIterator(util.BytesPrefix(prefix), nil)
+		for iter.Next() {
+			key := iter.Key()[len(prefix):]
+			name, length, ok := namespaceOf(key)
+			if !ok {
+				continue
+			}
+
+			info := get(name)
+			info.Keys++
+			info.Bytes += int64(len(key) - length + len(iter.Value()))
+		}
+		iter.Release()
+
+		return iter.Error()
+	}
+
+	err := count(persistencePrefix, func(key []byte) (string, int, bool) { return SharedNamespace, 0, true })
+	if err != nil {
+		return nil, err
+	}
+
+	err = count(namespacesDataPrefix, func(key []byte) (string, int, bool) {
+		slash := bytes.IndexByte(key, '/')
+		if slash < 0 {
+			return "", 0, false
+		}
+		name, err := url.PathUnescape(string(key[:slash]))
+		if err != nil {
+			return "", 0, false
+		}
+
+		return name, slash + 1, true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	iter := o.db.NewIterator(util.BytesPrefix(namespacesGrantsPrefix), nil)
+	for iter.Next() {
+		parts := strings.SplitN(string(iter.Key()[len(namespacesGrantsPrefix):]), "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name, err1 := url.PathUnescape(parts[0])
+		grantee, err2 := url.PathUnescape(parts[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		get(name).Grants[grantee] = string(iter.Value())
+	}
+	iter.Release()
+
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]*NamespaceInfo, 0, len(namespaces))
+	for _, info := range namespaces {
+		r = append(r, info)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Name < r[j].Name })
+
+	return r, nil
+}
+
+// isApplicationNamespaceUsed tells if the namespace of a blob application, or of one of its names,
+// has keys or grants or is given by the "namespace" tag of a plug
+func (o *Orchestrator) isApplicationNamespaceUsed(application string) bool {
+	escaped := url.PathEscape(application)
+	for _, prefix := range [][]byte{namespacesDataPrefix, namespacesGrantsPrefix} {
+		for _, separator := range []string{"/", url.PathEscape("/")} {
+			iter := o.db.NewIterator(util.BytesPrefix(append(dup(prefix), []byte(escaped+separator)...)), nil)
+			used := iter.Next()
+			iter.Release()
+
+			if used {
+				return true
+			}
+		}
+	}
+
+	for _, plugJSON := range o.GetPlugs() {
+		plug := &Plug{}
+		if json.Unmarshal([]byte(plugJSON), plug) != nil {
+			continue
+		}
+
+		if namespace, ok := plug.Tags["namespace"]; ok && blobApplication(namespace) == application {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UseNamespace selects the namespace of the following persistence calls of the function, its own
+// one when empty. It fails with ErrPermissionDenied when the namespace is not granted to it.
+func (fctx *FunctionExecutionContext) UseNamespace(namespace string) error {
+	if namespace == "" || namespace == fctx.Namespace {
+		fctx.persistenceNamespace = ""
+		fctx.persistenceReadOnly = false
+		return nil
+	}
+
+	access := fctx.Orchestrator.namespaceAccess(namespace, fctx.Namespace)
+	if access == "" {
+		return fmt.Errorf("%w: namespace '%s' is not granted to '%s'", ErrPermissionDenied, namespace, fctx.Namespace)
+	}
+
+	fctx.persistenceNamespace = namespace
+	fctx.persistenceReadOnly = access != NAMESPACE_WRITE
+
+	return nil
+}
+
+// PersistenceNamespace returns the namespace used by the persistence calls of the function
+func (fctx *FunctionExecutionContext) PersistenceNamespace() string {
+	if fctx.persistenceNamespace != "" {
+		return fctx.persistenceNamespace
+	}
+
+	return fctx.Namespace
+}
+
+// CheckPersistenceWrite fails with ErrPermissionDenied when the function only reads the namespace it uses
+func (fctx *FunctionExecutionContext) CheckPersistenceWrite() error {
+	if fctx.persistenceReadOnly {
+		return fmt.Errorf("%w: namespace '%s' is read only for '%s'", ErrPermissionDenied, fctx.persistenceNamespace, fctx.Namespace)
+	}
+
+	return nil
+}
+
+// maintainPersistence sweeps the expired keys and trims the change log, forever
+func (o *Orchestrator) maintainPersistence() {
+	ticker := time.NewTicker(persistenceMaintenanceInterval)
+	for {
+		<-ticker.C
+
+		swept, err := o.SweepExpiredKeys()
+		if err != nil {
+			fmt.Printf("cannot sweep expired persistence keys (%v)\n", err)
+		}
+		if swept > 0 && o.trace {
+			fmt.Printf("swept %d expired persistence keys\n", swept)
+		}
+
+		err = o.TrimPersistenceChanges()
+		if err != nil {
+			fmt.Printf("cannot trim the persistence change log (%v)\n", err)
+		}
+	}
+}
@@ -2,49 +2,61 @@ package common
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
-/****
+/*
+	Plugs are persisted in the database under the '/plug_system/<identifier>/byspec/<method>/<path>' keys.
 
-BUG TO FIX :
+	Routing is done on an in-memory trie compiled from those keys, which is rebuilt each time
+	a path is plugged or unplugged. A path spec is made of '/' separated segments which can be :
 
-when there is those path plugged :
+	- a literal, which must be equal to the requested path segment,
+	- '!name', which binds one non empty path segment to the 'name' parameter,
+	- '*name', which binds the whole remaining path to the 'name' parameter.
 
-/api/toto
-/api/toto/!param
-
-the query to /api/toto/coucou is 404 but should be routed to the second plug
-
-*****/
+	When several plugs match a path, the most specific one wins : at each segment, from left to right,
+	a literal is preferred over a parameter, which is preferred over a wildcard. The matcher backtracks
+	so that a less specific branch is tried when a more specific one leads nowhere.
+*/
 
 type PlugSystem struct {
 	db         *leveldb.DB
 	identifier string
 	trace      bool
+
+	// serializes the modifications, each one with the rebuild of the routes
+	lock sync.Mutex
+
+	routesLock sync.RWMutex
+	routes     map[string]*routeNode // method => routing trie
 }
 
-func NewPlugSystem(db *leveldb.DB, identifier string, trace bool) *PlugSystem {
-	return &PlugSystem{
+func NewPlugSystem(db *leveldb.DB, identifier string, trace bool) (*PlugSystem, error) {
+	p := &PlugSystem{
 		db:         db,
 		identifier: identifier,
 		trace:      trace,
 	}
+
+	err := p.rebuildRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the plugs of '%s' (%v)", identifier, err)
+	}
+
+	return p, nil
 }
 
 func (p *PlugSystem) getPlugsStartKey() []byte {
 	return []byte(fmt.Sprintf("/plug_system/%s/byspec/", p.identifier))
 }
 
-func (p *PlugSystem) getPlugsStartKeyByMethod(method string) []byte {
-	return []byte(fmt.Sprintf("/plug_system/%s/byspec/%s/", p.identifier, method))
-}
-
 func (p *PlugSystem) getPlugKey(method string, path string) []byte {
 	return []byte(fmt.Sprintf("/plug_system/%s/byspec/%s/%s", p.identifier, method, path))
 }
@@ -56,19 +68,31 @@ URL plugging and routing
 func (p *PlugSystem) PlugPath(method string, path string, data []byte) error {
 	method = strings.ToLower(method)
 
-	p.db.Put(p.getPlugKey(method, path), data, &opt.WriteOptions{Sync: true})
+	p.lock.Lock()
+	defer p.lock.Unlock()
 
-	return nil
+	err := p.db.Put(p.getPlugKey(method, path), data, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return err
+	}
+
+	return p.rebuildRoutes()
 }
 
 func (p *PlugSystem) UnplugPath(method string, path string) error {
 	method = strings.ToLower(method)
 
-	p.db.Delete(p.getPlugKey(method, path), nil)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	err := p.db.Delete(p.getPlugKey(method, path), nil)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("unplugged_path '%s' on method:%s, path:'%s'\n", p.identifier, method, path)
 
-	return nil
+	return p.rebuildRoutes()
 }
 
 func (p *PlugSystem) GetPlugs() map[string]string {
@@ -88,133 +112,190 @@ func (p *PlugSystem) GetPlugs() map[string]string {
 	return r
 }
 
-type walker struct {
-	db         *leveldb.DB
-	it         iterator.Iterator
-	basePrefix string
+/**
+Routing trie
+*/
+
+type routeParam struct {
+	name string
+	node *routeNode
 }
 
-func (w *walker) Key() string {
-	if w.it.Key() == nil {
-		return ""
+type routeWildcard struct {
+	name string
+	data []byte
+}
+
+type routeNode struct {
+	literals  map[string]*routeNode
+	params    []*routeParam    // sorted by name
+	wildcards []*routeWildcard // sorted by name
+
+	hasData bool
+	data    []byte
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{
+		literals: make(map[string]*routeNode),
 	}
+}
 
-	return string(w.it.Key()[len(w.basePrefix):])
+// splitPath returns the segments of a path, "/a/b" gives ["a", "b"] and "/" gives [""]
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
 }
 
-func (w *walker) Seek(key string) bool {
-	key = w.basePrefix + key
+func (n *routeNode) insert(segments []string, data []byte) {
+	if len(segments) == 0 {
+		n.hasData = true
+		n.data = data
+		return
+	}
+
+	segment := segments[0]
 
-	if w.it.Key() != nil {
-		// check that maybe we are already on it
-		if strings.HasPrefix(string(w.it.Key()), key) {
-			return true
+	if strings.HasPrefix(segment, "*") {
+		// a wildcard consumes the rest of the path, so following segments are meaningless
+		name := segment[1:]
+		for _, w := range n.wildcards {
+			if w.name == name {
+				w.data = data
+				return
+			}
 		}
+
+		n.wildcards = append(n.wildcards, &routeWildcard{name: name, data: data})
+		sort.Slice(n.wildcards, func(i, j int) bool { return n.wildcards[i].name < n.wildcards[j].name })
+		return
 	}
 
-	w.it.Seek([]byte(key))
+	if strings.HasPrefix(segment, "!") {
+		name := segment[1:]
+		for _, param := range n.params {
+			if param.name == name {
+				param.node.insert(segments[1:], data)
+				return
+			}
+		}
 
-	return strings.HasPrefix(string(w.it.Key()), key)
+		param := &routeParam{name: name, node: newRouteNode()}
+		n.params = append(n.params, param)
+		sort.Slice(n.params, func(i, j int) bool { return n.params[i].name < n.params[j].name })
+		param.node.insert(segments[1:], data)
+		return
+	}
+
+	child, ok := n.literals[segment]
+	if !ok {
+		child = newRouteNode()
+		n.literals[segment] = child
+	}
+	child.insert(segments[1:], data)
 }
 
-func (p *PlugSystem) findPlug(method string, path string) (bool, []byte, map[string]string) {
-	if p.trace {
-		fmt.Printf("START findPlug '%s' '%s'\n", method, path)
+// match walks the trie depth first, trying literals, then parameters, then wildcards
+func (n *routeNode) match(segments []string, boundParameters map[string]string) ([]byte, bool) {
+	if len(segments) == 0 {
+		return n.data, n.hasData
 	}
 
-	walker := &walker{
-		db:         p.db,
-		it:         p.db.NewIterator(nil, nil),
-		basePrefix: string(p.getPlugsStartKeyByMethod(method)),
+	segment := segments[0]
+
+	if child, ok := n.literals[segment]; ok {
+		if data, ok := child.match(segments[1:], boundParameters); ok {
+			return data, true
+		}
 	}
 
-	originalPath := path
+	if len(segment) > 0 {
+		for _, param := range n.params {
+			if data, ok := param.node.match(segments[1:], boundParameters); ok {
+				boundParameters[param.name] = segment
+				return data, true
+			}
+		}
+	}
 
-	boundParameters := make(map[string]string)
+	if len(n.wildcards) > 0 {
+		w := n.wildcards[0]
+		boundParameters[w.name] = strings.Join(segments, "/")
+		return w.data, true
+	}
 
-	prefix := ""
+	return nil, false
+}
 
-	defer walker.it.Release()
+// rebuildRoutes compiles the routes from the database, with the lock held once the plug system is created
+func (p *PlugSystem) rebuildRoutes() error {
+	routes := make(map[string]*routeNode)
 
-	if !walker.Seek("") {
-		fmt.Printf("no plugs registered (%s)\n", walker.Key())
-		return false, nil, nil
-	}
+	prefix := p.getPlugsStartKey()
 
-	// seek the beginning of the plug entries
-	for len(path) > 0 {
-		// path part that can be consummed (basically, everything until a '/')
-		askedPathPart := path
-		nextPartIndex := 1 + strings.Index(askedPathPart[1:], "/")
-		if nextPartIndex > 0 {
-			askedPathPart = askedPathPart[:nextPartIndex]
+	iter := p.db.NewIterator(util.BytesPrefix(prefix), nil)
+	for iter.Next() {
+		spec := string(iter.Key()[len(prefix):])
+
+		s := strings.Index(spec, "/")
+		if s < 0 {
+			fmt.Printf("plug '%s' ignoring malformed plug spec '%s'\n", p.identifier, spec)
+			continue
 		}
 
-		if p.trace {
-			fmt.Printf("plug '%s' seek [%s] [%s] key:'%s' prefix:'%s' askedPathPart:'%s'\n", p.identifier, method, path, walker.Key(), prefix, askedPathPart)
+		method := spec[:s]
+		path := spec[s+1:]
+
+		root, ok := routes[method]
+		if !ok {
+			root = newRouteNode()
+			routes[method] = root
 		}
 
-		if strings.HasPrefix(string(walker.Key()), prefix+"/*") {
-			currentKey := walker.Key()
-			partName := currentKey[len(prefix)+2:]
+		root.insert(splitPath(path), dup(iter.Value()))
+	}
+	iter.Release()
 
-			// we have a plug that consumes the whole path
-			if p.trace {
-				fmt.Printf("plug '%s' seek we have partName:'%s' = partValue:'%s'\n", p.identifier, partName, path[1:])
-			}
-			boundParameters[partName] = path[1:]
-
-			// fucked up surely
-			prefix = walker.Key()
-			path = ""
-		} else if strings.HasPrefix(string(walker.Key()), prefix+"/!") {
-			currentKey := walker.Key()
-			partName := currentKey[len(prefix)+2:]
-			nextPrefix := currentKey
-			nextPartInKey := strings.Index(currentKey[len(prefix)+1:], "/")
-			if nextPartInKey >= 0 {
-				nextPrefix = currentKey[:nextPartInKey]
-				partName = currentKey[len(prefix)+2 : nextPartInKey]
-			}
+	err := iter.Error()
+	if err != nil {
+		return err
+	}
 
-			partValue := askedPathPart[1:]
-			if len(partValue) == 0 {
-				fmt.Printf("CANNOT HAVE AN EMPTY PATH PART VALUE\n")
-				return false, nil, nil
-			}
+	p.routesLock.Lock()
+	p.routes = routes
+	p.routesLock.Unlock()
 
-			prefix = nextPrefix
-			path = path[len(askedPathPart):]
+	return nil
+}
 
-			// we have a plug that consumes the path part
-			if p.trace {
-				fmt.Printf("plug '%s' seek we have partName:'%s' = partValue:'%s'\n", p.identifier, partName, partValue)
-			}
-			boundParameters[partName] = partValue
-		} else { // general case
-			prefix = prefix + askedPathPart
-			path = path[len(askedPathPart):]
-
-			ok := walker.Seek(prefix)
-			if !ok {
-				fmt.Printf("plug '%s' seek cannot seek to prefix:'%s'\n", p.identifier, prefix)
-				return false, nil, nil
-			}
-		}
+func (p *PlugSystem) findPlug(method string, path string) (bool, []byte, map[string]string) {
+	if p.trace {
+		fmt.Printf("START findPlug '%s' '%s'\n", method, path)
 	}
 
-	if p.trace {
-		fmt.Printf("plug '%s' seek (%s) [%s] '%s' '%s' '%s'\n", p.identifier, method, path, walker.Key(), prefix, path)
+	p.routesLock.RLock()
+	root, ok := p.routes[method]
+	p.routesLock.RUnlock()
+
+	if !ok {
+		if p.trace {
+			fmt.Printf("plug '%s' no plugs registered for method %s\n", p.identifier, method)
+		}
+		return false, nil, nil
 	}
 
-	if prefix != walker.Key() {
-		fmt.Printf("'%s' PATH NOT MATCHING RESIDUAL KEY '%s'/'%s'\n", p.identifier, prefix, walker.Key())
+	boundParameters := make(map[string]string)
+
+	data, ok := root.match(splitPath(path), boundParameters)
+	if !ok {
+		if p.trace {
+			fmt.Printf("plug '%s' no plug matching path '%s'\n", p.identifier, path)
+		}
 		return false, nil, nil
 	}
 
 	if p.trace {
-		fmt.Printf("plugged '%s' path '%s' matched with '%s'\n", p.identifier, walker.Key(), originalPath)
+		fmt.Printf("plugged '%s' path matched with '%s' parameters:%v\n", p.identifier, path, boundParameters)
 	}
 
-	return true, walker.it.Value(), boundParameters
+	return true, data, boundParameters
 }
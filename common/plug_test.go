@@ -0,0 +1,147 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+func newTestDB(t *testing.T) *leveldb.DB {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatalf("cannot open database (%v)", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestPlugSystemRouting(t *testing.T) {
+	plugs := []struct {
+		method string
+		path   string
+		data   string
+	}{
+		{"GET", "/", "root"},
+		{"GET", "/users", "users"},
+		{"GET", "/users/me", "me"},
+		{"GET", "/users/!id", "user"},
+		{"GET", "/users/!id/posts", "user-posts"},
+		{"GET", "/users/*rest", "users-rest"},
+		{"GET", "/files/*path", "files"},
+		{"GET", "/a/!x/c", "a-x-c"},
+		{"GET", "/a/b/!y", "a-b-y"},
+		{"GET", "/p/b/z", "p-b-z"},
+		{"GET", "/p/!x/c", "p-x-c"},
+		{"POST", "/users", "create-user"},
+		{"GET", "/removed", "removed"},
+	}
+
+	unplugs := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/removed"},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		found      bool
+		data       string
+		parameters map[string]string
+	}{
+		{"root", "get", "/", true, "root", map[string]string{}},
+		{"literal", "get", "/users", true, "users", map[string]string{}},
+		{"literal over parameter", "get", "/users/me", true, "me", map[string]string{}},
+		{"parameter", "get", "/users/42", true, "user", map[string]string{"id": "42"}},
+		{"parameter and literal", "get", "/users/42/posts", true, "user-posts", map[string]string{"id": "42"}},
+		{"wildcard when the parameter branch fails", "get", "/users/42/friends", true, "users-rest", map[string]string{"rest": "42/friends"}},
+		{"wildcard", "get", "/files/a/b/c.txt", true, "files", map[string]string{"path": "a/b/c.txt"}},
+		{"empty wildcard", "get", "/files/", true, "files", map[string]string{"path": ""}},
+		{"parameter is not empty", "get", "/users//posts", true, "users-rest", map[string]string{"rest": "/posts"}},
+		{"literal then parameter", "get", "/a/b/c", true, "a-b-y", map[string]string{"y": "c"}},
+		{"parameter then literal", "get", "/a/z/c", true, "a-x-c", map[string]string{"x": "z"}},
+		{"backtrack from literal to parameter", "get", "/p/b/c", true, "p-x-c", map[string]string{"x": "b"}},
+		{"method", "post", "/users", true, "create-user", map[string]string{}},
+		{"unknown method", "delete", "/users", false, "", nil},
+		{"unknown path", "get", "/unknown", false, "", nil},
+		{"unplugged", "get", "/removed", false, "", nil},
+	}
+
+	p, err := NewPlugSystem(newTestDB(t), "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, plug := range plugs {
+		err = p.PlugPath(plug.method, plug.path, []byte(plug.data))
+		if err != nil {
+			t.Fatalf("cannot plug %s %s (%v)", plug.method, plug.path, err)
+		}
+	}
+
+	for _, unplug := range unplugs {
+		err = p.UnplugPath(unplug.method, unplug.path)
+		if err != nil {
+			t.Fatalf("cannot unplug %s %s (%v)", unplug.method, unplug.path, err)
+		}
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found, data, parameters := p.findPlug(test.method, test.path)
+			if found != test.found {
+				t.Fatalf("found %v, expected %v", found, test.found)
+			}
+			if !found {
+				return
+			}
+			if string(data) != test.data {
+				t.Errorf("matched '%s', expected '%s'", string(data), test.data)
+			}
+			if !reflect.DeepEqual(parameters, test.parameters) {
+				t.Errorf("bound %v, expected %v", parameters, test.parameters)
+			}
+		})
+	}
+}
+
+func TestPlugSystemReload(t *testing.T) {
+	db := newTestDB(t)
+
+	p, err := NewPlugSystem(db, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.PlugPath("GET", "/hello/!name", []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// plugs are reloaded from the database
+	reloaded, err := NewPlugSystem(db, "test", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, data, parameters := reloaded.findPlug("get", "/hello/world")
+	if !found || string(data) != "hello" || parameters["name"] != "world" {
+		t.Fatalf("unexpected match %v '%s' %v", found, string(data), parameters)
+	}
+
+	// plug systems with another identifier are separate
+	other, err := NewPlugSystem(db, "other", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, _, _ = other.findPlug("get", "/hello/world")
+	if found {
+		t.Fatal("plug found in another plug system")
+	}
+}
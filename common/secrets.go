@@ -0,0 +1,289 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Secrets
+
+	Secrets are values like api keys or passwords given to functions without being in their code.
+	They are encrypted with AES-256-GCM by a master key which is not in the database : it is given
+	by the MYOWNCLUSTER_MASTER_KEY environment variable (base64), or read from a key file created
+	at the first start. Without the master key, the secrets cannot be read.
+
+	Each secret lists the names of the functions allowed to read it ("*" for every function).
+	Functions read them with get_secret, or as environment variables in posix mode with the
+	"secrets_env" tag of their plug, like "DB_PASSWORD=db-password,API_KEY=api-key". Secrets are
+	managed by admins only, their values are never listed.
+*/
+
+const MasterKeyEnvironmentVariable = "MYOWNCLUSTER_MASTER_KEY"
+
+const masterKeySize = 32
+
+var secretsKeyPrefix = []byte("/secrets/")
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+var environmentVariablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var ErrNoMasterKey = errors.New("no master key, secrets are not available")
+
+type Secret struct {
+	Name      string   `json:"name"`
+	Functions []string `json:"functions"` // names of the functions which may read it, "*" for all
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+type storedSecret struct {
+	Secret
+	Nonce []byte `json:"nonce"`
+	Value []byte `json:"value"` // encrypted, with the name as additional data
+}
+
+// LoadMasterKey reads the master key from the environment, or from keyPath which is created when missing
+func LoadMasterKey(keyPath string) ([]byte, error) {
+	encoded, fromEnvironment := os.LookupEnv(MasterKeyEnvironmentVariable)
+
+	if !fromEnvironment {
+		content, err := ioutil.ReadFile(keyPath)
+		if os.IsNotExist(err) {
+			key := make([]byte, masterKeySize)
+			_, err = rand.Read(key)
+			if err != nil {
+				return nil, err
+			}
+
+			err = ioutil.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+			if err != nil {
+				return nil, fmt.Errorf("cannot write the master key file (%v)", err)
+			}
+
+			fmt.Printf("created a master key for the secrets in '%s', keep it safe : the secrets cannot be read without it\n", keyPath)
+
+			return key, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the master key file (%v)", err)
+		}
+
+		encoded = string(content)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != masterKeySize {
+		return nil, fmt.Errorf("the master key should be %d bytes encoded in base64", masterKeySize)
+	}
+
+	return key, nil
+}
+
+// SetMasterKey sets the key encrypting the secrets
+func (o *Orchestrator) SetMasterKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	o.secretsCipher = aead
+
+	return nil
+}
+
+func secretKey(name string) []byte {
+	return append(dup(secretsKeyPrefix), []byte(name)...)
+}
+
+// SetSecret creates or replaces a secret
+func (o *Orchestrator) SetSecret(name string, value []byte, functions []string) error {
+	if o.secretsCipher == nil {
+		return ErrNoMasterKey
+	}
+
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name '%s', should only have letters, digits, '_', '.' and '-'", name)
+	}
+
+	cleanFunctions := []string{}
+	for _, function := range functions {
+		function = strings.TrimSpace(function)
+		if function != "" {
+			cleanFunctions = append(cleanFunctions, function)
+		}
+	}
+	if len(cleanFunctions) == 0 {
+		return fmt.Errorf("secret '%s' needs the functions which may read it ('*' for all)", name)
+	}
+
+	now := time.Now().Unix()
+	stored := &storedSecret{
+		Secret: Secret{
+			Name:      name,
+			Functions: cleanFunctions,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Nonce: make([]byte, o.secretsCipher.NonceSize()),
+	}
+
+	if previous, err := o.getStoredSecret(name); err == nil {
+		stored.CreatedAt = previous.CreatedAt
+	}
+
+	_, err := rand.Read(stored.Nonce)
+	if err != nil {
+		return err
+	}
+	stored.Value = o.secretsCipher.Seal(nil, stored.Nonce, value, []byte(name))
+
+	storedJSON, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return o.db.Put(secretKey(name), storedJSON, &opt.WriteOptions{Sync: true})
+}
+
+func (o *Orchestrator) DeleteSecret(name string) error {
+	_, err := o.getStoredSecret(name)
+	if err != nil {
+		return err
+	}
+
+	return o.db.Delete(secretKey(name), &opt.WriteOptions{Sync: true})
+}
+
+// GetSecrets returns the secrets, without their values
+func (o *Orchestrator) GetSecrets() []Secret {
+	r := make([]Secret, 0)
+
+	iter := o.db.NewIterator(util.BytesPrefix(secretsKeyPrefix), nil)
+	for iter.Next() {
+		stored := storedSecret{}
+		if json.Unmarshal(iter.Value(), &stored) == nil {
+			r = append(r, stored.Secret)
+		}
+	}
+	iter.Release()
+
+	sort.Slice(r, func(i, j int) bool { return r[i].Name < r[j].Name })
+
+	return r
+}
+
+func (o *Orchestrator) getStoredSecret(name string) (*storedSecret, error) {
+	storedJSON, err := o.db.Get(secretKey(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("no secret named '%s'", name)
+	}
+
+	stored := &storedSecret{}
+	err = json.Unmarshal(storedJSON, stored)
+	if err != nil {
+		return nil, fmt.Errorf("unreadable secret '%s' (%v)", name, err)
+	}
+
+	return stored, nil
+}
+
+func (s *Secret) allows(function string) bool {
+	for _, allowed := range s.Functions {
+		if allowed == "*" || allowed == function {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetSecret returns the value of a secret, nil when it does not exist. It fails with
+// ErrPermissionDenied when the function may not read it.
+func (fctx *FunctionExecutionContext) GetSecret(name string) ([]byte, error) {
+	o := fctx.Orchestrator
+	if o.secretsCipher == nil {
+		return nil, ErrNoMasterKey
+	}
+
+	stored, err := o.getStoredSecret(name)
+	if err != nil {
+		return nil, nil
+	}
+
+	if !stored.allows(fctx.Name) {
+		return nil, fmt.Errorf("%w: function '%s' may not read the secret '%s'", ErrPermissionDenied, fctx.Name, name)
+	}
+
+	value, err := o.secretsCipher.Open(nil, stored.Nonce, stored.Value, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the secret '%s', the master key may have changed", name)
+	}
+
+	return value, nil
+}
+
+// ParseSecretsEnvironment reads the "secrets_env" tag of a plug, environment variable => secret name
+func ParseSecretsEnvironment(spec string) (map[string]string, error) {
+	r := make(map[string]string)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		equal := strings.Index(entry, "=")
+		if equal < 0 || !environmentVariablePattern.MatchString(entry[:equal]) || !secretNamePattern.MatchString(entry[equal+1:]) {
+			return nil, fmt.Errorf("invalid secret environment variable '%s', should be VARIABLE=SECRET_NAME", entry)
+		}
+
+		r[entry[:equal]] = entry[equal+1:]
+	}
+
+	return r, nil
+}
+
+// SecretsEnvironment returns the environment variables of a posix execution, as "NAME=VALUE", from
+// a secrets_env specification
+func (fctx *FunctionExecutionContext) SecretsEnvironment(variables map[string]string) ([]string, error) {
+	names := make([]string, 0, len(variables))
+	for variable := range variables {
+		names = append(names, variable)
+	}
+	sort.Strings(names)
+
+	environment := []string{}
+	for _, variable := range names {
+		value, err := fctx.GetSecret(variables[variable])
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, fmt.Errorf("no secret named '%s' for the environment variable '%s'", variables[variable], variable)
+		}
+
+		environment = append(environment, variable+"="+string(value))
+	}
+
+	return environment, nil
+}
@@ -0,0 +1,159 @@
+package common
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestMasterKey(t *testing.T) []byte {
+	key := make([]byte, masterKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestSecretsRoundTrip(t *testing.T) {
+	o := newTestOrchestrator(t)
+
+	if err := o.SetSecret("db-password", []byte("s3cr3t"), []string{"*"}); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("a secret was set without master key (%v)", err)
+	}
+
+	err := o.SetMasterKey(newTestMasterKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = o.SetSecret("db-password", []byte("s3cr3t"), []string{"shop/api.wasm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the value is encrypted at rest
+	storedJSON, err := o.db.Get(secretKey("db-password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(storedJSON, []byte("s3cr3t")) {
+		t.Fatalf("the secret is stored in clear : %s", storedJSON)
+	}
+
+	fctx := &FunctionExecutionContext{Orchestrator: o, Name: "shop/api.wasm"}
+	value, err := fctx.GetSecret("db-password")
+	if err != nil || string(value) != "s3cr3t" {
+		t.Fatalf("the secret is %q (%v)", value, err)
+	}
+
+	// the nonce changes when the secret is replaced
+	stored, _ := o.getStoredSecret("db-password")
+	err = o.SetSecret("db-password", []byte("n3w"), []string{"shop/api.wasm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced, _ := o.getStoredSecret("db-password")
+	if bytes.Equal(stored.Nonce, replaced.Nonce) || replaced.CreatedAt != stored.CreatedAt {
+		t.Fatalf("the replaced secret is %+v, it was %+v", replaced, stored)
+	}
+	if value, _ := fctx.GetSecret("db-password"); string(value) != "n3w" {
+		t.Fatalf("the replaced secret is %q", value)
+	}
+
+	// the name is authenticated with the value : a value moved to another secret cannot be read
+	err = o.SetSecret("api-key", []byte("key"), []string{"*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, _ := o.getStoredSecret("api-key")
+	moved.Nonce, moved.Value = replaced.Nonce, replaced.Value
+	movedJSON, _ := json.Marshal(moved)
+	o.db.Put(secretKey("api-key"), movedJSON, nil)
+	if _, err := fctx.GetSecret("api-key"); err == nil {
+		t.Fatal("the value of a secret was read under another name")
+	}
+
+	// another master key cannot read the secrets
+	err = o.SetMasterKey(newTestMasterKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fctx.GetSecret("db-password"); err == nil {
+		t.Fatal("the secret was read with another master key")
+	}
+
+	if err := o.SetMasterKey([]byte("short")); err == nil {
+		t.Fatal("an invalid master key was accepted")
+	}
+}
+
+func TestSecretsFunctionScoping(t *testing.T) {
+	o := newTestOrchestrator(t)
+	err := o.SetMasterKey(newTestMasterKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o.SetSecret("db-password", []byte("db"), []string{"shop/api.wasm", "shop/admin.wasm"})
+	o.SetSecret("public-key", []byte("public"), []string{"*"})
+
+	tests := []struct {
+		function string
+		secret   string
+		value    string
+		denied   bool
+	}{
+		{"shop/api.wasm", "db-password", "db", false},
+		{"shop/admin.wasm", "db-password", "db", false},
+		{"blog/api.wasm", "db-password", "", true},
+		{"shop/api", "db-password", "", true},
+		{"blog/api.wasm", "public-key", "public", false},
+		{"blog/api.wasm", "missing", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.function+"/"+test.secret, func(t *testing.T) {
+			fctx := &FunctionExecutionContext{Orchestrator: o, Name: test.function}
+
+			value, err := fctx.GetSecret(test.secret)
+			if test.denied {
+				if !errors.Is(err, ErrPermissionDenied) || value != nil {
+					t.Fatalf("'%s' read '%s' : %q (%v)", test.function, test.secret, value, err)
+				}
+				return
+			}
+			if err != nil || string(value) != test.value {
+				t.Fatalf("'%s' read '%s' : %q (%v), expected %q", test.function, test.secret, value, err, test.value)
+			}
+		})
+	}
+
+	// the environment of a posix execution follows the same scoping
+	variables, err := ParseSecretsEnvironment("DB_PASSWORD=db-password, PUBLIC_KEY=public-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	environment, err := (&FunctionExecutionContext{Orchestrator: o, Name: "shop/api.wasm"}).SecretsEnvironment(variables)
+	if err != nil || !reflect.DeepEqual(environment, []string{"DB_PASSWORD=db", "PUBLIC_KEY=public"}) {
+		t.Fatalf("the environment is %v (%v)", environment, err)
+	}
+	_, err = (&FunctionExecutionContext{Orchestrator: o, Name: "blog/api.wasm"}).SecretsEnvironment(variables)
+	if !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("the environment of another function gives %v", err)
+	}
+
+	// secrets are listed without their values
+	secrets := o.GetSecrets()
+	if len(secrets) != 2 || secrets[0].Name != "db-password" || !reflect.DeepEqual(secrets[0].Functions, []string{"shop/api.wasm", "shop/admin.wasm"}) {
+		t.Fatalf("the secrets are listed as %+v", secrets)
+	}
+	if err := o.SetSecret("unscoped", []byte("x"), []string{" "}); err == nil {
+		t.Fatal("a secret without functions was set")
+	}
+}
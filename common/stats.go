@@ -5,6 +5,13 @@ type StatName string
 var STAT_NB_CREATED_BUFFERS StatName = "nb_created_buffers"
 var STAT_NB_REQUESTS_RECEIVED StatName = "nb_received_request"
 var STAT_NB_CURRENT_BUFFERS StatName = "nb_current_buffers"
+var STAT_NB_RELEASED_BUFFERS StatName = "nb_released_buffers"
+var STAT_NB_LIMITED_EXECUTIONS StatName = "nb_limited_executions"
+var STAT_NB_DETACHED_EXECUTIONS StatName = "nb_detached_executions"
+var STAT_NB_FAILED_EXECUTIONS StatName = "nb_failed_executions"
+var STAT_NB_DENIED_EGRESS StatName = "nb_denied_egress"
+var STAT_NB_PERSISTENCE_CONFLICTS StatName = "nb_persistence_conflicts"
+var STAT_NB_PERSISTENCE_EXPIRED_KEYS StatName = "nb_persistence_expired_keys"
 
 func (o *Orchestrator) StatIncrement(name StatName) {
 	o.statsLock.Lock()
@@ -0,0 +1,345 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Guest domains
+
+	A guest function becomes the handler of a domain with register_domain_handler, stored under
+	'/versionning/handlers/<domain>'. The orchestrator then migrates the domain in the background,
+	calling the GetModernVersion and MigrateData functions of the handler, see versionning.go.
+
+	Each call of the handler is stopped after guestMigrationTimeout, so that a MigrateData which
+	does not return does not keep its domain held. The domains a function may hold are the ones
+	handled by a function of its blob application (see blob.go), the built-in domains are only
+	held by admins.
+*/
+
+var versionningHandlersPrefix = []byte("/versionning/handlers/")
+
+type DomainInfo struct {
+	*DomainState
+	Handler string `json:"handler"` // function handling the domain, "built-in" for the ones of the cluster
+}
+
+const builtinDomainHandler = "built-in"
+
+// maximum duration of a call to the handler of a guest domain
+const guestMigrationTimeout = 60 * time.Second
+
+// maximum time a function without deadline waits for a domain held by another one
+const maxDomainHoldWait = 30 * time.Second
+
+// guestMigrator calls the migration functions of a guest function
+type guestMigrator struct {
+	o        *Orchestrator
+	domain   string
+	function string
+}
+
+func (m *guestMigrator) run(startFunction string, targetVersion int) (int, error) {
+	inputExchangeBufferID := m.o.CreateExchangeBuffer()
+	outputExchangeBufferID := m.o.CreateExchangeBuffer()
+	defer m.o.ReleaseExchangeBuffer(inputExchangeBufferID)
+	defer m.o.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+	input := m.o.GetExchangeBuffer(inputExchangeBufferID)
+	input.SetHeader("x-moc-domain", m.domain)
+	input.SetHeader("x-moc-target-version", strconv.Itoa(targetVersion))
+
+	fctx := m.o.NewFunctionExecutionContext(
+		m.function,
+		startFunction,
+		[]int{targetVersion},
+		m.o.trace,
+		"direct",
+		nil,
+		nil,
+		inputExchangeBufferID,
+		outputExchangeBufferID,
+	)
+	fctx.SetLimits(ExecutionLimits{Timeout: guestMigrationTimeout})
+	fctx.inheritedDomains = map[string]bool{m.domain: true}
+
+	err := fctx.Run()
+	if err != nil {
+		return 0, err
+	}
+
+	return fctx.Result, nil
+}
+
+func (m *guestMigrator) GetModernVersion() (int, error) {
+	version, err := m.run("GetModernVersion", 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if version < initialDomainVersion {
+		return 0, fmt.Errorf("invalid modern version %d", version)
+	}
+
+	return version, nil
+}
+
+func (m *guestMigrator) MigrateData(targetVersion int) (bool, error) {
+	result, err := m.run("MigrateData", targetVersion)
+	if err != nil {
+		return false, err
+	}
+
+	if result < 0 {
+		return false, fmt.Errorf("MigrateData failed with %d", result)
+	}
+
+	return result > 0, nil
+}
+
+func domainHandlerKey(domain string) []byte {
+	return append(dup(versionningHandlersPrefix), []byte(url.PathEscape(domain))...)
+}
+
+func (o *Orchestrator) SetVersionning(versionning *Versionning) {
+	o.versionning = versionning
+}
+
+func (o *Orchestrator) getVersionning() (*Versionning, error) {
+	if o.versionning == nil {
+		return nil, fmt.Errorf("no data versionning")
+	}
+
+	return o.versionning, nil
+}
+
+// RegisterGuestDomainHandler makes a function the handler of a domain and migrates it in the background
+func (o *Orchestrator) RegisterGuestDomainHandler(domain string, function string) error {
+	v, err := o.getVersionning()
+	if err != nil {
+		return err
+	}
+
+	if domain == "" {
+		return fmt.Errorf("invalid empty domain")
+	}
+
+	if migrator := v.getDomainHandler(domain); migrator != nil {
+		if _, ok := migrator.(*guestMigrator); !ok {
+			return fmt.Errorf("domain '%s' has a %s handler", domain, builtinDomainHandler)
+		}
+	}
+
+	_, err = o.GetBlobTechIDFromReference(function)
+	if err != nil {
+		return fmt.Errorf("unknown function '%s'", function)
+	}
+
+	err = o.db.Put(domainHandlerKey(domain), []byte(function), &opt.WriteOptions{Sync: true})
+	if err != nil {
+		return err
+	}
+
+	v.RegisterDomainHandler(domain, &guestMigrator{o, domain, function})
+
+	go o.migrateGuestDomain(domain)
+
+	return nil
+}
+
+func (o *Orchestrator) migrateGuestDomain(domain string) {
+	err := o.versionning.MigrateDomain(domain)
+	if err != nil {
+		fmt.Printf("[error] %v\n", err)
+	}
+}
+
+// MigrateGuestDomains registers the stored guest handlers and migrates their domains in the background
+func (o *Orchestrator) MigrateGuestDomains() error {
+	v, err := o.getVersionning()
+	if err != nil {
+		return err
+	}
+
+	domains := make([]string, 0)
+
+	iter := o.db.NewIterator(util.BytesPrefix(versionningHandlersPrefix), nil)
+	for iter.Next() {
+		domain, err := url.PathUnescape(string(iter.Key()[len(versionningHandlersPrefix):]))
+		if err != nil {
+			continue
+		}
+
+		v.RegisterDomainHandler(domain, &guestMigrator{o, domain, string(iter.Value())})
+		domains = append(domains, domain)
+	}
+	iter.Release()
+
+	err = iter.Error()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for _, domain := range domains {
+			o.migrateGuestDomain(domain)
+		}
+	}()
+
+	return nil
+}
+
+// GetDomains returns the versionned domains with their handler
+func (o *Orchestrator) GetDomains() ([]*DomainInfo, error) {
+	v, err := o.getVersionning()
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := v.GetDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]*DomainInfo, 0, len(states))
+	for _, state := range states {
+		info := &DomainInfo{DomainState: state}
+		switch migrator := v.getDomainHandler(state.Domain).(type) {
+		case nil:
+		case *guestMigrator:
+			info.Handler = migrator.function
+		default:
+			info.Handler = builtinDomainHandler
+		}
+		r = append(r, info)
+	}
+
+	return r, nil
+}
+
+func (fctx *FunctionExecutionContext) GetDomainWriteVersion(domain string) (int, error) {
+	v, err := fctx.Orchestrator.getVersionning()
+	if err != nil {
+		return 0, err
+	}
+
+	return v.GetDomainWriteVersion(domain)
+}
+
+func (fctx *FunctionExecutionContext) IsDomainBackwardCompatible(domain string) (bool, error) {
+	v, err := fctx.Orchestrator.getVersionning()
+	if err != nil {
+		return false, err
+	}
+
+	return v.IsDomainBackwardCompatible(domain)
+}
+
+// CheckHoldDomain fails with ErrPermissionDenied when the function may not hold a domain
+func (fctx *FunctionExecutionContext) CheckHoldDomain(domain string) error {
+	if fctx.Credential != nil && RoleAllows(fctx.Credential.Role, ROLE_ADMIN) {
+		return nil
+	}
+
+	v, err := fctx.Orchestrator.getVersionning()
+	if err != nil {
+		return err
+	}
+
+	switch migrator := v.getDomainHandler(domain).(type) {
+	case nil:
+		return fmt.Errorf("%w: domain '%s' has no handler", ErrPermissionDenied, domain)
+	case *guestMigrator:
+		if blobApplication(migrator.function) != blobApplication(fctx.Name) {
+			return fmt.Errorf("%w: domain '%s' is handled by '%s', not by a function of '%s'", ErrPermissionDenied, domain, migrator.function, blobApplication(fctx.Name))
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: domain '%s' has a %s handler", ErrPermissionDenied, domain, builtinDomainHandler)
+	}
+}
+
+// InheritDomains gives a called function the domains held by its caller, which it then does not
+// wait for
+func (fctx *FunctionExecutionContext) InheritDomains(caller *FunctionExecutionContext) {
+	if len(caller.inheritedDomains) == 0 && len(caller.heldDomains) == 0 {
+		return
+	}
+
+	fctx.inheritedDomains = make(map[string]bool)
+	for domain := range caller.inheritedDomains {
+		fctx.inheritedDomains[domain] = true
+	}
+	for domain := range caller.heldDomains {
+		fctx.inheritedDomains[domain] = true
+	}
+}
+
+// HoldDomain holds a domain until UnholdDomain or the end of the function, waiting for it until the
+// deadline of the function. The MigrateData function of a handler already holds its domain.
+func (fctx *FunctionExecutionContext) HoldDomain(domain string) error {
+	v, err := fctx.Orchestrator.getVersionning()
+	if err != nil {
+		return err
+	}
+
+	if fctx.inheritedDomains[domain] {
+		return nil
+	}
+
+	if _, ok := fctx.heldDomains[domain]; ok {
+		return fmt.Errorf("domain '%s' is already held", domain)
+	}
+
+	err = fctx.CheckHoldDomain(domain)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(maxDomainHoldWait)
+	if !fctx.Deadline.IsZero() && fctx.Deadline.Before(deadline) {
+		deadline = fctx.Deadline
+	}
+
+	unholder, err := v.HoldUntil(domain, deadline)
+	if err != nil {
+		return err
+	}
+
+	if fctx.heldDomains == nil {
+		fctx.heldDomains = make(map[string]*Unholder)
+	}
+	fctx.heldDomains[domain] = unholder
+
+	return nil
+}
+
+func (fctx *FunctionExecutionContext) UnholdDomain(domain string) error {
+	if fctx.inheritedDomains[domain] {
+		return nil
+	}
+
+	unholder, ok := fctx.heldDomains[domain]
+	if !ok {
+		return fmt.Errorf("domain '%s' is not held", domain)
+	}
+
+	delete(fctx.heldDomains, domain)
+	unholder.Unhold()
+
+	return nil
+}
+
+// releaseHeldDomains releases the domains the function did not unhold
+func (fctx *FunctionExecutionContext) releaseHeldDomains() {
+	for domain, unholder := range fctx.heldDomains {
+		delete(fctx.heldDomains, domain)
+		unholder.Unhold()
+	}
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Persistence domain
+
+	Version 1 had a single keyspace, the '/persistence' keys, now the "shared" namespace.
+	Version 2 has the namespaces of persistence.go, where a function uses the namespace named
+	after its blob by default. So that the functions plugged before keep their data, the migration
+	gives the "shared" namespace to the function plugs without "namespace" tag. It is done only
+	when there are keys in the shared namespace : without data, the plugs take their default
+	namespace.
+
+	Functions called with call_function and filters have no plug to tag : they reach the shared
+	namespace after a grant and persistence_use_namespace.
+
+	The plugs domain is migrated first, the plugs being read at their version 2 keys.
+*/
+
+const PersistenceDomain = "persistence"
+
+var functionPlugsPrefix = []byte("/plug_system/plugs/byspec/")
+
+// number of plugs tagged by a MigrateData call
+const persistenceMigrationBatchSize = 100
+
+type persistenceMigrator struct {
+	db    *leveldb.DB
+	trace bool
+}
+
+func NewPersistenceMigrator(db *leveldb.DB, trace bool) DomainMigrator {
+	return &persistenceMigrator{db, trace}
+}
+
+func (m *persistenceMigrator) GetModernVersion() (int, error) {
+	return 2, nil
+}
+
+func (m *persistenceMigrator) MigrateData(targetVersion int) (bool, error) {
+	if targetVersion != 2 {
+		return false, fmt.Errorf("unknown persistence version %d", targetVersion)
+	}
+
+	iter := m.db.NewIterator(util.BytesPrefix(persistencePrefix), nil)
+	hasSharedData := iter.Next()
+	iter.Release()
+	if !hasSharedData {
+		return false, iter.Error()
+	}
+
+	batch := new(leveldb.Batch)
+	tagged := 0
+
+	iter = m.db.NewIterator(util.BytesPrefix(functionPlugsPrefix), nil)
+	for tagged < persistenceMigrationBatchSize && iter.Next() {
+		plug := make(map[string]interface{})
+		err := json.Unmarshal(iter.Value(), &plug)
+		if err != nil || plug["type"] != "function" {
+			continue
+		}
+
+		tags, _ := plug["tags"].(map[string]interface{})
+		if tags == nil {
+			tags = make(map[string]interface{})
+		}
+		if _, ok := tags["namespace"]; ok {
+			continue
+		}
+
+		tags["namespace"] = SharedNamespace
+		plug["tags"] = tags
+
+		plugJSON, err := json.Marshal(plug)
+		if err != nil {
+			iter.Release()
+			return false, err
+		}
+		batch.Put(dup(iter.Key()), plugJSON)
+		tagged++
+
+		if m.trace {
+			fmt.Printf("plug '%s' keeps the shared persistence namespace\n", string(iter.Key()[len(functionPlugsPrefix):]))
+		}
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return false, err
+	}
+
+	if tagged == 0 {
+		return false, nil
+	}
+
+	return true, m.db.Write(batch, nil)
+}
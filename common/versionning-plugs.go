@@ -0,0 +1,89 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+/*
+	Plugs domain
+
+	Version 1 stored the plugs under '/plugs/byspec/<method>/<path>' keys, and marked the
+	database with a '/database-version' key once they were moved. Version 2 is the one of the
+	plug system, see plug.go.
+*/
+
+const PlugsDomain = "plugs"
+
+var legacyPlugsPrefix = []byte("/plugs/byspec/")
+var legacyDatabaseVersionKey = []byte("/database-version")
+
+// number of plugs moved by a MigrateData call
+const plugsMigrationBatchSize = 100
+
+type plugsMigrator struct {
+	db    *leveldb.DB
+	trace bool
+}
+
+func NewPlugsMigrator(db *leveldb.DB, trace bool) DomainMigrator {
+	return &plugsMigrator{db, trace}
+}
+
+func (m *plugsMigrator) GetModernVersion() (int, error) {
+	return 2, nil
+}
+
+func (m *plugsMigrator) MigrateData(targetVersion int) (bool, error) {
+	if targetVersion != 2 {
+		return false, fmt.Errorf("unknown plugs version %d", targetVersion)
+	}
+
+	batch := new(leveldb.Batch)
+	moved := 0
+
+	iter := m.db.NewIterator(util.BytesPrefix(legacyPlugsPrefix), nil)
+	for moved < plugsMigrationBatchSize && iter.Next() {
+		moved++
+
+		key := iter.Key()[len(legacyPlugsPrefix):]
+		value := iter.Value()
+
+		s := bytes.IndexByte(key, '/')
+		if s < 0 {
+			fmt.Printf("dropping invalid legacy plug key '%s'\n", string(iter.Key()))
+			batch.Delete(dup(iter.Key()))
+			continue
+		}
+		method := key[0:s]
+		path := key[s+1:]
+
+		newKey := []byte(fmt.Sprintf("/plug_system/plugs/byspec/%s/%s", method, path))
+		hasIt, _ := m.db.Has(newKey, nil)
+		if !hasIt {
+			batch.Put(newKey, dup(value))
+		}
+
+		if m.trace {
+			fmt.Printf("migrating plug %s %s to '%s' (already there: %v)\n", method, path, string(newKey), hasIt)
+		}
+
+		batch.Delete(dup(iter.Key()))
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return false, err
+	}
+
+	// the plugs are moved, the domain state replaces the database version marker
+	if moved == 0 {
+		return false, m.db.Delete(legacyDatabaseVersionKey, nil)
+	}
+
+	return true, m.db.Write(batch, nil)
+}
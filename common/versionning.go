@@ -1,20 +1,34 @@
 package common
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
 /*
 
 Provides a basic mechanism to help components do data migration easily
 
-reader
-writer
-domain
-version
-()
-Schemas are stored by "domain", which allows to segregate data migration between sub-components
+Schemas are stored by "domain", which allows to segregate data migration between sub-components.
+A domain without any migration has the version 1, the one of the data written before versionning.
 
 Vx-1 : fully transtionned to Vx-1 version, writes AND reads are done with the Vx-1 schema
 Tx   : transitionning to Vx version, writes are done with Vx schema, but reads are an aggregation of Vx and Vx-1 data. Meanwhile the migration process occurs...
 Vx   : fully transtionned to Vx version, writes AND reads are done with the Vx schema
 
+The state of a domain is stored in the database ('/versionning/domains/<domain>') with its version,
+the last one fully transitionned to, and its write version : they are equal in Vx, the write
+version is the next one in Tx. The runner stores each transition before going on, so that a
+migration stopped in Tx continues at the next start.
+
 
 
 Users provides those methods which are called by MyOwnCluster :
@@ -22,6 +36,15 @@ Users provides those methods which are called by MyOwnCluster :
 GetModernVersion(): number : the user code should return the best fitted most recent schema version. My Own Cluster will undertake a data migration if there is need for it
 MigrateData(x: target version):Status : the user code should pick one data in format x-1 and migrate it to format x. If there is no data to migrate, the user code should return NULL to signify that data migration to version 'x' is done for the domain.
 
+Built-in components implement DomainMigrator. Guest functions are registered as the handler of a
+domain with register_domain_handler, and export a 'GetModernVersion' function returning the version
+and a 'MigrateData' function returning 0 when there is no data left to migrate, a positive number
+otherwise and a negative one on failure. MigrateData reads the domain and the target version in the
+'x-moc-domain' and 'x-moc-target-version' headers of its input buffer. Guest domains are migrated in
+the background after the start and after their registration.
+
+Each MigrateData call runs with the domain held, a guest one being stopped after
+guestMigrationTimeout. The functions it calls with call_function run with the domain held too.
 
 
 
@@ -29,10 +52,15 @@ MyOwnCluster provides this API:
 
 RegisterDomainHandler(name: String) : registers a code for handling data migration of a domain
 GetDomainWriteVersion():String : returns the schema version the user code should use when storing a data. This is this version that is implicitely used as a reference in the IsDomainBackwardCompatible()
-IsDomainBackwardCompatible():boolean : returns true if the data is actual stored in both the domain's WRITE_VERSION and its predecessor, or if all data is at the domain's WRITE_VERSION
+IsDomainBackwardCompatible():boolean : returns true while the domain transitions, its data then being stored in both the domain's WRITE_VERSION and its predecessor. It returns false when all data is at the domain's WRITE_VERSION
 Hold(domain:String):Unholder : all read and writes to a domain should be contained between a Hold and Unhold() call. Acts like a critical section, so only one thread at a time. This is important to maintain database migrations' consistency.
 Unholder.Unhold():void : releases a domain
 
+Guest functions use get_domain_write_version, is_domain_backward_compatible, hold_domain and
+unhold_domain. The domains they hold are released when they finish. A function may only hold the
+domains handled by a function of its blob application, and waits for them until its deadline, at
+most maxDomainHoldWait.
+
 
 
 A user code should typically write data in the format indicated by `GetDomainWriteVersion()`.
@@ -40,3 +68,277 @@ For reads, the the user code should read data in the format indicated by `GetDom
 
 
 */
+
+// the version of a domain without any migration
+const initialDomainVersion = 1
+
+var versionningDomainsPrefix = []byte("/versionning/domains/")
+
+// DomainMigrator migrates the data of a domain, see the protocol above
+type DomainMigrator interface {
+	GetModernVersion() (int, error)
+
+	// MigrateData migrates data to the target version, and returns false when there is none left
+	MigrateData(targetVersion int) (bool, error)
+}
+
+type DomainState struct {
+	Domain       string `json:"domain"`
+	Version      int    `json:"version"`       // last version fully transitionned to
+	WriteVersion int    `json:"write_version"` // the next one while transitionning
+}
+
+// Transitionning tells if the domain is in the Tx state
+func (s *DomainState) Transitionning() bool {
+	return s.WriteVersion != s.Version
+}
+
+type Versionning struct {
+	db *leveldb.DB
+
+	lock      sync.Mutex
+	migrators map[string]DomainMigrator
+	holds     map[string]chan struct{} // one slot per domain, taken by its holder
+	running   map[string]bool          // domains being migrated
+}
+
+type Unholder struct {
+	hold chan struct{}
+}
+
+// Unhold releases the domain
+func (u *Unholder) Unhold() {
+	<-u.hold
+}
+
+func NewVersionning(db *leveldb.DB) *Versionning {
+	return &Versionning{
+		db:        db,
+		migrators: make(map[string]DomainMigrator),
+		holds:     make(map[string]chan struct{}),
+		running:   make(map[string]bool),
+	}
+}
+
+func domainStateKey(domain string) []byte {
+	return append(dup(versionningDomainsPrefix), []byte(url.PathEscape(domain))...)
+}
+
+// RegisterDomainHandler registers the migrator of a domain, replacing the previous one
+func (v *Versionning) RegisterDomainHandler(domain string, migrator DomainMigrator) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.migrators[domain] = migrator
+}
+
+func (v *Versionning) getDomainHandler(domain string) DomainMigrator {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.migrators[domain]
+}
+
+// GetDomainState returns the state of a domain, the initial one for a domain never migrated
+func (v *Versionning) GetDomainState(domain string) (*DomainState, error) {
+	state := &DomainState{Domain: domain, Version: initialDomainVersion, WriteVersion: initialDomainVersion}
+
+	stateBytes, err := v.db.Get(domainStateKey(domain), nil)
+	if err == leveldb.ErrNotFound {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(stateBytes, state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid state of domain '%s' (%v)", domain, err)
+	}
+
+	return state, nil
+}
+
+func (v *Versionning) storeDomainState(state *DomainState) error {
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return v.db.Put(domainStateKey(state.Domain), stateBytes, &opt.WriteOptions{Sync: true})
+}
+
+// GetDomainWriteVersion returns the schema version of the data written in a domain
+func (v *Versionning) GetDomainWriteVersion(domain string) (int, error) {
+	state, err := v.GetDomainState(domain)
+	if err != nil {
+		return 0, err
+	}
+
+	return state.WriteVersion, nil
+}
+
+// IsDomainBackwardCompatible tells if the reads of a domain should also merge the data of the
+// version preceding the write version
+func (v *Versionning) IsDomainBackwardCompatible(domain string) (bool, error) {
+	state, err := v.GetDomainState(domain)
+	if err != nil {
+		return false, err
+	}
+
+	return state.Transitionning(), nil
+}
+
+func (v *Versionning) domainHold(domain string) chan struct{} {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	hold, ok := v.holds[domain]
+	if !ok {
+		hold = make(chan struct{}, 1)
+		v.holds[domain] = hold
+	}
+
+	return hold
+}
+
+// Hold waits for the domain to be released and holds it until Unhold
+func (v *Versionning) Hold(domain string) *Unholder {
+	hold := v.domainHold(domain)
+	hold <- struct{}{}
+
+	return &Unholder{hold}
+}
+
+// HoldUntil is Hold, failing when the domain is still held by another at the deadline
+func (v *Versionning) HoldUntil(domain string, deadline time.Time) (*Unholder, error) {
+	hold := v.domainHold(domain)
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case hold <- struct{}{}:
+		return &Unholder{hold}, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("domain '%s' is still held by another function", domain)
+	}
+}
+
+// GetDomains returns the states of the domains which were migrated or have a handler
+func (v *Versionning) GetDomains() ([]*DomainState, error) {
+	states := make(map[string]*DomainState)
+
+	iter := v.db.NewIterator(util.BytesPrefix(versionningDomainsPrefix), nil)
+	for iter.Next() {
+		state := &DomainState{}
+		if json.Unmarshal(iter.Value(), state) == nil {
+			states[state.Domain] = state
+		}
+	}
+	iter.Release()
+
+	err := iter.Error()
+	if err != nil {
+		return nil, err
+	}
+
+	v.lock.Lock()
+	domains := make([]string, 0, len(v.migrators))
+	for domain := range v.migrators {
+		domains = append(domains, domain)
+	}
+	v.lock.Unlock()
+
+	for _, domain := range domains {
+		if _, ok := states[domain]; !ok {
+			states[domain], err = v.GetDomainState(domain)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	r := make([]*DomainState, 0, len(states))
+	for _, state := range states {
+		r = append(r, state)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Domain < r[j].Domain })
+
+	return r, nil
+}
+
+/*
+	MigrateDomain brings a domain to the modern version of its handler, one version after the
+	other : it stores the Tx state, calls MigrateData until there is no data left, then stores
+	the Vx state. A domain migrated meanwhile is left to the running migration.
+*/
+func (v *Versionning) MigrateDomain(domain string) error {
+	migrator := v.getDomainHandler(domain)
+	if migrator == nil {
+		return fmt.Errorf("no handler for domain '%s'", domain)
+	}
+
+	v.lock.Lock()
+	if v.running[domain] {
+		v.lock.Unlock()
+		return nil
+	}
+	v.running[domain] = true
+	v.lock.Unlock()
+
+	defer func() {
+		v.lock.Lock()
+		delete(v.running, domain)
+		v.lock.Unlock()
+	}()
+
+	modernVersion, err := migrator.GetModernVersion()
+	if err != nil {
+		return fmt.Errorf("cannot get the modern version of domain '%s' (%v)", domain, err)
+	}
+
+	state, err := v.GetDomainState(domain)
+	if err != nil {
+		return err
+	}
+
+	if modernVersion < state.WriteVersion {
+		return fmt.Errorf("domain '%s' is at version %d, newer than the modern version %d of its handler", domain, state.WriteVersion, modernVersion)
+	}
+
+	for state.Version < modernVersion {
+		if !state.Transitionning() {
+			state.WriteVersion = state.Version + 1
+			err = v.storeDomainState(state)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("migrating domain '%s' from version %d to %d\n", domain, state.Version, state.WriteVersion)
+
+		for {
+			unholder := v.Hold(domain)
+			more, err := migrator.MigrateData(state.WriteVersion)
+			unholder.Unhold()
+
+			if err != nil {
+				return fmt.Errorf("cannot migrate domain '%s' to version %d (%v)", domain, state.WriteVersion, err)
+			}
+			if !more {
+				break
+			}
+		}
+
+		state.Version = state.WriteVersion
+		err = v.storeDomainState(state)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("domain '%s' migrated to version %d\n", domain, state.Version)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package common
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testMigrator migrates remaining items one by one, failing at the failAt-th call of MigrateData
+type testMigrator struct {
+	modernVersion int
+	remaining     int
+	targets       []int
+	failAt        int
+}
+
+func (m *testMigrator) GetModernVersion() (int, error) {
+	return m.modernVersion, nil
+}
+
+func (m *testMigrator) MigrateData(targetVersion int) (bool, error) {
+	m.targets = append(m.targets, targetVersion)
+	if len(m.targets) == m.failAt {
+		return false, errors.New("interrupted")
+	}
+
+	if m.remaining == 0 {
+		return false, nil
+	}
+	m.remaining--
+
+	return true, nil
+}
+
+func TestMigrationResumesInTxState(t *testing.T) {
+	db := newTestDB(t)
+
+	interrupted := &testMigrator{modernVersion: 2, remaining: 5, failAt: 3}
+	v := NewVersionning(db)
+	v.RegisterDomainHandler("orders", interrupted)
+	if err := v.MigrateDomain("orders"); err == nil {
+		t.Fatal("the interrupted migration did not fail")
+	}
+
+	state, err := v.GetDomainState("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Transitionning() || state.Version != 1 || state.WriteVersion != 2 {
+		t.Fatalf("the interrupted domain is not in the T2 state (%+v)", state)
+	}
+
+	// the next start resumes the migration to version 2, without starting another one
+	resumed := &testMigrator{modernVersion: 2, remaining: interrupted.remaining}
+	v = NewVersionning(db)
+	v.RegisterDomainHandler("orders", resumed)
+	if err := v.MigrateDomain("orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range resumed.targets {
+		if target != 2 {
+			t.Fatalf("the resumed migration targets version %d", target)
+		}
+	}
+	if resumed.remaining != 0 || len(resumed.targets) != 4 {
+		t.Fatalf("the resumed migration called MigrateData %d times, %d items left", len(resumed.targets), resumed.remaining)
+	}
+
+	state, err = v.GetDomainState("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Transitionning() || state.Version != 2 {
+		t.Fatalf("the resumed domain is not in the V2 state (%+v)", state)
+	}
+}
+
+func TestHoldDomain(t *testing.T) {
+	o := newTestOrchestrator(t)
+	v := NewVersionning(o.db)
+	o.SetVersionning(v)
+	v.RegisterDomainHandler("orders", &guestMigrator{o, "orders", "shop/migrate-orders.wasm"})
+	v.RegisterDomainHandler(PlugsDomain, NewPlugsMigrator(o.db, false))
+
+	holder := &FunctionExecutionContext{Orchestrator: o, Name: "shop/api.wasm"}
+	if err := holder.HoldDomain("orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the functions of other applications and the built-in domains are refused
+	other := &FunctionExecutionContext{Orchestrator: o, Name: "blog/api.wasm"}
+	if err := other.HoldDomain("orders"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("a function of another application holds the domain (%v)", err)
+	}
+	if err := holder.HoldDomain(PlugsDomain); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("a function holds a built-in domain (%v)", err)
+	}
+	if err := holder.HoldDomain("unknown"); !errors.Is(err, ErrPermissionDenied) {
+		t.Fatalf("a function holds a domain without handler (%v)", err)
+	}
+
+	// another function waits for the domain until its deadline
+	waiting := &FunctionExecutionContext{Orchestrator: o, Name: "shop/worker.wasm", Deadline: time.Now().Add(50 * time.Millisecond)}
+	start := time.Now()
+	if err := waiting.HoldDomain("orders"); err == nil {
+		t.Fatal("the domain is held twice")
+	}
+	if waited := time.Since(start); waited < 40*time.Millisecond || waited > 5*time.Second {
+		t.Fatalf("waited %v for the domain", waited)
+	}
+
+	// a function called by the holder uses its domain
+	called := &FunctionExecutionContext{Orchestrator: o, Name: "shop/worker.wasm"}
+	called.InheritDomains(holder)
+	if err := called.HoldDomain("orders"); err != nil {
+		t.Fatal(err)
+	}
+	if err := called.UnholdDomain("orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	holder.releaseHeldDomains()
+
+	waiting.Deadline = time.Now().Add(time.Second)
+	if err := waiting.HoldDomain("orders"); err != nil {
+		t.Fatal(err)
+	}
+	waiting.releaseHeldDomains()
+}
+
+func TestPersistenceMigrationKeepsSharedNamespace(t *testing.T) {
+	plugFunctions := func(o *Orchestrator) {
+		for _, plug := range []struct{ path, tags string }{
+			{"/shop/api", `{}`},
+			{"/shop/limited", `{"timeout":"5s"}`},
+			{"/billing", `{"namespace":"billing"}`},
+		} {
+			if err := o.PlugFunction("GET", plug.path, "shop/api.wasm", "_start", "", plug.tags); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := o.PlugFile("GET", "/index.html", "index.html", `{}`); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migratedNamespaces := func(o *Orchestrator) map[string]string {
+		v := NewVersionning(o.db)
+		v.RegisterDomainHandler(PersistenceDomain, NewPersistenceMigrator(o.db, false))
+		if err := v.MigrateDomain(PersistenceDomain); err != nil {
+			t.Fatal(err)
+		}
+		if state, _ := v.GetDomainState(PersistenceDomain); state.Version != 2 || state.Transitionning() {
+			t.Fatalf("the persistence domain is in the state %+v", state)
+		}
+
+		// the plugs are read again, like at the next start
+		reloaded, err := NewOrchestrator(o.db, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		namespaces := make(map[string]string)
+		for _, path := range []string{"/shop/api", "/shop/limited", "/billing"} {
+			_, _, plug, _ := reloaded.GetPlugFromPath("GET", path)
+			namespaces[path] = plug.(*PluggedFunction).GetNamespace()
+		}
+		if _, _, plug, _ := reloaded.GetPlugFromPath("GET", "/index.html"); plug.(*PluggedFile).Tags["namespace"] != "" {
+			t.Fatal("a file plug was given a namespace")
+		}
+		if _, _, plug, _ := reloaded.GetPlugFromPath("GET", "/shop/limited"); plug.(*PluggedFunction).Tags["timeout"] != "5s" {
+			t.Fatal("the migration lost the other tags of a plug")
+		}
+
+		return namespaces
+	}
+
+	// the plugs of a cluster having data in the historical keyspace keep it
+	o := newTestOrchestrator(t)
+	plugFunctions(o)
+	o.PersistenceSet(SharedNamespace, []byte("/orders/1"), []byte("order"))
+
+	expected := map[string]string{"/shop/api": SharedNamespace, "/shop/limited": SharedNamespace, "/billing": "billing"}
+	if namespaces := migratedNamespaces(o); !reflect.DeepEqual(namespaces, expected) {
+		t.Fatalf("the migrated plugs have the namespaces %v", namespaces)
+	}
+	fctx := &FunctionExecutionContext{Orchestrator: o, Namespace: SharedNamespace}
+	if value, present, _ := fctx.PersistenceGet([]byte("/orders/1")); !present || string(value) != "order" {
+		t.Fatal("the data of the historical keyspace is not read by the migrated plugs")
+	}
+
+	// without such data, the plugs take the namespace of their blob
+	o = newTestOrchestrator(t)
+	plugFunctions(o)
+
+	expected = map[string]string{"/shop/api": "shop/api.wasm", "/shop/limited": "shop/api.wasm", "/billing": "billing"}
+	if namespaces := migratedNamespaces(o); !reflect.DeepEqual(namespaces, expected) {
+		t.Fatalf("the plugs of a cluster without shared data have the namespaces %v", namespaces)
+	}
+}
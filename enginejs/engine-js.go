@@ -1,7 +1,11 @@
 package enginejs
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/ltearno/my-own-cluster/common"
 
@@ -12,19 +16,56 @@ type JSProcessContext struct {
 	Fctx *common.FunctionExecutionContext
 
 	Context *duktape.Context
+
+	engine *JavascriptDuktapeEngine
 }
 
+// number of fresh heaps created in advance
+const heapPoolSize = 4
+
 type JavascriptDuktapeEngine struct {
+	// compiled scripts bytecode, by techID
+	bytecodeCache *common.Cache
+
+	// heap creation is the most expensive part of an execution, so fresh heaps are created
+	// in the background. Heaps are never reused, executions cannot see each other's globals.
+	// They count their allocations, so that the limits of each execution are applied to them.
+	heaps chan *duktape.Context
 }
 
 func NewJavascriptDuktapeEngine() *JavascriptDuktapeEngine {
-	return &JavascriptDuktapeEngine{}
+	e := &JavascriptDuktapeEngine{
+		bytecodeCache: common.NewCache("js-bytecode", common.DefaultCacheSize, nil),
+		heaps:         make(chan *duktape.Context, heapPoolSize),
+	}
+
+	go func() {
+		for {
+			e.heaps <- duktape.NewWithLimits()
+		}
+	}()
+
+	return e
+}
+
+func (e *JavascriptDuktapeEngine) newHeap() *duktape.Context {
+	select {
+	case heap := <-e.heaps:
+		return heap
+	default:
+		return duktape.NewWithLimits()
+	}
+}
+
+func (e *JavascriptDuktapeEngine) GetCaches() []*common.Cache {
+	return []*common.Cache{e.bytecodeCache}
 }
 
 func (e *JavascriptDuktapeEngine) PrepareContext(fctx *common.FunctionExecutionContext) (common.ExecutionEngineContext, error) {
 	ctx := &JSProcessContext{
 		Fctx:    fctx,
-		Context: duktape.New(),
+		Context: e.newHeap(),
+		engine:  e,
 	}
 
 	ctx.Context.PushGlobalObject()
@@ -40,6 +81,7 @@ func (e *JavascriptDuktapeEngine) PrepareContext(fctx *common.FunctionExecutionC
 		ctx.Context.PushObject()
 
 		apiProvider.BindToExecutionEngineContext(ctx)
+		ctx.denyForbiddenFunctions(module)
 
 		return 1
 	})
@@ -55,10 +97,27 @@ func (jsctx *JSProcessContext) Run() error {
 		fmt.Printf("- start run js function '%s'\n", jsctx.Fctx.StartFunction)
 	}
 
-	jsctx.Context.PushString(string(jsctx.Fctx.CodeBytes))
-	err := jsctx.Context.Peval()
+	// the script throws an error it cannot catch once it reaches a limit, so the calls always return
+	jsctx.Context.SetMemoryLimit(jsctx.Fctx.Limits.MaxMemory)
+	jsctx.Context.SetDeadline(jsctx.Fctx.Deadline)
+
+	defer func() {
+		jsctx.Context.DestroyHeap()
+
+		if jsctx.Fctx.Trace {
+			fmt.Printf("- heap destroyed, result:%d\n", jsctx.Fctx.Result)
+		}
+	}()
+
+	err := jsctx.pushProgram()
 	if err != nil {
-		return fmt.Errorf("cannot eval script, probably syntax error (%v)", err)
+		jsctx.checkHeapLimits()
+		return fmt.Errorf("cannot compile script, probably syntax error (%v)", err)
+	}
+
+	if jsctx.Context.Pcall(0) != 0 {
+		jsctx.checkHeapLimits()
+		return fmt.Errorf("cannot eval script (%s)", jsctx.Context.SafeToString(-1))
 	}
 
 	if jsctx.Fctx.Trace {
@@ -78,19 +137,146 @@ func (jsctx *JSProcessContext) Run() error {
 
 	res := jsctx.Context.Pcall(0)
 	if res != 0 {
-		return fmt.Errorf("error while executing js function '%s'", jsctx.Fctx.StartFunction)
+		// an exception thrown by the function is its trap, unless it was thrown by a limit
+		jsctx.checkHeapLimits()
+		jsctx.Fctx.FinishRunning(jsctx.exceptionExit())
+	} else {
+		if jsctx.Fctx.Trace {
+			fmt.Printf("- function executed\n")
+		}
+
+		jsctx.Fctx.FinishRunning(common.ReturnedExit(jsctx.Context.GetInt(-1)))
 	}
 
-	if jsctx.Fctx.Trace {
-		fmt.Printf("- function executed\n")
+	return nil
+}
+
+// checkHeapLimits records the limit which made the heap stop the script, if any
+func (jsctx *JSProcessContext) checkHeapLimits() {
+	if size := jsctx.Context.ExceededMemoryLimit(); size > 0 {
+		jsctx.Fctx.CheckMemory(size)
+	}
+
+	if !jsctx.Fctx.Deadline.IsZero() && time.Now().After(jsctx.Fctx.Deadline) {
+		jsctx.Fctx.CheckLimits()
+	}
+}
+
+// exceptionExit describes the exception on the top of the stack, with its stack trace when it is an error
+func (jsctx *JSProcessContext) exceptionExit() *common.ExecutionExit {
+	var backtrace []string
+	if jsctx.Context.IsObject(-1) {
+		jsctx.Context.GetPropString(-1, "stack")
+		if jsctx.Context.IsString(-1) {
+			for _, line := range strings.Split(jsctx.Context.GetString(-1), "\n")[1:] {
+				backtrace = append(backtrace, strings.TrimSpace(line))
+			}
+		}
+		jsctx.Context.Pop()
 	}
 
-	jsctx.Fctx.Result = jsctx.Context.GetInt(-1)
+	message := jsctx.Context.SafeToString(-1)
 
-	jsctx.Context.DestroyHeap()
+	return common.TrapExit(common.TRAP_EXCEPTION, message, backtrace)
+}
 
-	if jsctx.Fctx.Trace {
-		fmt.Printf("- heap destroyed, result:%d\n", jsctx.Fctx.Result)
+// BindAPIFunction sets a host api function on the api object on the top of the stack, the limits of the
+// execution are checked before each call
+func (jsctx *JSProcessContext) BindAPIFunction(name string, fn func(c *duktape.Context) int) {
+	jsctx.Context.PushGoFunction(func(c *duktape.Context) int {
+		if jsctx.Fctx.CheckLimits() != nil {
+			return duktape.ErrRetError
+		}
+
+		return fn(c)
+	})
+	jsctx.Context.PutPropString(-2, name)
+}
+
+// APIError gives the return of a host api function which failed : javascript gets undefined, except
+// when the function used an exchange buffer handle it does not hold or a function it may not use,
+// which stops it
+func (jsctx *JSProcessContext) APIError(err error) int {
+	if errors.Is(err, common.ErrExchangeBufferAccessDenied) || errors.Is(err, common.ErrPermissionDenied) {
+		jsctx.Fctx.SetExit(common.HostErrorExit(err))
+		return duktape.ErrRetError
+	}
+
+	return 0
+}
+
+// denyForbiddenFunctions replaces the functions of the api object on the top of the stack that the
+// function may not use by stubs stopping it
+func (jsctx *JSProcessContext) denyForbiddenFunctions(module string) {
+	forbidden := []string{}
+
+	jsctx.Context.Enum(-1, duktape.EnumOwnPropertiesOnly)
+	for jsctx.Context.Next(-1, false) {
+		name := jsctx.Context.SafeToString(-1)
+		if !jsctx.Fctx.Capabilities.Allows(module, apiFunctionName(name)) {
+			forbidden = append(forbidden, name)
+		}
+		jsctx.Context.Pop()
+	}
+	jsctx.Context.Pop()
+
+	for _, name := range forbidden {
+		err := jsctx.Fctx.CheckImport(module, apiFunctionName(name))
+		jsctx.Context.PushGoFunction(func(c *duktape.Context) int {
+			return jsctx.APIError(err)
+		})
+		jsctx.Context.PutPropString(-2, name)
+	}
+}
+
+// apiFunctionName gives the api name of a javascript binding, "readExchangeBuffer" is "read_exchange_buffer"
+func apiFunctionName(jsName string) string {
+	var name strings.Builder
+	for _, r := range jsName {
+		if unicode.IsUpper(r) {
+			name.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		name.WriteRune(r)
+	}
+
+	return name.String()
+}
+
+// pushProgram pushes the compiled script on the stack. The bytecode is kept in the
+// engine cache so that the script is compiled only once.
+func (jsctx *JSProcessContext) pushProgram() error {
+	cacheable := jsctx.engine != nil && jsctx.Fctx.TechID != ""
+
+	if cacheable {
+		if cached, ok := jsctx.engine.bytecodeCache.Get(jsctx.Fctx.TechID); ok {
+			bytecode := cached.([]byte)
+
+			ptr := jsctx.Context.PushBuffer(len(bytecode), false)
+			copy((*[1 << 30]byte)(ptr)[:len(bytecode):len(bytecode)], bytecode)
+			jsctx.Context.LoadFunction()
+
+			return nil
+		}
+	}
+
+	err := jsctx.Context.PcompileString(0, string(jsctx.Fctx.CodeBytes))
+	if err != nil {
+		return err
+	}
+
+	if cacheable {
+		jsctx.Context.DupTop()
+		jsctx.Context.DumpFunction()
+
+		ptr, size := jsctx.Context.GetBuffer(-1)
+		if ptr != nil {
+			bytecode := make([]byte, size)
+			copy(bytecode, (*[1 << 30]byte)(ptr)[:size:size])
+			jsctx.engine.bytecodeCache.Put(jsctx.Fctx.TechID, bytecode)
+		}
+
+		jsctx.Context.Pop()
 	}
 
 	return nil
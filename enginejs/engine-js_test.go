@@ -0,0 +1,113 @@
+package enginejs
+
+import (
+	"errors"
+	"io/ioutil"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+func runJS(t *testing.T, engine *JavascriptDuktapeEngine, fctx *common.FunctionExecutionContext) {
+	ectx, err := engine.PrepareContext(fctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ectx.Run()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the execution was not stopped")
+	}
+}
+
+// threadCount returns the number of threads of the process, or -1 when it is unknown
+func threadCount() int {
+	tasks, err := ioutil.ReadDir("/proc/self/task")
+	if err != nil {
+		return -1
+	}
+
+	return len(tasks)
+}
+
+func TestDeadlineUnwindsScript(t *testing.T) {
+	engine := NewJavascriptDuktapeEngine()
+
+	spin := func() *common.FunctionExecutionContext {
+		fctx := &common.FunctionExecutionContext{
+			Name:          "spin.js",
+			StartFunction: "spin",
+			CodeBytes:     []byte("function spin() { while (true) { try { while (true) {} } catch (e) {} } }"),
+			Deadline:      time.Now().Add(50 * time.Millisecond),
+		}
+
+		runJS(t, engine, fctx)
+
+		if !errors.Is(fctx.LimitError(), common.ErrExecutionTimeout) {
+			t.Fatalf("execution stopped with %v, expected %v", fctx.LimitError(), common.ErrExecutionTimeout)
+		}
+
+		return fctx
+	}
+
+	spin()
+	goroutines, threads := runtime.NumGoroutine(), threadCount()
+
+	for i := 0; i < 8; i++ {
+		spin()
+	}
+
+	// stopped scripts leave no parked goroutine or thread behind
+	time.Sleep(100 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > goroutines+1 {
+		t.Fatalf("%d goroutines before the executions, %d after", goroutines, after)
+	}
+	if after := threadCount(); after > threads+2 {
+		t.Fatalf("%d threads before the executions, %d after", threads, after)
+	}
+}
+
+func TestMemoryLimitStopsScript(t *testing.T) {
+	fctx := &common.FunctionExecutionContext{
+		Name:          "grow.js",
+		StartFunction: "grow",
+		CodeBytes:     []byte("function grow() { var a = []; while (true) { try { a.push('x'.repeat(1000) + a.length) } catch (e) {} } }"),
+		Limits:        common.ExecutionLimits{MaxMemory: 8 * 1024 * 1024},
+	}
+
+	runJS(t, NewJavascriptDuktapeEngine(), fctx)
+
+	if !errors.Is(fctx.LimitError(), common.ErrMemoryLimitExceeded) {
+		t.Fatalf("execution stopped with %v, expected %v", fctx.LimitError(), common.ErrMemoryLimitExceeded)
+	}
+	if fctx.Exit == nil || !fctx.Exit.Failed() {
+		t.Fatalf("execution not failed (%v)", fctx.Exit)
+	}
+}
+
+func TestScriptWithinLimits(t *testing.T) {
+	fctx := &common.FunctionExecutionContext{
+		Name:          "answer.js",
+		StartFunction: "answer",
+		CodeBytes:     []byte("function answer() { var a = []; for (var i = 0; i < 1000; i++) { a.push(i) } return 42 }"),
+		Limits:        common.ExecutionLimits{MaxMemory: 8 * 1024 * 1024},
+		Deadline:      time.Now().Add(5 * time.Second),
+	}
+
+	runJS(t, NewJavascriptDuktapeEngine(), fctx)
+
+	if fctx.LimitError() != nil {
+		t.Fatalf("execution stopped with %v", fctx.LimitError())
+	}
+	if fctx.Result != 42 {
+		t.Fatalf("result is %d, expected 42", fctx.Result)
+	}
+}
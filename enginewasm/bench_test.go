@@ -0,0 +1,89 @@
+package enginewasm_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ltearno/my-own-cluster/apicore"
+	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
+	"github.com/ltearno/my-own-cluster/enginewasmer"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+/*
+	BenchmarkFunctionExecution measures the execution of a sample function by each wasm engine,
+	with and without the engine caches :
+
+		go test -run none -bench . ./enginewasm
+*/
+func BenchmarkFunctionExecution(b *testing.B) {
+	codeBytes, err := ioutil.ReadFile("../_samples/other/api-demo-c.wasm")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, engine := range []string{"wasm3", "wasmer"} {
+		for _, cacheSize := range []int{0, common.DefaultCacheSize} {
+			b.Run(fmt.Sprintf("%s/cache-%d", engine, cacheSize), func(b *testing.B) {
+				orchestrator := newBenchOrchestrator(b, codeBytes)
+				orchestrator.SetCacheSize(cacheSize)
+
+				run := func() {
+					inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+					outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+					defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+					defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+					fctx := orchestrator.NewFunctionExecutionContext("bench", "_start", []int{264, 55}, false, "direct", nil, nil, inputExchangeBufferID, outputExchangeBufferID)
+					fctx.Engine = engine
+
+					err := fctx.Run()
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				// warm up
+				run()
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					run()
+				}
+			})
+		}
+	}
+}
+
+func newBenchOrchestrator(b *testing.B, codeBytes []byte) *common.Orchestrator {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
+	orchestrator.AddExecutionEngine("application/wasm", "wasmer", enginewasmer.NewWasmWasmerEngine())
+
+	apiProvider, err := apicore.NewCoreAPIProvider()
+	if err != nil {
+		b.Fatal(err)
+	}
+	orchestrator.AddAPIProvider("core", apiProvider)
+	orchestrator.AddAPIProvider("my-own-cluster", apiProvider)
+
+	_, err = orchestrator.RegisterBlobWithName("bench", "application/wasm", codeBytes)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return orchestrator
+}
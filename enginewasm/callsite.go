@@ -1,16 +1,18 @@
 package enginewasm
 
 import (
+	"encoding/binary"
 	"fmt"
 	"unsafe"
 
 	"github.com/ltearno/my-own-cluster/tools"
 )
 
-// CallSite represents the call information (memory start and stack pointer)
+// CallSite represents the call information (memory start and size, and stack pointer)
 type CallSite struct {
-	sp  unsafe.Pointer
-	mem unsafe.Pointer
+	sp      unsafe.Pointer
+	mem     unsafe.Pointer
+	memSize uint64
 }
 
 // GetParamUINT32 retruevs uint
@@ -23,11 +25,40 @@ func (cs *CallSite) GetParamInt(index int) int {
 	return int(getParameter(cs.sp, index))
 }
 
+// GetParamINT64 returns a 64 bits parameter
+func (cs *CallSite) GetParamINT64(index int) int64 {
+	return *(*int64)(unsafe.Pointer(uintptr(cs.sp) + uintptr(8)*uintptr(index)))
+}
+
+// SetResultINT64 writes a 64 bits result at the pointer given by a parameter, for host functions
+// which cannot return it (wasmer only returns i32 results from host functions)
+func (cs *CallSite) SetResultINT64(index int, value int64) error {
+	result, ok := cs.GetParamPointerRange(index, 8)
+	if !ok {
+		return fmt.Errorf("the result pointer %d is out of the memory", cs.GetParamUINT32(index))
+	}
+
+	binary.LittleEndian.PutUint64((*[8]byte)(result)[:], uint64(value))
+
+	return nil
+}
+
 // GetParamPointer retrive pointer
 func (cs *CallSite) GetParamPointer(index int) unsafe.Pointer {
 	return m3ApiOffsetToPtr(cs.mem, getParameter(cs.sp, index))
 }
 
+// GetParamPointerRange returns the pointer given by a parameter when the size bytes it points to are
+// in the memory
+func (cs *CallSite) GetParamPointerRange(index int, size uint64) (unsafe.Pointer, bool) {
+	offset := uint64(getParameter(cs.sp, index))
+	if offset+size > cs.memSize {
+		return nil, false
+	}
+
+	return m3ApiOffsetToPtr(cs.mem, uint32(offset)), true
+}
+
 // GetParamByteBuffer GetParamByteBuffer
 func (cs *CallSite) GetParamByteBuffer(addrParamIndex int, lengthParamIndex int) []byte {
 	addr := cs.GetParamPointer(addrParamIndex)
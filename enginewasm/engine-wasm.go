@@ -3,8 +3,8 @@ package enginewasm
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
-	"net/http"
 	"unsafe"
 
 	"github.com/ltearno/my-own-cluster/common"
@@ -23,6 +23,14 @@ type WasmProcessContext struct {
 	APIPlugins []WASMAPIPlugin
 
 	//GuestAllocatorFn func(int) int
+
+	engine *WasmWasm3Engine
+	// linear memory just after instantiation, restored before reusing a pooled runtime
+	memorySnapshot []byte
+	// the globals of the module can be restored by its reset function
+	resettable bool
+	// the file system of a posix execution
+	workingDirectory *MountedDirectory
 }
 
 type WASMAPIPlugin interface {
@@ -35,11 +43,43 @@ type VirtualFile interface {
 	Close() int
 }
 
+// SeekableVirtualFile is implemented by virtual files supporting fd_seek
+type SeekableVirtualFile interface {
+	VirtualFile
+	Seek(offset int64, whence int) (int64, error)
+}
+
+/*
+	The engine keeps a pool of idle runtimes, by techID, with their module loaded and the host
+	functions bound. wasm3 modules belong to the runtime they are loaded in, so parsed modules
+	cannot be shared between runtimes, whole runtimes are reused instead.
+
+	Only direct mode executions are pooled. Before each reuse, the linear memory is restored to
+	its initial content and the globals are set back to their initial value by the reset function
+	of the instrumentation (see metering.go). A runtime whose memory has grown, which was aborted
+	or whose module is not resettable (it writes its tables or drops segments) is not reused. The
+	capabilities of the function are part of the pool key, since the imports have been checked
+	against them when the runtime was created.
+
+	Modules linked to a function are run by the same engine, so their runtimes are pooled too.
+*/
 type WasmWasm3Engine struct {
+	runtimePool *common.Cache
+	// instrumented modules, by techID
+	instrumentations *common.Cache
 }
 
 func NewWasmWasm3Engine() *WasmWasm3Engine {
-	return &WasmWasm3Engine{}
+	return &WasmWasm3Engine{
+		runtimePool: common.NewCache("wasm3-runtimes", common.DefaultCacheSize, func(techID string, value interface{}) {
+			value.(*WasmProcessContext).Runtime.Destroy()
+		}),
+		instrumentations: common.NewCache("wasm3-instrumentations", common.DefaultCacheSize, nil),
+	}
+}
+
+func (e *WasmWasm3Engine) GetCaches() []*common.Cache {
+	return []*common.Cache{e.runtimePool, e.instrumentations}
 }
 
 func CreateWasmContext(fctx *common.FunctionExecutionContext) *WasmProcessContext {
@@ -57,20 +97,20 @@ type StdAccess struct {
 	ReadPos    int
 }
 
-func CreateStdInVirtualFile(wctx *WasmProcessContext, buffer []byte) VirtualFile {
+func CreateStdInVirtualFile(buffer []byte) VirtualFile {
 	return &StdAccess{
 		Name:       "stdin",
 		ReadBuffer: buffer,
 	}
 }
 
-func CreateStdOutVirtualFile(wctx *WasmProcessContext) VirtualFile {
+func CreateStdOutVirtualFile() VirtualFile {
 	return &StdAccess{
 		Name: "stdout",
 	}
 }
 
-func CreateStdErrVirtualFile(wctx *WasmProcessContext) VirtualFile {
+func CreateStdErrVirtualFile() VirtualFile {
 	return &StdAccess{
 		Name: "stderr",
 	}
@@ -94,22 +134,31 @@ func (vf *StdAccess) Close() int {
 }
 
 type InputAccessState struct {
-	Wctx    *WasmProcessContext
+	Fctx    *common.FunctionExecutionContext
 	ReadPos int
+
+	reader *common.ExchangeBufferReader
 }
 
-func CreateInputVirtualFile(wctx *WasmProcessContext) VirtualFile {
+func CreateInputVirtualFile(fctx *common.FunctionExecutionContext) VirtualFile {
 	return &InputAccessState{
 		ReadPos: 0,
-		Wctx:    wctx,
+		Fctx:    fctx,
 	}
 }
 
 func (vf *InputAccessState) Read(buffer []byte) int {
-	inputBuffer := vf.Wctx.Fctx.Orchestrator.GetExchangeBuffer(vf.Wctx.Fctx.InputExchangeBufferID)
+	if vf.reader == nil {
+		inputBuffer := vf.Fctx.Orchestrator.GetExchangeBuffer(vf.Fctx.InputExchangeBufferID)
+		if inputBuffer == nil {
+			return 0
+		}
+
+		vf.reader = common.NewExchangeBufferReader(inputBuffer)
+	}
 
-	l := tools.Min(len(buffer), len(inputBuffer.GetBuffer())-vf.ReadPos)
-	copy(buffer, (inputBuffer.GetBuffer())[vf.ReadPos:][:l])
+	// the input is streamed part by part, it is never materialized as a whole
+	l, _ := vf.reader.Read(buffer)
 	vf.ReadPos = vf.ReadPos + l
 
 	return l
@@ -125,14 +174,14 @@ func (vf *InputAccessState) Close() int {
 }
 
 type OutputAccessState struct {
-	Wctx     *WasmProcessContext
+	Fctx     *common.FunctionExecutionContext
 	WritePos int
 }
 
-func CreateOutputVirtualFile(wctx *WasmProcessContext) VirtualFile {
+func CreateOutputVirtualFile(fctx *common.FunctionExecutionContext) VirtualFile {
 	return &OutputAccessState{
 		WritePos: 0,
-		Wctx:     wctx,
+		Fctx:     fctx,
 	}
 }
 
@@ -142,7 +191,7 @@ func (vf *OutputAccessState) Read(buffer []byte) int {
 }
 
 func (vf *OutputAccessState) Write(buffer []byte) (int, error) {
-	exchangeBuffer := vf.Wctx.Fctx.Orchestrator.GetExchangeBuffer(vf.Wctx.Fctx.OutputExchangeBufferID)
+	exchangeBuffer := vf.Fctx.Orchestrator.GetExchangeBuffer(vf.Fctx.OutputExchangeBufferID)
 	written, _ := exchangeBuffer.Write(buffer)
 	vf.WritePos += written
 
@@ -150,7 +199,7 @@ func (vf *OutputAccessState) Write(buffer []byte) (int, error) {
 }
 
 func (vf *OutputAccessState) Close() int {
-	exchangeBuffer := vf.Wctx.Fctx.Orchestrator.GetExchangeBuffer(vf.Wctx.Fctx.OutputExchangeBufferID)
+	exchangeBuffer := vf.Fctx.Orchestrator.GetExchangeBuffer(vf.Fctx.OutputExchangeBufferID)
 	exchangeBuffer.Close()
 	return 0
 }
@@ -161,12 +210,33 @@ type WebAccessState struct {
 	ReadPos  int
 }
 
-func CreateWebAccessVirtualFile(path string) VirtualFile {
-	return &WebAccessState{
+// CreateWebAccessVirtualFile fetches an url allowed by the egress policy of the function
+func CreateWebAccessVirtualFile(fctx *common.FunctionExecutionContext, path string) (VirtualFile, error) {
+	vf := &WebAccessState{
 		Path:     path,
 		Response: nil,
 		ReadPos:  0,
 	}
+
+	err := fctx.CheckEgress(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fctx.EgressHTTPClient().Get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	vf.Response = &bytes
+
+	return vf, nil
 }
 
 func (vf *WebAccessState) Write(buffer []byte) (int, error) {
@@ -175,8 +245,6 @@ func (vf *WebAccessState) Write(buffer []byte) (int, error) {
 }
 
 func (vf *WebAccessState) Read(buffer []byte) int {
-	vf.readAll()
-
 	l := tools.Min(len(buffer), len(*vf.Response)-vf.ReadPos)
 	copy(buffer, (*vf.Response)[vf.ReadPos:][:l])
 	vf.ReadPos = vf.ReadPos + l
@@ -188,23 +256,6 @@ func (vf *WebAccessState) Close() int {
 	return 0
 }
 
-func (vf *WebAccessState) readAll() {
-	if vf.Response != nil {
-		return
-	}
-
-	resp, err := http.Get(vf.Path)
-	if err != nil {
-		panic(err)
-	}
-	defer resp.Body.Close()
-
-	bytes, _ := ioutil.ReadAll(resp.Body)
-
-	vf.Response = &bytes
-	vf.ReadPos = 0
-}
-
 func (wctx *WasmProcessContext) GetImportedModules() map[string]bool {
 	importedModules := make(map[string]bool)
 	for i := 0; i < wctx.Module.NumFunctions(); i++ {
@@ -249,53 +300,128 @@ func (e *WasmWasm3Engine) PrepareContext(fctx *common.FunctionExecutionContext)
 		return nil, errors.New("cannot create wasm context")
 	}
 
+	wctx.engine = e
+
 	return wctx, nil
 }
 
 type WrappedExchangeBufferInVirtualFile struct {
-	buffer  []byte
-	b       common.ExchangeBuffer
-	readPos int
+	b      common.ExchangeBuffer
+	reader *common.ExchangeBufferReader
 }
 
 func WrapExchangeBufferInVirtualFile(b common.ExchangeBuffer) VirtualFile {
 	return &WrappedExchangeBufferInVirtualFile{
-		buffer: nil,
 		b:      b,
+		reader: common.NewExchangeBufferReader(b),
 	}
 }
 
-func (b WrappedExchangeBufferInVirtualFile) Read(buffer []byte) int {
-	if b.buffer == nil {
-		b.buffer = b.b.GetBuffer()
+func (b *WrappedExchangeBufferInVirtualFile) Read(buffer []byte) int {
+	n, _ := b.reader.Read(buffer)
+	return n
+}
+
+func (b *WrappedExchangeBufferInVirtualFile) Write(buffer []byte) (int, error) {
+	res, err := b.b.Write(buffer)
+	return res, err
+}
+
+func (b *WrappedExchangeBufferInVirtualFile) Close() int {
+	return b.b.Close()
+}
+
+// Seek moves both the read and write positions, like for a file descriptor
+func (b *WrappedExchangeBufferInVirtualFile) Seek(offset int64, whence int) (int64, error) {
+	seekable, ok := b.b.(common.SeekableExchangeBuffer)
+	if !ok {
+		return 0, fmt.Errorf("exchange buffer is not seekable")
 	}
 
-	// refactored and not tested yet (2020/07/20)
-	notRead := len(b.buffer) - b.readPos
-	if notRead <= 0 {
-		return 0
+	if whence == io.SeekCurrent {
+		offset -= int64(b.reader.Buffered())
 	}
 
-	toRead := tools.Min(notRead, len(buffer))
-	if toRead > 0 {
-		copy(buffer[0:toRead], b.buffer[b.readPos:b.readPos+toRead])
-		b.readPos += toRead
+	pos, err := seekable.SeekRead(offset, whence)
+	if err != nil {
+		return 0, err
 	}
 
-	return toRead
-}
+	_, err = seekable.SeekWrite(pos, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
 
-func (b WrappedExchangeBufferInVirtualFile) Write(buffer []byte) (int, error) {
-	res, err := b.b.Write(buffer)
-	return res, err
-}
+	// drop what the reader had already fetched before the old position
+	b.reader = common.NewExchangeBufferReader(b.b)
 
-func (b WrappedExchangeBufferInVirtualFile) Close() int {
-	return b.Close()
+	return pos, nil
 }
 
 // Run runs the process
 func (wctx *WasmProcessContext) Run() error {
+	if wctx.isPoolable() {
+		if pooled, ok := wctx.engine.runtimePool.Take(wctx.poolKey()); ok {
+			// host functions are bound to the pooled context
+			instance := pooled.(*WasmProcessContext)
+			instance.Fctx = wctx.Fctx
+			copy(instance.Runtime.Memory(), instance.memorySnapshot)
+
+			err := instance.reset()
+			if err == nil {
+				return instance.call()
+			}
+
+			fmt.Printf("cannot reset pooled runtime of '%s' (%v)\n", wctx.Fctx.Name, err)
+			instance.Runtime.Destroy()
+		}
+	}
+
+	err := wctx.instantiate()
+	if err != nil {
+		wctx.Runtime.Destroy()
+		return err
+	}
+
+	if wctx.isPoolable() {
+		wctx.memorySnapshot = dupBytes(wctx.Runtime.Memory())
+	}
+
+	return wctx.call()
+}
+
+func (wctx *WasmProcessContext) isPoolable() bool {
+	return wctx.engine != nil && wctx.Fctx.TechID != "" && wctx.Fctx.Mode == "direct"
+}
+
+func (wctx *WasmProcessContext) poolKey() string {
+	return fmt.Sprintf("%s %s", wctx.Fctx.TechID, wctx.Fctx.Capabilities)
+}
+
+// checkImportCapabilities fails if the module imports a function of the api provider that the
+// function may not use
+func (wctx *WasmProcessContext) checkImportCapabilities(moduleName string) error {
+	for i := 0; i < wctx.Module.NumFunctions(); i++ {
+		f, err := wctx.Module.GetFunction(uint(i))
+		if err != nil {
+			continue
+		}
+
+		iModule := f.GetImportModule()
+		iField := f.GetImportField()
+		if iModule != nil && *iModule == moduleName && iField != nil {
+			err = wctx.Fctx.CheckImport(moduleName, *iField)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// instantiate creates the runtime, loads the module and binds its imports
+func (wctx *WasmProcessContext) instantiate() error {
 	wctx.Runtime = wasm3.NewRuntime(&wasm3.Config{
 		Environment: wasm3.NewEnvironment(),
 		StackSize:   64 * 1024, // original 64ko
@@ -307,7 +433,13 @@ func (wctx *WasmProcessContext) Run() error {
 	}
 
 	{
-		module, err := wctx.Runtime.ParseModule(wctx.Fctx.CodeBytes)
+		instrumented, err := InstrumentFunction(wctx.instrumentations(), wctx.Fctx)
+		if err != nil {
+			return err
+		}
+		wctx.resettable = instrumented.Resettable
+
+		module, err := wctx.Runtime.ParseModule(instrumented.Code)
 		if err != nil {
 			return errors.New("cannot parse module")
 		}
@@ -332,8 +464,18 @@ func (wctx *WasmProcessContext) Run() error {
 	// auto import and dynamically link functions together
 	// TODO watch for updates on https://webassembly.org/docs/dynamic-linking/
 	for m := range wctx.GetImportedModules() {
+		if m == MeteringModule {
+			wctx.bindMetering()
+			continue
+		}
+
 		apiProvider := wctx.Fctx.Orchestrator.GetAPIProvider(m)
 		if apiProvider != nil {
+			err := wctx.checkImportCapabilities(m)
+			if err != nil {
+				return err
+			}
+
 			if wctx.Fctx.Trace {
 				fmt.Printf("emulating '%s' imported module with api provider '%s'\n", m, m)
 			}
@@ -368,37 +510,15 @@ func (wctx *WasmProcessContext) Run() error {
 
 					// TODO check imported signature is the same as exported signature...
 
-					wctx.BindAPIFunction(m, *iField, iSignature, func(wctx *WasmProcessContext, cs *CallSite) (uint32, error) {
-						parameters := make([]int, f.GetNumArgs())
-						for i := 0; i < int(f.GetNumArgs()); i++ {
+					functionName := *iField
+					numArgs := int(f.GetNumArgs())
+					wctx.BindAPIFunction(m, functionName, iSignature, func(fctx *common.FunctionExecutionContext, cs *CallSite) (uint32, error) {
+						parameters := make([]int, numArgs)
+						for i := 0; i < numArgs; i++ {
 							parameters[i] = int(cs.GetParamUINT32(i))
 						}
 
-						outputExchangeBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-						inputExchangeBufferID := wctx.Fctx.Orchestrator.CreateExchangeBuffer()
-
-						subFctx := &common.FunctionExecutionContext{
-							Name:                   m,
-							StartFunction:          *iField,
-							HasFinishedRunning:     false,
-							InputExchangeBufferID:  inputExchangeBufferID,
-							OutputExchangeBufferID: outputExchangeBufferID,
-							Orchestrator:           wctx.Fctx.Orchestrator,
-							Result:                 0,
-							Mode:                   "direct",
-							CodeBytes:              wasmBytes,
-							Arguments:              parameters,
-						}
-
-						e := NewWasmWasm3Engine()
-						subWctx, err := e.PrepareContext(subFctx)
-						if err != nil {
-							return 0xffff, err
-						}
-
-						subWctx.Run()
-
-						return uint32(subFctx.Result), nil
+						return RunLinkedFunction(fctx, wctx.linkedEngine(), m, moduleFunctionTechID, functionName, wasmBytes, parameters)
 					})
 				}
 			}
@@ -411,15 +531,7 @@ func (wctx *WasmProcessContext) Run() error {
 					fmt.Printf("emulating '%s' imported module with WASI runtime layer\n", m)
 				}
 
-				inputExchangeBuffer := wctx.Fctx.Orchestrator.GetExchangeBuffer(wctx.Fctx.InputExchangeBufferID)
-
-				// TODO : provide the WASI interface by a rust program doing it with the core api compiled to wasm and pushed as a wasm module (but we need api descriptions for that !)
-				wasiHostPlugin := NewWASIHostPlugin(wctx.Fctx.POSIXFileName, wctx.Fctx.POSIXArguments, map[int]VirtualFile{
-					0: CreateStdInVirtualFile(wctx, inputExchangeBuffer.GetBuffer()),
-					1: WrapExchangeBufferInVirtualFile(wctx.Fctx.Orchestrator.GetExchangeBuffer(wctx.Fctx.OutputExchangeBufferID)),
-					2: CreateStdErrVirtualFile(wctx),
-				})
-				wasiHostPlugin.Bind(wctx)
+				wctx.workingDirectory = BindWASIHost(wctx)
 
 				continue
 			}
@@ -440,8 +552,16 @@ func (wctx *WasmProcessContext) Run() error {
 	// WITHOUT CALLING THIS, THE fn.Call() call fails ! need to investigate
 	wctx.Runtime.FindFunction(wctx.Fctx.StartFunction)
 
+	return nil
+}
+
+// call runs the start function, then gives the runtime back to the pool or destroys it
+func (wctx *WasmProcessContext) call() error {
+	wctx.Runtime.FindFunction(wctx.Fctx.StartFunction)
+
 	fn, err := wctx.Module.GetFunctionByName(wctx.Fctx.StartFunction)
 	if err != nil {
+		wctx.release(false)
 		return fmt.Errorf("not found '%s' function (using module.GetFunctionByName)", wctx.Fctx.StartFunction)
 	}
 
@@ -459,52 +579,193 @@ func (wctx *WasmProcessContext) Run() error {
 		fmt.Printf("found GuestAllocatorFunction in wasm module (%v)\n", wctx.GuestAllocatorFn)
 	}*/
 
-	wctx.Fctx.Result = 0
 	result, err := fn.Call2(wctx.Fctx.Arguments)
-	if wctx.Fctx.Mode != "posix" {
-		wctx.Fctx.Result = result
+	// wasm3 gives the top of its stack for a function without result
+	if fn.GetReturnType() == 0 {
+		result = 0
+	}
+
+	// memory could have grown since the last host api call
+	limitErr := wctx.checkLimits()
+
+	wctx.syncFiles()
+
+	// a host function stopping the program (proc_exit, api error) has already recorded the exit,
+	// wasm3 does not provide backtraces
+	if err != nil {
+		wctx.Fctx.FinishRunning(common.TrapExit(TrapKind(err.Error()), err.Error(), nil))
+	} else {
+		wctx.Fctx.FinishRunning(common.ReturnedExit(result))
 	}
 
-	wctx.Fctx.HasFinishedRunning = true
-	wctx.Fctx.Result = result
+	wctx.release(err == nil && limitErr == nil && len(wctx.Runtime.Memory()) == len(wctx.memorySnapshot))
 
-	return nil /*err*/ // the error "[trap] program called exit" should not be seen as an error, how to do that ?
+	return nil
 }
 
-// WasmCallHandler is the type of functions called back by wasm3 runtime
-type WasmCallHandler func(wctx *WasmProcessContext, cs *CallSite) (uint32, error)
+// syncFiles writes back the persistent files the program did not close
+func (wctx *WasmProcessContext) syncFiles() {
+	if wctx.workingDirectory == nil {
+		return
+	}
 
-// BindAPIFunction binds a module+function name in wasm3 to a go routine
-func (wctx *WasmProcessContext) BindAPIFunction(moduleName string, functionName string, signature string, handler WasmCallHandler) {
+	err := wctx.workingDirectory.Sync()
+	if err != nil {
+		fmt.Printf("cannot write back the files of '%s' (%v)\n", wctx.Fctx.Name, err)
+	}
+}
+
+// reset sets the globals of a pooled runtime back to their initial value
+func (wctx *WasmProcessContext) reset() error {
+	wctx.Runtime.FindFunction(MeteringResetFunction)
+
+	fn, err := wctx.Module.GetFunctionByName(MeteringResetFunction)
+	if err != nil {
+		return err
+	}
+
+	_, err = fn.Call2([]int{})
+
+	return err
+}
+
+// instrumentations is the cache of the instrumented modules of the engine
+func (wctx *WasmProcessContext) instrumentations() *common.Cache {
+	if wctx.engine != nil {
+		return wctx.engine.instrumentations
+	}
+
+	return common.NewCache("wasm3-instrumentations", 1, nil)
+}
+
+// linkedEngine is the engine running the modules linked to the function
+func (wctx *WasmProcessContext) linkedEngine() *WasmWasm3Engine {
+	if wctx.engine != nil {
+		return wctx.engine
+	}
+
+	return NewWasmWasm3Engine()
+}
+
+func (wctx *WasmProcessContext) release(reusable bool) {
+	if reusable && wctx.resettable && wctx.isPoolable() {
+		wctx.engine.runtimePool.Put(wctx.poolKey(), wctx)
+		return
+	}
+
+	wctx.Runtime.Destroy()
+}
+
+func dupBytes(b []byte) []byte {
+	r := make([]byte, len(b))
+	copy(r, b)
+	return r
+}
+
+func (wctx *WasmProcessContext) GetFunctionExecutionContext() *common.FunctionExecutionContext {
+	return wctx.Fctx
+}
+
+// BindHostFunction binds a module+function name in wasm3 to a go routine
+func (wctx *WasmProcessContext) BindHostFunction(moduleName string, functionName string, signature string, handler HostCallHandler) {
 	if wctx.Fctx.Trace {
 		fmt.Printf("binding function '%s'::'%s' signature:%s\n", moduleName, functionName, signature)
 	}
 
 	wctx.Runtime.AttachFunction(moduleName, functionName, signature, func(runtime wasm3.RuntimeT, sp unsafe.Pointer, mem unsafe.Pointer) int {
 		callSite := &CallSite{
-			sp:  sp,
-			mem: mem,
+			sp:      sp,
+			mem:     mem,
+			memSize: uint64(wctx.Runtime.GetAllocatedMemoryLength()),
 		}
 
-		result, err := handler(wctx, callSite)
-		if err != nil {
-			fmt.Println("BOUND API ERROR !", err)
+		if wctx.checkLimits() != nil {
 			return -1
 		}
 
-		*(*uint32)(sp) = result
+		result, m3PossibleTrap := handler(callSite)
+
+		if m3PossibleTrap == 0 {
+			*(*uint32)(sp) = result
+		}
+
+		return m3PossibleTrap
+	})
+}
+
+// BindAPIFunction binds a module+function name in wasm3 to a go routine, an error traps the program
+func (wctx *WasmProcessContext) BindAPIFunction(moduleName string, functionName string, signature string, handler WasmCallHandler) {
+	wctx.BindHostFunction(moduleName, functionName, signature, APIHostCallHandler(wctx, handler))
+}
+
+// bindMetering binds the function checking the limits at the metering points of the module
+func (wctx *WasmProcessContext) bindMetering() {
+	wctx.Runtime.AttachFunction(MeteringModule, MeteringCheckFunction, "i()", func(runtime wasm3.RuntimeT, sp unsafe.Pointer, mem unsafe.Pointer) int {
+		granted, err := wctx.Fctx.Meter()
+		if err == nil {
+			err = wctx.Fctx.CheckMemory(wctx.Runtime.GetAllocatedMemoryLength())
+		}
+		if err != nil {
+			granted = 0
+		}
+
+		*(*uint32)(sp) = uint32(granted)
+
 		return 0
 	})
 }
 
-// BindNotYetImplementedFunction exits the whole process when not yet implemented function is called
+// checkLimits verifies the execution limits, a failure should trap the wasm program
+func (wctx *WasmProcessContext) checkLimits() error {
+	err := wctx.Fctx.CheckLimits()
+	if err != nil {
+		return err
+	}
+
+	return wctx.Fctx.CheckMemory(wctx.Runtime.GetAllocatedMemoryLength())
+}
+
+// BindNotYetImplementedFunction exits the whole process when not yet implemented function is called. The
+// stub has the signature of the import, which wasm3 needs to link it, the given one is a fallback
 func (wctx *WasmProcessContext) BindNotYetImplementedFunction(module string, name string, signature string) {
+	if imported := wctx.importSignature(module, name); imported != "" {
+		signature = imported
+	}
+
 	if wctx.Fctx.Trace {
 		fmt.Printf("binding not_test_implemented stub function '%s'::'%s' signature:%s\n", module, name, signature)
 	}
 
 	wctx.Runtime.AttachFunction(module, name, signature, func(runtime wasm3.RuntimeT, sp unsafe.Pointer, mem unsafe.Pointer) int {
-		fmt.Printf("called not yet implemented function '%s'... ABORTING WASM PROGRAM EXECUTION\n", name)
-		return -2
+		_, m3PossibleTrap := NotYetImplementedHostCallHandler(wctx, name)(nil)
+		return m3PossibleTrap
 	})
 }
+
+// wasm3 value types, by signature letter
+var signatureTypes = map[uint8]string{0: "v", 1: "i", 2: "I", 3: "f", 4: "F"}
+
+// importSignature gives the signature of an imported function, empty when it is not imported
+func (wctx *WasmProcessContext) importSignature(module string, name string) string {
+	for i := 0; i < wctx.Module.NumFunctions(); i++ {
+		f, err := wctx.Module.GetFunction(uint(i))
+		if err != nil {
+			continue
+		}
+
+		iModule := f.GetImportModule()
+		iField := f.GetImportField()
+		if iModule == nil || iField == nil || *iModule != module || *iField != name {
+			continue
+		}
+
+		signature := signatureTypes[f.GetReturnType()] + "("
+		for arg := 0; arg < int(f.GetNumArgs()); arg++ {
+			signature += signatureTypes[f.GetArgType(arg)]
+		}
+
+		return signature + ")"
+	}
+
+	return ""
+}
@@ -0,0 +1,116 @@
+package enginewasm
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+var errTestHostFunction = errors.New("test host function failed")
+
+// failingAPIProvider binds "test"::"fail", which fails with errTestHostFunction
+type failingAPIProvider struct{}
+
+func (p *failingAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngineContextBounding) {
+	ctx.(WasmHost).BindAPIFunction("test", "fail", "i()", func(fctx *common.FunctionExecutionContext, cs *CallSite) (uint32, error) {
+		return 0, errTestHostFunction
+	})
+}
+
+// runExitModule runs the _start function of a module of testdata/exits with wasm3
+func runExitModule(t *testing.T, name string, mode string) (*common.FunctionExecutionContext, error) {
+	codeBytes, err := ioutil.ReadFile("testdata/exits/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.AddExecutionEngine("application/wasm", "wasm3", NewWasmWasm3Engine())
+	orchestrator.AddAPIProvider("test", &failingAPIProvider{})
+
+	_, err = orchestrator.RegisterBlobWithName(name, "application/wasm", codeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+	defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+	fctx := orchestrator.NewFunctionExecutionContext(name, "_start", []int{}, false, mode, &name, &[]string{}, inputExchangeBufferID, outputExchangeBufferID)
+	fctx.Deadline = time.Now().Add(10 * time.Second)
+
+	err = fctx.Run()
+	if fctx.Exit == nil {
+		t.Fatalf("no exit recorded (%v)", err)
+	}
+
+	return fctx, err
+}
+
+func TestProcExitGivesExitedExit(t *testing.T) {
+	fctx, err := runExitModule(t, "exit.wasm", "posix")
+
+	// exiting is not a failure of the execution, whatever the code
+	if err != nil {
+		t.Fatalf("exit 3 gives the error %v", err)
+	}
+	if fctx.Exit.Kind != common.EXIT_EXITED || fctx.Exit.Code != 3 || fctx.Result != 3 {
+		t.Fatalf("exit 3 gives %+v", fctx.Exit)
+	}
+}
+
+func TestTrapsGiveTrappedExit(t *testing.T) {
+	tests := []struct {
+		module string
+		trap   string
+	}{
+		{"unreachable.wasm", common.TRAP_UNREACHABLE},
+		{"out-of-bounds.wasm", common.TRAP_OUT_OF_BOUNDS_MEMORY_ACCESS},
+	}
+
+	for _, test := range tests {
+		t.Run(test.module, func(t *testing.T) {
+			fctx, err := runExitModule(t, test.module, "direct")
+
+			var executionError *common.ExecutionError
+			if !errors.As(err, &executionError) {
+				t.Fatalf("the trap gives the error %v", err)
+			}
+			if fctx.Exit.Kind != common.EXIT_TRAPPED || fctx.Exit.Trap != test.trap || fctx.Exit.Code != -1 {
+				t.Fatalf("the trap gives %+v", fctx.Exit)
+			}
+			// the wasm3 version bound by go-wasm3 gives no backtrace
+			if len(fctx.Exit.Backtrace) != 0 {
+				t.Fatalf("wasm3 gave the backtrace %v", fctx.Exit.Backtrace)
+			}
+		})
+	}
+}
+
+func TestFailingHostCallGivesHostErrorExit(t *testing.T) {
+	fctx, err := runExitModule(t, "host-error.wasm", "direct")
+
+	if !errors.Is(err, errTestHostFunction) {
+		t.Fatalf("the failing host call gives the error %v", err)
+	}
+	if fctx.Exit.Kind != common.EXIT_HOST_ERROR || fctx.Exit.Message != errTestHostFunction.Error() {
+		t.Fatalf("the failing host call gives %+v", fctx.Exit)
+	}
+}
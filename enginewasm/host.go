@@ -0,0 +1,152 @@
+package enginewasm
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+/*
+	WasmHost is what a wasm engine context provides to bind host functions.
+
+	The api providers, the WASI layer and the TinyGo layer only bind through this interface,
+	so they are shared by the wasm3 engine and the other wasm engines (wasmer). Host functions
+	read their parameters from a CallSite, which follows the wasm3 calling convention : one
+	64 bits slot per parameter and a pointer to the start of the linear memory.
+*/
+type WasmHost interface {
+	GetFunctionExecutionContext() *common.FunctionExecutionContext
+	GetImportedModules() map[string]bool
+
+	// BindHostFunction binds a low level handler, a non zero trap code aborts the wasm program
+	BindHostFunction(moduleName string, functionName string, signature string, handler HostCallHandler)
+	// BindAPIFunction binds a handler, an error aborts the wasm program
+	BindAPIFunction(moduleName string, functionName string, signature string, handler WasmCallHandler)
+	// BindNotYetImplementedFunction binds a stub aborting the wasm program when called
+	BindNotYetImplementedFunction(moduleName string, functionName string, signature string)
+}
+
+// HostCallHandler is a low level host function, returning its result or a non zero trap code
+type HostCallHandler func(cs *CallSite) (uint32, int)
+
+// WasmCallHandler is a host function, returning its result or an error
+type WasmCallHandler func(fctx *common.FunctionExecutionContext, cs *CallSite) (uint32, error)
+
+// NewCallSite creates a call site reading its parameters from the slots at sp, used by engines
+// not following the wasm3 calling convention
+func NewCallSite(sp unsafe.Pointer, mem unsafe.Pointer, memSize uint64) *CallSite {
+	return &CallSite{
+		sp:      sp,
+		mem:     mem,
+		memSize: memSize,
+	}
+}
+
+// APIHostCallHandler adapts a WasmCallHandler to a HostCallHandler. The execution context is
+// fetched at each call since pooled runtimes are reused by other executions
+func APIHostCallHandler(host WasmHost, handler WasmCallHandler) HostCallHandler {
+	return func(cs *CallSite) (uint32, int) {
+		fctx := host.GetFunctionExecutionContext()
+		result, err := handler(fctx, cs)
+		if err != nil {
+			fmt.Println("BOUND API ERROR !", err)
+			fctx.SetExit(common.HostErrorExit(err))
+			return 0xffff, -1
+		}
+
+		return result, 0
+	}
+}
+
+// NotYetImplementedHostCallHandler exits the whole wasm program
+func NotYetImplementedHostCallHandler(host WasmHost, name string) HostCallHandler {
+	return func(cs *CallSite) (uint32, int) {
+		fmt.Printf("called not yet implemented function '%s'... ABORTING WASM PROGRAM EXECUTION\n", name)
+		host.GetFunctionExecutionContext().SetExit(common.HostErrorExit(fmt.Errorf("function '%s' is not implemented", name)))
+		return 0, -2
+	}
+}
+
+// BindWASIHost binds the WASI layer, stdin is the input exchange buffer and stdout the output one.
+// The working directory is the root of the file system with the execution mounts, preopened as
+// "./", "../" and "/". It is returned so that the engine syncs it at the end of the execution
+func BindWASIHost(host WasmHost) *MountedDirectory {
+	fctx := host.GetFunctionExecutionContext()
+
+	workingDirectory := NewMountedDirectory(fctx.Orchestrator, fctx.Namespace, fctx.Mounts)
+
+	// TODO : provide the WASI interface by a rust program doing it with the core api compiled to wasm and pushed as a wasm module (but we need api descriptions for that !)
+	wasiHostPlugin := newWASIHost(fctx.POSIXFileName, fctx.POSIXArguments, map[int]VirtualFile{
+		0: CreateInputVirtualFile(fctx),
+		1: WrapExchangeBufferInVirtualFile(fctx.Orchestrator.GetExchangeBuffer(fctx.OutputExchangeBufferID)),
+		2: CreateStdErrVirtualFile(),
+		3: &OpenedVirtualDirectory{Directory: workingDirectory, Path: "."},
+		4: &OpenedVirtualDirectory{Directory: workingDirectory, Path: "."},
+		5: &OpenedVirtualDirectory{Directory: workingDirectory, Path: "."},
+	})
+	wasiHostPlugin.BindHost(host)
+
+	return workingDirectory
+}
+
+/*
+	RunLinkedFunction runs a function exported by another module, for wasm modules importing
+	functions from a module registered with its name (auto-linking). The function is executed in
+	direct mode with the given engine, with the limits, the fuel left and the deadline of its
+	caller, which is charged for the fuel it consumes, the
+	default capabilities restricted to the ones of its caller, the egress denials and the
+	persistence namespace of its caller. The techID of the module lets the engine cache it.
+*/
+func RunLinkedFunction(fctx *common.FunctionExecutionContext, engine common.ExecutionEngine, moduleName string, techID string, functionName string, wasmBytes []byte, parameters []int) (uint32, error) {
+	limits, err := fctx.CalledFunctionLimits()
+	if err != nil {
+		return 0xffff, err
+	}
+
+	outputExchangeBufferID := fctx.Orchestrator.CreateExchangeBuffer()
+	inputExchangeBufferID := fctx.Orchestrator.CreateExchangeBuffer()
+	defer fctx.Orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+	defer fctx.Orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+
+	subFctx := &common.FunctionExecutionContext{
+		Name:                   moduleName,
+		StartFunction:          functionName,
+		HasFinishedRunning:     false,
+		InputExchangeBufferID:  inputExchangeBufferID,
+		OutputExchangeBufferID: outputExchangeBufferID,
+		Orchestrator:           fctx.Orchestrator,
+		Result:                 0,
+		Mode:                   "direct",
+		TechID:                 techID,
+		CodeBytes:              wasmBytes,
+		Arguments:              parameters,
+		Limits:                 limits,
+		Deadline:               fctx.Deadline,
+		Capabilities:           fctx.Capabilities.ForCalledFunction(),
+		Egress:                 fctx.Egress.ForCalledFunction(),
+		Namespace:              fctx.Namespace,
+	}
+
+	// the linked function has its own exchange buffer handles
+	subFctx.HoldInputOutputExchangeBuffers()
+	defer subFctx.ReleaseHeldExchangeBuffers()
+
+	subWctx, err := engine.PrepareContext(subFctx)
+	if err != nil {
+		return 0xffff, err
+	}
+
+	subWctx.Run()
+	fctx.ChargeCalledFunction(subFctx)
+
+	if err := subFctx.LimitError(); err != nil {
+		return 0xffff, err
+	}
+
+	if subFctx.Exit != nil && subFctx.Exit.Failed() {
+		return 0xffff, &common.ExecutionError{Name: moduleName, Exit: subFctx.Exit}
+	}
+
+	return uint32(subFctx.Result), nil
+}
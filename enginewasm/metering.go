@@ -0,0 +1,849 @@
+package enginewasm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+/*
+	Metering
+
+	Neither wasm3 nor wasmer can be interrupted or count the instructions they execute, so modules
+	are instrumented before being loaded. A countdown global is decremented at the start of each
+	function, of each loop iteration and after each call to an imported function, the metering
+	points. When it reaches zero, the module calls the "my-own-cluster-metering"::"check" host
+	function which checks the limits of the execution and returns the number of points to run
+	before the next check, or zero to stop it. A function running out of time, fuel or memory traps
+	at its next check, even when it never calls the host api.
+
+	The countdown is exported, so an engine which cannot trap from a host function stops the module by
+	setting it to zero : the metering point following the host call checks the limits at once.
+
+	The check function is imported after the imports of the module, so the index of each function
+	defined by the module is shifted by one. Calls, function references, exports, the start function,
+	the elements and the "name" section are rewritten accordingly.
+
+	The instrumentation also exports a function setting the mutable globals and the countdown back to
+	their initial value, which is called before reusing an instance. It cannot restore the tables and
+	the segments, nor the globals set by a start function, such modules are not resettable.
+
+	A module using instructions the instrumentation does not know still runs when its execution has
+	no limit to enforce, as it is. The engines cache the instrumentation of the modules by techID.
+*/
+
+const MeteringModule = "my-own-cluster-metering"
+const MeteringCheckFunction = "check"
+const MeteringResetFunction = "my_own_cluster_reset"
+const MeteringCountdownGlobal = "my_own_cluster_countdown"
+
+// InstrumentedModule is a module with its metering points
+type InstrumentedModule struct {
+	Code []byte
+	// calling the reset function restores the instance as it was just after instantiation, except its memory
+	Resettable bool
+	// the module has its metering points, it is the original module otherwise
+	Metered bool
+}
+
+// instrumentation is the result of the instrumentation of a module, kept in the engine caches
+type instrumentation struct {
+	module *InstrumentedModule
+	err    error
+}
+
+// InstrumentFunction returns the module of the function with its metering points, taken from the cache
+// when possible. A module which cannot be instrumented is returned as it is, unless the execution has limits.
+func InstrumentFunction(cache *common.Cache, fctx *common.FunctionExecutionContext) (*InstrumentedModule, error) {
+	var result *instrumentation
+	if fctx.TechID != "" {
+		if cached, ok := cache.Get(fctx.TechID); ok {
+			result = cached.(*instrumentation)
+		}
+	}
+	if result == nil {
+		module, err := InstrumentModule(fctx.CodeBytes)
+		result = &instrumentation{module, err}
+		if fctx.TechID != "" {
+			cache.Put(fctx.TechID, result)
+		}
+	}
+
+	if result.err == nil {
+		return result.module, nil
+	}
+
+	if fctx.HasLimits() {
+		return nil, fmt.Errorf("cannot instrument module to enforce its limits (%v)", result.err)
+	}
+
+	return &InstrumentedModule{Code: fctx.CodeBytes}, nil
+}
+
+var errUnsupportedInstruction = errors.New("unsupported instruction")
+
+// section ids, in their order in a module
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionFunction  = 3
+	sectionTable     = 4
+	sectionMemory    = 5
+	sectionGlobal    = 6
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionData      = 11
+	sectionDataCount = 12
+	sectionTag       = 13
+)
+
+// sectionRank gives the position of a section in a module, tags come before globals and the data count before the code
+var sectionRank = map[byte]int{
+	sectionType:      1,
+	sectionImport:    2,
+	sectionFunction:  3,
+	sectionTable:     4,
+	sectionMemory:    5,
+	sectionTag:       6,
+	sectionGlobal:    7,
+	sectionExport:    8,
+	sectionStart:     9,
+	sectionElement:   10,
+	sectionDataCount: 11,
+	sectionCode:      12,
+	sectionData:      13,
+}
+
+type wasmSection struct {
+	id   byte
+	data []byte
+}
+
+type meteringInstrumenter struct {
+	importedFunctions uint32
+	importedGlobals   uint32
+	types             uint32
+	functions         uint32
+	globals           uint32
+
+	// index of the check function, of the countdown global and of the reset function
+	checkFunction uint32
+	counterGlobal uint32
+	resetFunction uint32
+
+	// code of the reset function, setting the mutable globals to their initial value
+	resetCode []byte
+	// the module writes its tables or drops segments, or has a start function
+	mutatesState bool
+}
+
+// InstrumentModule returns the module with its metering points, an error is returned for a malformed
+// module or a module using instructions the instrumentation does not know
+func InstrumentModule(code []byte) (*InstrumentedModule, error) {
+	if len(code) < 8 || string(code[:4]) != "\x00asm" {
+		return nil, errors.New("not a wasm module")
+	}
+
+	sections := []wasmSection{}
+	r := &wasmReader{data: code, pos: 8}
+	for r.pos < len(r.data) {
+		id := r.byte()
+		size := int(r.uleb())
+		if r.failed || size > len(r.data)-r.pos {
+			return nil, errors.New("malformed section")
+		}
+
+		sections = append(sections, wasmSection{id, r.data[r.pos : r.pos+size]})
+		r.pos += size
+	}
+
+	m := &meteringInstrumenter{}
+	for _, section := range sections {
+		var err error
+		switch section.id {
+		case sectionType:
+			m.types, err = sectionCount(section.data)
+		case sectionImport:
+			err = m.countImports(section.data)
+		case sectionFunction:
+			m.functions, err = sectionCount(section.data)
+		case sectionStart:
+			m.mutatesState = true
+		case sectionGlobal:
+			m.globals, err = sectionCount(section.data)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.checkFunction = m.importedFunctions
+	m.counterGlobal = m.importedGlobals + m.globals
+	m.resetFunction = m.importedFunctions + 1 + m.functions
+
+	result := append([]byte{}, code[:8]...)
+	emitted := map[byte]bool{}
+
+	// the sections receiving the added functions, global and export are created when the module has none
+	emitMissing := func(beforeRank int) error {
+		for _, id := range []byte{sectionType, sectionImport, sectionFunction, sectionGlobal, sectionExport, sectionCode} {
+			if emitted[id] || sectionRank[id] >= beforeRank {
+				continue
+			}
+
+			data, err := m.rewriteSection(id, []byte{0})
+			if err != nil {
+				return err
+			}
+
+			result = appendSection(result, id, data)
+			emitted[id] = true
+		}
+
+		return nil
+	}
+
+	for _, section := range sections {
+		if section.id != sectionCustom {
+			rank, ok := sectionRank[section.id]
+			if !ok {
+				return nil, fmt.Errorf("unknown section %d", section.id)
+			}
+
+			err := emitMissing(rank)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		data, err := m.rewriteSection(section.id, section.data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot instrument section %d (%v)", section.id, err)
+		}
+
+		result = appendSection(result, section.id, data)
+		emitted[section.id] = true
+	}
+
+	err := emitMissing(len(sectionRank) + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InstrumentedModule{
+		Code:       result,
+		Resettable: !m.mutatesState,
+		Metered:    true,
+	}, nil
+}
+
+func sectionCount(data []byte) (uint32, error) {
+	r := &wasmReader{data: data}
+	count := r.uleb()
+	if r.failed {
+		return 0, errors.New("malformed section")
+	}
+
+	return count, nil
+}
+
+// countImports counts the imported functions and globals, which come before the module ones in the index spaces
+func (m *meteringInstrumenter) countImports(data []byte) error {
+	r := &wasmReader{data: data}
+	count := r.uleb()
+	for i := uint32(0); i < count && !r.failed; i++ {
+		r.name()
+		r.name()
+		switch r.byte() {
+		case 0x00:
+			r.uleb()
+			m.importedFunctions++
+		case 0x01:
+			r.byte()
+			r.limits()
+		case 0x02:
+			r.limits()
+		case 0x03:
+			r.byte()
+			r.byte()
+			m.importedGlobals++
+		case 0x04:
+			r.byte()
+			r.uleb()
+		default:
+			return errors.New("unknown import kind")
+		}
+	}
+
+	if r.failed {
+		return errors.New("malformed import section")
+	}
+
+	return nil
+}
+
+func (m *meteringInstrumenter) function(index uint32) uint32 {
+	if index < m.importedFunctions {
+		return index
+	}
+
+	return index + 1
+}
+
+func (m *meteringInstrumenter) rewriteSection(id byte, data []byte) ([]byte, error) {
+	r := &wasmReader{data: data}
+	var out []byte
+
+	switch id {
+	case sectionType:
+		// the check function type () -> i32 and the reset function type () -> ()
+		count := r.uleb()
+		out = appendULEB(out, count+2)
+		out = append(out, r.rest()...)
+		out = append(out, 0x60, 0x00, 0x01, 0x7f)
+		out = append(out, 0x60, 0x00, 0x00)
+
+	case sectionImport:
+		count := r.uleb()
+		out = appendULEB(out, count+1)
+		out = append(out, r.rest()...)
+		out = appendName(out, MeteringModule)
+		out = appendName(out, MeteringCheckFunction)
+		out = append(out, 0x00)
+		out = appendULEB(out, m.types)
+
+	case sectionFunction:
+		count := r.uleb()
+		out = appendULEB(out, count+1)
+		out = append(out, r.rest()...)
+		out = appendULEB(out, m.types+1)
+
+	case sectionGlobal:
+		count := r.uleb()
+		out = appendULEB(out, count+1)
+		for i := uint32(0); i < count && !r.failed; i++ {
+			globalType := r.bytes(2)
+			out = append(out, globalType...)
+
+			start := len(out)
+			out = m.rewriteExpression(r, out, false)
+
+			// the reset function evaluates the initial value again, without its end
+			if len(globalType) == 2 && globalType[1] == 0x01 && len(out) > start {
+				m.resetCode = append(m.resetCode, out[start:len(out)-1]...)
+				m.resetCode = append(m.resetCode, 0x24)
+				m.resetCode = appendULEB(m.resetCode, m.importedGlobals+i)
+			}
+		}
+		// the countdown, a mutable i32 starting at zero so that the first metering point checks the limits
+		out = append(out, 0x7f, 0x01, 0x41, 0x00, 0x0b)
+
+	case sectionExport:
+		count := r.uleb()
+		out = appendULEB(out, count+2)
+		for i := uint32(0); i < count && !r.failed; i++ {
+			out = appendName(out, r.name())
+			kind := r.byte()
+			index := r.uleb()
+			if kind == 0x00 {
+				index = m.function(index)
+			}
+			out = append(out, kind)
+			out = appendULEB(out, index)
+		}
+		out = appendName(out, MeteringResetFunction)
+		out = append(out, 0x00)
+		out = appendULEB(out, m.resetFunction)
+		out = appendName(out, MeteringCountdownGlobal)
+		out = append(out, 0x03)
+		out = appendULEB(out, m.counterGlobal)
+
+	case sectionStart:
+		out = appendULEB(out, m.function(r.uleb()))
+
+	case sectionElement:
+		count := r.uleb()
+		out = appendULEB(out, count)
+		for i := uint32(0); i < count && !r.failed; i++ {
+			out = m.rewriteElement(r, out)
+		}
+
+	case sectionCode:
+		count := r.uleb()
+		out = appendULEB(out, count+1)
+		for i := uint32(0); i < count && !r.failed; i++ {
+			size := int(r.uleb())
+			if r.failed || size > len(r.data)-r.pos {
+				return nil, errors.New("malformed function body")
+			}
+
+			body, err := m.rewriteBody(r.data[r.pos : r.pos+size])
+			if err != nil {
+				return nil, fmt.Errorf("function %d: %v", m.importedFunctions+i, err)
+			}
+			r.pos += size
+
+			out = appendULEB(out, uint32(len(body)))
+			out = append(out, body...)
+		}
+
+		body := m.resetBody()
+		out = appendULEB(out, uint32(len(body)))
+		out = append(out, body...)
+
+	case sectionCustom:
+		name := r.name()
+		if r.failed || name != "name" {
+			return data, nil
+		}
+
+		out = appendName(out, name)
+		rewritten, err := m.rewriteNames(r)
+		if err != nil {
+			// names are only used for backtraces, they are dropped rather than being wrong
+			return out, nil
+		}
+		out = append(out, rewritten...)
+
+	default:
+		return data, nil
+	}
+
+	if r.failed {
+		return nil, errors.New("malformed section")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.pos != len(r.data) {
+		return nil, errors.New("unexpected section end")
+	}
+
+	return out, nil
+}
+
+// rewriteElement rewrites an element segment, its function indexes and constant expressions
+func (m *meteringInstrumenter) rewriteElement(r *wasmReader, out []byte) []byte {
+	flags := r.uleb()
+	out = appendULEB(out, flags)
+
+	// explicit table index
+	if flags&0x02 != 0 && flags&0x01 == 0 {
+		out = appendULEB(out, r.uleb())
+	}
+	// active segment offset
+	if flags&0x01 == 0 {
+		out = m.rewriteExpression(r, out, false)
+	}
+	// element kind or reference type
+	if flags&0x03 != 0 {
+		out = append(out, r.byte())
+	}
+
+	count := r.uleb()
+	out = appendULEB(out, count)
+	for i := uint32(0); i < count && !r.failed; i++ {
+		if flags&0x04 != 0 {
+			out = m.rewriteExpression(r, out, false)
+		} else {
+			out = appendULEB(out, m.function(r.uleb()))
+		}
+	}
+
+	return out
+}
+
+// rewriteBody copies the locals of a function body and instruments its code
+func (m *meteringInstrumenter) rewriteBody(body []byte) ([]byte, error) {
+	r := &wasmReader{data: body}
+	var out []byte
+
+	count := r.uleb()
+	out = appendULEB(out, count)
+	for i := uint32(0); i < count && !r.failed; i++ {
+		out = appendULEB(out, r.uleb())
+		out = append(out, r.byte())
+	}
+
+	out = m.appendMeteringPoint(out)
+	out = m.rewriteExpression(r, out, true)
+
+	if r.failed {
+		return nil, errors.New("malformed function body")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.pos != len(r.data) {
+		return nil, errors.New("unexpected function body end")
+	}
+
+	return out, nil
+}
+
+// resetBody is the body of the reset function, which also sets the countdown back to zero
+func (m *meteringInstrumenter) resetBody() []byte {
+	body := []byte{0x00}
+	body = append(body, m.resetCode...)
+	body = append(body, 0x41, 0x00, 0x24)
+	body = appendULEB(body, m.counterGlobal)
+
+	return append(body, 0x0b)
+}
+
+/*
+	appendMeteringPoint appends the countdown, calling the check function when it has reached zero. The
+	check function returns zero to stop the execution, which traps on the unreachable instruction
+	rather than relying on the engine to trap from a host function :
+
+		global.get $counter
+		i32.eqz
+		if
+			call $check
+			global.set $counter
+			global.get $counter
+			i32.eqz
+			if
+				unreachable
+			end
+		end
+		global.get $counter
+		i32.const 1
+		i32.sub
+		global.set $counter
+*/
+func (m *meteringInstrumenter) appendMeteringPoint(out []byte) []byte {
+	out = append(out, 0x23)
+	out = appendULEB(out, m.counterGlobal)
+	out = append(out, 0x45, 0x04, 0x40, 0x10)
+	out = appendULEB(out, m.checkFunction)
+	out = append(out, 0x24)
+	out = appendULEB(out, m.counterGlobal)
+	out = append(out, 0x23)
+	out = appendULEB(out, m.counterGlobal)
+	out = append(out, 0x45, 0x04, 0x40, 0x00, 0x0b, 0x0b, 0x23)
+	out = appendULEB(out, m.counterGlobal)
+	out = append(out, 0x41, 0x01, 0x6b, 0x24)
+	out = appendULEB(out, m.counterGlobal)
+
+	return out
+}
+
+// rewriteExpression copies instructions up to the end of the expression, remapping function indexes and
+// adding a metering point at the start of the loops and after the host calls when instrumenting a function body
+func (m *meteringInstrumenter) rewriteExpression(r *wasmReader, out []byte, instrument bool) []byte {
+	depth := 0
+	for !r.failed && r.err == nil {
+		start := r.pos
+		opcode := r.byte()
+
+		switch opcode {
+		// block, loop, if, try
+		case 0x02, 0x03, 0x04, 0x06:
+			r.leb()
+			depth++
+			out = append(out, r.data[start:r.pos]...)
+			if opcode == 0x03 && instrument {
+				out = m.appendMeteringPoint(out)
+			}
+			continue
+
+		// end
+		case 0x0b:
+			out = append(out, opcode)
+			if depth == 0 {
+				return out
+			}
+			depth--
+			continue
+
+		// delegate ends a try block
+		case 0x18:
+			r.leb()
+			depth--
+
+		// call, return_call, ref.func
+		case 0x10, 0x12, 0xd2:
+			index := r.uleb()
+			out = append(out, opcode)
+			out = appendULEB(out, m.function(index))
+			if opcode == 0x10 && index < m.importedFunctions && instrument {
+				out = m.appendMeteringPoint(out)
+			}
+			continue
+
+		// table.set
+		case 0x26:
+			r.leb()
+			m.mutatesState = true
+
+		case 0xfc:
+			subOpcode := r.uleb()
+			// data.drop, table.init, elem.drop, table.copy, table.grow, table.fill
+			if subOpcode == 9 || (subOpcode >= 12 && subOpcode <= 17 && subOpcode != 16) {
+				m.mutatesState = true
+			}
+			r.skipMiscImmediates(subOpcode)
+
+		default:
+			r.skipImmediates(opcode)
+		}
+
+		out = append(out, r.data[start:r.pos]...)
+	}
+
+	return out
+}
+
+// rewriteNames rewrites the function indexes of the "name" section : the function, local and label names
+func (m *meteringInstrumenter) rewriteNames(r *wasmReader) ([]byte, error) {
+	var out []byte
+
+	for r.pos < len(r.data) && !r.failed {
+		id := r.byte()
+		size := int(r.uleb())
+		if r.failed || size > len(r.data)-r.pos {
+			return nil, errors.New("malformed name section")
+		}
+
+		subsection := &wasmReader{data: r.data[r.pos : r.pos+size]}
+		r.pos += size
+
+		var data []byte
+		switch id {
+		case 1:
+			count := subsection.uleb()
+			data = appendULEB(data, count)
+			for i := uint32(0); i < count && !subsection.failed; i++ {
+				data = appendULEB(data, m.function(subsection.uleb()))
+				data = appendName(data, subsection.name())
+			}
+
+		case 2, 3:
+			count := subsection.uleb()
+			data = appendULEB(data, count)
+			for i := uint32(0); i < count && !subsection.failed; i++ {
+				data = appendULEB(data, m.function(subsection.uleb()))
+				names := subsection.uleb()
+				data = appendULEB(data, names)
+				for j := uint32(0); j < names && !subsection.failed; j++ {
+					data = appendULEB(data, subsection.uleb())
+					data = appendName(data, subsection.name())
+				}
+			}
+
+		default:
+			data = subsection.rest()
+		}
+
+		if subsection.failed || subsection.pos != len(subsection.data) {
+			return nil, errors.New("malformed name subsection")
+		}
+
+		out = append(out, id)
+		out = appendULEB(out, uint32(len(data)))
+		out = append(out, data...)
+	}
+
+	if r.failed {
+		return nil, errors.New("malformed name section")
+	}
+
+	return out, nil
+}
+
+// skipImmediates reads the immediates of an instruction which are copied as they are
+func (r *wasmReader) skipImmediates(opcode byte) {
+	switch {
+	// unreachable, nop, else, return, catch_all, drop, select, ref.is_null
+	case opcode == 0x00 || opcode == 0x01 || opcode == 0x05 || opcode == 0x0f || opcode == 0x19 || opcode == 0x1a || opcode == 0x1b || opcode == 0xd1:
+
+	// catch, throw, rethrow, br, br_if, call_ref, return_call_ref
+	case opcode >= 0x07 && opcode <= 0x09, opcode == 0x0c, opcode == 0x0d, opcode == 0x14, opcode == 0x15:
+		r.leb()
+
+	// br_table
+	case opcode == 0x0e:
+		count := r.uleb()
+		for i := uint32(0); i <= count && !r.failed; i++ {
+			r.leb()
+		}
+
+	// call_indirect, return_call_indirect
+	case opcode == 0x11 || opcode == 0x13:
+		r.leb()
+		r.leb()
+
+	// typed select
+	case opcode == 0x1c:
+		count := r.uleb()
+		r.bytes(int(count))
+
+	// local, global and table get and set
+	case opcode >= 0x20 && opcode <= 0x26:
+		r.leb()
+
+	// loads and stores
+	case opcode >= 0x28 && opcode <= 0x3e:
+		r.memarg()
+
+	// memory.size, memory.grow, i32.const, i64.const
+	case opcode >= 0x3f && opcode <= 0x42:
+		r.leb()
+
+	case opcode == 0x43:
+		r.bytes(4)
+
+	case opcode == 0x44:
+		r.bytes(8)
+
+	// numeric instructions
+	case opcode >= 0x45 && opcode <= 0xc4:
+
+	// ref.null
+	case opcode == 0xd0:
+		r.leb()
+
+	case opcode == 0xfc:
+		r.skipMiscImmediates(r.uleb())
+
+	case opcode == 0xfd:
+		r.skipVectorImmediates(r.uleb())
+
+	case opcode == 0xfe:
+		r.skipAtomicImmediates(r.uleb())
+
+	default:
+		r.err = fmt.Errorf("%w 0x%02x", errUnsupportedInstruction, opcode)
+	}
+}
+
+// skipMiscImmediates reads the immediates of the saturating truncations, bulk memory and table instructions
+func (r *wasmReader) skipMiscImmediates(subOpcode uint32) {
+	switch {
+	case subOpcode <= 7:
+
+	// memory.init, memory.copy, table.init, table.copy
+	case subOpcode == 8 || subOpcode == 10 || subOpcode == 12 || subOpcode == 14:
+		r.leb()
+		r.leb()
+
+	// data.drop, memory.fill, elem.drop, table.grow, table.size, table.fill
+	case subOpcode == 9 || subOpcode == 11 || subOpcode == 13 || (subOpcode >= 15 && subOpcode <= 17):
+		r.leb()
+
+	default:
+		r.err = fmt.Errorf("%w 0xfc %d", errUnsupportedInstruction, subOpcode)
+	}
+}
+
+// skipVectorImmediates reads the immediates of the simd instructions
+func (r *wasmReader) skipVectorImmediates(subOpcode uint32) {
+	switch {
+	// loads and stores, load32_zero and load64_zero
+	case subOpcode <= 11 || subOpcode == 92 || subOpcode == 93:
+		r.memarg()
+
+	// v128.const, i8x16.shuffle
+	case subOpcode == 12 || subOpcode == 13:
+		r.bytes(16)
+
+	// extract and replace lane
+	case subOpcode >= 21 && subOpcode <= 34:
+		r.byte()
+
+	// load and store lane
+	case subOpcode >= 84 && subOpcode <= 91:
+		r.memarg()
+		r.byte()
+	}
+}
+
+// skipAtomicImmediates reads the immediates of the threads instructions
+func (r *wasmReader) skipAtomicImmediates(subOpcode uint32) {
+	switch {
+	// atomic.fence
+	case subOpcode == 3:
+		r.byte()
+
+	case subOpcode <= 2 || (subOpcode >= 0x10 && subOpcode <= 0x4e):
+		r.memarg()
+
+	default:
+		r.err = fmt.Errorf("%w 0xfe %d", errUnsupportedInstruction, subOpcode)
+	}
+}
+
+// memarg reads the alignment, the memory index when present and the offset of a memory access
+func (r *wasmReader) memarg() {
+	align := r.uleb()
+	if align&0x40 != 0 {
+		r.leb()
+	}
+	r.leb()
+}
+
+// limits reads the limits of a table or a memory
+func (r *wasmReader) limits() {
+	flags := r.byte()
+	r.leb()
+	if flags&0x01 != 0 {
+		r.leb()
+	}
+}
+
+// leb reads a signed or unsigned LEB128 integer of at most 64 bits
+func (r *wasmReader) leb() {
+	for i := 0; i < 10; i++ {
+		if r.byte()&0x80 == 0 || r.failed {
+			return
+		}
+	}
+
+	r.failed = true
+}
+
+func (r *wasmReader) bytes(length int) []byte {
+	if length < 0 || length > len(r.data)-r.pos {
+		r.failed = true
+		return nil
+	}
+
+	b := r.data[r.pos : r.pos+length]
+	r.pos += length
+
+	return b
+}
+
+func (r *wasmReader) rest() []byte {
+	b := r.data[r.pos:]
+	r.pos = len(r.data)
+
+	return b
+}
+
+func appendULEB(out []byte, value uint32) []byte {
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func appendName(out []byte, name string) []byte {
+	out = appendULEB(out, uint32(len(name)))
+	return append(out, name...)
+}
+
+func appendSection(out []byte, id byte, data []byte) []byte {
+	out = append(out, id)
+	out = appendULEB(out, uint32(len(data)))
+	return append(out, data...)
+}
@@ -0,0 +1,208 @@
+package enginewasm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+// spinModule exports "spin", a function looping forever without calling the host
+var spinModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// type () -> i32
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x08, 0x01, 0x04, 's', 'p', 'i', 'n', 0x00, 0x00,
+	// loop br 0 end i32.const 0
+	0x0a, 0x0b, 0x01, 0x09, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b,
+}
+
+// answerModule exports "answer", calling "fortytwo" which returns 42, with a name section
+var answerModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x03, 0x03, 0x02, 0x00, 0x00,
+	0x07, 0x0a, 0x01, 0x06, 'a', 'n', 's', 'w', 'e', 'r', 0x00, 0x00,
+	// call 1, i32.const 42
+	0x0a, 0x0b, 0x02, 0x04, 0x00, 0x10, 0x01, 0x0b, 0x04, 0x00, 0x41, 0x2a, 0x0b,
+	0x00, 0x1a, 0x04, 'n', 'a', 'm', 'e',
+	0x01, 0x13, 0x02, 0x00, 0x06, 'a', 'n', 's', 'w', 'e', 'r', 0x01, 0x08, 'f', 'o', 'r', 't', 'y', 't', 'w', 'o',
+}
+
+// counterModule exports "next", incrementing a mutable global and returning it
+var counterModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x03, 0x02, 0x01, 0x00,
+	0x06, 0x06, 0x01, 0x7f, 0x01, 0x41, 0x00, 0x0b,
+	0x07, 0x08, 0x01, 0x04, 'n', 'e', 'x', 't', 0x00, 0x00,
+	// global.get 0, i32.const 1, i32.add, global.set 0, global.get 0
+	0x0a, 0x0d, 0x01, 0x0b, 0x00, 0x23, 0x00, 0x41, 0x01, 0x6a, 0x24, 0x00, 0x23, 0x00, 0x0b,
+}
+
+func runMetered(t *testing.T, code []byte, startFunction string, limits common.ExecutionLimits, deadline time.Time) *common.FunctionExecutionContext {
+	fctx := &common.FunctionExecutionContext{
+		Name:          "metered",
+		StartFunction: startFunction,
+		Mode:          "direct",
+		CodeBytes:     code,
+		Limits:        limits,
+		Deadline:      deadline,
+	}
+
+	runWasm3(t, NewWasmWasm3Engine(), fctx)
+
+	return fctx
+}
+
+func runWasm3(t *testing.T, engine *WasmWasm3Engine, fctx *common.FunctionExecutionContext) {
+	ectx, err := engine.PrepareContext(fctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ectx.Run()
+	}()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the execution was not stopped")
+	}
+}
+
+func TestMeteringFuel(t *testing.T) {
+	fctx := runMetered(t, spinModule, "spin", common.ExecutionLimits{Fuel: 100000}, time.Time{})
+
+	if !errors.Is(fctx.LimitError(), common.ErrFuelExhausted) {
+		t.Fatalf("execution stopped with %v, expected %v", fctx.LimitError(), common.ErrFuelExhausted)
+	}
+	if fctx.Exit == nil || !fctx.Exit.Failed() {
+		t.Fatalf("execution not trapped (%v)", fctx.Exit)
+	}
+}
+
+func TestMeteringDeadline(t *testing.T) {
+	fctx := runMetered(t, spinModule, "spin", common.ExecutionLimits{}, time.Now().Add(50*time.Millisecond))
+
+	if !errors.Is(fctx.LimitError(), common.ErrExecutionTimeout) {
+		t.Fatalf("execution stopped with %v, expected %v", fctx.LimitError(), common.ErrExecutionTimeout)
+	}
+}
+
+func TestMeteringKeepsResults(t *testing.T) {
+	// two functions calls, the fuel is just enough
+	fctx := runMetered(t, answerModule, "answer", common.ExecutionLimits{Fuel: 2}, time.Time{})
+
+	if fctx.LimitError() != nil {
+		t.Fatalf("execution stopped with %v", fctx.LimitError())
+	}
+	if fctx.Result != 42 {
+		t.Fatalf("result is %d, expected 42", fctx.Result)
+	}
+}
+
+func TestMeteringRemapsNames(t *testing.T) {
+	instrumented, err := InstrumentModule(answerModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the check function is imported before the functions of the module
+	names := FunctionNames(instrumented.Code)
+	if names[1] != "answer" || names[2] != "fortytwo" || len(names) != 2 {
+		t.Fatalf("unexpected names %v", names)
+	}
+}
+
+func TestPooledRuntimeReset(t *testing.T) {
+	engine := NewWasmWasm3Engine()
+
+	for i := 0; i < 3; i++ {
+		fctx := &common.FunctionExecutionContext{
+			Name:          "counter",
+			StartFunction: "next",
+			Mode:          "direct",
+			TechID:        "counter",
+			CodeBytes:     counterModule,
+		}
+
+		runWasm3(t, engine, fctx)
+
+		if fctx.Result != 1 {
+			t.Fatalf("execution %d returned %d, the globals were not reset", i, fctx.Result)
+		}
+	}
+
+	if hits := engine.runtimePool.GetStatus().Hits; hits != 2 {
+		t.Fatalf("runtime reused %d times, expected 2", hits)
+	}
+}
+
+func TestMutatedTablesAreNotResettable(t *testing.T) {
+	// a table and a function doing table.set 0 with a null reference at index 0
+	module := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+		0x03, 0x02, 0x01, 0x00,
+		0x04, 0x04, 0x01, 0x70, 0x00, 0x01,
+		0x0a, 0x0a, 0x01, 0x08, 0x00, 0x41, 0x00, 0xd0, 0x70, 0x26, 0x00, 0x0b,
+	}
+
+	instrumented, err := InstrumentModule(module)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instrumented.Resettable {
+		t.Fatal("a module writing its tables is resettable")
+	}
+
+	instrumented, err = InstrumentModule(counterModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !instrumented.Resettable {
+		t.Fatal("a module writing its globals is not resettable")
+	}
+}
+
+func TestUninstrumentableModuleRunsWithoutLimits(t *testing.T) {
+	// spinModule with an instruction of the 0xfb prefix, unknown to the instrumentation, before its loop
+	module := append([]byte{}, spinModule[:len(spinModule)-13]...)
+	module = append(module, 0x0a, 0x0d, 0x01, 0x0b, 0x00, 0xfb, 0x00, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b)
+
+	cache := common.NewCache("instrumentations", common.DefaultCacheSize, nil)
+
+	fctx := &common.FunctionExecutionContext{TechID: "unknown", CodeBytes: module}
+	instrumented, err := InstrumentFunction(cache, fctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instrumented.Metered || string(instrumented.Code) != string(module) {
+		t.Fatal("the module without limits is not run as it is")
+	}
+
+	fctx.Limits = common.ExecutionLimits{Fuel: 1000}
+	if _, err := InstrumentFunction(cache, fctx); err == nil {
+		t.Fatal("the module runs with limits it cannot enforce")
+	}
+
+	// the modules are instrumented once
+	fctx = &common.FunctionExecutionContext{TechID: "spin", CodeBytes: spinModule}
+	for i := 0; i < 3; i++ {
+		instrumented, err = InstrumentFunction(cache, fctx)
+		if err != nil || !instrumented.Metered {
+			t.Fatalf("the module is not instrumented (%v)", err)
+		}
+	}
+	if status := cache.GetStatus(); status.Misses != 2 || status.Hits != 3 {
+		t.Fatalf("the instrumentation cache has %d hits and %d misses", status.Hits, status.Misses)
+	}
+}
@@ -0,0 +1,878 @@
+package enginewasm
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+/*
+	Mounted directories
+
+	The file system of a posix execution is an in-memory directory over which the mounts of the
+	execution context (see common.Mount) are placed. Paths are given to the directory mounted on
+	their longest prefix, and the directories opened inside a mount stay attached to the mount
+	table so that ".." leads back to the parent directories.
+
+	Blob mounts are read-only. Persistence mounts store each file in a persistence key of the
+	namespace of the function. The modifications of an opened file are kept in memory and written
+	back when the file is synced or closed, before any other operation on the directory, and at
+	the end of the execution for the files the program did not close. A file removed or renamed
+	while opened is not written back anymore. Persistence mounts do not store file times.
+*/
+
+var ErrVirtualFileCrossDevice = errors.New("invalid cross-device link")
+var ErrVirtualFileBusy = errors.New("device or resource busy")
+var ErrVirtualFileNotStored = errors.New("cannot store the file")
+
+type mountPoint struct {
+	path      string
+	directory VirtualDirectory
+}
+
+type MountedDirectory struct {
+	root   *MemoryDirectory
+	mounts []mountPoint
+}
+
+func NewMountedDirectory(orchestrator *common.Orchestrator, namespace string, mounts []common.Mount) *MountedDirectory {
+	d := &MountedDirectory{
+		root: NewMemoryDirectory(),
+	}
+
+	for _, mount := range mounts {
+		mountPath := strings.TrimPrefix(path.Clean(mount.Path), "/")
+
+		// the mount point and its parents are directories of the root
+		names := splitVirtualPath(mountPath)
+		for i := range names {
+			d.root.Mkdir(strings.Join(names[:i+1], "/"))
+		}
+
+		var directory VirtualDirectory
+		switch mount.Type {
+		case common.MOUNT_BLOBS:
+			directory = NewBlobDirectory(orchestrator, mount.Source)
+		case common.MOUNT_PERSISTENCE:
+			directory = NewPersistentDirectory(orchestrator, namespace, mount.Source)
+		default:
+			directory = NewMemoryDirectory()
+		}
+
+		d.mounts = append(d.mounts, mountPoint{mountPath, directory})
+	}
+
+	return d
+}
+
+// find returns the directory containing a path, the path relative to it and if it is a mount point
+func (d *MountedDirectory) find(p string) (VirtualDirectory, string, bool) {
+	p = path.Clean(p)
+	for _, mount := range d.mounts {
+		if p == mount.path {
+			return mount.directory, ".", true
+		}
+		if strings.HasPrefix(p, mount.path+"/") {
+			return mount.directory, p[len(mount.path)+1:], false
+		}
+	}
+
+	return d.root, p, false
+}
+
+// Sync writes back the files opened in the persistence mounts
+func (d *MountedDirectory) Sync() error {
+	var err error
+	for _, mount := range d.mounts {
+		if directory, ok := mount.directory.(*PersistentDirectory); ok {
+			if syncErr := directory.Sync(); syncErr != nil && err == nil {
+				err = syncErr
+			}
+		}
+	}
+
+	return err
+}
+
+// containsMountPoint tells if a directory of the root contains mount points
+func (d *MountedDirectory) containsMountPoint(p string) bool {
+	p = path.Clean(p)
+	for _, mount := range d.mounts {
+		if p == "." || strings.HasPrefix(mount.path, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *MountedDirectory) Open(p string, flags int) (VirtualFile, error) {
+	directory, relativePath, _ := d.find(p)
+
+	file, err := directory.Open(relativePath, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := file.(*OpenedVirtualDirectory); ok {
+		return &OpenedVirtualDirectory{
+			Directory: d,
+			Path:      path.Clean(p),
+		}, nil
+	}
+
+	return file, nil
+}
+
+func (d *MountedDirectory) Stat(p string) (VirtualFileStat, error) {
+	directory, relativePath, _ := d.find(p)
+	return directory.Stat(relativePath)
+}
+
+func (d *MountedDirectory) SetTimes(p string, accessTime time.Time, modTime time.Time) error {
+	directory, relativePath, _ := d.find(p)
+	return directory.SetTimes(relativePath, accessTime, modTime)
+}
+
+func (d *MountedDirectory) ReadDir(p string) ([]VirtualDirEntry, error) {
+	directory, relativePath, _ := d.find(p)
+	return directory.ReadDir(relativePath)
+}
+
+func (d *MountedDirectory) Mkdir(p string) error {
+	directory, relativePath, isMountPoint := d.find(p)
+	if isMountPoint {
+		return ErrVirtualFileExists
+	}
+
+	return directory.Mkdir(relativePath)
+}
+
+func (d *MountedDirectory) Rmdir(p string) error {
+	directory, relativePath, isMountPoint := d.find(p)
+	if isMountPoint {
+		return ErrVirtualFileBusy
+	}
+
+	return directory.Rmdir(relativePath)
+}
+
+func (d *MountedDirectory) Unlink(p string) error {
+	directory, relativePath, isMountPoint := d.find(p)
+	if isMountPoint {
+		return ErrVirtualFileIsDirectory
+	}
+
+	return directory.Unlink(relativePath)
+}
+
+func (d *MountedDirectory) Rename(oldPath string, newPath string) error {
+	oldDirectory, oldRelativePath, oldIsMountPoint := d.find(oldPath)
+	newDirectory, newRelativePath, newIsMountPoint := d.find(newPath)
+	if oldIsMountPoint || newIsMountPoint {
+		return ErrVirtualFileBusy
+	}
+	if oldDirectory != newDirectory {
+		return ErrVirtualFileCrossDevice
+	}
+	if oldDirectory == VirtualDirectory(d.root) && d.containsMountPoint(oldRelativePath) {
+		return ErrVirtualFileBusy
+	}
+
+	return oldDirectory.Rename(oldRelativePath, newRelativePath)
+}
+
+func pathInode(p string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(p))
+	return h.Sum64()
+}
+
+/*
+	BlobDirectory is the read-only directory of the blobs which names start with a prefix, the
+	rest of their name being their path. Directories are implied by the blob names.
+*/
+type BlobDirectory struct {
+	orchestrator *common.Orchestrator
+	prefix       string
+
+	load        sync.Once
+	files       map[string]string // path => blob techID
+	directories map[string]bool
+}
+
+func NewBlobDirectory(orchestrator *common.Orchestrator, prefix string) *BlobDirectory {
+	return &BlobDirectory{
+		orchestrator: orchestrator,
+		prefix:       prefix,
+	}
+}
+
+// list reads the blob names once, at the first access to the directory
+func (d *BlobDirectory) list() {
+	d.load.Do(func() {
+		d.files = make(map[string]string)
+		d.directories = map[string]bool{".": true}
+
+		for name, techID := range d.orchestrator.GetBlobNamesWithPrefix(d.prefix) {
+			relativePath := name[len(d.prefix):]
+			if relativePath == "" || strings.HasPrefix(relativePath, "/") {
+				continue
+			}
+
+			relativePath = path.Clean(relativePath)
+			if relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+				continue
+			}
+
+			d.files[relativePath] = techID
+			for directory := path.Dir(relativePath); directory != "."; directory = path.Dir(directory) {
+				d.directories[directory] = true
+			}
+		}
+	})
+}
+
+func (d *BlobDirectory) Open(p string, flags int) (VirtualFile, error) {
+	d.list()
+	p = path.Clean(p)
+
+	if d.directories[p] {
+		if flags&(VIRTUAL_OPEN_CREATE|VIRTUAL_OPEN_TRUNCATE) != 0 {
+			return nil, ErrVirtualFileIsDirectory
+		}
+
+		return &OpenedVirtualDirectory{
+			Directory: d,
+			Path:      p,
+		}, nil
+	}
+
+	techID, ok := d.files[p]
+	if !ok {
+		if flags&VIRTUAL_OPEN_CREATE != 0 {
+			return nil, ErrVirtualFileReadOnly
+		}
+		return nil, ErrVirtualFileNotFound
+	}
+	if flags&VIRTUAL_OPEN_CREATE != 0 && flags&VIRTUAL_OPEN_EXCLUSIVE != 0 {
+		return nil, ErrVirtualFileExists
+	}
+	if flags&VIRTUAL_OPEN_DIRECTORY != 0 {
+		return nil, ErrVirtualFileNotDirectory
+	}
+	if flags&VIRTUAL_OPEN_TRUNCATE != 0 {
+		return nil, ErrVirtualFileReadOnly
+	}
+
+	data, err := d.orchestrator.GetBlobBytesByTechID(techID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bytesFile{
+		data:  data,
+		inode: pathInode(p),
+	}, nil
+}
+
+func (d *BlobDirectory) Stat(p string) (VirtualFileStat, error) {
+	d.list()
+	p = path.Clean(p)
+
+	if d.directories[p] {
+		return VirtualFileStat{
+			FileType: VIRTUAL_FILE_TYPE_DIRECTORY,
+			Inode:    pathInode(p),
+		}, nil
+	}
+
+	techID, ok := d.files[p]
+	if !ok {
+		return VirtualFileStat{}, ErrVirtualFileNotFound
+	}
+
+	data, err := d.orchestrator.GetBlobBytesByTechID(techID)
+	if err != nil {
+		return VirtualFileStat{}, err
+	}
+
+	return VirtualFileStat{
+		FileType: VIRTUAL_FILE_TYPE_REGULAR_FILE,
+		Inode:    pathInode(p),
+		Size:     uint64(len(data)),
+	}, nil
+}
+
+func (d *BlobDirectory) SetTimes(p string, accessTime time.Time, modTime time.Time) error {
+	return ErrVirtualFileReadOnly
+}
+
+func (d *BlobDirectory) ReadDir(p string) ([]VirtualDirEntry, error) {
+	d.list()
+	p = path.Clean(p)
+
+	if !d.directories[p] {
+		if _, ok := d.files[p]; ok {
+			return nil, ErrVirtualFileNotDirectory
+		}
+		return nil, ErrVirtualFileNotFound
+	}
+
+	entries := []VirtualDirEntry{}
+	for file := range d.files {
+		if file != "." && path.Dir(file) == p {
+			entries = append(entries, VirtualDirEntry{path.Base(file), VIRTUAL_FILE_TYPE_REGULAR_FILE, pathInode(file)})
+		}
+	}
+	for directory := range d.directories {
+		if directory != "." && path.Dir(directory) == p {
+			entries = append(entries, VirtualDirEntry{path.Base(directory), VIRTUAL_FILE_TYPE_DIRECTORY, pathInode(directory)})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries, nil
+}
+
+func (d *BlobDirectory) Mkdir(p string) error {
+	return ErrVirtualFileReadOnly
+}
+
+func (d *BlobDirectory) Rmdir(p string) error {
+	return ErrVirtualFileReadOnly
+}
+
+func (d *BlobDirectory) Unlink(p string) error {
+	return ErrVirtualFileReadOnly
+}
+
+func (d *BlobDirectory) Rename(oldPath string, newPath string) error {
+	return ErrVirtualFileReadOnly
+}
+
+/*
+	PersistentDirectory stores its files in a persistence namespace, under a key prefix : the
+	file "a/b" is the key "PREFIX/a/b". Directories are implied by the keys, an empty directory
+	is kept with a "PREFIX/a/" key.
+*/
+type PersistentDirectory struct {
+	orchestrator *common.Orchestrator
+	namespace    string
+	prefix       string
+
+	lock   sync.Mutex
+	opened map[*persistentFile]bool
+}
+
+func NewPersistentDirectory(orchestrator *common.Orchestrator, namespace string, prefix string) *PersistentDirectory {
+	return &PersistentDirectory{
+		orchestrator: orchestrator,
+		namespace:    namespace,
+		prefix:       strings.TrimSuffix(prefix, "/"),
+		opened:       make(map[*persistentFile]bool),
+	}
+}
+
+// openedFiles returns the files of the directory which are not closed
+func (d *PersistentDirectory) openedFiles() []*persistentFile {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	files := []*persistentFile{}
+	for file := range d.opened {
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// Sync writes back the modified files of the directory
+func (d *PersistentDirectory) Sync() error {
+	var err error
+	for _, file := range d.openedFiles() {
+		if syncErr := file.Sync(); syncErr != nil && err == nil {
+			err = syncErr
+		}
+	}
+
+	return err
+}
+
+// detach stops writing back the opened files stored at key, or under key for a directory key
+func (d *PersistentDirectory) detach(key []byte) {
+	for _, file := range d.openedFiles() {
+		if file.key == string(key) || (strings.HasSuffix(string(key), "/") && strings.HasPrefix(file.key, string(key))) {
+			file.detach()
+		}
+	}
+}
+
+func (d *PersistentDirectory) fileKey(p string) []byte {
+	return []byte(d.prefix + "/" + p)
+}
+
+func (d *PersistentDirectory) directoryKey(p string) []byte {
+	if p == "." {
+		return []byte(d.prefix + "/")
+	}
+
+	return []byte(d.prefix + "/" + p + "/")
+}
+
+// lookup returns the type of the file at path, and its content for a regular file. The opened
+// files are written back first so that the database is up to date
+func (d *PersistentDirectory) lookup(p string) (uint8, []byte, error) {
+	if p == "." {
+		return VIRTUAL_FILE_TYPE_DIRECTORY, nil, nil
+	}
+
+	err := d.Sync()
+	if err != nil {
+		return VIRTUAL_FILE_TYPE_UNKNOWN, nil, err
+	}
+
+	if data, ok := d.orchestrator.PersistenceGet(d.namespace, d.fileKey(p)); ok {
+		return VIRTUAL_FILE_TYPE_REGULAR_FILE, data, nil
+	}
+
+	keys, err := d.orchestrator.PersistenceGetKeys(d.namespace, d.directoryKey(p))
+	if err != nil {
+		return VIRTUAL_FILE_TYPE_UNKNOWN, nil, err
+	}
+	if len(keys) > 0 {
+		return VIRTUAL_FILE_TYPE_DIRECTORY, nil, nil
+	}
+
+	return VIRTUAL_FILE_TYPE_UNKNOWN, nil, ErrVirtualFileNotFound
+}
+
+// checkParent verifies that the directory which would contain path exists
+func (d *PersistentDirectory) checkParent(p string) error {
+	fileType, _, err := d.lookup(path.Dir(p))
+	if err != nil {
+		return err
+	}
+	if fileType != VIRTUAL_FILE_TYPE_DIRECTORY {
+		return ErrVirtualFileNotDirectory
+	}
+
+	return nil
+}
+
+func (d *PersistentDirectory) Open(p string, flags int) (VirtualFile, error) {
+	p = path.Clean(p)
+
+	fileType, data, err := d.lookup(p)
+	if err == ErrVirtualFileNotFound && flags&VIRTUAL_OPEN_CREATE != 0 && flags&VIRTUAL_OPEN_DIRECTORY == 0 {
+		err = d.checkParent(p)
+		if err != nil {
+			return nil, err
+		}
+
+		fileType, data = VIRTUAL_FILE_TYPE_REGULAR_FILE, []byte{}
+		d.orchestrator.PersistenceSet(d.namespace, d.fileKey(p), data)
+	} else if err != nil {
+		return nil, err
+	} else if flags&VIRTUAL_OPEN_CREATE != 0 && flags&VIRTUAL_OPEN_EXCLUSIVE != 0 {
+		return nil, ErrVirtualFileExists
+	}
+
+	if fileType == VIRTUAL_FILE_TYPE_DIRECTORY {
+		if flags&(VIRTUAL_OPEN_CREATE|VIRTUAL_OPEN_TRUNCATE) != 0 {
+			return nil, ErrVirtualFileIsDirectory
+		}
+
+		return &OpenedVirtualDirectory{
+			Directory: d,
+			Path:      p,
+		}, nil
+	}
+
+	if flags&VIRTUAL_OPEN_DIRECTORY != 0 {
+		return nil, ErrVirtualFileNotDirectory
+	}
+
+	key := d.fileKey(p)
+	if flags&VIRTUAL_OPEN_TRUNCATE != 0 && len(data) > 0 {
+		data = []byte{}
+		d.orchestrator.PersistenceSet(d.namespace, key, data)
+	}
+
+	file := &persistentFile{
+		bytesFile: bytesFile{
+			data:     data,
+			inode:    pathInode(string(key)),
+			writable: true,
+		},
+		directory: d,
+		key:       string(key),
+	}
+
+	d.lock.Lock()
+	d.opened[file] = true
+	d.lock.Unlock()
+
+	return file, nil
+}
+
+func (d *PersistentDirectory) Stat(p string) (VirtualFileStat, error) {
+	p = path.Clean(p)
+
+	fileType, data, err := d.lookup(p)
+	if err != nil {
+		return VirtualFileStat{}, err
+	}
+
+	return VirtualFileStat{
+		FileType: fileType,
+		Inode:    pathInode(string(d.fileKey(p))),
+		Size:     uint64(len(data)),
+	}, nil
+}
+
+func (d *PersistentDirectory) SetTimes(p string, accessTime time.Time, modTime time.Time) error {
+	_, _, err := d.lookup(path.Clean(p))
+	return err
+}
+
+func (d *PersistentDirectory) ReadDir(p string) ([]VirtualDirEntry, error) {
+	p = path.Clean(p)
+
+	fileType, _, err := d.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	if fileType != VIRTUAL_FILE_TYPE_DIRECTORY {
+		return nil, ErrVirtualFileNotDirectory
+	}
+
+	directoryKey := d.directoryKey(p)
+	keys, err := d.orchestrator.PersistenceGetKeys(d.namespace, directoryKey)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []VirtualDirEntry{}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		rest := string(key[len(directoryKey):])
+		if rest == "" {
+			continue
+		}
+
+		entry := VirtualDirEntry{Name: rest, FileType: VIRTUAL_FILE_TYPE_REGULAR_FILE}
+		if slash := strings.Index(rest, "/"); slash >= 0 {
+			entry.Name = rest[:slash]
+			entry.FileType = VIRTUAL_FILE_TYPE_DIRECTORY
+		}
+		if seen[entry.Name] {
+			continue
+		}
+		seen[entry.Name] = true
+
+		entry.Inode = pathInode(string(directoryKey) + entry.Name)
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries, nil
+}
+
+func (d *PersistentDirectory) Mkdir(p string) error {
+	p = path.Clean(p)
+	if p == "." {
+		return ErrVirtualFileExists
+	}
+
+	err := d.checkParent(p)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = d.lookup(p)
+	if err == nil {
+		return ErrVirtualFileExists
+	}
+	if err != ErrVirtualFileNotFound {
+		return err
+	}
+
+	d.orchestrator.PersistenceSet(d.namespace, d.directoryKey(p), []byte{})
+
+	return nil
+}
+
+func (d *PersistentDirectory) Rmdir(p string) error {
+	p = path.Clean(p)
+	if p == "." {
+		return ErrVirtualFileBusy
+	}
+
+	fileType, _, err := d.lookup(p)
+	if err != nil {
+		return err
+	}
+	if fileType != VIRTUAL_FILE_TYPE_DIRECTORY {
+		return ErrVirtualFileNotDirectory
+	}
+
+	directoryKey := d.directoryKey(p)
+	keys, err := d.orchestrator.PersistenceGetKeys(d.namespace, directoryKey)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 1 || (len(keys) == 1 && string(keys[0]) != string(directoryKey)) {
+		return ErrVirtualDirectoryNotEmpty
+	}
+
+	d.orchestrator.PersistenceDelete(d.namespace, directoryKey)
+
+	return nil
+}
+
+func (d *PersistentDirectory) Unlink(p string) error {
+	p = path.Clean(p)
+
+	fileType, _, err := d.lookup(p)
+	if err != nil {
+		return err
+	}
+	if fileType == VIRTUAL_FILE_TYPE_DIRECTORY {
+		return ErrVirtualFileIsDirectory
+	}
+
+	d.orchestrator.PersistenceDelete(d.namespace, d.fileKey(p))
+	d.detach(d.fileKey(p))
+
+	return nil
+}
+
+func (d *PersistentDirectory) Rename(oldPath string, newPath string) error {
+	oldPath, newPath = path.Clean(oldPath), path.Clean(newPath)
+	if oldPath == "." || newPath == "." {
+		return ErrVirtualFileBusy
+	}
+
+	fileType, data, err := d.lookup(oldPath)
+	if err != nil {
+		return err
+	}
+	if oldPath == newPath {
+		return nil
+	}
+
+	// a directory cannot be moved inside itself
+	if fileType == VIRTUAL_FILE_TYPE_DIRECTORY && strings.HasPrefix(newPath, oldPath+"/") {
+		return ErrVirtualFileNotSupported
+	}
+
+	err = d.checkParent(newPath)
+	if err != nil {
+		return err
+	}
+
+	targetType, _, err := d.lookup(newPath)
+	if err == nil {
+		if fileType == VIRTUAL_FILE_TYPE_DIRECTORY && targetType != VIRTUAL_FILE_TYPE_DIRECTORY {
+			return ErrVirtualFileNotDirectory
+		}
+		if fileType != VIRTUAL_FILE_TYPE_DIRECTORY && targetType == VIRTUAL_FILE_TYPE_DIRECTORY {
+			return ErrVirtualFileIsDirectory
+		}
+		if targetType == VIRTUAL_FILE_TYPE_DIRECTORY {
+			err = d.Rmdir(newPath)
+			if err != nil {
+				return err
+			}
+		}
+	} else if err != ErrVirtualFileNotFound {
+		return err
+	}
+
+	if fileType != VIRTUAL_FILE_TYPE_DIRECTORY {
+		d.orchestrator.PersistenceSet(d.namespace, d.fileKey(newPath), data)
+		d.orchestrator.PersistenceDelete(d.namespace, d.fileKey(oldPath))
+		d.detach(d.fileKey(oldPath))
+		d.detach(d.fileKey(newPath))
+		return nil
+	}
+
+	oldKey, newKey := d.directoryKey(oldPath), d.directoryKey(newPath)
+	d.detach(oldKey)
+	keys, err := d.orchestrator.PersistenceGetKeys(d.namespace, oldKey)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value, ok := d.orchestrator.PersistenceGet(d.namespace, key)
+		if !ok {
+			continue
+		}
+
+		d.orchestrator.PersistenceSet(d.namespace, append(dupBytes(newKey), key[len(oldKey):]...), value)
+		d.orchestrator.PersistenceDelete(d.namespace, key)
+	}
+
+	return nil
+}
+
+// bytesFile is an opened blob or persistent file, dirty is set when a writable file is modified
+type bytesFile struct {
+	lock     sync.Mutex
+	data     []byte
+	pos      int64
+	inode    uint64
+	writable bool
+	dirty    bool
+}
+
+func (f *bytesFile) Read(buffer []byte) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.pos >= int64(len(f.data)) {
+		return 0
+	}
+
+	l := copy(buffer, f.data[f.pos:])
+	f.pos += int64(l)
+
+	return l
+}
+
+func (f *bytesFile) Write(buffer []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.writable {
+		return 0, ErrVirtualFileReadOnly
+	}
+
+	// the capacity grows geometrically so that appending stays linear
+	end := f.pos + int64(len(buffer))
+	if end > int64(len(f.data)) {
+		if end > int64(cap(f.data)) {
+			data := make([]byte, end, 2*end)
+			copy(data, f.data)
+			f.data = data
+		}
+		f.data = f.data[:end]
+	}
+
+	copy(f.data[f.pos:], buffer)
+	f.pos = end
+	f.dirty = true
+
+	return len(buffer), nil
+}
+
+func (f *bytesFile) Close() int {
+	return 0
+}
+
+func (f *bytesFile) Seek(offset int64, whence int) (int64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	f.pos = offset
+
+	return f.pos, nil
+}
+
+func (f *bytesFile) Stat() (VirtualFileStat, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return VirtualFileStat{
+		FileType: VIRTUAL_FILE_TYPE_REGULAR_FILE,
+		Inode:    f.inode,
+		Size:     uint64(len(f.data)),
+	}, nil
+}
+
+func (f *bytesFile) Truncate(size int64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.writable {
+		return ErrVirtualFileReadOnly
+	}
+	if size < 0 {
+		return errors.New("negative size")
+	}
+
+	data := make([]byte, size)
+	copy(data, f.data)
+	f.data = data
+	f.dirty = true
+
+	return nil
+}
+
+// persistentFile is an opened file of a persistent directory, written back to its key
+type persistentFile struct {
+	bytesFile
+	directory *PersistentDirectory
+	key       string
+	detached  bool
+}
+
+// Sync writes the file back if it was modified
+func (f *persistentFile) Sync() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !f.dirty || f.detached {
+		return nil
+	}
+
+	if !f.directory.orchestrator.PersistenceSet(f.directory.namespace, []byte(f.key), f.data) {
+		return ErrVirtualFileNotStored
+	}
+	f.dirty = false
+
+	return nil
+}
+
+func (f *persistentFile) Close() int {
+	err := f.Sync()
+
+	f.directory.lock.Lock()
+	delete(f.directory.opened, f)
+	f.directory.lock.Unlock()
+
+	if err != nil {
+		return -1
+	}
+
+	return 0
+}
+
+func (f *persistentFile) detach() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.detached = true
+}
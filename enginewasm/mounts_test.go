@@ -0,0 +1,122 @@
+package enginewasm
+
+import (
+	"testing"
+
+	"github.com/ltearno/my-own-cluster/common"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+func newTestMountedDirectory(t *testing.T) (*common.Orchestrator, *MountedDirectory) {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mounts, err := common.ParseMounts("/data=persistence:/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return orchestrator, NewMountedDirectory(orchestrator, "test", mounts)
+}
+
+func storedFile(orchestrator *common.Orchestrator, key string) string {
+	data, _ := orchestrator.PersistenceGet("test", []byte(key))
+	return string(data)
+}
+
+func TestPersistentFilesAreWrittenBack(t *testing.T) {
+	orchestrator, directory := newTestMountedDirectory(t)
+
+	file, err := directory.Open("data/log", VIRTUAL_OPEN_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		file.Write([]byte("line\n"))
+	}
+
+	// the writes are kept in memory until the file is synced
+	if stored := storedFile(orchestrator, "/files/log"); stored != "" {
+		t.Fatalf("%d bytes stored before sync", len(stored))
+	}
+	err = file.(SyncableVirtualFile).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := storedFile(orchestrator, "/files/log"); len(stored) != 5000 {
+		t.Fatalf("%d bytes stored after sync", len(stored))
+	}
+
+	// other operations on the directory see the modifications
+	file.Write([]byte("end\n"))
+	stat, err := directory.Stat("data/log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Size != 5004 {
+		t.Fatalf("stat size is %d", stat.Size)
+	}
+
+	// a file left opened is written back at the end of the execution
+	file.Write([]byte("more\n"))
+	err = directory.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := storedFile(orchestrator, "/files/log"); len(stored) != 5009 {
+		t.Fatalf("%d bytes stored at the end of the execution", len(stored))
+	}
+}
+
+func TestRemovedPersistentFilesAreNotWrittenBack(t *testing.T) {
+	orchestrator, directory := newTestMountedDirectory(t)
+
+	file, err := directory.Open("data/removed", VIRTUAL_OPEN_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte("content"))
+
+	err = directory.Unlink("data/removed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Write([]byte(" after removal"))
+	file.Close()
+
+	if _, ok := orchestrator.PersistenceGet("test", []byte("/files/removed")); ok {
+		t.Fatal("the removed file was written back")
+	}
+
+	renamed, err := directory.Open("data/old", VIRTUAL_OPEN_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamed.Write([]byte("content"))
+
+	err = directory.Rename("data/old", "data/new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed.Write([]byte(" after rename"))
+	renamed.Close()
+
+	if _, ok := orchestrator.PersistenceGet("test", []byte("/files/old")); ok {
+		t.Fatal("the renamed file was written back to its old key")
+	}
+	if stored := storedFile(orchestrator, "/files/new"); stored != "content" {
+		t.Fatalf("the renamed file contains %q", stored)
+	}
+}
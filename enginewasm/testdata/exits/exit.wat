@@ -0,0 +1,7 @@
+;; exits with the code 3 through WASI
+(module
+  (import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+  (memory (export "memory") 1)
+  (func $_start (export "_start")
+    i32.const 3
+    call $proc_exit))
@@ -0,0 +1,6 @@
+;; calls "test"::"fail", a host function failing with an error
+(module
+  (import "test" "fail" (func $fail (result i32)))
+  (func $_start (export "_start")
+    call $fail
+    drop))
@@ -0,0 +1,9 @@
+;; traps on a load past the end of its single memory page, in a function called by _start
+(module
+  (memory (export "memory") 1)
+  (func $_start (export "_start")
+    call $load
+    drop)
+  (func $load (result i32)
+    i32.const 70000
+    i32.load))
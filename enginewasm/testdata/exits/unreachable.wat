@@ -0,0 +1,6 @@
+;; traps on an unreachable instruction, in a function called by _start
+(module
+  (func $_start (export "_start")
+    call $inner)
+  (func $inner
+    unreachable))
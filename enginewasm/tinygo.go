@@ -2,52 +2,49 @@ package enginewasm
 
 import (
 	"fmt"
-	"unsafe"
 
-	"github.com/ltearno/go-wasm3"
+	"github.com/ltearno/my-own-cluster/common"
 )
 
-func BindTinyGoRuntimeAPI(wctx *WasmProcessContext) {
-	importedModules := wctx.GetImportedModules()
+func BindTinyGoRuntimeAPI(host WasmHost) {
+	importedModules := host.GetImportedModules()
 	if _, ok := importedModules["env"]; !ok {
 		return
 	}
 
-	if wctx.Fctx.Trace {
+	if host.GetFunctionExecutionContext().Trace {
 		fmt.Println("binding TinyGo 0.11.0 API...")
 	}
 
-	wctx.Runtime.AttachFunction("env", "io_get_stdout", "i()", func(runtime wasm3.RuntimeT, sp unsafe.Pointer, mem unsafe.Pointer) int {
-		if wctx.Fctx.Trace {
+	host.BindHostFunction("env", "io_get_stdout", "i()", func(cs *CallSite) (uint32, int) {
+		if host.GetFunctionExecutionContext().Trace {
 			fmt.Printf("TinyGo talks to us !!!!\n")
 		}
 
-		*(*uint32)(sp) = 1
-
-		return 0
+		return 1, 0
 	})
 
-	wctx.BindAPIFunction("env", "resource_write", "i(iii)", func(wctx *WasmProcessContext, cs *CallSite) (uint32, error) {
+	host.BindAPIFunction("env", "resource_write", "i(iii)", func(fctx *common.FunctionExecutionContext, cs *CallSite) (uint32, error) {
 		fd := cs.GetParamUINT32(0)
 		buffer := cs.GetParamByteBuffer(1, 2)
 
-		if wctx.Fctx.Trace {
+		if fctx.Trace {
 			fmt.Printf("TinyGo on fd %d says '%s'\n", fd, string(buffer))
 		}
 
 		return uint32(len(buffer)), nil
 	})
 
-	wctx.BindNotYetImplementedFunction("env", "runtime.ticks", "i()")
-	wctx.BindNotYetImplementedFunction("env", "runtime.sleepTicks", "i(i)")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueLength", "i(*ii)")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueCall", "i(i*iiiiiii)")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueIndex", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueGet", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueNew", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueSet", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueSetIndex", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.stringVal", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valuePrepareString", "i()")
-	wctx.BindNotYetImplementedFunction("env", "syscall/js.valueLoadString", "i()")
+	host.BindNotYetImplementedFunction("env", "runtime.ticks", "i()")
+	host.BindNotYetImplementedFunction("env", "runtime.sleepTicks", "i(i)")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueLength", "i(*ii)")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueCall", "i(i*iiiiiii)")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueIndex", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueGet", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueNew", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueSet", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueSetIndex", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.stringVal", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valuePrepareString", "i()")
+	host.BindNotYetImplementedFunction("env", "syscall/js.valueLoadString", "i()")
 }
@@ -0,0 +1,160 @@
+package enginewasm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+/*
+	Traps
+
+	The wasm engines describe traps with their own messages, TrapKind gives the common kind of a
+	trap from them. Backtraces are symbolized with the function names of the "name" custom section
+	of the module when it is present, function indexes are used otherwise.
+*/
+
+var trapKinds = []struct {
+	pattern string
+	kind    string
+}{
+	{"unreachable", common.TRAP_UNREACHABLE},
+	{"out of bounds memory", common.TRAP_OUT_OF_BOUNDS_MEMORY_ACCESS},
+	{"memory out of bounds", common.TRAP_OUT_OF_BOUNDS_MEMORY_ACCESS},
+	{"stack overflow", common.TRAP_STACK_OVERFLOW},
+	{"call stack exhausted", common.TRAP_STACK_OVERFLOW},
+	{"divide by zero", common.TRAP_DIVISION_BY_ZERO},
+	{"division by zero", common.TRAP_DIVISION_BY_ZERO},
+	{"integer overflow", common.TRAP_INTEGER_OVERFLOW},
+	{"conversion to integer", common.TRAP_INVALID_CONVERSION},
+	{"indirect call", common.TRAP_INDIRECT_CALL_MISMATCH},
+	{"undefined element", common.TRAP_UNDEFINED_ELEMENT},
+	{"uninitialized element", common.TRAP_UNDEFINED_ELEMENT},
+	{"abort", common.TRAP_ABORT},
+}
+
+// TrapKind returns the kind of a trap from its engine message
+func TrapKind(message string) string {
+	message = strings.ToLower(message)
+	for _, trapKind := range trapKinds {
+		if strings.Contains(message, trapKind.pattern) {
+			return trapKind.kind
+		}
+	}
+
+	return common.TRAP_UNKNOWN
+}
+
+// FunctionNames reads the function names of the "name" custom section, by function index. Functions
+// are missing when the module has no name section or when it is malformed.
+func FunctionNames(wasmBytes []byte) map[uint32]string {
+	names := make(map[uint32]string)
+
+	if len(wasmBytes) < 8 || string(wasmBytes[:4]) != "\x00asm" {
+		return names
+	}
+
+	r := &wasmReader{data: wasmBytes, pos: 8}
+	for r.pos < len(r.data) {
+		id := r.byte()
+		size := int(r.uleb())
+		if r.failed || size > len(r.data)-r.pos {
+			return names
+		}
+
+		section := &wasmReader{data: r.data[r.pos : r.pos+size]}
+		r.pos += size
+
+		if id != 0 || section.name() != "name" {
+			continue
+		}
+
+		for section.pos < len(section.data) && !section.failed {
+			subsectionID := section.byte()
+			subsectionSize := int(section.uleb())
+			if section.failed || subsectionSize > len(section.data)-section.pos {
+				return names
+			}
+
+			subsection := &wasmReader{data: section.data[section.pos : section.pos+subsectionSize]}
+			section.pos += subsectionSize
+
+			// function names subsection
+			if subsectionID != 1 {
+				continue
+			}
+
+			count := subsection.uleb()
+			for i := uint32(0); i < count && !subsection.failed; i++ {
+				index := subsection.uleb()
+				name := subsection.name()
+				if !subsection.failed {
+					names[index] = name
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// BacktraceFrame formats a frame of a trap backtrace
+func BacktraceFrame(names map[uint32]string, functionIndex uint32, functionOffset uint) string {
+	if name, ok := names[functionIndex]; ok {
+		return fmt.Sprintf("%s+0x%x", name, functionOffset)
+	}
+
+	return fmt.Sprintf("<func %d>+0x%x", functionIndex, functionOffset)
+}
+
+type wasmReader struct {
+	data   []byte
+	pos    int
+	failed bool
+	// set when the data is well formed but not understood
+	err error
+}
+
+func (r *wasmReader) byte() byte {
+	if r.pos >= len(r.data) {
+		r.failed = true
+		return 0
+	}
+
+	b := r.data[r.pos]
+	r.pos++
+
+	return b
+}
+
+func (r *wasmReader) uleb() uint32 {
+	result := uint32(0)
+	for shift := uint(0); shift < 35; shift += 7 {
+		b := r.byte()
+		if r.failed {
+			return 0
+		}
+
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result
+		}
+	}
+
+	r.failed = true
+	return 0
+}
+
+func (r *wasmReader) name() string {
+	length := int(r.uleb())
+	if r.failed || length > len(r.data)-r.pos {
+		r.failed = true
+		return ""
+	}
+
+	name := string(r.data[r.pos : r.pos+length])
+	r.pos += length
+
+	return name
+}
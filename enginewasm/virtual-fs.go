@@ -0,0 +1,564 @@
+package enginewasm
+
+import (
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+	Virtual directories
+
+	Besides the virtual files (exchange buffers, web resources...), posix programs see a tree of
+	virtual directories through the WASI layer. A VirtualDirectory resolves slash separated paths
+	relative to its root, which never contain ".." or a leading "/" : the WASI layer resolves them
+	against the opened directory and refuses paths escaping it.
+
+	Opening a directory gives an OpenedVirtualDirectory, so directories are file descriptors like
+	the others and can be used as the base of the following path operations.
+*/
+
+var ErrVirtualFileNotFound = errors.New("no such file or directory")
+var ErrVirtualFileExists = errors.New("file exists")
+var ErrVirtualFileNotDirectory = errors.New("not a directory")
+var ErrVirtualFileIsDirectory = errors.New("is a directory")
+var ErrVirtualDirectoryNotEmpty = errors.New("directory not empty")
+var ErrVirtualFileReadOnly = errors.New("read-only file system")
+var ErrVirtualFileNotSupported = errors.New("operation not supported")
+
+const (
+	VIRTUAL_FILE_TYPE_UNKNOWN          = 0
+	VIRTUAL_FILE_TYPE_CHARACTER_DEVICE = 2
+	VIRTUAL_FILE_TYPE_DIRECTORY        = 3
+	VIRTUAL_FILE_TYPE_REGULAR_FILE     = 4
+)
+
+// flags for VirtualDirectory.Open
+const (
+	VIRTUAL_OPEN_CREATE    = 1 << 0
+	VIRTUAL_OPEN_EXCLUSIVE = 1 << 1
+	VIRTUAL_OPEN_TRUNCATE  = 1 << 2
+	VIRTUAL_OPEN_DIRECTORY = 1 << 3
+)
+
+// VirtualFileStat describes a virtual file, the file types are the WASI ones
+type VirtualFileStat struct {
+	FileType   uint8
+	Inode      uint64
+	Size       uint64
+	AccessTime time.Time
+	ModTime    time.Time
+	ChangeTime time.Time
+}
+
+// StatVirtualFile is implemented by virtual files able to describe themselves
+type StatVirtualFile interface {
+	VirtualFile
+	Stat() (VirtualFileStat, error)
+}
+
+// TruncatableVirtualFile is implemented by virtual files which size can be changed
+type TruncatableVirtualFile interface {
+	VirtualFile
+	Truncate(size int64) error
+}
+
+// SyncableVirtualFile is implemented by virtual files keeping modifications in memory
+type SyncableVirtualFile interface {
+	VirtualFile
+	Sync() error
+}
+
+// TimestampedVirtualFile is implemented by virtual files which times can be changed
+type TimestampedVirtualFile interface {
+	VirtualFile
+	SetTimes(accessTime time.Time, modTime time.Time) error
+}
+
+type VirtualDirEntry struct {
+	Name     string
+	FileType uint8
+	Inode    uint64
+}
+
+type VirtualDirectory interface {
+	Open(path string, flags int) (VirtualFile, error)
+	Stat(path string) (VirtualFileStat, error)
+	SetTimes(path string, accessTime time.Time, modTime time.Time) error
+	ReadDir(path string) ([]VirtualDirEntry, error)
+	Mkdir(path string) error
+	Rmdir(path string) error
+	Unlink(path string) error
+	Rename(oldPath string, newPath string) error
+}
+
+// OpenedVirtualDirectory is the virtual file of an opened directory
+type OpenedVirtualDirectory struct {
+	Directory VirtualDirectory
+	Path      string
+}
+
+func (d *OpenedVirtualDirectory) Read(buffer []byte) int {
+	return 0
+}
+
+func (d *OpenedVirtualDirectory) Write(buffer []byte) (int, error) {
+	return 0, ErrVirtualFileIsDirectory
+}
+
+func (d *OpenedVirtualDirectory) Close() int {
+	return 0
+}
+
+func (d *OpenedVirtualDirectory) Stat() (VirtualFileStat, error) {
+	return d.Directory.Stat(d.Path)
+}
+
+func (d *OpenedVirtualDirectory) SetTimes(accessTime time.Time, modTime time.Time) error {
+	return d.Directory.SetTimes(d.Path, accessTime, modTime)
+}
+
+// Resolve returns the path of a file relative to this directory, false if it escapes the directory root
+func (d *OpenedVirtualDirectory) Resolve(relativePath string) (string, bool) {
+	if strings.HasPrefix(relativePath, "/") {
+		return "", false
+	}
+
+	resolved := path.Clean(path.Join(d.Path, relativePath))
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", false
+	}
+
+	return resolved, true
+}
+
+/*
+	MemoryDirectory is a writable directory tree living in memory. Its content disappears with
+	it, it is the scratch space of posix executions.
+*/
+type MemoryDirectory struct {
+	lock      sync.Mutex
+	root      *memoryNode
+	nextInode uint64
+}
+
+type memoryNode struct {
+	inode    uint64
+	children map[string]*memoryNode // nil for regular files
+	data     []byte
+
+	accessTime time.Time
+	modTime    time.Time
+	changeTime time.Time
+}
+
+func NewMemoryDirectory() *MemoryDirectory {
+	d := &MemoryDirectory{
+		nextInode: 1,
+	}
+	d.root = d.newNode(true)
+
+	return d
+}
+
+func (d *MemoryDirectory) newNode(directory bool) *memoryNode {
+	now := time.Now()
+	node := &memoryNode{
+		inode:      d.nextInode,
+		accessTime: now,
+		modTime:    now,
+		changeTime: now,
+	}
+	d.nextInode++
+
+	if directory {
+		node.children = make(map[string]*memoryNode)
+	}
+
+	return node
+}
+
+func (n *memoryNode) isDirectory() bool {
+	return n.children != nil
+}
+
+func (n *memoryNode) stat() VirtualFileStat {
+	stat := VirtualFileStat{
+		FileType:   VIRTUAL_FILE_TYPE_REGULAR_FILE,
+		Inode:      n.inode,
+		Size:       uint64(len(n.data)),
+		AccessTime: n.accessTime,
+		ModTime:    n.modTime,
+		ChangeTime: n.changeTime,
+	}
+	if n.isDirectory() {
+		stat.FileType = VIRTUAL_FILE_TYPE_DIRECTORY
+		stat.Size = 0
+	}
+
+	return stat
+}
+
+func splitVirtualPath(p string) []string {
+	p = path.Clean(p)
+	if p == "." || p == "" {
+		return []string{}
+	}
+
+	return strings.Split(p, "/")
+}
+
+// lookup returns the node at path, must be called with the lock held
+func (d *MemoryDirectory) lookup(p string) (*memoryNode, error) {
+	node := d.root
+	for _, name := range splitVirtualPath(p) {
+		if !node.isDirectory() {
+			return nil, ErrVirtualFileNotDirectory
+		}
+
+		child, ok := node.children[name]
+		if !ok {
+			return nil, ErrVirtualFileNotFound
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+// lookupParent returns the directory containing path and the name in it, must be called with the lock held
+func (d *MemoryDirectory) lookupParent(p string) (*memoryNode, string, error) {
+	names := splitVirtualPath(p)
+	if len(names) == 0 {
+		return nil, "", ErrVirtualFileExists
+	}
+
+	parent, err := d.lookup(strings.Join(names[:len(names)-1], "/"))
+	if err != nil {
+		return nil, "", err
+	}
+	if !parent.isDirectory() {
+		return nil, "", ErrVirtualFileNotDirectory
+	}
+
+	return parent, names[len(names)-1], nil
+}
+
+func (d *MemoryDirectory) Open(p string, flags int) (VirtualFile, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	node, err := d.lookup(p)
+	if err == ErrVirtualFileNotFound && flags&VIRTUAL_OPEN_CREATE != 0 && flags&VIRTUAL_OPEN_DIRECTORY == 0 {
+		parent, name, err := d.lookupParent(p)
+		if err != nil {
+			return nil, err
+		}
+
+		node = d.newNode(false)
+		parent.children[name] = node
+		parent.modTime = node.modTime
+	} else if err != nil {
+		return nil, err
+	} else if flags&VIRTUAL_OPEN_CREATE != 0 && flags&VIRTUAL_OPEN_EXCLUSIVE != 0 {
+		return nil, ErrVirtualFileExists
+	}
+
+	if node.isDirectory() {
+		if flags&(VIRTUAL_OPEN_CREATE|VIRTUAL_OPEN_TRUNCATE) != 0 {
+			return nil, ErrVirtualFileIsDirectory
+		}
+
+		return &OpenedVirtualDirectory{
+			Directory: d,
+			Path:      path.Clean(p),
+		}, nil
+	}
+
+	if flags&VIRTUAL_OPEN_DIRECTORY != 0 {
+		return nil, ErrVirtualFileNotDirectory
+	}
+
+	if flags&VIRTUAL_OPEN_TRUNCATE != 0 {
+		node.data = nil
+		node.modTime = time.Now()
+	}
+
+	return &memoryFile{
+		directory: d,
+		node:      node,
+	}, nil
+}
+
+func (d *MemoryDirectory) Stat(p string) (VirtualFileStat, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	node, err := d.lookup(p)
+	if err != nil {
+		return VirtualFileStat{}, err
+	}
+
+	return node.stat(), nil
+}
+
+func (d *MemoryDirectory) SetTimes(p string, accessTime time.Time, modTime time.Time) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	node, err := d.lookup(p)
+	if err != nil {
+		return err
+	}
+
+	node.accessTime = accessTime
+	node.modTime = modTime
+	node.changeTime = time.Now()
+
+	return nil
+}
+
+func (d *MemoryDirectory) ReadDir(p string) ([]VirtualDirEntry, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	node, err := d.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	if !node.isDirectory() {
+		return nil, ErrVirtualFileNotDirectory
+	}
+
+	entries := []VirtualDirEntry{}
+	for name, child := range node.children {
+		entries = append(entries, VirtualDirEntry{
+			Name:     name,
+			FileType: child.stat().FileType,
+			Inode:    child.inode,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries, nil
+}
+
+func (d *MemoryDirectory) Mkdir(p string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	parent, name, err := d.lookupParent(p)
+	if err != nil {
+		return err
+	}
+	if _, ok := parent.children[name]; ok {
+		return ErrVirtualFileExists
+	}
+
+	node := d.newNode(true)
+	parent.children[name] = node
+	parent.modTime = node.modTime
+
+	return nil
+}
+
+func (d *MemoryDirectory) Rmdir(p string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	parent, name, err := d.lookupParent(p)
+	if err != nil {
+		return err
+	}
+
+	node, ok := parent.children[name]
+	if !ok {
+		return ErrVirtualFileNotFound
+	}
+	if !node.isDirectory() {
+		return ErrVirtualFileNotDirectory
+	}
+	if len(node.children) > 0 {
+		return ErrVirtualDirectoryNotEmpty
+	}
+
+	delete(parent.children, name)
+	parent.modTime = time.Now()
+
+	return nil
+}
+
+func (d *MemoryDirectory) Unlink(p string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	parent, name, err := d.lookupParent(p)
+	if err != nil {
+		return err
+	}
+
+	node, ok := parent.children[name]
+	if !ok {
+		return ErrVirtualFileNotFound
+	}
+	if node.isDirectory() {
+		return ErrVirtualFileIsDirectory
+	}
+
+	delete(parent.children, name)
+	parent.modTime = time.Now()
+
+	return nil
+}
+
+func (d *MemoryDirectory) Rename(oldPath string, newPath string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	oldParent, oldName, err := d.lookupParent(oldPath)
+	if err != nil {
+		return err
+	}
+
+	node, ok := oldParent.children[oldName]
+	if !ok {
+		return ErrVirtualFileNotFound
+	}
+
+	newParent, newName, err := d.lookupParent(newPath)
+	if err != nil {
+		return err
+	}
+
+	// a directory cannot be moved inside itself
+	cleanOld, cleanNew := path.Clean(oldPath), path.Clean(newPath)
+	if node.isDirectory() && strings.HasPrefix(cleanNew, cleanOld+"/") {
+		return ErrVirtualFileNotSupported
+	}
+
+	if target, ok := newParent.children[newName]; ok && target != node {
+		if node.isDirectory() && !target.isDirectory() {
+			return ErrVirtualFileNotDirectory
+		}
+		if !node.isDirectory() && target.isDirectory() {
+			return ErrVirtualFileIsDirectory
+		}
+		if target.isDirectory() && len(target.children) > 0 {
+			return ErrVirtualDirectoryNotEmpty
+		}
+	}
+
+	now := time.Now()
+	delete(oldParent.children, oldName)
+	newParent.children[newName] = node
+	oldParent.modTime = now
+	newParent.modTime = now
+	node.changeTime = now
+
+	return nil
+}
+
+type memoryFile struct {
+	directory *MemoryDirectory
+	node      *memoryNode
+	pos       int64
+}
+
+func (f *memoryFile) Read(buffer []byte) int {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	if f.pos >= int64(len(f.node.data)) {
+		return 0
+	}
+
+	l := copy(buffer, f.node.data[f.pos:])
+	f.pos += int64(l)
+	f.node.accessTime = time.Now()
+
+	return l
+}
+
+func (f *memoryFile) Write(buffer []byte) (int, error) {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	end := f.pos + int64(len(buffer))
+	if end > int64(len(f.node.data)) {
+		data := make([]byte, end)
+		copy(data, f.node.data)
+		f.node.data = data
+	}
+
+	copy(f.node.data[f.pos:], buffer)
+	f.pos = end
+	f.node.modTime = time.Now()
+
+	return len(buffer), nil
+}
+
+func (f *memoryFile) Close() int {
+	return 0
+}
+
+func (f *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	f.pos = offset
+
+	return f.pos, nil
+}
+
+func (f *memoryFile) Stat() (VirtualFileStat, error) {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	return f.node.stat(), nil
+}
+
+func (f *memoryFile) Truncate(size int64) error {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	if size < 0 {
+		return errors.New("negative size")
+	}
+
+	data := make([]byte, size)
+	copy(data, f.node.data)
+	f.node.data = data
+	f.node.modTime = time.Now()
+
+	return nil
+}
+
+func (f *memoryFile) SetTimes(accessTime time.Time, modTime time.Time) error {
+	f.directory.lock.Lock()
+	defer f.directory.lock.Unlock()
+
+	f.node.accessTime = accessTime
+	f.node.modTime = modTime
+	f.node.changeTime = time.Now()
+
+	return nil
+}
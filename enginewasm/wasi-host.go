@@ -9,10 +9,18 @@ package enginewasm
 import "C"
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	"path"
+	"runtime"
 	"strings"
+	"time"
 	"unsafe"
 
+	"github.com/ltearno/my-own-cluster/common"
+
 	wasm3 "github.com/ltearno/go-wasm3"
 )
 
@@ -22,33 +30,61 @@ var preopen = []string{
 	"<stderr>",
 	"./",
 	"../",
+	"/",
 }
 
 const (
-	WASI_ESUCCESS                  = C.__WASI_ESUCCESS
-	WASI_EBADF                     = C.__WASI_EBADF
-	WASI_PREOPENTYPE_DIR           = C.__WASI_PREOPENTYPE_DIR
-	WASI_FILETYPE_CHARACTER_DEVICE = C.__WASI_FILETYPE_CHARACTER_DEVICE
-	WASI_FILETYPE_DIRECTORY        = C.__WASI_FILETYPE_DIRECTORY
-	WASI_FILETYPE_REGULAR_FILE     = C.__WASI_FILETYPE_REGULAR_FILE
+	WASI_ESUCCESS                   = C.__WASI_ESUCCESS
+	WASI_EBADF                      = C.__WASI_EBADF
+	WASI_EBUSY                      = C.__WASI_EBUSY
+	WASI_EEXIST                     = C.__WASI_EEXIST
+	WASI_EFAULT                     = C.__WASI_EFAULT
+	WASI_EINVAL                     = C.__WASI_EINVAL
+	WASI_EIO                        = C.__WASI_EIO
+	WASI_EISDIR                     = C.__WASI_EISDIR
+	WASI_ENOENT                     = C.__WASI_ENOENT
+	WASI_ENOTCAPABLE                = C.__WASI_ENOTCAPABLE
+	WASI_ENOTDIR                    = C.__WASI_ENOTDIR
+	WASI_ENOTEMPTY                  = C.__WASI_ENOTEMPTY
+	WASI_ENOTSOCK                   = C.__WASI_ENOTSOCK
+	WASI_ENOTSUP                    = C.__WASI_ENOTSUP
+	WASI_EROFS                      = C.__WASI_EROFS
+	WASI_ESPIPE                     = C.__WASI_ESPIPE
+	WASI_EXDEV                      = C.__WASI_EXDEV
+	WASI_PREOPENTYPE_DIR            = C.__WASI_PREOPENTYPE_DIR
+	WASI_FILETYPE_CHARACTER_DEVICE  = C.__WASI_FILETYPE_CHARACTER_DEVICE
+	WASI_FILETYPE_DIRECTORY         = C.__WASI_FILETYPE_DIRECTORY
+	WASI_FILETYPE_REGULAR_FILE      = C.__WASI_FILETYPE_REGULAR_FILE
+	WASI_CLOCK_REALTIME             = C.__WASI_CLOCK_REALTIME
+	WASI_CLOCK_MONOTONIC            = C.__WASI_CLOCK_MONOTONIC
+	WASI_CLOCK_PROCESS_CPUTIME_ID   = C.__WASI_CLOCK_PROCESS_CPUTIME_ID
+	WASI_CLOCK_THREAD_CPUTIME_ID    = C.__WASI_CLOCK_THREAD_CPUTIME_ID
+	WASI_EVENTTYPE_CLOCK            = C.__WASI_EVENTTYPE_CLOCK
+	WASI_EVENTTYPE_FD_READ          = C.__WASI_EVENTTYPE_FD_READ
+	WASI_EVENTTYPE_FD_WRITE         = C.__WASI_EVENTTYPE_FD_WRITE
+	WASI_SUBSCRIPTION_CLOCK_ABSTIME = C.__WASI_SUBSCRIPTION_CLOCK_ABSTIME
+	WASI_FDFLAG_APPEND              = C.__WASI_FDFLAG_APPEND
+	WASI_O_CREAT                    = C.__WASI_O_CREAT
+	WASI_O_DIRECTORY                = C.__WASI_O_DIRECTORY
+	WASI_O_EXCL                     = C.__WASI_O_EXCL
+	WASI_O_TRUNC                    = C.__WASI_O_TRUNC
+	WASI_FILESTAT_SET_ATIM          = C.__WASI_FILESTAT_SET_ATIM
+	WASI_FILESTAT_SET_ATIM_NOW      = C.__WASI_FILESTAT_SET_ATIM_NOW
+	WASI_FILESTAT_SET_MTIM          = C.__WASI_FILESTAT_SET_MTIM
+	WASI_FILESTAT_SET_MTIM_NOW      = C.__WASI_FILESTAT_SET_MTIM_NOW
 )
 
-func setWasiStat(fd int, fdStatAddr unsafe.Pointer) {
+func setWasiStat(fdStatAddr unsafe.Pointer, fileType uint8, flags uint16) {
 	fdStat := (*C.__wasi_fdstat_t)(fdStatAddr)
-	if fd >= 0 && fd <= 2 {
-		fdStat.fs_filetype = C.__WASI_FILETYPE_CHARACTER_DEVICE
-	} else if fd == 3 || fd == 4 {
-		fdStat.fs_filetype = C.__WASI_FILETYPE_DIRECTORY
-	} else {
-		fdStat.fs_filetype = C.__WASI_FILETYPE_REGULAR_FILE
-	}
-	fdStat.fs_flags = 0
+	fdStat.fs_filetype = C.__wasi_filetype_t(fileType)
+	fdStat.fs_flags = C.__wasi_fdflags_t(flags)
 	fdStat.fs_rights_base = C.ulong(0xfffffffffff)
 	fdStat.fs_rights_inheriting = C.ulong(0xfffffffffff)
 }
 
 type state struct {
-	wctx *WasmProcessContext
+	host WasmHost
+	fctx *common.FunctionExecutionContext
 
 	Arguments []string
 
@@ -56,16 +92,24 @@ type state struct {
 	CmdLineNbArgs        int
 	CmdLineArgs          []string
 	CmdLineArgsSize      int
+	EnvironmentSize      int
 	OpenedVirtualFiles   map[int]VirtualFile
+	FdFlags              map[int]uint16
 	NextFileDescriptorID int
 
 	WasiExitValue uint32
+
+	// wasi_snapshot_preview1 or the older wasi_unstable, some layouts and constants differ
+	preview1  bool
+	startTime time.Time
 }
 
-func NewWASIHostPlugin(wasiFileName *string, arguments *[]string, preopenedFiles map[int]VirtualFile) WASMAPIPlugin {
+func newWASIHost(wasiFileName *string, arguments *[]string, preopenedFiles map[int]VirtualFile) *state {
 	s := &state{
 		OpenedVirtualFiles:   make(map[int]VirtualFile),
+		FdFlags:              make(map[int]uint16),
 		NextFileDescriptorID: 42,
+		startTime:            time.Now(),
 	}
 
 	if wasiFileName != nil {
@@ -90,26 +134,16 @@ func NewWASIHostPlugin(wasiFileName *string, arguments *[]string, preopenedFiles
 type WasiCallHandler func(state *state, cs *CallSite) (uint32, int)
 
 func (state *state) BindAPIFunction(moduleName string, functionName string, signature string, handler WasiCallHandler) {
-	state.wctx.Runtime.AttachFunction(moduleName, functionName, signature, func(runtime wasm3.RuntimeT, sp unsafe.Pointer, mem unsafe.Pointer) int {
-		callSite := &CallSite{
-			sp:  sp,
-			mem: mem,
-		}
-
-		result, m3PossibleTrap := handler(state, callSite)
-
-		if m3PossibleTrap == 0 {
-			*(*uint32)(sp) = result
-		}
-
-		return m3PossibleTrap
+	state.host.BindHostFunction(moduleName, functionName, signature, func(cs *CallSite) (uint32, int) {
+		return handler(state, cs)
 	})
 }
 
-func (s *state) Bind(wctx *WasmProcessContext) {
-	s.wctx = wctx
+func (s *state) BindHost(host WasmHost) {
+	s.host = host
+	s.fctx = host.GetFunctionExecutionContext()
 
-	importedModules := wctx.GetImportedModules()
+	importedModules := host.GetImportedModules()
 
 	var wasiModuleName string
 	if _, ok := importedModules["wasi_unstable"]; ok {
@@ -117,59 +151,68 @@ func (s *state) Bind(wctx *WasmProcessContext) {
 		wasiModuleName = "wasi_unstable"
 	} else if _, ok := importedModules["wasi_snapshot_preview1"]; ok {
 		wasiModuleName = "wasi_snapshot_preview1"
+		s.preview1 = true
 	} else {
 		return
 	}
 
-	if wctx.Fctx.Trace {
+	if s.fctx.Trace {
 		fmt.Println("preparing WASI host", s.WasiFilename, s.Arguments, "...")
 	}
 
 	s.prepareCmdLineArgs()
 
-	s.BindAPIFunction(wasiModuleName, "path_open", "i(ii*iiiii*)", WASIPathOpen)
-	s.BindAPIFunction(wasiModuleName, "proc_exit", "i(i)", WASIProcExit)
-	s.BindAPIFunction(wasiModuleName, "fd_readdir", "i(i*ii*)", WASIFdReadDir)
-	s.BindAPIFunction(wasiModuleName, "args_sizes_get", "i(**)", WASIArgsSizesGet)
+	s.EnvironmentSize = 0
+	for _, variable := range s.fctx.Environment {
+		s.EnvironmentSize = s.EnvironmentSize + len(variable) + 1
+	}
+
 	s.BindAPIFunction(wasiModuleName, "args_get", "i(**)", WASIArgsGet)
+	s.BindAPIFunction(wasiModuleName, "args_sizes_get", "i(**)", WASIArgsSizesGet)
+	s.BindAPIFunction(wasiModuleName, "environ_get", "i(**)", WASIEnvironGet)
+	s.BindAPIFunction(wasiModuleName, "environ_sizes_get", "i(**)", WASIEnvironSizesGet)
+	s.BindAPIFunction(wasiModuleName, "clock_res_get", "i(i*)", WASIClockResGet)
+	s.BindAPIFunction(wasiModuleName, "clock_time_get", "i(iI*)", WASIClockTimeGet)
+	s.BindAPIFunction(wasiModuleName, "fd_advise", "i(iIIi)", WASIFdAdvise)
+	s.BindAPIFunction(wasiModuleName, "fd_allocate", "i(iII)", WASIFdAllocate)
+	s.BindAPIFunction(wasiModuleName, "fd_close", "i(i)", WASIFdClose)
+	s.BindAPIFunction(wasiModuleName, "fd_datasync", "i(i)", WASIFdSync)
+	s.BindAPIFunction(wasiModuleName, "fd_fdstat_get", "i(i*)", WASIFdFdStatGet)
+	s.BindAPIFunction(wasiModuleName, "fd_fdstat_set_flags", "i(ii)", WASIFdFdStatSetFlags)
+	s.BindAPIFunction(wasiModuleName, "fd_fdstat_set_rights", "i(iII)", WASIFdFdStatSetRights)
+	s.BindAPIFunction(wasiModuleName, "fd_filestat_get", "i(i*)", WASIFdFilestatGet)
+	s.BindAPIFunction(wasiModuleName, "fd_filestat_set_size", "i(iI)", WASIFdFilestatSetSize)
+	s.BindAPIFunction(wasiModuleName, "fd_filestat_set_times", "i(iIIi)", WASIFdFilestatSetTimes)
+	s.BindAPIFunction(wasiModuleName, "fd_pread", "i(i*iI*)", WASIFdPread)
 	s.BindAPIFunction(wasiModuleName, "fd_prestat_get", "i(i*)", WASIFdPrestatGet)
 	s.BindAPIFunction(wasiModuleName, "fd_prestat_dir_name", "i(i*i)", WASIFdPrestatDirName)
-	s.BindAPIFunction(wasiModuleName, "fd_fdstat_get", "i(i*)", WASIFdFdStatGet)
+	s.BindAPIFunction(wasiModuleName, "fd_pwrite", "i(i*iI*)", WASIFdPwrite)
+	s.BindAPIFunction(wasiModuleName, "fd_read", "i(i*i*)", WASIFdRead)
+	s.BindAPIFunction(wasiModuleName, "fd_readdir", "i(i*iI*)", WASIFdReadDir)
+	s.BindAPIFunction(wasiModuleName, "fd_renumber", "i(ii)", WASIFdRenumber)
+	s.BindAPIFunction(wasiModuleName, "fd_seek", "i(iIi*)", WASIFdSeek)
+	s.BindAPIFunction(wasiModuleName, "fd_sync", "i(i)", WASIFdSync)
+	s.BindAPIFunction(wasiModuleName, "fd_tell", "i(i*)", WASIFdTell)
+	s.BindAPIFunction(wasiModuleName, "fd_write", "i(i*i*)", WASIFdWrite)
+	s.BindAPIFunction(wasiModuleName, "path_create_directory", "i(i*i)", WASIPathCreateDirectory)
 	s.BindAPIFunction(wasiModuleName, "path_filestat_get", "i(ii*i*)", WASIPathFilestatGet)
-	s.BindAPIFunction(wasiModuleName, "fd_write", "i(iii*)", WASIFdWrite)
-	s.BindAPIFunction(wasiModuleName, "fd_read", "i(iii*)", WASIFdRead)
-	s.BindAPIFunction(wasiModuleName, "fd_seek", "i(iii*)", WASIFdSeek)
-	s.BindAPIFunction(wasiModuleName, "fd_close", "i(i)", WASIFdClose)
-	s.BindAPIFunction(wasiModuleName, "environ_sizes_get", "i(**)", WASIEnvironSizesGet)
-	s.BindAPIFunction(wasiModuleName, "environ_get", "i(**)", WASIEnvironGet)
-
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_fdstat_set_flags", "i(ii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_datasync", "i(i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "clock_res_get", "i(i*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "clock_time_get", "i(ii*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_renumber", "i(ii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_tell", "i(i*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_fdstat_set_rights", "i(iii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_advise", "i(iiii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_allocate", "i(iii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_create_directory", "i(i*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_link", "i(ii*ii*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_readlink", "i(i*i*i*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_rename", "i(i*ii*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_filestat_get", "i(i*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_filestat_set_times", "i(iiii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "fd_filestat_set_size", "i(ii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_filestat_set_times", "i(ii*iiii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_symlink", "i(*ii*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_unlink_file", "i(i*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "path_remove_directory", "i(i*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "poll_oneoff", "i(**i*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "proc_raise", "i(i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "random_get", "i(*i)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "sock_recv", "i(i*ii**)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "sock_send", "i(i*ii*)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "sock_shutdown", "i(ii)")
-	wctx.BindNotYetImplementedFunction(wasiModuleName, "sched_yield", "i()")
+	s.BindAPIFunction(wasiModuleName, "path_filestat_set_times", "i(ii*iIIi)", WASIPathFilestatSetTimes)
+	s.BindAPIFunction(wasiModuleName, "path_link", "i(ii*ii*i)", WASIPathLink)
+	s.BindAPIFunction(wasiModuleName, "path_open", "i(ii*iiIIi*)", WASIPathOpen)
+	s.BindAPIFunction(wasiModuleName, "path_readlink", "i(i*i*i*)", WASIPathReadlink)
+	s.BindAPIFunction(wasiModuleName, "path_remove_directory", "i(i*i)", WASIPathRemoveDirectory)
+	s.BindAPIFunction(wasiModuleName, "path_rename", "i(i*ii*i)", WASIPathRename)
+	s.BindAPIFunction(wasiModuleName, "path_symlink", "i(*ii*i)", WASIPathSymlink)
+	s.BindAPIFunction(wasiModuleName, "path_unlink_file", "i(i*i)", WASIPathUnlinkFile)
+	s.BindAPIFunction(wasiModuleName, "poll_oneoff", "i(**i*)", WASIPollOneOff)
+	s.BindAPIFunction(wasiModuleName, "proc_exit", "v(i)", WASIProcExit)
+	s.BindAPIFunction(wasiModuleName, "proc_raise", "i(i)", WASIProcRaise)
+	s.BindAPIFunction(wasiModuleName, "random_get", "i(*i)", WASIRandomGet)
+	s.BindAPIFunction(wasiModuleName, "sched_yield", "i()", WASISchedYield)
+	s.BindAPIFunction(wasiModuleName, "sock_accept", "i(ii*)", WASISockAccept)
+	s.BindAPIFunction(wasiModuleName, "sock_recv", "i(i*ii**)", WASISockRecv)
+	s.BindAPIFunction(wasiModuleName, "sock_send", "i(i*ii*)", WASISockSend)
+	s.BindAPIFunction(wasiModuleName, "sock_shutdown", "i(ii)", WASISockShutdown)
 }
 
 func (s *state) prepareCmdLineArgs() {
@@ -186,6 +229,204 @@ func (s *state) prepareCmdLineArgs() {
 	s.CmdLineArgsSize = s.CmdLineArgsSize + s.CmdLineNbArgs
 }
 
+// wasiErrno converts a virtual file layer error to a WASI error code
+func wasiErrno(err error) uint32 {
+	switch {
+	case err == nil:
+		return WASI_ESUCCESS
+	case errors.Is(err, ErrVirtualFileNotFound):
+		return WASI_ENOENT
+	case errors.Is(err, ErrVirtualFileExists):
+		return WASI_EEXIST
+	case errors.Is(err, ErrVirtualFileNotDirectory):
+		return WASI_ENOTDIR
+	case errors.Is(err, ErrVirtualFileIsDirectory):
+		return WASI_EISDIR
+	case errors.Is(err, ErrVirtualDirectoryNotEmpty):
+		return WASI_ENOTEMPTY
+	case errors.Is(err, ErrVirtualFileReadOnly):
+		return WASI_EROFS
+	case errors.Is(err, ErrVirtualFileNotSupported):
+		return WASI_ENOTSUP
+	case errors.Is(err, ErrVirtualFileCrossDevice):
+		return WASI_EXDEV
+	case errors.Is(err, ErrVirtualFileBusy):
+		return WASI_EBUSY
+	case errors.Is(err, common.ErrPermissionDenied):
+		return WASI_ENOTCAPABLE
+	default:
+		return WASI_EIO
+	}
+}
+
+func putUint8(addr unsafe.Pointer, offset uintptr, value uint8) {
+	*(*uint8)(unsafe.Pointer(uintptr(addr) + offset)) = value
+}
+
+func putUint16(addr unsafe.Pointer, offset uintptr, value uint16) {
+	*(*uint16)(unsafe.Pointer(uintptr(addr) + offset)) = value
+}
+
+func putUint32(addr unsafe.Pointer, offset uintptr, value uint32) {
+	*(*uint32)(unsafe.Pointer(uintptr(addr) + offset)) = value
+}
+
+func putUint64(addr unsafe.Pointer, offset uintptr, value uint64) {
+	*(*uint64)(unsafe.Pointer(uintptr(addr) + offset)) = value
+}
+
+func getUint16(addr unsafe.Pointer, offset uintptr) uint16 {
+	return *(*uint16)(unsafe.Pointer(uintptr(addr) + offset))
+}
+
+func getUint32(addr unsafe.Pointer, offset uintptr) uint32 {
+	return *(*uint32)(unsafe.Pointer(uintptr(addr) + offset))
+}
+
+func getUint64(addr unsafe.Pointer, offset uintptr) uint64 {
+	return *(*uint64)(unsafe.Pointer(uintptr(addr) + offset))
+}
+
+func wasiTimestamp(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.UnixNano())
+}
+
+// putFileStat writes a filestat structure, the link count is 32 bits wide before preview1
+func (state *state) putFileStat(addr unsafe.Pointer, stat VirtualFileStat) {
+	size := 56
+	if state.preview1 {
+		size = 64
+	}
+	fileStat := (*[64]byte)(addr)[:size:size]
+	for i := range fileStat {
+		fileStat[i] = 0
+	}
+
+	putUint64(addr, 8, stat.Inode)
+	putUint8(addr, 16, stat.FileType)
+	if state.preview1 {
+		putUint64(addr, 24, 1)
+		putUint64(addr, 32, stat.Size)
+		putUint64(addr, 40, wasiTimestamp(stat.AccessTime))
+		putUint64(addr, 48, wasiTimestamp(stat.ModTime))
+		putUint64(addr, 56, wasiTimestamp(stat.ChangeTime))
+	} else {
+		putUint32(addr, 20, 1)
+		putUint64(addr, 24, stat.Size)
+		putUint64(addr, 32, wasiTimestamp(stat.AccessTime))
+		putUint64(addr, 40, wasiTimestamp(stat.ModTime))
+		putUint64(addr, 48, wasiTimestamp(stat.ChangeTime))
+	}
+}
+
+// whence converts a WASI whence to the io package one, the values changed with preview1
+func (state *state) whence(whence uint32) (int, bool) {
+	if state.preview1 {
+		// SET, CUR, END
+		if whence > 2 {
+			return 0, false
+		}
+		return int(whence), true
+	}
+
+	// CUR, END, SET
+	switch whence {
+	case 0:
+		return io.SeekCurrent, true
+	case 1:
+		return io.SeekEnd, true
+	case 2:
+		return io.SeekStart, true
+	}
+
+	return 0, false
+}
+
+// stat describes an opened file, virtual files not able to do it are seen as character devices
+// for the standard streams and regular files otherwise
+func (state *state) stat(fd int) (VirtualFileStat, error) {
+	virtualFile := state.OpenedVirtualFiles[fd]
+	if statFile, ok := virtualFile.(StatVirtualFile); ok {
+		return statFile.Stat()
+	}
+
+	if fd <= 2 {
+		return VirtualFileStat{FileType: WASI_FILETYPE_CHARACTER_DEVICE}, nil
+	}
+
+	return VirtualFileStat{FileType: WASI_FILETYPE_REGULAR_FILE}, nil
+}
+
+// getDirectory returns an opened directory, or the WASI error if the fd is not one
+func (state *state) getDirectory(fd uint32) (*OpenedVirtualDirectory, uint32) {
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return nil, WASI_EBADF
+	}
+
+	directory, ok := virtualFile.(*OpenedVirtualDirectory)
+	if !ok {
+		return nil, WASI_ENOTDIR
+	}
+
+	return directory, WASI_ESUCCESS
+}
+
+// resolvePath returns the directory and the path of a file relative to an opened directory
+func (state *state) resolvePath(fd uint32, relativePath string) (*OpenedVirtualDirectory, string, uint32) {
+	directory, errno := state.getDirectory(fd)
+	if errno != WASI_ESUCCESS {
+		return nil, "", errno
+	}
+
+	resolved, ok := directory.Resolve(relativePath)
+	if !ok {
+		return nil, "", WASI_ENOTCAPABLE
+	}
+
+	return directory, resolved, WASI_ESUCCESS
+}
+
+// fileTimes computes the times given to a *_filestat_set_times function
+func fileTimes(current VirtualFileStat, accessTime uint64, modTime uint64, fstFlags uint32) (time.Time, time.Time, bool) {
+	if fstFlags&WASI_FILESTAT_SET_ATIM != 0 && fstFlags&WASI_FILESTAT_SET_ATIM_NOW != 0 {
+		return time.Time{}, time.Time{}, false
+	}
+	if fstFlags&WASI_FILESTAT_SET_MTIM != 0 && fstFlags&WASI_FILESTAT_SET_MTIM_NOW != 0 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	now := time.Now()
+	atime, mtime := current.AccessTime, current.ModTime
+
+	if fstFlags&WASI_FILESTAT_SET_ATIM != 0 {
+		atime = time.Unix(0, int64(accessTime))
+	} else if fstFlags&WASI_FILESTAT_SET_ATIM_NOW != 0 {
+		atime = now
+	}
+
+	if fstFlags&WASI_FILESTAT_SET_MTIM != 0 {
+		mtime = time.Unix(0, int64(modTime))
+	} else if fstFlags&WASI_FILESTAT_SET_MTIM_NOW != 0 {
+		mtime = now
+	}
+
+	return atime, mtime, true
+}
+
+func (state *state) openFileDescriptor(virtualFile VirtualFile, flags uint16) uint32 {
+	fd := state.NextFileDescriptorID
+	state.NextFileDescriptorID = state.NextFileDescriptorID + 1
+	state.OpenedVirtualFiles[fd] = virtualFile
+	state.FdFlags[fd] = flags
+
+	return uint32(fd)
+}
+
 // WASIPathOpen is path_open
 func WASIPathOpen(state *state, cs *CallSite) (uint32, int) {
 	dirFd := cs.GetParamUINT32(0)
@@ -197,7 +438,7 @@ func WASIPathOpen(state *state, cs *CallSite) (uint32, int) {
 	flags := cs.GetParamUINT32(7)
 	fd := cs.GetParamUINT32Ptr(8)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called path_open dirFd %d lookupFlags %x oFlags %x rightsBase %x rightsInheriting %x flags %x fdAddr %x  '%s'\n",
 			dirFd,
 			lookupFlags,
@@ -210,22 +451,55 @@ func WASIPathOpen(state *state, cs *CallSite) (uint32, int) {
 		)
 	}
 
+	// platform resources are reachable from any directory
 	var virtualFile VirtualFile = nil
 
 	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
-		virtualFile = CreateWebAccessVirtualFile(path)
+		var err error
+		virtualFile, err = CreateWebAccessVirtualFile(state.fctx, path)
+		if err != nil {
+			if state.fctx.Trace {
+				fmt.Printf("cannot open '%s' (%v)\n", path, err)
+			}
+			*fd = 0
+			return wasiErrno(err), 0
+		}
 	} else if path == "api://input" {
-		virtualFile = CreateInputVirtualFile(state.wctx)
+		virtualFile = CreateInputVirtualFile(state.fctx)
 	} else if path == "api://output" {
-		virtualFile = CreateOutputVirtualFile(state.wctx)
-	} else {
-		*fd = 0
-		return WASI_EBADF, 0
+		virtualFile = CreateOutputVirtualFile(state.fctx)
 	}
 
-	*fd = uint32(state.NextFileDescriptorID)
-	state.NextFileDescriptorID = state.NextFileDescriptorID + 1
-	state.OpenedVirtualFiles[int(*fd)] = virtualFile
+	if virtualFile == nil {
+		directory, resolved, errno := state.resolvePath(dirFd, path)
+		if errno != WASI_ESUCCESS {
+			*fd = 0
+			return errno, 0
+		}
+
+		openFlags := 0
+		if oFlags&WASI_O_CREAT != 0 {
+			openFlags |= VIRTUAL_OPEN_CREATE
+		}
+		if oFlags&WASI_O_EXCL != 0 {
+			openFlags |= VIRTUAL_OPEN_EXCLUSIVE
+		}
+		if oFlags&WASI_O_TRUNC != 0 {
+			openFlags |= VIRTUAL_OPEN_TRUNCATE
+		}
+		if oFlags&WASI_O_DIRECTORY != 0 {
+			openFlags |= VIRTUAL_OPEN_DIRECTORY
+		}
+
+		var err error
+		virtualFile, err = directory.Directory.Open(resolved, openFlags)
+		if err != nil {
+			*fd = 0
+			return wasiErrno(err), 0
+		}
+	}
+
+	*fd = state.openFileDescriptor(virtualFile, uint16(flags))
 
 	return WASI_ESUCCESS, 0
 }
@@ -234,43 +508,95 @@ func WASIPathOpen(state *state, cs *CallSite) (uint32, int) {
 func WASIProcExit(state *state, cs *CallSite) (uint32, int) {
 	exitValue := cs.GetParamUINT32(0)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called proc_exit with value %d\n", exitValue)
 	}
 
 	state.WasiExitValue = exitValue
-	state.wctx.Fctx.Result = int(exitValue)
+	state.fctx.SetExit(common.ProcExit(int(exitValue)))
+
+	return WASI_ESUCCESS, 1
+}
+
+// WASIProcRaise terminates the program like the default action of a signal, with the shell exit code
+func WASIProcRaise(state *state, cs *CallSite) (uint32, int) {
+	signal := cs.GetParamUINT32(0)
+
+	if state.fctx.Trace {
+		fmt.Printf("called proc_raise with signal %d\n", signal)
+	}
+
+	state.WasiExitValue = 128 + signal
+	state.fctx.SetExit(common.ProcExit(int(state.WasiExitValue)))
 
 	return WASI_ESUCCESS, 1
 }
 
-// WASIFdReadDir WASIFdReadDir
+// WASIFdReadDir lists a directory, starting with the entry at index cookie
 func WASIFdReadDir(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
 	buffer := cs.GetParamByteBuffer(1, 2)
-	cookie := cs.GetParamUINT32(3)
+	cookie := uint64(cs.GetParamINT64(3))
 	bufusedAddr := cs.GetParamPointer(4)
 
-	if state.wctx.Fctx.Trace {
-		fmt.Printf("called fd_readdir fd %d bufAddr %x cookie %d bufusedAddr %x\n", fd, &buffer, cookie, bufusedAddr)
+	if state.fctx.Trace {
+		fmt.Printf("called fd_readdir fd %d bufLen %d cookie %d bufusedAddr %x\n", fd, len(buffer), cookie, bufusedAddr)
 	}
 
 	*(*uint32)(bufusedAddr) = 0
 
+	directory, errno := state.getDirectory(fd)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	entries, err := directory.Directory.ReadDir(directory.Path)
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	self, _ := directory.Directory.Stat(directory.Path)
+	parent, _ := directory.Directory.Stat(path.Dir(directory.Path))
+	entries = append([]VirtualDirEntry{
+		{Name: ".", FileType: WASI_FILETYPE_DIRECTORY, Inode: self.Inode},
+		{Name: "..", FileType: WASI_FILETYPE_DIRECTORY, Inode: parent.Inode},
+	}, entries...)
+
+	// each entry is a 24 bytes dirent followed by the name, the last one is truncated when the buffer is full
+	used := 0
+	for index := cookie; index < uint64(len(entries)) && used < len(buffer); index++ {
+		entry := entries[index]
+
+		dirent := make([]byte, 24+len(entry.Name))
+		direntAddr := unsafe.Pointer(&dirent[0])
+		putUint64(direntAddr, 0, index+1)
+		putUint64(direntAddr, 8, entry.Inode)
+		putUint32(direntAddr, 16, uint32(len(entry.Name)))
+		putUint8(direntAddr, 20, entry.FileType)
+		copy(dirent[24:], entry.Name)
+
+		used += copy(buffer[used:], dirent)
+	}
+
+	*(*uint32)(bufusedAddr) = uint32(used)
+
 	return WASI_ESUCCESS, 0
 }
 
 // WASIArgsSizesGet WASIArgsSizesGet
 func WASIArgsSizesGet(state *state, cs *CallSite) (uint32, int) {
-	argc := cs.GetParamUINT32Ptr(0)
-	argvBufSize := cs.GetParamUINT32Ptr(1)
+	argc, argcOk := cs.GetParamPointerRange(0, 4)
+	argvBufSize, argvBufSizeOk := cs.GetParamPointerRange(1, 4)
+	if !argcOk || !argvBufSizeOk {
+		return WASI_EFAULT, 0
+	}
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called args_sizes_get %x %x\n", argc, argvBufSize)
 	}
 
-	*argc = uint32(state.CmdLineNbArgs)
-	*argvBufSize = uint32(state.CmdLineArgsSize)
+	*(*uint32)(argc) = uint32(state.CmdLineNbArgs)
+	*(*uint32)(argvBufSize) = uint32(state.CmdLineArgsSize)
 
 	return WASI_ESUCCESS, 0
 }
@@ -280,10 +606,16 @@ func WASIArgsGet(state *state, cs *CallSite) (uint32, int) {
 	p0 := cs.GetParamUINT32(0)
 	p1 := cs.GetParamUINT32(1)
 
-	argv := (*[1 << 30]uint32)(cs.GetParamPointer(0))[:state.CmdLineNbArgs:state.CmdLineNbArgs]
-	argvBuf := (*[1 << 30]byte)(cs.GetParamPointer(1))[:state.CmdLineArgsSize:state.CmdLineArgsSize]
+	argvAddr, argvOk := cs.GetParamPointerRange(0, 4*uint64(state.CmdLineNbArgs))
+	argvBufAddr, argvBufOk := cs.GetParamPointerRange(1, uint64(state.CmdLineArgsSize))
+	if !argvOk || !argvBufOk {
+		return WASI_EFAULT, 0
+	}
+
+	argv := (*[1 << 30]uint32)(argvAddr)[:state.CmdLineNbArgs:state.CmdLineNbArgs]
+	argvBuf := (*[1 << 30]byte)(argvBufAddr)[:state.CmdLineArgsSize:state.CmdLineArgsSize]
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called args_get argv:%08x argvBuf:%08x p0:%x p1:%x\n", argv, argvBuf, p0, p1)
 	}
 
@@ -305,11 +637,15 @@ func WASIFdPrestatGet(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
 	buf := cs.GetParamPointer(1)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called fd_prestat_get: fd %d buf %08x\n", fd, buf)
 	}
 
-	if fd < 3 || fd >= 5 {
+	if fd < 3 || fd >= 6 {
+		return WASI_EBADF, 0
+	}
+
+	if _, errno := state.getDirectory(fd); errno != WASI_ESUCCESS {
 		return WASI_EBADF, 0
 	}
 
@@ -322,16 +658,19 @@ func WASIFdPrestatGet(state *state, cs *CallSite) (uint32, int) {
 // WASIFdPrestatDirName WASIFdPrestatDirName
 func WASIFdPrestatDirName(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
-	buf := cs.GetParamPointer(1)
 	length := cs.GetParamUINT32(2)
+	buf, ok := cs.GetParamPointerRange(1, uint64(length))
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called fd_prestat_dir_name: fd %d buf %x lenght %d\n", fd, buf, length)
 	}
 
-	if fd < 3 || fd >= 5 {
+	if fd < 3 || fd >= 6 {
 		return WASI_EBADF, 0
 	}
+	if !ok {
+		return WASI_EFAULT, 0
+	}
 
 	nameBuf := (*[1 << 30]byte)(buf)[:length:length]
 	copy(nameBuf, []byte(preopen[fd]))
@@ -344,43 +683,392 @@ func WASIFdFdStatGet(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
 	fdStatAddr := cs.GetParamPointer(1)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called fd_fdstat_get: fd %d fdStatAddr %x\n", fd, fdStatAddr)
 	}
 
-	setWasiStat(int(fd), fdStatAddr)
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF, 0
+	}
+
+	stat, err := state.stat(int(fd))
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	setWasiStat(fdStatAddr, stat.FileType, state.FdFlags[int(fd)])
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdFdStatSetFlags changes the fd flags, only append has an effect
+func WASIFdFdStatSetFlags(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	flags := uint16(cs.GetParamUINT32(1))
+
+	if state.fctx.Trace {
+		fmt.Printf("called fd_fdstat_set_flags: fd %d flags %x\n", fd, flags)
+	}
+
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF, 0
+	}
+
+	state.FdFlags[int(fd)] = flags
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdFdStatSetRights accepts any rights, they are not enforced
+func WASIFdFdStatSetRights(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF, 0
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdAdvise ignores the advice
+func WASIFdAdvise(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF, 0
+	}
 
 	return WASI_ESUCCESS, 0
 }
 
+// WASIFdAllocate grows a file to at least offset+length bytes
+func WASIFdAllocate(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	offset := cs.GetParamINT64(1)
+	length := cs.GetParamINT64(2)
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	truncatable, ok := virtualFile.(TruncatableVirtualFile)
+	if !ok {
+		return WASI_ENOTSUP, 0
+	}
+
+	stat, err := state.stat(int(fd))
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	if offset < 0 || length < 0 {
+		return WASI_EINVAL, 0
+	}
+
+	if uint64(offset+length) > stat.Size {
+		err = truncatable.Truncate(offset + length)
+		if err != nil {
+			return wasiErrno(err), 0
+		}
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdSync is fd_sync and fd_datasync, only persistent files have modifications to write back
+func WASIFdSync(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	if syncable, ok := virtualFile.(SyncableVirtualFile); ok {
+		return wasiErrno(syncable.Sync()), 0
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdFilestatGet WASIFdFilestatGet
+func WASIFdFilestatGet(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	fileStatAddr := cs.GetParamPointer(1)
+
+	if state.fctx.Trace {
+		fmt.Printf("called fd_filestat_get: fd %d\n", fd)
+	}
+
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF, 0
+	}
+
+	stat, err := state.stat(int(fd))
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	state.putFileStat(fileStatAddr, stat)
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdFilestatSetSize WASIFdFilestatSetSize
+func WASIFdFilestatSetSize(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	size := cs.GetParamINT64(1)
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	truncatable, ok := virtualFile.(TruncatableVirtualFile)
+	if !ok {
+		return WASI_EINVAL, 0
+	}
+
+	return wasiErrno(truncatable.Truncate(size)), 0
+}
+
+// WASIFdFilestatSetTimes WASIFdFilestatSetTimes
+func WASIFdFilestatSetTimes(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	accessTime := uint64(cs.GetParamINT64(1))
+	modTime := uint64(cs.GetParamINT64(2))
+	fstFlags := cs.GetParamUINT32(3)
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	timestamped, ok := virtualFile.(TimestampedVirtualFile)
+	if !ok {
+		return WASI_ENOTSUP, 0
+	}
+
+	current, err := state.stat(int(fd))
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	atime, mtime, ok := fileTimes(current, accessTime, modTime, fstFlags)
+	if !ok {
+		return WASI_EINVAL, 0
+	}
+
+	return wasiErrno(timestamped.SetTimes(atime, mtime)), 0
+}
+
 // WASIPathFilestatGet WASIPathFilestatGet
 func WASIPathFilestatGet(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
 	lookupFlags := cs.GetParamUINT32(1)
-	pathSlice := cs.GetParamByteBuffer(2, 3)
-	fileStatAddr := (*C.__wasi_filestat_t)(cs.GetParamPointer(4))
+	path := cs.GetParamString(2, 3)
+	fileStatAddr := cs.GetParamPointer(4)
 
-	if state.wctx.Fctx.Trace {
-		fmt.Printf("called path_filestat_get: fd %d lookupFlags %x path %s\n", fd, lookupFlags, string(pathSlice))
+	if state.fctx.Trace {
+		fmt.Printf("called path_filestat_get: fd %d lookupFlags %x path %s\n", fd, lookupFlags, path)
 	}
 
-	fileStatAddr.st_size = 30
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	stat, err := directory.Directory.Stat(resolved)
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	state.putFileStat(fileStatAddr, stat)
 
 	return WASI_ESUCCESS, 0
 }
 
-// WASIFdWrite WASIFdWrite
-func WASIFdWrite(state *state, cs *CallSite) (uint32, int) {
+// WASIPathFilestatSetTimes WASIPathFilestatSetTimes
+func WASIPathFilestatSetTimes(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
-	wasiIovs := cs.GetParamPointer(1)
-	iovsLen := cs.GetParamUINT32(2)
-	nwritten := cs.GetParamPointer(3)
+	path := cs.GetParamString(2, 3)
+	accessTime := uint64(cs.GetParamINT64(4))
+	modTime := uint64(cs.GetParamINT64(5))
+	fstFlags := cs.GetParamUINT32(6)
 
-	if state.wctx.Fctx.Trace {
-		fmt.Printf("called fd_write on fd %d: iovs: %x iovsLen: %d\n", fd, uintptr(wasiIovs), iovsLen)
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	current, err := directory.Directory.Stat(resolved)
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	atime, mtime, ok := fileTimes(current, accessTime, modTime, fstFlags)
+	if !ok {
+		return WASI_EINVAL, 0
+	}
+
+	return wasiErrno(directory.Directory.SetTimes(resolved, atime, mtime)), 0
+}
+
+// WASIPathCreateDirectory WASIPathCreateDirectory
+func WASIPathCreateDirectory(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	path := cs.GetParamString(1, 2)
+
+	if state.fctx.Trace {
+		fmt.Printf("called path_create_directory: fd %d path %s\n", fd, path)
+	}
+
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	return wasiErrno(directory.Directory.Mkdir(resolved)), 0
+}
+
+// WASIPathRemoveDirectory WASIPathRemoveDirectory
+func WASIPathRemoveDirectory(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	path := cs.GetParamString(1, 2)
+
+	if state.fctx.Trace {
+		fmt.Printf("called path_remove_directory: fd %d path %s\n", fd, path)
+	}
+
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
 	}
 
-	writtenLength := uint32(0)
+	return wasiErrno(directory.Directory.Rmdir(resolved)), 0
+}
+
+// WASIPathUnlinkFile WASIPathUnlinkFile
+func WASIPathUnlinkFile(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	path := cs.GetParamString(1, 2)
+
+	if state.fctx.Trace {
+		fmt.Printf("called path_unlink_file: fd %d path %s\n", fd, path)
+	}
+
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	return wasiErrno(directory.Directory.Unlink(resolved)), 0
+}
+
+// WASIPathRename moves a file, both paths must be in the same virtual directory
+func WASIPathRename(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	oldPath := cs.GetParamString(1, 2)
+	newFd := cs.GetParamUINT32(3)
+	newPath := cs.GetParamString(4, 5)
+
+	if state.fctx.Trace {
+		fmt.Printf("called path_rename: fd %d path %s newFd %d newPath %s\n", fd, oldPath, newFd, newPath)
+	}
+
+	directory, oldResolved, errno := state.resolvePath(fd, oldPath)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	newDirectory, newResolved, errno := state.resolvePath(newFd, newPath)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	if directory.Directory != newDirectory.Directory {
+		return WASI_EXDEV, 0
+	}
+
+	return wasiErrno(directory.Directory.Rename(oldResolved, newResolved)), 0
+}
+
+// WASIPathLink is not supported, virtual directories have no hard links
+func WASIPathLink(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	newFd := cs.GetParamUINT32(4)
+
+	if _, errno := state.getDirectory(fd); errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+	if _, errno := state.getDirectory(newFd); errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	return WASI_ENOTSUP, 0
+}
+
+// WASIPathSymlink is not supported, virtual directories have no symbolic links
+func WASIPathSymlink(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(2)
+
+	if _, errno := state.getDirectory(fd); errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	return WASI_ENOTSUP, 0
+}
+
+// WASIPathReadlink fails on existing files since none of them is a symbolic link
+func WASIPathReadlink(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	path := cs.GetParamString(1, 2)
+	bufused := cs.GetParamUINT32Ptr(5)
+
+	*bufused = 0
+
+	directory, resolved, errno := state.resolvePath(fd, path)
+	if errno != WASI_ESUCCESS {
+		return errno, 0
+	}
+
+	_, err := directory.Directory.Stat(resolved)
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	return WASI_EINVAL, 0
+}
+
+// readIovs reads a virtual file into the iovs, returns the read length
+func readIovs(cs *CallSite, virtualFile VirtualFile, wasiIovs unsafe.Pointer, iovsLen uint32) int {
+	readLength := 0
+
+	for i := uint32(0); i < iovsLen; i++ {
+		wasiIov := (*wasm3.WasiIoVec)(unsafe.Pointer(uintptr(wasiIovs) + unsafe.Sizeof(wasm3.WasiIoVec{})*uintptr(i)))
+		addr := m3ApiOffsetToPtr(cs.mem, wasiIov.GetBuf())
+		length := int(wasiIov.GetBufLen())
+		if length == 0 {
+			continue
+		}
+
+		buffer := (*[1 << 30]byte)(unsafe.Pointer(addr))[:length:length]
+
+		l := virtualFile.Read(buffer)
+		readLength = readLength + l
+		if l < length {
+			break
+		}
+	}
+
+	return readLength
+}
+
+// writeIovs writes the iovs to a virtual file, returns the written length
+func writeIovs(state *state, cs *CallSite, fd uint32, virtualFile VirtualFile, wasiIovs unsafe.Pointer, iovsLen uint32) (int, error) {
+	writtenLength := 0
+
 	for i := uint32(0); i < iovsLen; i++ {
 		wasiIov := (*wasm3.WasiIoVec)(unsafe.Pointer(uintptr(wasiIovs) + unsafe.Sizeof(wasm3.WasiIoVec{})*uintptr(i)))
 		addr := m3ApiOffsetToPtr(cs.mem, wasiIov.GetBuf())
@@ -388,20 +1076,50 @@ func WASIFdWrite(state *state, cs *CallSite) (uint32, int) {
 
 		buffer := (*[1 << 30]byte)(unsafe.Pointer(addr))[:length:length]
 
-		if fd == 1 && state.wctx.Fctx.Trace {
+		if fd == 1 && state.fctx.Trace {
 			fmt.Printf(" [received iov:] '%s'\n", string(buffer))
 		}
 
-		virtualFile := state.OpenedVirtualFiles[int(fd)]
 		l, err := virtualFile.Write(buffer)
 		if err != nil {
-			*(*uint32)(nwritten) = 0
-			return WASI_EBADF, 0
+			return writtenLength, err
 		}
-		writtenLength += uint32(l)
+		writtenLength += l
 	}
 
-	*(*uint32)(nwritten) = writtenLength
+	return writtenLength, nil
+}
+
+// WASIFdWrite WASIFdWrite
+func WASIFdWrite(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	wasiIovs := cs.GetParamPointer(1)
+	iovsLen := cs.GetParamUINT32(2)
+	nwritten := cs.GetParamPointer(3)
+
+	if state.fctx.Trace {
+		fmt.Printf("called fd_write on fd %d: iovs: %x iovsLen: %d\n", fd, uintptr(wasiIovs), iovsLen)
+	}
+
+	*(*uint32)(nwritten) = 0
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	if state.FdFlags[int(fd)]&WASI_FDFLAG_APPEND != 0 {
+		if seekable, ok := virtualFile.(SeekableVirtualFile); ok {
+			seekable.Seek(0, io.SeekEnd)
+		}
+	}
+
+	writtenLength, err := writeIovs(state, cs, fd, virtualFile, wasiIovs, iovsLen)
+	if err != nil {
+		return wasiErrno(err), 0
+	}
+
+	*(*uint32)(nwritten) = uint32(writtenLength)
 
 	// return value (was top of stack at the beginning of execution)
 	return WASI_ESUCCESS, 0
@@ -414,44 +1132,145 @@ func WASIFdRead(state *state, cs *CallSite) (uint32, int) {
 	iovsLen := cs.GetParamUINT32(2)
 	nRead := cs.GetParamPointer(3)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called fd_read on fd %d: iovs: %x iovsLen: %d\n", fd, uintptr(wasiIovs), iovsLen)
 	}
 
-	readLength := 0
-
-	for i := uint32(0); i < iovsLen; i++ {
-		wasiIov := (*wasm3.WasiIoVec)(unsafe.Pointer(uintptr(wasiIovs) + unsafe.Sizeof(wasm3.WasiIoVec{})*uintptr(i)))
-		addr := m3ApiOffsetToPtr(cs.mem, wasiIov.GetBuf())
-		length := int(wasiIov.GetBufLen())
-		if length == 0 {
-			continue
-		}
+	*(*uint32)(nRead) = 0
 
-		buffer := (*[1 << 30]byte)(unsafe.Pointer(addr))[:length:length]
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
 
-		virtualFile := state.OpenedVirtualFiles[int(fd)]
-		l := virtualFile.Read(buffer)
-		readLength = readLength + l
+	if _, ok := virtualFile.(*OpenedVirtualDirectory); ok {
+		return WASI_EISDIR, 0
 	}
 
-	*(*uint32)(nRead) = uint32(readLength)
+	*(*uint32)(nRead) = uint32(readIovs(cs, virtualFile, wasiIovs, iovsLen))
 
 	return WASI_ESUCCESS, 0
 }
 
+// positioned runs an operation at a position of a seekable file, then restores the file position
+func (state *state) positioned(fd uint32, offset int64, operation func(virtualFile VirtualFile) uint32) uint32 {
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF
+	}
+
+	seekable, ok := virtualFile.(SeekableVirtualFile)
+	if !ok {
+		return WASI_ESPIPE
+	}
+
+	pos, err := seekable.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return WASI_ESPIPE
+	}
+
+	_, err = seekable.Seek(offset, io.SeekStart)
+	if err != nil {
+		return WASI_EINVAL
+	}
+	defer seekable.Seek(pos, io.SeekStart)
+
+	return operation(virtualFile)
+}
+
+// WASIFdPread reads at a position without moving the file position
+func WASIFdPread(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	wasiIovs := cs.GetParamPointer(1)
+	iovsLen := cs.GetParamUINT32(2)
+	offset := cs.GetParamINT64(3)
+	nRead := cs.GetParamUINT32Ptr(4)
+
+	*nRead = 0
+
+	return state.positioned(fd, offset, func(virtualFile VirtualFile) uint32 {
+		*nRead = uint32(readIovs(cs, virtualFile, wasiIovs, iovsLen))
+		return WASI_ESUCCESS
+	}), 0
+}
+
+// WASIFdPwrite writes at a position without moving the file position
+func WASIFdPwrite(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	wasiIovs := cs.GetParamPointer(1)
+	iovsLen := cs.GetParamUINT32(2)
+	offset := cs.GetParamINT64(3)
+	nWritten := cs.GetParamUINT32Ptr(4)
+
+	*nWritten = 0
+
+	return state.positioned(fd, offset, func(virtualFile VirtualFile) uint32 {
+		writtenLength, err := writeIovs(state, cs, fd, virtualFile, wasiIovs, iovsLen)
+		*nWritten = uint32(writtenLength)
+		return wasiErrno(err)
+	}), 0
+}
+
 // WASIFdSeek WASIFdSeek
 func WASIFdSeek(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
-	offset := cs.GetParamUINT32(1)
+	offset := cs.GetParamINT64(1)
 	whence := cs.GetParamUINT32(2)
 	resultAddr := cs.GetParamPointer(3)
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called fd_seek fd %d offset %d whence %d resultAddr %x\n", fd, offset, whence, resultAddr)
 	}
 
-	*(*uint32)(resultAddr) = offset
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	seekable, ok := virtualFile.(SeekableVirtualFile)
+	if !ok {
+		return WASI_ESPIPE, 0
+	}
+
+	ioWhence, ok := state.whence(whence)
+	if !ok {
+		return WASI_EINVAL, 0
+	}
+
+	pos, err := seekable.Seek(offset, ioWhence)
+	if err != nil {
+		if state.fctx.Trace {
+			fmt.Printf("fd_seek error: %v\n", err)
+		}
+		return WASI_EINVAL, 0
+	}
+
+	*(*uint64)(resultAddr) = uint64(pos)
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdTell WASIFdTell
+func WASIFdTell(state *state, cs *CallSite) (uint32, int) {
+	fd := cs.GetParamUINT32(0)
+	resultAddr := cs.GetParamPointer(1)
+
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	seekable, ok := virtualFile.(SeekableVirtualFile)
+	if !ok {
+		return WASI_ESPIPE, 0
+	}
+
+	pos, err := seekable.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return WASI_ESPIPE, 0
+	}
+
+	*(*uint64)(resultAddr) = uint64(pos)
 
 	return WASI_ESUCCESS, 0
 }
@@ -459,36 +1278,329 @@ func WASIFdSeek(state *state, cs *CallSite) (uint32, int) {
 func WASIFdClose(state *state, cs *CallSite) (uint32, int) {
 	fd := cs.GetParamUINT32(0)
 
-	virtualFile := state.OpenedVirtualFiles[int(fd)]
-	virtualFile.Close()
+	virtualFile, ok := state.OpenedVirtualFiles[int(fd)]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	result := virtualFile.Close()
+	delete(state.OpenedVirtualFiles, int(fd))
+	delete(state.FdFlags, int(fd))
+
+	if result != 0 {
+		return WASI_EIO, 0
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIFdRenumber moves a file descriptor over an opened one, which is closed
+func WASIFdRenumber(state *state, cs *CallSite) (uint32, int) {
+	fd := int(cs.GetParamUINT32(0))
+	to := int(cs.GetParamUINT32(1))
+
+	virtualFile, ok := state.OpenedVirtualFiles[fd]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	previous, ok := state.OpenedVirtualFiles[to]
+	if !ok {
+		return WASI_EBADF, 0
+	}
+
+	if fd == to {
+		return WASI_ESUCCESS, 0
+	}
+
+	previous.Close()
+	state.OpenedVirtualFiles[to] = virtualFile
+	state.FdFlags[to] = state.FdFlags[fd]
+	delete(state.OpenedVirtualFiles, fd)
+	delete(state.FdFlags, fd)
 
 	return WASI_ESUCCESS, 0
 }
 
 func WASIEnvironSizesGet(state *state, cs *CallSite) (uint32, int) {
-	envCount := cs.GetParamUINT32Ptr(0)
-	envBufSize := cs.GetParamUINT32Ptr(1)
+	envCount, envCountOk := cs.GetParamPointerRange(0, 4)
+	envBufSize, envBufSizeOk := cs.GetParamPointerRange(1, 4)
+	if !envCountOk || !envBufSizeOk {
+		return WASI_EFAULT, 0
+	}
 
-	if state.wctx.Fctx.Trace {
+	if state.fctx.Trace {
 		fmt.Printf("called environ_sizes_get with %x %x\n", envCount, envBufSize)
 	}
 
-	*envCount = 0
-	*envBufSize = 0
+	*(*uint32)(envCount) = uint32(len(state.fctx.Environment))
+	*(*uint32)(envBufSize) = uint32(state.EnvironmentSize)
 
 	return WASI_ESUCCESS, 0
 }
 
+// WASIEnvironGet writes the environment variables of the function, the secrets of its secrets_env tag
 func WASIEnvironGet(state *state, cs *CallSite) (uint32, int) {
-	if state.wctx.Fctx.Trace {
-		fmt.Printf("called environ_get\n")
+	nbVariables := len(state.fctx.Environment)
+
+	environAddr, environOk := cs.GetParamPointerRange(0, 4*uint64(nbVariables))
+	environBufAddr, environBufOk := cs.GetParamPointerRange(1, uint64(state.EnvironmentSize))
+	if !environOk || !environBufOk {
+		return WASI_EFAULT, 0
+	}
+
+	environ := (*[1 << 30]uint32)(environAddr)[:nbVariables:nbVariables]
+	environBuf := (*[1 << 30]byte)(environBufAddr)[:state.EnvironmentSize:state.EnvironmentSize]
+	environBufOffset := cs.GetParamUINT32(1)
+
+	if state.fctx.Trace {
+		fmt.Printf("called environ_get, %d variables\n", nbVariables)
+	}
+
+	index := uint32(0)
+	for i, variable := range state.fctx.Environment {
+		environ[i] = environBufOffset + index
+
+		copy(environBuf[index:], []byte(variable))
+		environBuf[int(index)+len(variable)] = 0
+
+		index = uint32(int(index) + len(variable) + 1)
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// clockTime returns the time of a clock in nanoseconds, the cpu time clocks count from the start
+// of the execution like the monotonic one
+func (state *state) clockTime(clockID uint32) (uint64, bool) {
+	switch clockID {
+	case WASI_CLOCK_REALTIME:
+		return uint64(time.Now().UnixNano()), true
+	case WASI_CLOCK_MONOTONIC, WASI_CLOCK_PROCESS_CPUTIME_ID, WASI_CLOCK_THREAD_CPUTIME_ID:
+		return uint64(time.Since(state.startTime)), true
+	}
+
+	return 0, false
+}
+
+// WASIClockResGet WASIClockResGet
+func WASIClockResGet(state *state, cs *CallSite) (uint32, int) {
+	clockID := cs.GetParamUINT32(0)
+	resolution, ok := cs.GetParamPointerRange(1, 8)
+	if !ok {
+		return WASI_EFAULT, 0
+	}
+
+	if _, ok := state.clockTime(clockID); !ok {
+		return WASI_EINVAL, 0
 	}
-	/*m3ApiReturnType  (uint32_t)
-	    m3ApiGetArgMem   (u32*                 , env)
-	    m3ApiGetArgMem   (char*                , env_buf)
 
-	    if (runtime == NULL) { m3ApiReturn(__WASI_EINVAL); }
-	    // TODO
-		m3ApiReturn(__WASI_ESUCCESS);*/
+	*(*uint64)(resolution) = 1
+
 	return WASI_ESUCCESS, 0
 }
+
+// WASIClockTimeGet WASIClockTimeGet
+func WASIClockTimeGet(state *state, cs *CallSite) (uint32, int) {
+	clockID := cs.GetParamUINT32(0)
+	timeAddr, ok := cs.GetParamPointerRange(2, 8)
+	if !ok {
+		return WASI_EFAULT, 0
+	}
+
+	now, ok := state.clockTime(clockID)
+	if !ok {
+		return WASI_EINVAL, 0
+	}
+
+	*(*uint64)(timeAddr) = now
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASIRandomGet fills the buffer with cryptographically secure random bytes
+func WASIRandomGet(state *state, cs *CallSite) (uint32, int) {
+	size := cs.GetParamUINT32(1)
+	addr, ok := cs.GetParamPointerRange(0, uint64(size))
+	if !ok {
+		return WASI_EFAULT, 0
+	}
+	if size == 0 {
+		return WASI_ESUCCESS, 0
+	}
+
+	_, err := rand.Read((*[1 << 30]byte)(addr)[:size:size])
+	if err != nil {
+		return WASI_EIO, 0
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// WASISchedYield WASISchedYield
+func WASISchedYield(state *state, cs *CallSite) (uint32, int) {
+	runtime.Gosched()
+
+	return WASI_ESUCCESS, 0
+}
+
+/*
+	WASIPollOneOff waits for the first subscription to be ready.
+
+	Virtual files never block, so reading or writing subscriptions are ready immediately. Clock
+	subscriptions wait, but never past the execution deadline : the function is then aborted
+	without reporting the clocks which have not elapsed.
+*/
+func WASIPollOneOff(state *state, cs *CallSite) (uint32, int) {
+	nSubscriptions := cs.GetParamUINT32(2)
+
+	if state.fctx.Trace {
+		fmt.Printf("called poll_oneoff with %d subscriptions\n", nSubscriptions)
+	}
+
+	// the clock subscriptions have an identifier field before preview1
+	subscriptionSize, clockOffset := uintptr(56), uintptr(24)
+	if state.preview1 {
+		subscriptionSize, clockOffset = 48, 16
+	}
+
+	subscriptions, subscriptionsOk := cs.GetParamPointerRange(0, uint64(nSubscriptions)*uint64(subscriptionSize))
+	events, eventsOk := cs.GetParamPointerRange(1, uint64(nSubscriptions)*32)
+	nEventsAddr, nEventsOk := cs.GetParamPointerRange(3, 4)
+	if !subscriptionsOk || !eventsOk || !nEventsOk {
+		return WASI_EFAULT, 0
+	}
+	nEvents := (*uint32)(nEventsAddr)
+
+	*nEvents = 0
+
+	if nSubscriptions == 0 {
+		return WASI_EINVAL, 0
+	}
+
+	putEvent := func(userData uint64, errno uint32, eventType uint8, nBytes uint64) {
+		event := unsafe.Pointer(uintptr(events) + uintptr(*nEvents)*32)
+		eventBytes := (*[32]byte)(event)
+		for i := range eventBytes {
+			eventBytes[i] = 0
+		}
+
+		putUint64(event, 0, userData)
+		putUint16(event, 8, uint16(errno))
+		putUint8(event, 10, eventType)
+		putUint64(event, 16, nBytes)
+
+		*nEvents = *nEvents + 1
+	}
+
+	type clockSubscription struct {
+		userData uint64
+		deadline time.Time
+	}
+	clocks := []clockSubscription{}
+
+	for i := uint32(0); i < nSubscriptions; i++ {
+		subscription := unsafe.Pointer(uintptr(subscriptions) + uintptr(i)*subscriptionSize)
+		userData := getUint64(subscription, 0)
+		eventType := *(*uint8)(unsafe.Pointer(uintptr(subscription) + 8))
+
+		switch eventType {
+		case WASI_EVENTTYPE_FD_READ, WASI_EVENTTYPE_FD_WRITE:
+			fd := int(getUint32(subscription, 16))
+			if _, ok := state.OpenedVirtualFiles[fd]; !ok {
+				putEvent(userData, WASI_EBADF, eventType, 0)
+				continue
+			}
+
+			nBytes := uint64(0)
+			if eventType == WASI_EVENTTYPE_FD_READ {
+				if seekable, ok := state.OpenedVirtualFiles[fd].(SeekableVirtualFile); ok {
+					stat, err := state.stat(fd)
+					pos, errPos := seekable.Seek(0, io.SeekCurrent)
+					if err == nil && errPos == nil && stat.Size > uint64(pos) {
+						nBytes = stat.Size - uint64(pos)
+					}
+				}
+			}
+			putEvent(userData, WASI_ESUCCESS, eventType, nBytes)
+
+		case WASI_EVENTTYPE_CLOCK:
+			clockID := getUint32(subscription, clockOffset)
+			timeout := getUint64(subscription, clockOffset+8)
+			flags := getUint16(subscription, clockOffset+24)
+
+			now, ok := state.clockTime(clockID)
+			if !ok {
+				putEvent(userData, WASI_EINVAL, eventType, 0)
+				continue
+			}
+
+			wait := time.Duration(timeout)
+			if flags&WASI_SUBSCRIPTION_CLOCK_ABSTIME != 0 {
+				wait = 0
+				if timeout > now {
+					wait = time.Duration(timeout - now)
+				}
+			}
+
+			clocks = append(clocks, clockSubscription{userData, time.Now().Add(wait)})
+
+		default:
+			putEvent(userData, WASI_EINVAL, eventType, 0)
+		}
+	}
+
+	if *nEvents > 0 || len(clocks) == 0 {
+		return WASI_ESUCCESS, 0
+	}
+
+	earliest := clocks[0].deadline
+	for _, clock := range clocks {
+		if clock.deadline.Before(earliest) {
+			earliest = clock.deadline
+		}
+	}
+
+	if !state.fctx.Deadline.IsZero() && state.fctx.Deadline.Before(earliest) {
+		time.Sleep(time.Until(state.fctx.Deadline))
+
+		// no clock has elapsed, the execution timed out
+		if state.fctx.CheckLimits() != nil {
+			return 0, -1
+		}
+	}
+	time.Sleep(time.Until(earliest))
+
+	for _, clock := range clocks {
+		if !clock.deadline.After(earliest) {
+			putEvent(clock.userData, WASI_ESUCCESS, WASI_EVENTTYPE_CLOCK, 0)
+		}
+	}
+
+	return WASI_ESUCCESS, 0
+}
+
+// sockets cannot be opened by posix functions, so no file descriptor is a socket
+func wasiNotASocket(state *state, fd uint32) uint32 {
+	if _, ok := state.OpenedVirtualFiles[int(fd)]; !ok {
+		return WASI_EBADF
+	}
+
+	return WASI_ENOTSOCK
+}
+
+func WASISockAccept(state *state, cs *CallSite) (uint32, int) {
+	return wasiNotASocket(state, cs.GetParamUINT32(0)), 0
+}
+
+func WASISockRecv(state *state, cs *CallSite) (uint32, int) {
+	return wasiNotASocket(state, cs.GetParamUINT32(0)), 0
+}
+
+func WASISockSend(state *state, cs *CallSite) (uint32, int) {
+	return wasiNotASocket(state, cs.GetParamUINT32(0)), 0
+}
+
+func WASISockShutdown(state *state, cs *CallSite) (uint32, int) {
+	return wasiNotASocket(state, cs.GetParamUINT32(0)), 0
+}
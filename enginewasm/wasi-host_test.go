@@ -0,0 +1,81 @@
+package enginewasm
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+// callWASI calls a WASI function with the given parameters on a guest memory
+func callWASI(handler WasiCallHandler, state *state, memory []byte, params ...uint64) (uint32, int) {
+	sp := make([]uint64, len(params)+1)
+	copy(sp, params)
+
+	return handler(state, NewCallSite(unsafe.Pointer(&sp[0]), unsafe.Pointer(&memory[0]), uint64(len(memory))))
+}
+
+func newTestWASIState(fctx *common.FunctionExecutionContext) *state {
+	s := newWASIHost(nil, nil, nil)
+	s.fctx = fctx
+	s.preview1 = true
+
+	for _, variable := range fctx.Environment {
+		s.EnvironmentSize = s.EnvironmentSize + len(variable) + 1
+	}
+
+	return s
+}
+
+func TestWASIPointersOutOfMemory(t *testing.T) {
+	state := newTestWASIState(&common.FunctionExecutionContext{Environment: []string{"A=1", "B=2"}})
+	memory := make([]byte, 256)
+
+	// the environment buffer crosses the end of the memory
+	if errno, _ := callWASI(WASIEnvironGet, state, memory, 0, 250); errno != WASI_EFAULT {
+		t.Fatalf("environ_get returned %d, expected EFAULT", errno)
+	}
+	if errno, _ := callWASI(WASIEnvironGet, state, memory, 0, 16); errno != WASI_ESUCCESS {
+		t.Fatalf("environ_get returned %d", errno)
+	}
+	if string(memory[16:24]) != "A=1\x00B=2\x00" {
+		t.Fatalf("unexpected environment %q", memory[16:24])
+	}
+
+	// four subscriptions of 48 bytes do not fit after 100
+	if errno, _ := callWASI(WASIPollOneOff, state, memory, 100, 0, 4, 200); errno != WASI_EFAULT {
+		t.Fatalf("poll_oneoff returned %d, expected EFAULT", errno)
+	}
+	if errno, _ := callWASI(WASIPollOneOff, state, memory, 0, 0, 1, 254); errno != WASI_EFAULT {
+		t.Fatalf("poll_oneoff returned %d, expected EFAULT", errno)
+	}
+	if errno, _ := callWASI(WASIRandomGet, state, memory, 200, 100); errno != WASI_EFAULT {
+		t.Fatalf("random_get returned %d, expected EFAULT", errno)
+	}
+}
+
+func TestWASIPollOneOffStopsAtDeadline(t *testing.T) {
+	fctx := &common.FunctionExecutionContext{Deadline: time.Now().Add(50 * time.Millisecond)}
+	state := newTestWASIState(fctx)
+	memory := make([]byte, 256)
+
+	// one relative clock subscription of an hour, events at 64, the number of events at 128
+	putUint64(unsafe.Pointer(&memory[0]), 0, 7)
+	putUint8(unsafe.Pointer(&memory[0]), 8, WASI_EVENTTYPE_CLOCK)
+	putUint32(unsafe.Pointer(&memory[0]), 16, WASI_CLOCK_MONOTONIC)
+	putUint64(unsafe.Pointer(&memory[0]), 24, uint64(time.Hour))
+	putUint32(unsafe.Pointer(&memory[0]), 128, 12)
+
+	start := time.Now()
+	_, trap := callWASI(WASIPollOneOff, state, memory, 0, 64, 1, 128)
+	if trap != -1 {
+		t.Fatal("poll_oneoff did not stop the execution at the deadline")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("poll_oneoff returned after %v", time.Since(start))
+	}
+	if nEvents := getUint32(unsafe.Pointer(&memory[0]), 128); nEvents != 0 {
+		t.Fatalf("poll_oneoff reported %d events for a clock which has not elapsed", nEvents)
+	}
+}
@@ -2,20 +2,50 @@ package enginewasmer
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"unsafe"
 
 	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
 	"github.com/wasmerio/wasmer-go/wasmer"
 )
 
+/*
+	The wasmer engine runs the same functions as the wasm3 engine : the api providers, the WASI
+	layer (posix mode), the TinyGo layer and the auto-linking of modules are bound through the
+	enginewasm.WasmHost interface, so both engines expose exactly the same host functions.
+
+	Modules are instrumented with their metering points and compiled by wasmer, which is expensive,
+	so compiled modules are kept by techID. A wasmer store is not thread safe and keeps the objects
+	created in it, so each module is compiled in its own store, which is used by one execution at a
+	time : an execution takes the compiled module out of the cache and puts it back when it ends,
+	concurrent executions of the same function compile their own. A fresh instance is created for
+	each execution.
+*/
 type WasmWasmerEngine struct {
+	engine *wasmer.Engine
+
+	modules *common.Cache
+	// instrumented modules, by techID
+	instrumentations *common.Cache
 }
 
 func NewWasmWasmerEngine() *WasmWasmerEngine {
-	return &WasmWasmerEngine{}
+	return &WasmWasmerEngine{
+		engine:           wasmer.NewEngine(),
+		modules:          common.NewCache("wasmer-modules", common.DefaultCacheSize, nil),
+		instrumentations: common.NewCache("wasmer-instrumentations", common.DefaultCacheSize, nil),
+	}
+}
+
+func (e *WasmWasmerEngine) GetCaches() []*common.Cache {
+	return []*common.Cache{e.modules, e.instrumentations}
 }
 
 func (e *WasmWasmerEngine) PrepareContext(fctx *common.FunctionExecutionContext) (common.ExecutionEngineContext, error) {
-	wctx := CreateWasmWasmerContext(fctx)
+	wctx := CreateWasmWasmerContext(e, fctx)
 	if wctx == nil {
 		return nil, errors.New("cannot create wasm context")
 	}
@@ -23,93 +53,498 @@ func (e *WasmWasmerEngine) PrepareContext(fctx *common.FunctionExecutionContext)
 	return wctx, nil
 }
 
+// compiledModule is a module compiled in its own store, with its instrumented code whose function
+// indexes are the ones of the backtraces
+type compiledModule struct {
+	store  *wasmer.Store
+	module *wasmer.Module
+	code   []byte
+}
+
+// compile returns the compiled module of the function, taken from the cache when possible
+func (e *WasmWasmerEngine) compile(fctx *common.FunctionExecutionContext) (*compiledModule, error) {
+	// a module which cannot be instrumented is refused to the executions with limits, even compiled
+	instrumented, err := enginewasm.InstrumentFunction(e.instrumentations, fctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if fctx.TechID != "" {
+		if compiled, ok := e.modules.Take(fctx.TechID); ok {
+			return compiled.(*compiledModule), nil
+		}
+	}
+
+	store := wasmer.NewStore(e.engine)
+	module, err := wasmer.NewModule(store, instrumented.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledModule{store, module, instrumented.Code}, nil
+}
+
+// release puts the compiled module back in the cache once the execution has ended
+func (e *WasmWasmerEngine) release(fctx *common.FunctionExecutionContext, compiled *compiledModule) {
+	if fctx.TechID != "" {
+		e.modules.Put(fctx.TechID, compiled)
+	}
+}
+
+// WasmProcessContext represents a running WASM context
 type WasmProcessContext struct {
 	Fctx *common.FunctionExecutionContext
 
-	// the engine or something to get to it
-	engine *wasmer.Engine
+	Module   *wasmer.Module
+	Instance *wasmer.Instance
+
+	engine *WasmWasmerEngine
 	store  *wasmer.Store
-}
+	code   []byte
+	memory *wasmer.Memory
+	// the metering countdown, set to zero to stop the program after a host call
+	countdown *wasmer.Global
+	// set when a host function aborted the execution
+	abortError error
 
-func CreateWasmWasmerContext(fctx *common.FunctionExecutionContext) *WasmProcessContext {
-	engine := wasmer.NewEngine()
-	store := wasmer.NewStore(engine)
+	// host functions bound by module and name, wasmer functions are created with the imported
+	// function types when the module is instantiated
+	hostFunctions map[string]map[string]enginewasm.HostCallHandler
+	// wasmer-go forgets a host function when its Function is collected, they are kept until the
+	// end of the execution
+	importedFunctions []*wasmer.Function
+	// the file system of a posix execution
+	workingDirectory *enginewasm.MountedDirectory
+}
 
+func CreateWasmWasmerContext(engine *WasmWasmerEngine, fctx *common.FunctionExecutionContext) *WasmProcessContext {
 	return &WasmProcessContext{
-		Fctx:   fctx,
-		engine: engine,
-		store:  store,
+		Fctx:          fctx,
+		engine:        engine,
+		hostFunctions: make(map[string]map[string]enginewasm.HostCallHandler),
 	}
 }
 
-func (ctx *WasmProcessContext) Run() error {
-	println("we should run now")
-	wasmBytes := ctx.Fctx.CodeBytes
+// Run runs the process
+func (wctx *WasmProcessContext) Run() error {
+	compiled, err := wctx.engine.compile(wctx.Fctx)
+	if err != nil {
+		return fmt.Errorf("cannot compile module (%v)", err)
+	}
+	defer wctx.engine.release(wctx.Fctx, compiled)
 
-	module, err := wasmer.NewModule(ctx.store, wasmBytes)
+	wctx.Module = compiled.module
+	wctx.store = compiled.store
+	wctx.code = compiled.code
+
+	err = wctx.bindImportedModules()
 	if err != nil {
-		println("KGHDZKJGHDZKJDGH 1")
 		return err
 	}
 
-	get_input_buffer_idFunction := wasmer.NewFunction(
-		ctx.store,
-		wasmer.NewFunctionType(wasmer.NewValueTypes(), wasmer.NewValueTypes(wasmer.I32)),
-		func(values []wasmer.Value) ([]wasmer.Value, error) {
-			return []wasmer.Value{wasmer.NewI32(ctx.Fctx.InputExchangeBufferID)}, nil
+	importObject, err := wctx.createImportObject()
+	if err != nil {
+		return err
+	}
+
+	instance, err := wasmer.NewInstance(wctx.Module, importObject)
+	if err != nil {
+		return fmt.Errorf("cannot instantiate module (%v)", err)
+	}
+	wctx.Instance = instance
+
+	memory, err := instance.Exports.GetMemory("memory")
+	if err == nil {
+		wctx.memory = memory
+	}
+
+	countdown, err := instance.Exports.GetGlobal(enginewasm.MeteringCountdownGlobal)
+	if err == nil {
+		wctx.countdown = countdown
+	}
+
+	fn, err := instance.Exports.GetRawFunction(wctx.Fctx.StartFunction)
+	if err != nil {
+		return fmt.Errorf("not found '%s' function", wctx.Fctx.StartFunction)
+	}
+
+	params := fn.Type().Params()
+	if len(params) != len(wctx.Fctx.Arguments) {
+		return fmt.Errorf("function '%s' expects %d arguments, %d given", wctx.Fctx.StartFunction, len(params), len(wctx.Fctx.Arguments))
+	}
+
+	arguments := make([]interface{}, len(params))
+	for i, param := range params {
+		switch param.Kind() {
+		case wasmer.I64:
+			arguments[i] = int64(wctx.Fctx.Arguments[i])
+		default:
+			arguments[i] = int32(wctx.Fctx.Arguments[i])
+		}
+	}
+
+	result, err := fn.Call(arguments...)
+	runtime.KeepAlive(wctx.importedFunctions)
+	if err != nil && wctx.Fctx.Trace {
+		fmt.Printf("execution of '%s' stopped (%v)\n", wctx.Fctx.StartFunction, err)
+	}
+
+	// memory could have grown since the last host api call
+	wctx.checkLimits()
+
+	wctx.syncFiles()
+
+	// a host function stopping the program (proc_exit, api error) has already recorded the exit
+	if wctx.abortError != nil {
+		wctx.Fctx.FinishRunning(common.TrapExit(enginewasm.TrapKind(wctx.abortError.Error()), wctx.abortError.Error(), nil))
+	} else if trapError, ok := err.(*wasmer.TrapError); ok {
+		wctx.Fctx.FinishRunning(common.TrapExit(enginewasm.TrapKind(trapError.Error()), trapError.Error(), wctx.backtrace(trapError)))
+	} else if err != nil {
+		wctx.Fctx.FinishRunning(common.TrapExit(enginewasm.TrapKind(err.Error()), err.Error(), nil))
+	} else {
+		code := 0
+		switch r := result.(type) {
+		case int32:
+			code = int(r)
+		case int64:
+			code = int(r)
+		}
+		wctx.Fctx.FinishRunning(common.ReturnedExit(code))
+	}
+
+	return nil
+}
+
+// syncFiles writes back the persistent files the program did not close
+func (wctx *WasmProcessContext) syncFiles() {
+	if wctx.workingDirectory == nil {
+		return
+	}
+
+	err := wctx.workingDirectory.Sync()
+	if err != nil {
+		fmt.Printf("cannot write back the files of '%s' (%v)\n", wctx.Fctx.Name, err)
+	}
+}
+
+// backtrace symbolizes the frames of a trap, innermost first
+func (wctx *WasmProcessContext) backtrace(trapError *wasmer.TrapError) []string {
+	frames := trapError.Trace()
+	if len(frames) == 0 {
+		return nil
+	}
+
+	names := enginewasm.FunctionNames(wctx.code)
+	backtrace := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		backtrace = append(backtrace, enginewasm.BacktraceFrame(names, frame.FunctionIndex(), frame.FunctionOffset()))
+	}
+
+	return backtrace
+}
+
+// bindImportedModules binds the host functions for each module imported by the wasm module
+func (wctx *WasmProcessContext) bindImportedModules() error {
+	// auto import and dynamically link functions together
+	for m := range wctx.GetImportedModules() {
+		// bound when creating the import object
+		if m == enginewasm.MeteringModule {
+			continue
+		}
+
+		apiProvider := wctx.Fctx.Orchestrator.GetAPIProvider(m)
+		if apiProvider != nil {
+			err := wctx.checkImportCapabilities(m)
+			if err != nil {
+				return err
+			}
+
+			if wctx.Fctx.Trace {
+				fmt.Printf("emulating '%s' imported module with api provider '%s'\n", m, m)
+			}
+			apiProvider.BindToExecutionEngineContext(wctx)
+			continue
+		}
+
+		moduleFunctionTechID, err := wctx.Fctx.Orchestrator.GetBlobTechIDFromName(m)
+		if err == nil {
+			if wctx.Fctx.Trace {
+				fmt.Printf("emulating '%s' imported module with function '%s' techID:%s\n", m, m, moduleFunctionTechID)
+			}
+
+			wasmBytes, err := wctx.Fctx.Orchestrator.GetBlobBytesByTechID(moduleFunctionTechID)
+			if err != nil {
+				return fmt.Errorf("can't find sub function bytes (%s)", m)
+			}
+
+			wctx.bindLinkedModule(m, moduleFunctionTechID, wasmBytes)
+			continue
+		}
+
+		if wctx.Fctx.Mode == "posix" {
+			if m == "wasi_unstable" || m == "wasi_snapshot_preview1" {
+				if wctx.Fctx.Trace {
+					fmt.Printf("emulating '%s' imported module with WASI runtime layer\n", m)
+				}
+
+				wctx.workingDirectory = enginewasm.BindWASIHost(wctx)
+				continue
+			}
+		}
+
+		if m == "env" {
+			if wctx.Fctx.Trace {
+				fmt.Printf("emulating '%s' imported module with TinyGo runtime layer\n", m)
+			}
+
+			enginewasm.BindTinyGoRuntimeAPI(wctx)
+			continue
+		}
+
+		return fmt.Errorf("cannot emulate imported module '%s'", m)
+	}
+
+	return nil
+}
+
+// checkImportCapabilities fails if the module imports a function of the api provider that the
+// function may not use
+func (wctx *WasmProcessContext) checkImportCapabilities(moduleName string) error {
+	for _, importType := range wctx.Module.Imports() {
+		if importType.Module() != moduleName {
+			continue
+		}
+
+		err := wctx.Fctx.CheckImport(moduleName, importType.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindLinkedModule binds the functions imported from another module, each call runs the exported function
+func (wctx *WasmProcessContext) bindLinkedModule(moduleName string, techID string, wasmBytes []byte) {
+	for _, importType := range wctx.Module.Imports() {
+		if importType.Module() != moduleName || importType.Type().Kind() != wasmer.FUNCTION {
+			continue
+		}
+
+		functionName := importType.Name()
+		numArgs := len(importType.Type().IntoFunctionType().Params())
+		if wctx.Fctx.Trace {
+			fmt.Printf("- imports func %s from module %s\n", functionName, moduleName)
+		}
+
+		// TODO check imported signature is the same as exported signature...
+
+		wctx.BindAPIFunction(moduleName, functionName, "", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+			parameters := make([]int, numArgs)
+			for i := 0; i < numArgs; i++ {
+				parameters[i] = int(cs.GetParamUINT32(i))
+			}
+
+			return enginewasm.RunLinkedFunction(fctx, wctx.engine, moduleName, techID, functionName, wasmBytes, parameters)
 		})
+	}
+}
+
+// createImportObject creates the wasmer functions for the bound host functions imported by the module
+func (wctx *WasmProcessContext) createImportObject() (*wasmer.ImportObject, error) {
+	namespaces := make(map[string]map[string]wasmer.IntoExtern)
+
+	for _, importType := range wctx.Module.Imports() {
+		moduleName := importType.Module()
+		functionName := importType.Name()
+
+		if importType.Type().Kind() != wasmer.FUNCTION {
+			return nil, fmt.Errorf("cannot emulate imported %s '%s'::'%s'", importType.Type().Kind(), moduleName, functionName)
+		}
+
+		if _, ok := namespaces[moduleName]; !ok {
+			namespaces[moduleName] = make(map[string]wasmer.IntoExtern)
+		}
+
+		functionType := importType.Type().IntoFunctionType()
+
+		var function *wasmer.Function
+		if moduleName == enginewasm.MeteringModule {
+			function = wasmer.NewFunction(wctx.store, functionType, wctx.meter)
+		} else {
+			handler, ok := wctx.hostFunctions[moduleName][functionName]
+			if !ok {
+				return nil, fmt.Errorf("cannot emulate imported function '%s'::'%s'", moduleName, functionName)
+			}
+
+			function = wasmer.NewFunction(wctx.store, functionType, wctx.wrapHostFunction(moduleName, functionName, functionType, handler))
+		}
+
+		namespaces[moduleName][functionName] = function
+		wctx.importedFunctions = append(wctx.importedFunctions, function)
+	}
 
 	importObject := wasmer.NewImportObject()
-	importObject.Register("core",
-		map[string]wasmer.IntoExtern{
-			"get_input_buffer_id":               get_input_buffer_idFunction,
-			"get_output_buffer_id":              get_input_buffer_idFunction,
-			"create_exchange_buffer":            get_input_buffer_idFunction,
-			"write_exchange_buffer":             get_input_buffer_idFunction,
-			"write_exchange_buffer_header":      get_input_buffer_idFunction,
-			"write_exchange_buffer_status_code": get_input_buffer_idFunction,
-			"read_exchange_buffer":              get_input_buffer_idFunction,
-			"read_exchange_buffer_headers":      get_input_buffer_idFunction,
-			"base64_decode":                     get_input_buffer_idFunction,
-			"base64_encode":                     get_input_buffer_idFunction,
-			"register_blob_with_name":           get_input_buffer_idFunction,
-			"register_blob":                     get_input_buffer_idFunction,
-			"get_blob_tech_id_from_name":        get_input_buffer_idFunction,
-			"get_blob_bytes_as_string":          get_input_buffer_idFunction,
-			"plug_function":                     get_input_buffer_idFunction,
-			"plug_file":                         get_input_buffer_idFunction,
-			"unplug_path":                       get_input_buffer_idFunction,
-			"get_status":                        get_input_buffer_idFunction,
-			"persistence_set":                   get_input_buffer_idFunction,
-			"get_url":                           get_input_buffer_idFunction,
-			"persistence_get":                   get_input_buffer_idFunction,
-			"persistence_get_subset":            get_input_buffer_idFunction,
-			"print_debug":                       get_input_buffer_idFunction,
-			"get_time":                          get_input_buffer_idFunction,
-			"free_buffer":                       get_input_buffer_idFunction,
-			"call_function":                     get_input_buffer_idFunction,
-			"export_database":                   get_input_buffer_idFunction,
-			"beta_web_proxy":                    get_input_buffer_idFunction,
-			"is_trace":                          get_input_buffer_idFunction,
-			"plug_filter":                       get_input_buffer_idFunction,
-			"unplug_filter":                     get_input_buffer_idFunction,
-		})
-	instance, err := wasmer.NewInstance(module, importObject)
+	for moduleName, namespace := range namespaces {
+		importObject.Register(moduleName, namespace)
+	}
+
+	return importObject, nil
+}
+
+/*
+	wrapHostFunction calls the host function with its parameters laid out like in wasm3, one 64 bits
+	slot each.
+
+	wasmer-go frees twice the traps returned by host functions, so host functions never trap : when
+	a host function aborts the execution (exit, api error, limit exceeded), the metering countdown is
+	set to zero and the metering point following the host call traps. Host calls made in between, for
+	example from a start function, are refused.
+
+	wasmer-go can only return i32 results from host functions, and panics on other types. Functions
+	declaring other results are still bound, since modules often import functions they never call,
+	but calling them aborts the execution, wasmer trapping on the mismatched result.
+*/
+func (wctx *WasmProcessContext) wrapHostFunction(moduleName string, functionName string, functionType *wasmer.FunctionType, handler enginewasm.HostCallHandler) func([]wasmer.Value) ([]wasmer.Value, error) {
+	resultTypes := functionType.Results()
+
+	var unsupportedResult error
+	for _, resultType := range resultTypes {
+		if resultType.Kind() != wasmer.I32 {
+			unsupportedResult = fmt.Errorf("host function '%s'::'%s' returns %s, which wasmer cannot return", moduleName, functionName, resultType.Kind())
+		}
+	}
+
+	return func(args []wasmer.Value) ([]wasmer.Value, error) {
+		result := uint32(0xffff)
+
+		if wctx.abortError == nil {
+			if err := wctx.checkLimits(); err != nil {
+				wctx.abort(err)
+			}
+		}
+
+		if wctx.abortError == nil {
+			slots := make([]uint64, len(args)+1)
+			for i, arg := range args {
+				switch arg.Kind() {
+				case wasmer.I32:
+					slots[i] = uint64(uint32(arg.I32()))
+				case wasmer.I64:
+					slots[i] = uint64(arg.I64())
+				case wasmer.F32:
+					slots[i] = uint64(math.Float32bits(arg.F32()))
+				case wasmer.F64:
+					slots[i] = math.Float64bits(arg.F64())
+				}
+			}
+
+			var mem unsafe.Pointer
+			var memSize uint64
+			if wctx.memory != nil {
+				data := wctx.memory.Data()
+				if len(data) > 0 {
+					mem = unsafe.Pointer(&data[0])
+					memSize = uint64(len(data))
+				}
+			}
+
+			var trap int
+			result, trap = handler(enginewasm.NewCallSite(unsafe.Pointer(&slots[0]), mem, memSize))
+			if trap != 0 {
+				wctx.abort(fmt.Errorf("host function '%s'::'%s' aborted the execution (%d)", moduleName, functionName, trap))
+			}
+		}
+
+		// the handler runs first so that the exit it records (like a not implemented error) is kept
+		if wctx.abortError == nil && unsupportedResult != nil {
+			wctx.abort(unsupportedResult)
+		}
+
+		results := make([]wasmer.Value, len(resultTypes))
+		for i := range resultTypes {
+			results[i] = wasmer.NewI32(int32(result))
+		}
+
+		return results, nil
+	}
+}
+
+// abort records the error stopping the execution and sets the metering countdown to zero, so that
+// the program traps as soon as the host call returns
+func (wctx *WasmProcessContext) abort(err error) {
+	wctx.abortError = err
+
+	if wctx.countdown != nil {
+		wctx.countdown.Set(int32(0), wasmer.I32)
+	}
+}
+
+func (wctx *WasmProcessContext) GetFunctionExecutionContext() *common.FunctionExecutionContext {
+	return wctx.Fctx
+}
+
+func (wctx *WasmProcessContext) GetImportedModules() map[string]bool {
+	importedModules := make(map[string]bool)
+	for _, importType := range wctx.Module.Imports() {
+		if importType.Type().Kind() == wasmer.FUNCTION {
+			importedModules[importType.Module()] = true
+		}
+	}
+
+	return importedModules
+}
+
+// BindHostFunction registers a host function, it is created when the module is instantiated
+func (wctx *WasmProcessContext) BindHostFunction(moduleName string, functionName string, signature string, handler enginewasm.HostCallHandler) {
+	if wctx.Fctx.Trace {
+		fmt.Printf("binding function '%s'::'%s' signature:%s\n", moduleName, functionName, signature)
+	}
+
+	if _, ok := wctx.hostFunctions[moduleName]; !ok {
+		wctx.hostFunctions[moduleName] = make(map[string]enginewasm.HostCallHandler)
+	}
+
+	wctx.hostFunctions[moduleName][functionName] = handler
+}
+
+// BindAPIFunction registers a host function, an error traps the program
+func (wctx *WasmProcessContext) BindAPIFunction(moduleName string, functionName string, signature string, handler enginewasm.WasmCallHandler) {
+	wctx.BindHostFunction(moduleName, functionName, signature, enginewasm.APIHostCallHandler(wctx, handler))
+}
+
+// BindNotYetImplementedFunction exits the whole process when not yet implemented function is called
+func (wctx *WasmProcessContext) BindNotYetImplementedFunction(moduleName string, functionName string, signature string) {
+	wctx.BindHostFunction(moduleName, functionName, signature, enginewasm.NotYetImplementedHostCallHandler(wctx, functionName))
+}
+
+// meter is the function checking the limits at the metering points of the module, zero stops it
+func (wctx *WasmProcessContext) meter(args []wasmer.Value) ([]wasmer.Value, error) {
+	if wctx.abortError != nil {
+		return []wasmer.Value{wasmer.NewI32(0)}, nil
+	}
+
+	granted, err := wctx.Fctx.Meter()
+	if err == nil && wctx.memory != nil {
+		err = wctx.Fctx.CheckMemory(int(wctx.memory.DataSize()))
+	}
 	if err != nil {
-		println("KGHDZKJGHDZKJDGH 2 ", err.Error())
-		return err
+		granted = 0
 	}
 
-	// getStatus() -> u32 in the Dashboard sample project
-	aFunctionInMyWasmModule, err := instance.Exports.GetFunction("getStatus")
+	return []wasmer.Value{wasmer.NewI32(int32(granted))}, nil
+}
+
+// checkLimits verifies the execution limits, a failure should trap the wasm program
+func (wctx *WasmProcessContext) checkLimits() error {
+	err := wctx.Fctx.CheckLimits()
 	if err != nil {
-		println("KGHDZKJGHDZKJDGH 3")
 		return err
 	}
 
-	println("call funcTION")
-
-	aFunctionInMyWasmModule()
+	if wctx.memory == nil {
+		return nil
+	}
 
-	return nil
+	return wctx.Fctx.CheckMemory(int(wctx.memory.DataSize()))
 }
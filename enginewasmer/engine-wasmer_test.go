@@ -0,0 +1,162 @@
+package enginewasmer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// hostModule imports "test"::"stop" and "test"::"seven". It exports "run", looping forever after
+// calling stop, and "seven", returning the result of seven
+var hostModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7f,
+	0x02, 0x1a, 0x02,
+	0x04, 't', 'e', 's', 't', 0x04, 's', 't', 'o', 'p', 0x00, 0x00,
+	0x04, 't', 'e', 's', 't', 0x05, 's', 'e', 'v', 'e', 'n', 0x00, 0x00,
+	0x03, 0x03, 0x02, 0x00, 0x00,
+	0x07, 0x0f, 0x02, 0x03, 'r', 'u', 'n', 0x00, 0x02, 0x05, 's', 'e', 'v', 'e', 'n', 0x00, 0x03,
+	// call 0, drop, loop br 0 end, i32.const 0 - call 1
+	0x0a, 0x13, 0x02,
+	0x0c, 0x00, 0x10, 0x00, 0x1a, 0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b,
+	0x04, 0x00, 0x10, 0x01, 0x0b,
+}
+
+// wideModule exports "wide", returning the i64 result of "test"::"wide"
+var wideModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	0x01, 0x05, 0x01, 0x60, 0x00, 0x01, 0x7e,
+	0x02, 0x0d, 0x01, 0x04, 't', 'e', 's', 't', 0x04, 'w', 'i', 'd', 'e', 0x00, 0x00,
+	0x03, 0x02, 0x01, 0x00,
+	0x07, 0x08, 0x01, 0x04, 'w', 'i', 'd', 'e', 0x00, 0x01,
+	0x0a, 0x06, 0x01, 0x04, 0x00, 0x10, 0x00, 0x0b,
+}
+
+var errTestHostFunction = errors.New("test host function failed")
+
+// testAPIProvider binds the "test" module, stop aborts the execution, seven and wide return 7, fail
+// fails with errTestHostFunction
+type testAPIProvider struct{}
+
+func (p *testAPIProvider) BindToExecutionEngineContext(ctx common.ExecutionEngineContextBounding) {
+	host := ctx.(enginewasm.WasmHost)
+
+	host.BindHostFunction("test", "stop", "i()", func(cs *enginewasm.CallSite) (uint32, int) {
+		return 0, 1
+	})
+	host.BindHostFunction("test", "seven", "i()", func(cs *enginewasm.CallSite) (uint32, int) {
+		return 7, 0
+	})
+	host.BindHostFunction("test", "wide", "I()", func(cs *enginewasm.CallSite) (uint32, int) {
+		return 7, 0
+	})
+	host.BindAPIFunction("test", "fail", "i()", func(fctx *common.FunctionExecutionContext, cs *enginewasm.CallSite) (uint32, error) {
+		return 0, errTestHostFunction
+	})
+}
+
+func newTestOrchestrator(t *testing.T) *common.Orchestrator {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.AddAPIProvider("test", &testAPIProvider{})
+
+	return orchestrator
+}
+
+func runWasmer(t *testing.T, engine *WasmWasmerEngine, fctx *common.FunctionExecutionContext) {
+	ectx, err := engine.PrepareContext(fctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ectx.Run()
+	}()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Error("the execution was not stopped")
+	}
+}
+
+func TestHostFunctionAbortStopsExecution(t *testing.T) {
+	fctx := &common.FunctionExecutionContext{
+		Orchestrator:  newTestOrchestrator(t),
+		Name:          "host",
+		StartFunction: "run",
+		Mode:          "direct",
+		CodeBytes:     hostModule,
+	}
+
+	runWasmer(t, NewWasmWasmerEngine(), fctx)
+
+	if fctx.Exit == nil || !fctx.Exit.Failed() {
+		t.Fatalf("execution not trapped (%v)", fctx.Exit)
+	}
+}
+
+func TestConcurrentExecutions(t *testing.T) {
+	orchestrator := newTestOrchestrator(t)
+	engine := NewWasmWasmerEngine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			fctx := &common.FunctionExecutionContext{
+				Orchestrator:  orchestrator,
+				Name:          "host",
+				StartFunction: "seven",
+				Mode:          "direct",
+				TechID:        "host",
+				CodeBytes:     hostModule,
+			}
+
+			runWasmer(t, engine, fctx)
+
+			if fctx.Result != 7 {
+				t.Errorf("result is %d, expected 7", fctx.Result)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNonI32HostResultsTrap(t *testing.T) {
+	fctx := &common.FunctionExecutionContext{
+		Orchestrator:  newTestOrchestrator(t),
+		Name:          "wide",
+		StartFunction: "wide",
+		Mode:          "direct",
+		CodeBytes:     wideModule,
+	}
+
+	runWasmer(t, NewWasmWasmerEngine(), fctx)
+
+	if fctx.Exit == nil || !fctx.Exit.Failed() {
+		t.Fatalf("execution not trapped (%v)", fctx.Exit)
+	}
+}
@@ -0,0 +1,100 @@
+package enginewasmer
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+// runExitModule runs the _start function of a module of enginewasm/testdata/exits with wasmer
+func runExitModule(t *testing.T, name string, mode string) (*common.FunctionExecutionContext, error) {
+	codeBytes, err := ioutil.ReadFile("../enginewasm/testdata/exits/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orchestrator := newTestOrchestrator(t)
+	orchestrator.AddExecutionEngine("application/wasm", "wasmer", NewWasmWasmerEngine())
+
+	_, err = orchestrator.RegisterBlobWithName(name, "application/wasm", codeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+	defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+	fctx := orchestrator.NewFunctionExecutionContext(name, "_start", []int{}, false, mode, &name, &[]string{}, inputExchangeBufferID, outputExchangeBufferID)
+	fctx.Deadline = time.Now().Add(10 * time.Second)
+
+	err = fctx.Run()
+	if fctx.Exit == nil {
+		t.Fatalf("no exit recorded (%v)", err)
+	}
+
+	return fctx, err
+}
+
+func TestProcExitGivesExitedExit(t *testing.T) {
+	fctx, err := runExitModule(t, "exit.wasm", "posix")
+
+	// exiting is not a failure of the execution, whatever the code
+	if err != nil {
+		t.Fatalf("exit 3 gives the error %v", err)
+	}
+	if fctx.Exit.Kind != common.EXIT_EXITED || fctx.Exit.Code != 3 || fctx.Result != 3 {
+		t.Fatalf("exit 3 gives %+v", fctx.Exit)
+	}
+}
+
+func TestTrapsGiveTrappedExit(t *testing.T) {
+	tests := []struct {
+		module    string
+		trap      string
+		backtrace []string
+	}{
+		{"unreachable.wasm", common.TRAP_UNREACHABLE, []string{"inner", "_start"}},
+		{"out-of-bounds.wasm", common.TRAP_OUT_OF_BOUNDS_MEMORY_ACCESS, []string{"load", "_start"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.module, func(t *testing.T) {
+			fctx, err := runExitModule(t, test.module, "direct")
+
+			var executionError *common.ExecutionError
+			if !errors.As(err, &executionError) {
+				t.Fatalf("the trap gives the error %v", err)
+			}
+			if fctx.Exit.Kind != common.EXIT_TRAPPED || fctx.Exit.Trap != test.trap || fctx.Exit.Code != -1 {
+				t.Fatalf("the trap gives %+v", fctx.Exit)
+			}
+			// the functions are named after the name section of the module, the offsets are the
+			// ones of the metered module
+			functions := []string{}
+			for _, frame := range fctx.Exit.Backtrace {
+				functions = append(functions, strings.SplitN(frame, "+", 2)[0])
+			}
+			if !reflect.DeepEqual(functions, test.backtrace) {
+				t.Fatalf("the backtrace is %v, expected %v", fctx.Exit.Backtrace, test.backtrace)
+			}
+		})
+	}
+}
+
+func TestFailingHostCallGivesHostErrorExit(t *testing.T) {
+	fctx, err := runExitModule(t, "host-error.wasm", "direct")
+
+	if !errors.Is(err, errTestHostFunction) {
+		t.Fatalf("the failing host call gives the error %v", err)
+	}
+	if fctx.Exit.Kind != common.EXIT_HOST_ERROR || fctx.Exit.Message != errTestHostFunction.Error() {
+		t.Fatalf("the failing host call gives %+v", fctx.Exit)
+	}
+}
@@ -0,0 +1,64 @@
+package enginewasmer
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/apicore"
+	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
+)
+
+// the versions of the core api are 64 bits, written at a result pointer since wasmer only
+// returns i32 results from host functions
+func TestPersistenceVersionsAre64Bits(t *testing.T) {
+	orchestrator := newTestOrchestrator(t)
+	orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
+	orchestrator.AddExecutionEngine("application/wasm", "wasmer", NewWasmWasmerEngine())
+
+	coreAPIProvider, err := apicore.NewCoreAPIProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.AddAPIProvider("core", coreAPIProvider)
+
+	codeBytes, err := ioutil.ReadFile("testdata/versions/versions.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = orchestrator.RegisterBlobWithName("versions.wasm", "application/wasm", codeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(engine string, startFunction string) *common.FunctionExecutionContext {
+		inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+		outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+		defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+		defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+		name := "versions.wasm"
+		fctx := orchestrator.NewFunctionExecutionContext(name, startFunction, []int{}, false, "direct", &name, &[]string{}, inputExchangeBufferID, outputExchangeBufferID)
+		fctx.Engine = engine
+		fctx.Deadline = time.Now().Add(10 * time.Second)
+		fctx.Run()
+
+		return fctx
+	}
+
+	for _, engine := range []string{"wasm3", "wasmer"} {
+		t.Run(engine, func(t *testing.T) {
+			fctx := run(engine, "_start")
+			if fctx.Exit == nil || fctx.Exit.Kind != common.EXIT_RETURNED || fctx.Result != 0 {
+				t.Fatalf("the versions check failed with %d (%+v)", fctx.Result, fctx.Exit)
+			}
+
+			fctx = run(engine, "out_of_memory")
+			if fctx.Exit == nil || fctx.Exit.Kind != common.EXIT_HOST_ERROR || !strings.Contains(fctx.Exit.Message, "out of the memory") {
+				t.Fatalf("a result pointer out of the memory gives %+v", fctx.Exit)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package enginewasmer
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/apicore"
+	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
+)
+
+/*
+	The samples of _samples/other run on wasm3 and on wasmer, with the same result, exit and output.
+	http-request is left out : it needs the network.
+*/
+var samples = []struct {
+	file          string
+	startFunction string
+	mode          string
+	arguments     []int
+	input         string
+}{
+	{file: "api-demo-c.wasm", startFunction: "_start", mode: "direct", arguments: []int{264, 55}},
+	{file: "uppercase.wasm", startFunction: "_start", mode: "posix", input: "This text should be in uppercase, if yes, you can say yeahh !"},
+	{file: "wasi-write.wasm", startFunction: "_start", mode: "posix"},
+	{file: "hello-go.wasm", startFunction: "_start", mode: "posix"},
+}
+
+type sampleRun struct {
+	result int
+	exit   string
+	output string
+}
+
+func runSample(t *testing.T, orchestrator *common.Orchestrator, name string, engine string, startFunction string, mode string, arguments []int, input string) sampleRun {
+	inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+	defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+	defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+	inputExchangeBuffer := orchestrator.GetExchangeBuffer(inputExchangeBufferID)
+	inputExchangeBuffer.Write([]byte(input))
+	inputExchangeBuffer.Close()
+
+	posixFileName := name
+	posixArguments := []string{}
+
+	fctx := orchestrator.NewFunctionExecutionContext(name, startFunction, arguments, false, mode, &posixFileName, &posixArguments, inputExchangeBufferID, outputExchangeBufferID)
+	fctx.Engine = engine
+	fctx.Deadline = time.Now().Add(10 * time.Second)
+
+	// a failed execution gives its exit, which is compared too
+	err := fctx.Run()
+	var executionError *common.ExecutionError
+	if err != nil && !errors.As(err, &executionError) {
+		t.Fatalf("%s failed on %s (%v)", name, engine, err)
+	}
+
+	return sampleRun{
+		result: fctx.Result,
+		exit:   fctx.Exit.String(),
+		output: string(orchestrator.GetExchangeBuffer(outputExchangeBufferID).GetBuffer()),
+	}
+}
+
+func TestSamplesOnBothEngines(t *testing.T) {
+	orchestrator := newTestOrchestrator(t)
+	orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
+	orchestrator.AddExecutionEngine("application/wasm", "wasmer", NewWasmWasmerEngine())
+
+	coreAPIProvider, err := apicore.NewCoreAPIProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.AddAPIProvider("core", coreAPIProvider)
+	orchestrator.AddAPIProvider("my-own-cluster", coreAPIProvider)
+
+	for _, sample := range samples {
+		sample := sample
+		t.Run(sample.file, func(t *testing.T) {
+			codeBytes, err := ioutil.ReadFile(filepath.Join("..", "_samples", "other", sample.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = orchestrator.RegisterBlobWithName(sample.file, "application/wasm", codeBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wasm3 := runSample(t, orchestrator, sample.file, "wasm3", sample.startFunction, sample.mode, sample.arguments, sample.input)
+			wasmer := runSample(t, orchestrator, sample.file, "wasmer", sample.startFunction, sample.mode, sample.arguments, sample.input)
+
+			if wasm3 != wasmer {
+				t.Fatalf("wasm3 %+v, wasmer %+v", wasm3, wasmer)
+			}
+			t.Logf("%+v", wasm3)
+		})
+	}
+}
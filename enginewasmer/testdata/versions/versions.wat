@@ -0,0 +1,54 @@
+;; uses the 64 bits versions of the core api, returns 0 when they are all right
+(module
+  (import "core" "persistence_set" (func $set (param i32 i32 i32 i32) (result i32)))
+  (import "core" "persistence_get_version" (func $get_version (param i32 i32 i32) (result i32)))
+  (import "core" "persistence_compare_and_set" (func $compare_and_set (param i32 i32 i64 i32 i32 i32) (result i32)))
+  (import "core" "persistence_begin_transaction" (func $begin_transaction (result i32)))
+  (import "core" "persistence_commit_transaction" (func $commit_transaction (param i32) (result i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "/key")
+  (data (i32.const 16) "value")
+
+  ;; the result slot, filled with ones so that the high bits are checked too
+  (func $result (result i64)
+    (i64.load (i32.const 32)))
+  (func $clear
+    (i64.store (i32.const 32) (i64.const -1)))
+
+  (func (export "_start") (result i32)
+    (local $version i64)
+
+    (drop (call $set (i32.const 0) (i32.const 4) (i32.const 16) (i32.const 5)))
+
+    (call $clear)
+    (drop (call $get_version (i32.const 0) (i32.const 4) (i32.const 32)))
+    (local.set $version (call $result))
+    (if (i64.le_s (local.get $version) (i64.const 0))
+      (then (return (i32.const 1))))
+
+    ;; the high bits of the expected version are not dropped
+    (call $clear)
+    (drop (call $compare_and_set (i32.const 0) (i32.const 4) (i64.add (local.get $version) (i64.const 0x100000000)) (i32.const 16) (i32.const 5) (i32.const 32)))
+    (if (i64.ne (call $result) (i64.const 0))
+      (then (return (i32.const 2))))
+
+    (call $clear)
+    (drop (call $compare_and_set (i32.const 0) (i32.const 4) (local.get $version) (i32.const 16) (i32.const 5) (i32.const 32)))
+    (if (i64.le_s (call $result) (local.get $version))
+      (then (return (i32.const 3))))
+    (local.set $version (call $result))
+
+    (drop (call $begin_transaction))
+    (drop (call $set (i32.const 0) (i32.const 4) (i32.const 16) (i32.const 5)))
+    (call $clear)
+    (drop (call $commit_transaction (i32.const 32)))
+    (if (i64.le_s (call $result) (local.get $version))
+      (then (return (i32.const 4))))
+
+    (i32.const 0))
+
+  ;; the result pointer is out of the memory
+  (func (export "out_of_memory") (result i32)
+    (drop (call $get_version (i32.const 0) (i32.const 4) (i32.const 65532)))
+    (i32.const 0)))
@@ -0,0 +1,252 @@
+// wasi-tests exercises the WASI preview1 functions used by posix programs, like the programs of the
+// WASI testsuite. The test to run is the first argument, the program prints "ok" when it passes and
+// exits with code 1 otherwise.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var tests = map[string]func() error{
+	"args":    testArgs,
+	"environ": testEnviron,
+	"clock":   testClock,
+	"random":  testRandom,
+	"files":   testFiles,
+	"dirs":    testDirectories,
+	"stdin":   testStdin,
+	"exit":    testExit,
+	"sleep":   testSleep,
+}
+
+func main() {
+	if len(os.Args) < 2 || tests[os.Args[1]] == nil {
+		fmt.Println("usage: wasi-tests TEST [ARGUMENTS...]")
+		os.Exit(1)
+	}
+
+	err := tests[os.Args[1]]()
+	if err != nil {
+		fmt.Println("failed:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("ok")
+}
+
+func testArgs() error {
+	expected := []string{"wasi-tests", "args", "a", "b c"}
+	if strings.Join(os.Args, "|") != strings.Join(expected, "|") {
+		return fmt.Errorf("arguments are %q", os.Args)
+	}
+
+	return nil
+}
+
+func testEnviron() error {
+	if os.Getenv("GREETING") != "hello world" {
+		return fmt.Errorf("GREETING is %q", os.Getenv("GREETING"))
+	}
+	if _, ok := os.LookupEnv("MISSING"); ok {
+		return errors.New("MISSING is set")
+	}
+
+	return nil
+}
+
+// testClock checks the realtime and monotonic clocks, and sleeps with a clock subscription
+func testClock() error {
+	now := time.Now()
+	if now.Year() < 2020 {
+		return fmt.Errorf("realtime clock gives %v", now)
+	}
+
+	start := time.Now()
+	time.Sleep(20 * time.Millisecond)
+	elapsed := time.Since(start)
+	if elapsed < 20*time.Millisecond || elapsed > 5*time.Second {
+		return fmt.Errorf("slept %v instead of 20ms", elapsed)
+	}
+
+	return nil
+}
+
+func testRandom() error {
+	a := make([]byte, 64)
+	b := make([]byte, 64)
+	if _, err := rand.Read(a); err != nil {
+		return err
+	}
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	if bytes.Equal(a, b) || bytes.Equal(a, make([]byte, 64)) {
+		return errors.New("random bytes are not random")
+	}
+
+	return nil
+}
+
+// testFiles writes, reads, seeks, truncates, renames and removes files
+func testFiles() error {
+	err := os.WriteFile("file.txt", []byte("hello world"), 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat("file.txt")
+	if err != nil {
+		return err
+	}
+	if info.Size() != 11 || info.IsDir() {
+		return fmt.Errorf("file stat is size:%d dir:%v", info.Size(), info.IsDir())
+	}
+
+	f, err := os.OpenFile("file.txt", os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	pos, err := f.Seek(6, io.SeekStart)
+	if err != nil || pos != 6 {
+		return fmt.Errorf("seek to %d (%v)", pos, err)
+	}
+	part := make([]byte, 5)
+	if _, err := io.ReadFull(f, part); err != nil || string(part) != "world" {
+		return fmt.Errorf("read %q (%v)", part, err)
+	}
+	if pos, err := f.Seek(0, io.SeekCurrent); err != nil || pos != 11 {
+		return fmt.Errorf("position is %d (%v)", pos, err)
+	}
+
+	if _, err := f.WriteAt([]byte("HELLO"), 0); err != nil {
+		return err
+	}
+	part = make([]byte, 5)
+	if _, err := f.ReadAt(part, 0); err != nil || string(part) != "HELLO" {
+		return fmt.Errorf("read at 0 %q (%v)", part, err)
+	}
+
+	if err := f.Truncate(5); err != nil {
+		return err
+	}
+	if info, err := f.Stat(); err != nil || info.Size() != 5 {
+		return fmt.Errorf("truncated file stat %v (%v)", info, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	f, err = os.OpenFile("file.txt", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte("!")); err != nil {
+		return err
+	}
+	f.Close()
+
+	if err := os.Rename("file.txt", "renamed.txt"); err != nil {
+		return err
+	}
+	content, err := os.ReadFile("renamed.txt")
+	if err != nil || string(content) != "HELLO!" {
+		return fmt.Errorf("renamed file is %q (%v)", content, err)
+	}
+	if _, err := os.Stat("file.txt"); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("file.txt still exists (%v)", err)
+	}
+
+	if _, err := os.OpenFile("renamed.txt", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("exclusive creation of an existing file (%v)", err)
+	}
+
+	if err := os.Remove("renamed.txt"); err != nil {
+		return err
+	}
+	if _, err := os.ReadFile("renamed.txt"); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removed file can be read (%v)", err)
+	}
+
+	return nil
+}
+
+// testDirectories creates, lists and removes directories
+func testDirectories() error {
+	if err := os.MkdirAll("dir/sub", 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile("dir/a.txt", []byte("a"), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile("dir/b.txt", []byte("b"), 0644); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir("dir")
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "a.txt,b.txt,sub" {
+		return fmt.Errorf("directory lists %v", names)
+	}
+
+	if err := os.Mkdir("dir", 0755); !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("directory created twice (%v)", err)
+	}
+	if err := os.Remove("dir"); err == nil {
+		return errors.New("non empty directory removed")
+	}
+	if _, err := os.ReadFile("dir/missing/file.txt"); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("missing file read (%v)", err)
+	}
+
+	if err := os.RemoveAll("dir"); err != nil {
+		return err
+	}
+	if _, err := os.Stat("dir"); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removed directory exists (%v)", err)
+	}
+
+	return nil
+}
+
+// testStdin copies stdin to stdout, in upper case
+func testStdin() error {
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(strings.ToUpper(string(input)))
+
+	return nil
+}
+
+func testExit() error {
+	os.Exit(3)
+
+	return nil
+}
+
+// testSleep sleeps longer than the execution deadline
+func testSleep() error {
+	time.Sleep(10 * time.Second)
+
+	return errors.New("woke up")
+}
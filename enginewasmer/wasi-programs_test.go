@@ -0,0 +1,103 @@
+package enginewasmer
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ltearno/my-own-cluster/common"
+)
+
+/*
+	The WASI test programs are in testdata/wasi, each test runs the program with the test name as
+	its first argument. They are built with 'make' in that directory. Go programs resolve relative
+	paths from the PWD environment variable, which is set to the root directory.
+
+	They only run on wasmer : the wasm3 interpreter bound by go-wasm3 crashes while loading Go
+	programs, before any WASI call. The WASI testsuite programs of enginewasm run on both engines.
+*/
+var wasiTests = []struct {
+	arguments   []string
+	environment []string
+	stdin       string
+	timeout     time.Duration
+
+	exitCode int
+	stdout   string
+	limitErr error
+}{
+	{arguments: []string{"args", "a", "b c"}, stdout: "ok\n"},
+	{arguments: []string{"environ"}, environment: []string{"GREETING=hello world"}, stdout: "ok\n"},
+	{arguments: []string{"clock"}, stdout: "ok\n"},
+	{arguments: []string{"random"}, stdout: "ok\n"},
+	{arguments: []string{"files"}, stdout: "ok\n"},
+	{arguments: []string{"dirs"}, stdout: "ok\n"},
+	{arguments: []string{"stdin"}, stdin: "hello", stdout: "HELLO\nok\n"},
+	{arguments: []string{"exit"}, exitCode: 3},
+	// the clock subscription is not reported as elapsed at the deadline, the program is stopped
+	{arguments: []string{"sleep"}, timeout: 200 * time.Millisecond, exitCode: -1, limitErr: common.ErrExecutionTimeout},
+}
+
+func TestWASIPrograms(t *testing.T) {
+	codeBytes, err := ioutil.ReadFile("testdata/wasi/wasi-tests.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orchestrator := newTestOrchestrator(t)
+	orchestrator.AddExecutionEngine("application/wasm", "wasmer", NewWasmWasmerEngine())
+
+	_, err = orchestrator.RegisterBlobWithName("wasi-tests", "application/wasm", codeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range wasiTests {
+		test := test
+		t.Run(test.arguments[0], func(t *testing.T) {
+			inputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+			outputExchangeBufferID := orchestrator.CreateExchangeBuffer()
+			defer orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+			defer orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+			input := orchestrator.GetExchangeBuffer(inputExchangeBufferID)
+			input.Write([]byte(test.stdin))
+			input.Close()
+
+			posixFileName := "wasi-tests"
+			posixArguments := test.arguments
+
+			fctx := orchestrator.NewFunctionExecutionContext("wasi-tests", "_start", []int{}, false, "posix", &posixFileName, &posixArguments, inputExchangeBufferID, outputExchangeBufferID)
+			fctx.Engine = "wasmer"
+			fctx.Environment = append([]string{"PWD=/"}, test.environment...)
+			if test.timeout > 0 {
+				fctx.Deadline = time.Now().Add(test.timeout)
+			}
+
+			start := time.Now()
+			err := fctx.Run()
+			if test.limitErr != nil {
+				if !errors.Is(err, test.limitErr) && !errors.Is(fctx.LimitError(), test.limitErr) {
+					t.Fatalf("execution stopped with %v, expected %v", err, test.limitErr)
+				}
+				if time.Since(start) > 5*time.Second {
+					t.Fatalf("execution stopped after %v", time.Since(start))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stdout := string(orchestrator.GetExchangeBuffer(outputExchangeBufferID).GetBuffer())
+			if fctx.Exit == nil || fctx.Exit.Kind != common.EXIT_EXITED || fctx.Exit.Code != test.exitCode {
+				t.Fatalf("program stopped with %v, stdout %q", fctx.Exit, stdout)
+			}
+			if !strings.HasSuffix(stdout, test.stdout) {
+				t.Fatalf("stdout is %q, expected %q", stdout, test.stdout)
+			}
+		})
+	}
+}
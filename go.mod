@@ -10,7 +10,10 @@ require (
 	github.com/ltearno/go-wasm3 v0.0.0-20200725083730-4cba5ab93931
 	github.com/rs/xid v1.2.1
 	github.com/syndtr/goleveldb v1.0.0
-	github.com/wasmerio/wasmer-go v1.0.3 // indirect
+	github.com/wasmerio/wasmer-go v1.0.3
 	golang.org/x/sys v0.0.0-20210313202042-bd2e13477e9c
 	gopkg.in/ltearno/go-duktape.v3 v3.0.0-20200305165431-80869a0a46ea
 )
+
+// heaps with a counting allocator and an execution deadline, see third_party/go-duktape.v3/limits.go
+replace gopkg.in/ltearno/go-duktape.v3 => ./third_party/go-duktape.v3
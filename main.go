@@ -1,7 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -22,7 +24,6 @@ import (
 	"github.com/ltearno/my-own-cluster/apigpu"
 
 	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 type Verb struct {
@@ -64,14 +65,57 @@ func printHelp() {
 	fmt.Printf("\nmy-own-cluster usage :\n\n")
 	fmt.Printf("  help\n")
 	fmt.Printf("      prints this message\n")
-	fmt.Printf("  serve\n")
-	fmt.Printf("      start the web server\n")
-	fmt.Printf("  push FUNCTION_NAME WASM_FILE\n")
-	fmt.Printf("      sends a wasm code to the server\n")
-	fmt.Printf("  call FUNCTION_NAME posix")
-	fmt.Printf("      calls a function in POSIX mode (through WASI implementation)\n")
-	fmt.Printf("  call FUNCTION_NAME direct\n")
+	fmt.Printf("  serve [-wasmer true] [-jwtIssuer ISSUER] [-egressPolicy POLICY_FILE] [-masterKeyFile KEY_FILE] [-defaultLimits LIMITS]\n")
+	fmt.Printf("      start the web server, wasmer being the default web assembly engine or not\n")
+	fmt.Printf("      functions and filters whose plug gives no limits run with the default ones, like '{\"timeout\":\"30s\",\"max_memory\":\"67108864\"}'\n")
+	fmt.Printf("      the JWTs signed by the cluster have the given issuer, 'my-own-cluster' by default\n")
+	fmt.Printf("      outbound connections of functions follow the egress policy file, 'egress-policy.json' of the working directory if present\n")
+	fmt.Printf("      secrets are encrypted with the master key of MYOWNCLUSTER_MASTER_KEY or of the key file, 'master.key' of the working directory, created when missing\n")
+	fmt.Printf("      the first start writes an admin api token in the 'admin-token' file of the working directory\n")
+	fmt.Printf("  push FUNCTION_NAME WASM_FILE [-engine ENGINES]\n")
+	fmt.Printf("      sends a wasm code to the server, which runs it with the given engines like 'wasmer,wasm3' when its plug or caller asks for none\n")
+	fmt.Printf("  call FUNCTION_NAME posix [-mounts MOUNTS] [-engine ENGINES]\n")
+	fmt.Printf("      calls a function in POSIX mode (through WASI implementation), with the given mounts like '/app=blobs:myapp/,/data=persistence:/myapp/files'\n")
+	fmt.Printf("  call FUNCTION_NAME direct [-engine ENGINES]\n")
 	fmt.Printf("      calls a function in direct mode\n")
+	fmt.Printf("  status\n")
+	fmt.Printf("      prints the status of the cluster\n")
+	fmt.Printf("  create-token NAME ROLE\n")
+	fmt.Printf("      creates an api token with the role 'read-only', 'deployer' or 'admin' and prints it\n")
+	fmt.Printf("  list-tokens\n")
+	fmt.Printf("      lists the api tokens\n")
+	fmt.Printf("  revoke-token NAME\n")
+	fmt.Printf("      revokes an api token\n")
+	fmt.Printf("  add-trust-provider ISSUER (-jwksUrl URL | -jwksFile SERVER_FILE) [-audiences AUD1,AUD2] [-algorithms ALG1,ALG2] [-refreshInterval SECONDS] [-clockSkew SECONDS]\n")
+	fmt.Printf("      trusts the JWTs of an issuer, signed with the keys of its JWKS\n")
+	fmt.Printf("  list-trust-providers\n")
+	fmt.Printf("      lists the JWT trust providers, with their keys\n")
+	fmt.Printf("  remove-trust-provider ISSUER\n")
+	fmt.Printf("      stops trusting the JWTs of an issuer\n")
+	fmt.Printf("  rotate-signing-key [-algorithm ALG]\n")
+	fmt.Printf("      replaces the key signing the JWTs of the cluster by a new one (ES256, RS256, PS256 or EdDSA, default ES256)\n")
+	fmt.Printf("  set-secret -functions FUNCTION1,FUNCTION2 [-file FILE] NAME [VALUE]\n")
+	fmt.Printf("      creates or replaces a secret readable by the given functions ('*' for all), its value is read from the file or the standard input when not given\n")
+	fmt.Printf("  list-secrets\n")
+	fmt.Printf("      lists the secrets, without their values\n")
+	fmt.Printf("  delete-secret NAME\n")
+	fmt.Printf("      deletes a secret\n")
+	fmt.Printf("  list-namespaces\n")
+	fmt.Printf("      lists the persistence namespaces, with their number of keys, size and grants\n")
+	fmt.Printf("  scan [-prefix PREFIX] [-start KEY] [-end KEY] [-limit N] [-reverse true] [-keysOnly true] [-cursor CURSOR] NAMESPACE\n")
+	fmt.Printf("      prints a page of the keys and values of a persistence namespace, and the cursor of the next one\n")
+	fmt.Printf("  watch [-prefix PREFIX] [-since SEQUENCE] NAMESPACE\n")
+	fmt.Printf("      prints the changes of a persistence namespace as they happen, or the ones after a sequence\n")
+	fmt.Printf("  list-domains\n")
+	fmt.Printf("      lists the versionned data domains with their version and handler\n")
+	fmt.Printf("  register-domain-handler DOMAIN FUNCTION_NAME\n")
+	fmt.Printf("      makes a function the data migration handler of a domain, and migrates it\n")
+	fmt.Printf("  grant-namespace [-access read|write] NAMESPACE GRANTEE\n")
+	fmt.Printf("      shares a persistence namespace with the functions of another one ('*' for all), read only by default\n")
+	fmt.Printf("  revoke-namespace-grant NAMESPACE GRANTEE\n")
+	fmt.Printf("      stops sharing a persistence namespace\n")
+	fmt.Printf("\nthe admin credential (api token or JWT) is given with the -token option, the MYOWNCLUSTER_TOKEN\n")
+	fmt.Printf("environment variable, or in the file named by MYOWNCLUSTER_TOKEN_FILE (default: USER_CONFIG_DIR/my-own-cluster/token)\n")
 }
 
 func dumpDB(db *leveldb.DB) {
@@ -84,6 +128,29 @@ func dumpDB(db *leveldb.DB) {
 	fmt.Println("<=database_dump_finished=")
 }
 
+// bootstrapAdminToken creates an admin api token when the cluster has none, so that it can be
+// administrated, and writes it in the working directory
+func bootstrapAdminToken(orchestrator *common.Orchestrator, workingDir string) {
+	if len(orchestrator.GetAPITokens()) > 0 {
+		return
+	}
+
+	token, err := orchestrator.CreateAPIToken("bootstrap", common.ROLE_ADMIN)
+	if err != nil {
+		fmt.Printf("[error] cannot create the bootstrap admin api token (%v)\n", err)
+		return
+	}
+
+	tokenPath := filepath.Join(workingDir, "admin-token")
+	err = ioutil.WriteFile(tokenPath, []byte(token+"\n"), 0600)
+	if err != nil {
+		fmt.Printf("[error] cannot write the bootstrap admin api token in %s (%v), here it is: %s\n", tokenPath, err, token)
+		return
+	}
+
+	fmt.Printf("created the 'bootstrap' admin api token in %s\n", tokenPath)
+}
+
 func main() {
 	verbs, err := ParseArgs(os.Args)
 	if err != nil {
@@ -100,6 +167,8 @@ func main() {
 		return
 	}
 
+	credential = getCredential(verbs[0])
+
 	// execute the verb
 	switch verbs[0].Name {
 	case "serve":
@@ -133,52 +202,89 @@ func main() {
 			dumpDB(db)
 		}
 
-		{
-			// migrate old plug system
-			prefix := []byte("/plugs/byspec/")
-			iter := db.NewIterator(util.BytesPrefix(prefix), nil)
-			for iter.Next() {
-				key := string(iter.Key()[len(prefix):])
-				value := iter.Value()
-
-				s := strings.Index(key, "/")
-				method := key[0:s]
-				path := key[s+1:]
-
-				fmt.Printf("migration: %s %s %s\n", method, path, string(value))
-
-				newKey := []byte(fmt.Sprintf("/plug_system/plugs/byspec/%s/%s", method, path))
-				hasIt, _ := db.Has(newKey, nil)
-				if hasIt {
-					fmt.Printf("=> skip, already on new version...\n")
-				} else {
-					fmt.Printf("=> copy to %s\n", string(newKey))
-					db.Put(newKey, value, nil)
-				}
-
-				fmt.Printf("=> deleting old key\n")
-				db.Delete(iter.Key(), nil)
+		// data migrations of the cluster, before the data is loaded
+		versionning := common.NewVersionning(db)
+		versionning.RegisterDomainHandler(common.PlugsDomain, common.NewPlugsMigrator(db, trace))
+		versionning.RegisterDomainHandler(common.PersistenceDomain, common.NewPersistenceMigrator(db, trace))
+		for _, domain := range []string{common.PlugsDomain, common.PersistenceDomain} {
+			err = versionning.MigrateDomain(domain)
+			if err != nil {
+				fmt.Printf("cannot migrate the database (%v)\n", err)
+				return
 			}
-			iter.Release()
 		}
 
-		db.Put([]byte("/database-version"), []byte("1"), nil)
-
 		if removeFilters {
 			fmt.Printf("\nremoving all filters because of command line option\n\n")
 			db.Delete([]byte("/filters"), nil)
 		}
 
-		orchestrator := common.NewOrchestrator(db, trace)
+		orchestrator, err := common.NewOrchestrator(db, trace)
+		if err != nil {
+			fmt.Printf("cannot create the orchestrator (%v)\n", err)
+			return
+		}
+		orchestrator.SetVersionning(versionning)
 
 		// register execution engines
-		orchestrator.AddExecutionEngine("text/javascript", enginejs.NewJavascriptDuktapeEngine())
+		// the first engine registered for a content type is the default one
+		orchestrator.AddExecutionEngine("text/javascript", "duktape", enginejs.NewJavascriptDuktapeEngine())
 		if useWasmer {
-			fmt.Printf("use WASMER web assembly engine")
-			orchestrator.AddExecutionEngine("application/wasm", enginewasmer.NewWasmWasmerEngine())
+			orchestrator.AddExecutionEngine("application/wasm", "wasmer", enginewasmer.NewWasmWasmerEngine())
+			orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
 		} else {
-			fmt.Printf("use WASM3 web assembly engine")
-			orchestrator.AddExecutionEngine("application/wasm", enginewasm.NewWasmWasm3Engine())
+			orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
+			orchestrator.AddExecutionEngine("application/wasm", "wasmer", enginewasmer.NewWasmWasmerEngine())
+		}
+
+		if cacheSizeOption, ok := verbs[0].Options["cache-size"]; ok {
+			cacheSize, err := strconv.Atoi(cacheSizeOption)
+			if err != nil || cacheSize < 0 {
+				fmt.Printf("wrong cache size '%s', should be a positive number\n", cacheSizeOption)
+				return
+			}
+			orchestrator.SetCacheSize(cacheSize)
+		}
+
+		if defaultLimitsOption, ok := verbs[0].Options["defaultLimits"]; ok {
+			tags := map[string]string{}
+			err := json.Unmarshal([]byte(defaultLimitsOption), &tags)
+			if err != nil {
+				fmt.Printf("wrong default limits '%s' (%v)\n", defaultLimitsOption, err)
+				return
+			}
+			defaultLimits, err := common.ParseExecutionLimits(tags)
+			if err != nil {
+				fmt.Printf("wrong default limits '%s' (%v)\n", defaultLimitsOption, err)
+				return
+			}
+			orchestrator.SetDefaultLimits(defaultLimits)
+		}
+
+		// the egress policy file is optional when not given explicitly
+		egressPolicyPath, egressPolicyGiven := verbs[0].Options["egressPolicy"]
+		if !egressPolicyGiven {
+			egressPolicyPath = filepath.Join(workingDir, "egress-policy.json")
+		}
+		if _, err := os.Stat(egressPolicyPath); err == nil || egressPolicyGiven {
+			egressPolicy, err := common.LoadEgressPolicy(egressPolicyPath)
+			if err == nil {
+				err = orchestrator.SetEgressPolicy(egressPolicy)
+			}
+			if err != nil {
+				fmt.Printf("cannot apply the egress policy '%s' (%v)\n", egressPolicyPath, err)
+				return
+			}
+			fmt.Printf("applied the egress policy '%s'\n", egressPolicyPath)
+		}
+
+		masterKey, err := common.LoadMasterKey(verbs[0].GetOptionOr("masterKeyFile", filepath.Join(workingDir, "master.key")))
+		if err == nil {
+			err = orchestrator.SetMasterKey(masterKey)
+		}
+		if err != nil {
+			fmt.Printf("cannot load the master key of the secrets (%v)\n", err)
+			return
 		}
 
 		// add api providers
@@ -191,28 +297,64 @@ func main() {
 		if err == nil {
 			orchestrator.AddAPIProvider("gpu", apiProvider)
 		}
-		apiProvider, err = apijwt.NewJWTAPIProvider()
+		jwtAPIProvider, err := apijwt.NewJWTAPIProvider(orchestrator)
 		if err == nil {
-			orchestrator.AddAPIProvider("jwt", apiProvider)
+			if issuer, ok := verbs[0].Options["jwtIssuer"]; ok {
+				jwtAPIProvider.(*apijwt.JWTAPIProvider).SetIssuer(issuer)
+			}
+			orchestrator.AddAPIProvider("jwt", jwtAPIProvider)
+		} else {
+			fmt.Printf("[error] cannot start the jwt api provider (%v)\n", err)
 		}
 
 		// init core-api
 		coreAPILibrary, err := assetsgen.Asset("assets/rest-default-api.js")
 		if err == nil {
-			systemTags := "{\"category\":\"system-bootstrap\"}"
+			readOnlyTags := "{\"category\":\"system-bootstrap\",\"capabilities\":\"*\",\"role\":\"read-only\"}"
+			deployerTags := "{\"category\":\"system-bootstrap\",\"capabilities\":\"*\",\"role\":\"deployer\"}"
+			adminTags := "{\"category\":\"system-bootstrap\",\"capabilities\":\"*\",\"role\":\"admin\"}"
+			// the changes are streamed until the client leaves, whatever the default timeout
+			watchTags := "{\"category\":\"system-bootstrap\",\"capabilities\":\"*\",\"role\":\"admin\",\"timeout\":\"0\"}"
 			orchestrator.RegisterBlobWithName("core-api", "text/javascript", coreAPILibrary)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/blob/register", "core-api", "registerBlob", "", systemTags)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/file/plug", "core-api", "plugFile", "", systemTags)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/plug", "core-api", "plugFunction", "", systemTags)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/unplug", "core-api", "unplugPath", "", systemTags)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/call", "core-api", "callFunction", "", systemTags)
-			orchestrator.PlugFunction("POST", "/my-own-cluster/api/filter/plug", "core-api", "plugFilter", "", systemTags)
-			orchestrator.PlugFunction("DELETE", "/my-own-cluster/api/filter/plug/!filter-id", "core-api", "unplugFilter", "", systemTags)
-			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/export-database", "core-api", "exportDatabase", "", systemTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/status", "core-api", "getStatus", "", readOnlyTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/blob/register", "core-api", "registerBlob", "", deployerTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/file/plug", "core-api", "plugFile", "", deployerTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/plug", "core-api", "plugFunction", "", deployerTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/unplug", "core-api", "unplugPath", "", deployerTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/function/call", "core-api", "callFunction", "", deployerTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/filter/plug", "core-api", "plugFilter", "", adminTags)
+			orchestrator.PlugFunction("DELETE", "/my-own-cluster/api/filter/plug/!filter-id", "core-api", "unplugFilter", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/export-database", "core-api", "exportDatabase", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/tokens", "core-api", "getApiTokens", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/tokens", "core-api", "createApiToken", "", adminTags)
+			orchestrator.PlugFunction("DELETE", "/my-own-cluster/api/admin/tokens/!name", "core-api", "revokeApiToken", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/trust-providers", "core-api", "getTrustProviders", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/trust-providers", "core-api", "addTrustProvider", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/trust-providers/remove", "core-api", "removeTrustProvider", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/signing-keys/rotate", "core-api", "rotateSigningKey", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/secrets", "core-api", "getSecrets", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/secrets", "core-api", "setSecret", "", adminTags)
+			orchestrator.PlugFunction("DELETE", "/my-own-cluster/api/admin/secrets/!name", "core-api", "deleteSecret", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/namespaces", "core-api", "getNamespaces", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/namespaces/keys", "core-api", "scanNamespace", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/namespaces/changes", "core-api", "watchNamespace", "", watchTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/namespaces/grants", "core-api", "grantNamespace", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/namespaces/grants/revoke", "core-api", "revokeNamespaceGrant", "", adminTags)
+			orchestrator.PlugFunction("GET", "/my-own-cluster/api/admin/domains", "core-api", "getDomains", "", adminTags)
+			orchestrator.PlugFunction("POST", "/my-own-cluster/api/admin/domains/handlers", "core-api", "registerDomainHandler", "", adminTags)
+			orchestrator.PlugFunction("GET", "/.well-known/jwks.json", "core-api", "getJwks", "", "{\"category\":\"system-bootstrap\"}")
 		} else {
 			fmt.Printf("[error] cannot load rest-default-api.js, things may go bad quickly...\n")
 		}
 
+		// guest domains are migrated while serving, their functions being loaded
+		err = orchestrator.MigrateGuestDomains()
+		if err != nil {
+			fmt.Printf("[error] cannot migrate the guest domains (%v)\n", err)
+		}
+
+		bootstrapAdminToken(orchestrator, workingDir)
+
 		port := 8443
 		if portOption, ok := verbs[0].Options["port"]; ok {
 			port, err = strconv.Atoi(portOption)
@@ -248,6 +390,60 @@ func main() {
 	case "export-database":
 		CliExportDatabase(verbs)
 
+	case "status":
+		CliStatus(verbs)
+
+	case "create-token":
+		CliCreateToken(verbs)
+
+	case "list-tokens":
+		CliListTokens(verbs)
+
+	case "revoke-token":
+		CliRevokeToken(verbs)
+
+	case "add-trust-provider":
+		CliAddTrustProvider(verbs)
+
+	case "list-trust-providers":
+		CliListTrustProviders(verbs)
+
+	case "remove-trust-provider":
+		CliRemoveTrustProvider(verbs)
+
+	case "rotate-signing-key":
+		CliRotateSigningKey(verbs)
+
+	case "set-secret":
+		CliSetSecret(verbs)
+
+	case "list-secrets":
+		CliListSecrets(verbs)
+
+	case "delete-secret":
+		CliDeleteSecret(verbs)
+
+	case "list-namespaces":
+		CliListNamespaces(verbs)
+
+	case "scan":
+		CliScanNamespace(verbs)
+
+	case "watch":
+		CliWatchNamespace(verbs)
+
+	case "list-domains":
+		CliListDomains(verbs)
+
+	case "register-domain-handler":
+		CliRegisterDomainHandler(verbs)
+
+	case "grant-namespace":
+		CliGrantNamespace(verbs)
+
+	case "revoke-namespace-grant":
+		CliRevokeNamespaceGrant(verbs)
+
 	case "upload":
 		CliUploadFile(verbs)
 
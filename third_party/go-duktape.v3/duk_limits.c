@@ -0,0 +1,128 @@
+/*
+ *  Counting allocator and execution timeout check of the heaps with limits.
+ *
+ *  Each allocation is preceded by a header keeping its size, so that the
+ *  heap size is known when blocks are reallocated and freed.
+ *
+ *  Duktape runs a garbage collection and retries a failed allocation, so the
+ *  limit is only exceeded when the retries keep failing : the refused
+ *  allocation is still pending at the next execution timeout check.
+ */
+
+#include <stdlib.h>
+#include <time.h>
+
+#include "duk_limits.h"
+
+/* keeps the alignment of malloc for the blocks given to duktape */
+#define DUK_LIMITS_HEADER_SIZE 16
+
+static void *duk__limits_reserve(duk_limits *limits, size_t previous, size_t size) {
+	size_t total = limits->used - previous + size;
+
+	if (limits->max_memory > 0 && size > previous && total > limits->max_memory) {
+		limits->refused = total;
+		limits->refused_size = size;
+		return NULL;
+	}
+
+	if (size == limits->refused_size) {
+		limits->refused = 0;
+	}
+
+	return limits;
+}
+
+static void *duk__limits_alloc(void *udata, duk_size_t size) {
+	duk_limits *limits = (duk_limits *) udata;
+	char *block;
+
+	if (duk__limits_reserve(limits, 0, size) == NULL) {
+		return NULL;
+	}
+
+	block = (char *) malloc(DUK_LIMITS_HEADER_SIZE + size);
+	if (block == NULL) {
+		return NULL;
+	}
+
+	*(size_t *) block = size;
+	limits->used += size;
+
+	return block + DUK_LIMITS_HEADER_SIZE;
+}
+
+static void duk__limits_free(void *udata, void *ptr) {
+	duk_limits *limits = (duk_limits *) udata;
+	char *block;
+
+	if (ptr == NULL) {
+		return;
+	}
+
+	block = (char *) ptr - DUK_LIMITS_HEADER_SIZE;
+	limits->used -= *(size_t *) block;
+
+	free(block);
+}
+
+static void *duk__limits_realloc(void *udata, void *ptr, duk_size_t size) {
+	duk_limits *limits = (duk_limits *) udata;
+	char *block;
+	size_t previous;
+
+	if (ptr == NULL) {
+		return duk__limits_alloc(udata, size);
+	}
+	if (size == 0) {
+		duk__limits_free(udata, ptr);
+		return NULL;
+	}
+
+	block = (char *) ptr - DUK_LIMITS_HEADER_SIZE;
+	previous = *(size_t *) block;
+
+	if (duk__limits_reserve(limits, previous, size) == NULL) {
+		return NULL;
+	}
+
+	block = (char *) realloc(block, DUK_LIMITS_HEADER_SIZE + size);
+	if (block == NULL) {
+		return NULL;
+	}
+
+	*(size_t *) block = size;
+	limits->used = limits->used - previous + size;
+
+	return block + DUK_LIMITS_HEADER_SIZE;
+}
+
+duk_context *duk_limits_create_heap(duk_limits *limits) {
+	return duk_create_heap(duk__limits_alloc, duk__limits_realloc, duk__limits_free, limits, NULL);
+}
+
+/* Called by the executor with the heap udata, which is NULL for the heaps created by duk_create_heap_default().
+ * Once it returns 1, it keeps doing so : the RangeError goes through all the catchpoints of the script. */
+int duk_limits_exec_timeout_check(void *udata) {
+	duk_limits *limits = (duk_limits *) udata;
+	struct timespec ts;
+
+	if (limits == NULL) {
+		return 0;
+	}
+
+	if (limits->refused > 0 && limits->exceeded == 0) {
+		limits->exceeded = limits->refused;
+	}
+	if (limits->exceeded > 0) {
+		return 1;
+	}
+
+	if (limits->deadline == 0) {
+		return 0;
+	}
+
+	clock_gettime(CLOCK_REALTIME, &ts);
+
+	return (int64_t) ts.tv_sec * 1000000000 + ts.tv_nsec >= limits->deadline;
+}
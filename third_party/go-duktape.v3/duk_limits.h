@@ -0,0 +1,30 @@
+#if !defined(DUK_LIMITS_H_INCLUDED)
+#define DUK_LIMITS_H_INCLUDED
+
+#include <stdint.h>
+
+#include "duktape.h"
+
+#if defined(__cplusplus)
+extern "C" {
+#endif
+
+/* Limits of a heap, given as heap udata to duktape */
+typedef struct duk_limits {
+	int64_t deadline;    /* realtime nanoseconds, 0 when there is no deadline */
+	size_t max_memory;   /* allocated bytes, 0 when there is no limit */
+	size_t used;         /* bytes currently allocated by the heap */
+	size_t refused;      /* heap size that the last refused allocation would have reached, until a retry succeeds */
+	size_t refused_size; /* size of the last refused allocation */
+	size_t exceeded;     /* heap size of the refused allocation which stopped the script, 0 if none */
+} duk_limits;
+
+/* Create a heap whose allocations are counted against limits, and which throws a RangeError once
+ * the deadline or the memory limit is reached */
+extern duk_context *duk_limits_create_heap(duk_limits *limits);
+
+#if defined(__cplusplus)
+}
+#endif  /* end 'extern "C"' wrapper */
+
+#endif  /* DUK_LIMITS_H_INCLUDED */
@@ -0,0 +1,3 @@
+module gopkg.in/ltearno/go-duktape.v3
+
+go 1.16
@@ -0,0 +1,87 @@
+package duktape
+
+/*
+#include <stdlib.h>
+
+#include "duk_logging.h"
+#include "duk_print_alert.h"
+#include "duk_module_duktape.h"
+#include "duk_console.h"
+#include "duk_limits.h"
+*/
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// NewWithLimits returns a context like New, whose heap counts its allocations. The limits are
+// set with SetMemoryLimit and SetDeadline, a script exceeding them gets a RangeError which it
+// cannot catch, so that the call returns.
+func NewWithLimits() *Context {
+	limits := (*C.duk_limits)(C.calloc(1, C.sizeof_duk_limits))
+
+	d := &Context{
+		&context{
+			duk_context: C.duk_limits_create_heap(limits),
+			fnIndex:     newFunctionIndex(),
+			timerIndex:  &timerIndex{},
+			limits:      limits,
+		},
+	}
+
+	ctx := d.duk_context
+	C.duk_logging_init(ctx, 0)
+	C.duk_print_alert_init(ctx, 0)
+	C.duk_module_duktape_init(ctx)
+	C.duk_console_init(ctx, 0)
+
+	return d
+}
+
+// SetMemoryLimit sets the maximum number of bytes allocated by the heap, 0 is unlimited
+func (d *Context) SetMemoryLimit(maxMemory int) {
+	if d.limits != nil {
+		d.limits.max_memory = C.size_t(maxMemory)
+	}
+}
+
+// SetDeadline sets the time after which scripts are stopped, the zero time is no deadline
+func (d *Context) SetDeadline(deadline time.Time) {
+	if d.limits == nil {
+		return
+	}
+
+	if deadline.IsZero() {
+		d.limits.deadline = 0
+	} else {
+		d.limits.deadline = C.int64_t(deadline.UnixNano())
+	}
+}
+
+// HeapSize returns the number of bytes allocated by the heap, 0 when it does not count them
+func (d *Context) HeapSize() int {
+	if d.limits == nil {
+		return 0
+	}
+
+	return int(d.limits.used)
+}
+
+// ExceededMemoryLimit returns the heap size that the allocation which stopped the script
+// would have reached, 0 when the memory limit has not stopped it
+func (d *Context) ExceededMemoryLimit() int {
+	if d.limits == nil {
+		return 0
+	}
+
+	return int(d.limits.exceeded)
+}
+
+func (d *Context) freeLimits() {
+	if d.limits != nil {
+		C.free(unsafe.Pointer(d.limits))
+		d.limits = nil
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -19,6 +20,11 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+type FunctionErrorResponse struct {
+	Message string                `json:"message"`
+	Exit    *common.ExecutionExit `json:"exit"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -94,6 +100,15 @@ func (server *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	credential, ok := server.authorize(w, r, path, plug)
+	if !ok {
+		return
+	}
+	if credential != nil {
+		// the function does not see the credential it was called with
+		r.Header.Del("Authorization")
+	}
+
 	var outputExchangeBufferID int
 	var inputExchangeBufferID int
 
@@ -112,9 +127,29 @@ func (server *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		inputExchangeBufferID = server.orchestrator.CreateWrappedHttpRequestExchangeBuffer(r)
 	}
 
-	// safe guard :  release exchange buffers
-	defer server.orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
-	defer server.orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+	// release the web server's references on the exchange buffers, then wait for the last
+	// reference to the output buffer to disappear : at this moment the http response is complete.
+	// An aborted function may still be running, in this case the response is not waited for.
+	outputReleased := server.orchestrator.WaitExchangeBufferRelease(outputExchangeBufferID)
+	aborted, broken := false, false
+	defer func() {
+		server.orchestrator.ReleaseExchangeBuffer(inputExchangeBufferID)
+		server.orchestrator.ReleaseExchangeBuffer(outputExchangeBufferID)
+
+		if !aborted {
+			select {
+			case <-outputReleased:
+			case <-r.Context().Done():
+				fmt.Printf("client gone before output exchange buffer %d was released\n", outputExchangeBufferID)
+			}
+		}
+
+		// a failed function has already sent a part of the response, the connection is cut so
+		// that the client does not take it for a complete response
+		if broken {
+			panic(http.ErrAbortHandler)
+		}
+	}()
 
 	// provide informations about current http request in the inputExchangeBuffer
 	inputExchangeBuffer := server.orchestrator.GetExchangeBuffer(inputExchangeBufferID)
@@ -150,7 +185,7 @@ func (server *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// ... and run it
 		err := fctx.Run()
 		if err != nil {
-			errorResponse(w, 500, fmt.Sprintf("error while executing the function: '%v'", err))
+			aborted, broken = server.functionErrorResponse(w, err, outputExchangeBufferID)
 			return
 		}
 
@@ -169,23 +204,68 @@ func (server *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			fmt.Printf("received plugged function request, path:'%s', type:%s, name:%s, start_function:%s\n", path, plugType, pluggedFunction.Name, pluggedFunction.StartFunction)
 		}
 
+		limits, err := pluggedFunction.GetExecutionLimits(server.orchestrator.DefaultLimits())
+		if err != nil {
+			errorResponse(w, 500, fmt.Sprintf("invalid plugged function limits: '%v'", err))
+			return
+		}
+
+		mounts, err := pluggedFunction.GetMounts()
+		if err != nil {
+			errorResponse(w, 500, fmt.Sprintf("invalid plugged function mounts: '%v'", err))
+			return
+		}
+
+		capabilities, err := pluggedFunction.GetCapabilities()
+		if err != nil {
+			errorResponse(w, 500, fmt.Sprintf("invalid plugged function capabilities: '%v'", err))
+			return
+		}
+
+		egress, err := pluggedFunction.GetEgressRules()
+		if err != nil {
+			errorResponse(w, 500, fmt.Sprintf("invalid plugged function egress: '%v'", err))
+			return
+		}
+
+		secretsEnvironment, err := pluggedFunction.GetSecretsEnvironment()
+		if err != nil {
+			errorResponse(w, 500, fmt.Sprintf("invalid plugged function secrets_env: '%v'", err))
+			return
+		}
+
 		// create a function execution context ...
 		fctx := server.orchestrator.NewFunctionExecutionContext(
 			pluggedFunction.Name,
 			pluggedFunction.StartFunction,
 			[]int{},
 			server.trace,
-			"direct",
+			pluggedFunction.GetMode(),
 			nil,
 			nil,
 			inputExchangeBufferID,
 			outputExchangeBufferID,
 		)
+		fctx.SetLimits(limits)
+		fctx.Engine = pluggedFunction.GetEngine()
+		fctx.Mounts = mounts
+		fctx.Capabilities = capabilities
+		fctx.Egress = egress
+		fctx.Credential = credential
+		fctx.Namespace = pluggedFunction.GetNamespace()
+
+		if fctx.Mode == "posix" {
+			fctx.Environment, err = fctx.SecretsEnvironment(secretsEnvironment)
+			if err != nil {
+				errorResponse(w, 500, fmt.Sprintf("cannot read the secrets of the function: '%v'", err))
+				return
+			}
+		}
 
 		// ... and run it
-		err := fctx.Run()
+		err = fctx.Run()
 		if err != nil {
-			errorResponse(w, 500, fmt.Sprintf("error while executing the function: '%v'", err))
+			aborted, broken = server.functionErrorResponse(w, err, outputExchangeBufferID)
 			return
 		}
 
@@ -240,6 +320,97 @@ func (server *WebServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	return
 }
 
+// authorize checks the admin credential of requests to plugs requiring a role, answering 401 when
+// there is no valid credential and 403 when its role is too low
+func (server *WebServer) authorize(w http.ResponseWriter, r *http.Request, path string, plug interface{}) (*common.AdminCredential, bool) {
+	var tags map[string]string
+	switch p := plug.(type) {
+	case *common.PluggedFunction:
+		tags = p.Tags
+	case *common.PluggedFile:
+		tags = p.Tags
+	}
+
+	role := common.RequiredRole(path, tags)
+	if role == "" {
+		return nil, true
+	}
+
+	credential, err := server.orchestrator.Authenticate(r.Header.Get("Authorization"))
+	if err != nil {
+		if server.trace {
+			fmt.Printf("refused unauthenticated request for path '%s' (%v)\n", path, err)
+		}
+
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		errorResponse(w, 401, fmt.Sprintf("the '%s' role is required: '%v'", role, err))
+		return nil, false
+	}
+
+	if !common.RoleAllows(credential.Role, role) {
+		errorResponse(w, 403, fmt.Sprintf("the '%s' role is required, %s '%s' has the '%s' role", role, credential.Kind, credential.Name, credential.Role))
+		return nil, false
+	}
+
+	return credential, true
+}
+
+// functionErrorResponse answers 504 to functions running out of time or fuel, 507 to functions
+// running out of memory, 403 to functions importing host functions they may not use and 500
+// otherwise, with the exit of the functions which trapped or which host function failed. When the
+// function has been aborted, the output buffer is closed first so that the function, which may
+// still be running, cannot write in the response anymore. It returns if the function has been
+// aborted, and if the response was already started by the function : no error can be answered
+// then and the connection has to be cut.
+func (server *WebServer) functionErrorResponse(w http.ResponseWriter, err error, outputExchangeBufferID int) (bool, bool) {
+	if errors.Is(err, common.ErrExecutionEngineUnavailable) {
+		errorResponse(w, 503, fmt.Sprintf("cannot execute the function: '%v'", err))
+		return false, false
+	}
+
+	if errors.Is(err, common.ErrPermissionDenied) {
+		errorResponse(w, 403, fmt.Sprintf("cannot execute the function: '%v'", err))
+		return false, false
+	}
+
+	// a function whose called function exceeded its limits is answered like the called function
+	var executionError *common.ExecutionError
+	isExecutionError := errors.As(err, &executionError) && !common.IsExecutionLimitError(err)
+	if !isExecutionError && !common.IsExecutionLimitError(err) {
+		errorResponse(w, 500, fmt.Sprintf("error while executing the function: '%v'", err))
+		return false, false
+	}
+
+	started := false
+	outputExchangeBuffer := server.orchestrator.GetExchangeBuffer(outputExchangeBufferID)
+	if outputExchangeBuffer != nil {
+		if httpWriter, ok := outputExchangeBuffer.(*common.HttpWriterExchangeBuffer); ok {
+			started = httpWriter.HasStartedResponse()
+		}
+		outputExchangeBuffer.Close()
+	}
+
+	aborted := !isExecutionError
+	if started {
+		fmt.Printf("function failed after starting its response, cutting the connection (%v)\n", err)
+		return aborted, true
+	}
+
+	if isExecutionError {
+		jsonResponse(w, 500, FunctionErrorResponse{fmt.Sprintf("function failed: '%v'", err), executionError.Exit})
+		return aborted, false
+	}
+
+	code := 504
+	if errors.Is(err, common.ErrMemoryLimitExceeded) {
+		code = 507
+	}
+
+	errorResponse(w, code, fmt.Sprintf("function aborted: '%v'", err))
+
+	return aborted, false
+}
+
 // StartWebServer runs a webserver hosting the application
 func StartWebServer(port int, workingDir string, orchestrator *common.Orchestrator, trace bool) {
 	webServer := &WebServer{
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ltearno/my-own-cluster/common"
+	"github.com/ltearno/my-own-cluster/enginewasm"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+func TestTrappedFunctionIsNotAnswered200(t *testing.T) {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	orchestrator, err := common.NewOrchestrator(db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orchestrator.AddExecutionEngine("application/wasm", "wasm3", enginewasm.NewWasmWasm3Engine())
+
+	codeBytes, err := ioutil.ReadFile("enginewasm/testdata/exits/unreachable.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = orchestrator.RegisterBlobWithName("unreachable.wasm", "application/wasm", codeBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = orchestrator.PlugFunction("GET", "/trap", "unreachable.wasm", "_start", "", "{}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &WebServer{name: "test", orchestrator: orchestrator}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("GET", "/trap", nil))
+
+	if recorder.Code != 500 {
+		t.Fatalf("a trapped function is answered %d", recorder.Code)
+	}
+
+	response := &FunctionErrorResponse{}
+	err = json.Unmarshal(recorder.Body.Bytes(), response)
+	if err != nil {
+		t.Fatalf("invalid error response %q (%v)", recorder.Body.String(), err)
+	}
+	if response.Exit == nil || response.Exit.Kind != common.EXIT_TRAPPED || response.Exit.Trap != common.TRAP_UNREACHABLE {
+		t.Fatalf("the error response gives the exit %+v", response.Exit)
+	}
+}